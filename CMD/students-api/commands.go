@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"      // Seed inserts run with a background context
+	"flag"         // -count / -seed
+	"fmt"          // Generated emails
+	"log"          // Fatal errors, same as serve
+	"log/slog"     // Progress and results
+	"math/rand/v2" // Deterministic fake data (PCG with a fixed seed)
+	"os"           // Exit status
+	"strings"      // Lower-case names in emails
+	"time"         // Default seed
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+//---------------------------------------------------------------------------
+// runMigrate → the "migrate" command
+//
+// Opening the storage is what applies the pending migrations (see
+// internal/storage/migrate), so this loads the config exactly like serve,
+// opens the configured backend and exits. Any failure → exit status 1.
+//---------------------------------------------------------------------------
+func runMigrate() {
+	cfg := config.MustLoad()
+	slog.SetDefault(newLogger(cfg, new(slog.LevelVar)))
+
+	if cfg.Storage.Driver == config.DriverMemory {
+		slog.Info("storage.driver is memory: there is no schema to migrate")
+		return
+	}
+
+	if _, err := newStorage(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	slog.Info("schema is up to date", slog.String("driver", cfg.Storage.Driver))
+}
+
+// seedBatchSize is how many fake students go into one CreateStudents call
+// (one transaction).
+const seedBatchSize = 500
+
+//---------------------------------------------------------------------------
+// runSeed → the "seed" command
+//
+//   -count N → how many students to insert (default 10)
+//   -seed S  → random seed; the same seed always generates the same
+//              students, so test data is reproducible. 0 (the default)
+//              picks one from the current time.
+//
+// Generated emails can already exist (running the same seed twice); those
+// students are skipped and counted, not treated as a failure.
+//---------------------------------------------------------------------------
+func runSeed() {
+	count := flag.Int("count", 10, "seed: number of students to insert")
+	seed := flag.Uint64("seed", 0, "seed: random seed for reproducible data (0 = pick one)")
+
+	cfg := config.MustLoad()
+	slog.SetDefault(newLogger(cfg, new(slog.LevelVar)))
+
+	if *count < 1 {
+		slog.Error("seed: -count must be at least 1", slog.Int("count", *count))
+		os.Exit(1)
+	}
+
+	// The chosen seed is logged at the end, so a random run can be repeated
+	if *seed == 0 {
+		*seed = uint64(time.Now().UnixNano())
+	}
+
+	storage, err := newStorage(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	students := fakeStudents(*count, *seed)
+	created, skipped := 0, 0
+
+	for start := 0; start < len(students); start += seedBatchSize {
+		end := min(start+seedBatchSize, len(students))
+
+		results, err := storage.CreateStudents(context.Background(), students[start:end], false)
+		if err != nil {
+			slog.Error("seed failed", slog.String("error", err.Error()), slog.Int("created", created))
+			os.Exit(1)
+		}
+
+		for _, result := range results {
+			if result.Err != nil {
+				skipped++
+				slog.Debug("seed: student skipped", slog.String("error", result.Err.Error()))
+				continue
+			}
+			created++
+		}
+	}
+
+	slog.Info("seed finished",
+		slog.Int("created", created),
+		slog.Int("skipped", skipped),
+		slog.Uint64("seed", *seed),
+	)
+}
+
+var (
+	seedFirstNames = []string{
+		"Aarav", "Aditi", "Arjun", "Diya", "Ishaan", "Kavya", "Meera", "Neha",
+		"Priya", "Rahul", "Riya", "Rohan", "Sara", "Vikram", "Vinayak", "Zoya",
+	}
+	seedLastNames = []string{
+		"Bansal", "Chopra", "Das", "Gupta", "Iyer", "Joshi", "Kapoor", "Khan",
+		"Mehta", "Nair", "Patel", "Rao", "Reddy", "Shah", "Sharma", "Singh",
+	}
+)
+
+// fakeStudents generates n students from seed. The output depends only on
+// n and seed (PCG is a fixed algorithm), never on the Go version or time.
+func fakeStudents(n int, seed uint64) []types.Student {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	students := make([]types.Student, n)
+	for i := range students {
+		first := seedFirstNames[rng.IntN(len(seedFirstNames))]
+		last := seedLastNames[rng.IntN(len(seedLastNames))]
+
+		students[i] = types.Student{
+			Name:   first + " " + last,
+			Email:  fmt.Sprintf("%s.%s.%d@example.com", strings.ToLower(first), strings.ToLower(last), rng.IntN(1_000_000)),
+			Age:    17 + rng.IntN(14),
+			Status: types.StatusActive,
+		}
+	}
+
+	return students
+}
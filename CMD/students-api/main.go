@@ -1,20 +1,97 @@
 package main
 
 import (
-	"context"   // Provides cancellation, deadlines → used for graceful shutdown
-	"fmt"       // For printing messages to console
-	"log/slog"  // Modern structured logger (Go 1.21+)
-	"net/http"  // HTTP server, routing, Request/Response
-	"os"        // Access OS features (signals, env, process)
-	"os/signal" // Used to catch CTRL+C or shutdown signals
-	"syscall"   // Provides OS-level signals like SIGTERM, SIGINT
-	"time"      // For timeouts: graceful shutdown timeout duration
-
-	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config" // Custom config loader
-	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/student"
+	"context"       // Provides cancellation, deadlines → Run stops when ctx is cancelled
+	"errors"        // errors.Is → tell a forced shutdown from a failure
+	"flag"          // Flags of the migrate / seed commands and the usage text
+	"fmt"           // Usage text on stderr
+	"log"           // For fatal startup errors (storage can't be opened)
+	"log/slog"      // Modern structured logger (Go 1.21+)
+	"os"            // Access OS features (signals, env, process)
+	"os/signal"     // Used to catch CTRL+C or shutdown signals
+	"path/filepath" // Program name in the usage text
+	"strings"       // Tell a command apart from a flag in os.Args[1]
+	"syscall"       // Provides OS-level signals like SIGTERM, SIGINT
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/app"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo" // Version / commit for --version
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"    // Custom config loader
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/photos" // photos.store: disk
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/postgres"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/sqlite"
 )
 
+//---------------------------------------------------------------------------
+// main → dispatches on the first argument
+//
+//   students-api [serve] [-config path]   → run the API (the default)
+//   students-api migrate [-config path]   → apply schema migrations and exit
+//   students-api seed [-count N] [-seed S] [-config path]
+//                                         → insert N fake students and exit
+//   students-api --version                → print the build and exit
+//
+// --version is answered before anything else, so it works without a
+// config. The command is removed from os.Args so the flag package (used by
+// config.Load) parses the remaining flags as usual.
+//---------------------------------------------------------------------------
 func main() {
+	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-version") {
+		fmt.Println(serviceName, buildinfo.Get())
+		return
+	}
+
+	command := "serve"
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		command = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
+	flag.Usage = usage
+
+	switch command {
+	case "serve":
+		serve()
+	case "migrate":
+		runMigrate()
+	case "seed":
+		runSeed()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		usage()
+		os.Exit(2)
+	}
+}
+
+// usage is printed for -h and for an unknown command.
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: %s [command] [flags]
+
+Commands:
+  serve     run the API (default)
+  migrate   apply pending schema migrations and exit
+  seed      insert fake students and exit
+
+  --version prints the build (version, commit, build time) and exits
+`, filepath.Base(os.Args[0]))
+
+	// Flags are registered by the command (and config.Load), so there are
+	// none to list when the command itself is unknown
+	defined := false
+	flag.VisitAll(func(*flag.Flag) { defined = true })
+	if defined {
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flag.PrintDefaults()
+	}
+}
+
+//---------------------------------------------------------------------------
+// serve → the "serve" command: runs the API until SIGINT / SIGTERM
+// (SIGHUP reloads the config and reopens the access log)
+//---------------------------------------------------------------------------
+func serve() {
 
 	//---------------------------------------------------------------------------
 	// STEP 1 → Load configuration from config.yaml (using MustLoad)
@@ -23,160 +100,203 @@ func main() {
 	//---------------------------------------------------------------------------
 	cfg := config.MustLoad()
 
-
-
-	//---------------------------------------------------------------------------
-	// STEP 2 → Setup router (HTTP multiplexer)
-	// http.NewServeMux creates a new router which maps routes to handler functions.
-	// This router will receive and route all HTTP requests.
-	//---------------------------------------------------------------------------
-	router := http.NewServeMux()
+	// Replace slog's default logger (plain text, INFO) with the configured one.
+	// Every slog.Info/Error call from here on goes through it. Its level is a
+	// LevelVar, so a reload (SIGHUP) can change it.
+	level := new(slog.LevelVar)
+	slog.SetDefault(newLogger(cfg, level))
 
 
 
 	//---------------------------------------------------------------------------
-	// STEP 3 → Register a route handler
-	//
-	// HandleFunc pattern: router.HandleFunc("METHOD /PATH", handlerFunc)
+	// STEP 2 → Setup storage (SQLite, Postgres or memory, see storage.driver)
 	//
-	// "GET /" means:
-	//   - Method must be GET
-	//   - Path must be "/"
-	// This requires Go 1.22+ (new HTTP pattern matching)
-	//
-	// Handler function parameters:
-	//   w → ResponseWriter (we write response back to the client)
-	//   r → Request (contains request data)
+	// newStorage opens the configured database and creates the students
+	// table if needed. Without storage the API can't do anything useful, so
+	// any error here is fatal.
 	//---------------------------------------------------------------------------
-	router.HandleFunc("POST /api/students", student.New())
-
-
-
-	//---------------------------------------------------------------------------
-	// STEP 4 → Create HTTP Server instance
-	//
-	// http.Server struct holds:
-	//   Addr    → Address where server listens (like ":8080")
-	//   Handler → Router handling all requests
-	//
-	// cfg.HTTPServer.Addr comes from your YAML config:
-	// 
-	// http_server:
-	//   addr: ":8082"
-	//---------------------------------------------------------------------------
-	server := http.Server{
-		Addr:    cfg.HTTPServer.Addr,
-		Handler: router,
+	db, err := newStorage(cfg)
+	if err != nil {
+		log.Fatal(err)
 	}
 
+	// Every storage call is counted in storage_queries_total /
+	// storage_errors_total, whatever the backend. The SQL backends also
+	// expose their connection pool (go_sql_*, storage.max_open_conns…)
+	// and retry the calls that failed with a transient error
+	// (storage.retry); the metrics count one call however many tries.
+	switch sqlDB := db.(type) {
+	case *sqlite.Sqlite:
+		metrics.RegisterDBStats(sqlDB.Db, cfg.Storage.Driver)
+		db = storage.Retry(db, cfg.Storage.Retry, sqlite.IsTransient)
+	case *postgres.Postgres:
+		metrics.RegisterDBStats(sqlDB.Db, cfg.Storage.Driver)
+		db = storage.Retry(db, cfg.Storage.Retry, postgres.IsTransient)
+	}
 
+	// With photos.store "disk" the photos are files next to the database
+	// rather than rows of it.
+	if cfg.Photos.Store == config.PhotoStoreDisk {
+		if db, err = photos.DiskStorage(db, cfg.Photos.DiskDir(cfg.StoragePath)); err != nil {
+			log.Fatal(err)
+		}
+	}
+	storage := metrics.InstrumentStorage(db)
 
-	//---------------------------------------------------------------------------
-	// STEP 5 → Create a channel to receive OS shutdown signals
-	//
-	// make(chan os.Signal, 1)
-	//   - Buffer size 1 means channel can hold 1 signal
-	//
-	// This channel listens for:
-	//   - CTRL + C  → os.Interrupt
-	//   - SIGINT    → syscall.SIGINT  (interrupt)
-	//   - SIGTERM   → syscall.SIGTERM (termination)
-	//---------------------------------------------------------------------------
-	done := make(chan os.Signal, 1)
+	slog.Info("storage initialized", slog.String("driver", cfg.Storage.Driver))
 
 
 
 	//---------------------------------------------------------------------------
-	// STEP 6 → Register signals to be caught by this channel
-	//
-	// signal.Notify listens for OS signals and sends them into `done` channel.
+	// STEP 3 → Context cancelled by CTRL+C (SIGINT) or SIGTERM
 	//
-	// Meaning:
-	//   When user presses CTRL+C → a signal is sent to `done`
-	//   When OS sends shutdown → signal goes into `done`
+	// The first signal cancels ctx; that is what starts the graceful
+	// shutdown. The channel keeps listening: a second signal (CTRL+C again
+	// while a request is stuck) forces it, see app.ForceShutdown.
 	//---------------------------------------------------------------------------
-	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
+	api := app.New(cfg, storage)
 
-	//---------------------------------------------------------------------------
-	// STEP 7 → Run HTTP server in a separate goroutine
-	//
-	// WHY A GOROUTINE?
-	//   Because ListenAndServe is a BLOCKING call.
-	//   If we run it in main goroutine, we can never receive shutdown signals.
-	//
-	// Anonymous goroutine:
-	//   go func() { ... }()
-	//---------------------------------------------------------------------------
+	stopSignals := make(chan os.Signal, 1)
+	signal.Notify(stopSignals, os.Interrupt, syscall.SIGTERM)
 	go func() {
+		sig := <-stopSignals
+		slog.Info("signal received, shutting down gracefully (send it again to force)", slog.String("signal", sig.String()))
+		cancel()
 
-		fmt.Println("server started on", cfg.HTTPServer.Addr)
-
-		// server.ListenAndServe starts serving HTTP requests.
-		// It returns an error only when server stops.
-		err := server.ListenAndServe()
+		sig = <-stopSignals
+		slog.Warn("second signal received, forcing shutdown", slog.String("signal", sig.String()))
+		api.ForceShutdown()
+	}()
 
-		// If server stops due to shutdown:
-		//   http.ErrServerClosed → normal shutdown
-		// If any other error:
-		//   real failure
-		if err != nil && err != http.ErrServerClosed {
-			slog.Error("server error", slog.String("error", err.Error()))
+	// SIGHUP does not stop the server: it reloads the config (see reload)
+	// and reopens the access log file, so logrotate can move it away and
+	// signal us (postrotate kill -HUP).
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			reload(api, level)
+
+			if err := api.ReopenAccessLog(); err != nil {
+				slog.Error("cannot reopen access log", slog.String("error", err.Error()))
+			} else if cfg.Log.AccessLogPath != "" {
+				slog.Info("access log reopened", slog.String("path", cfg.Log.AccessLogPath))
+			}
 		}
 	}()
 
 
 
 	//---------------------------------------------------------------------------
-	// STEP 8 → Block main goroutine until shutdown signal received
+	// STEP 4 → Run the API until ctx is cancelled
 	//
-	// <-done : this waits until something is sent to the channel.
-	// Once CTRL+C is pressed, we continue execution (shutdown begins).
-	//---------------------------------------------------------------------------
-	<-done
-
-
-
-	//---------------------------------------------------------------------------
-	// STEP 9 → Log shutdown initiation
+	// app.Run binds the listeners, serves, and shuts down gracefully
+	// (see internal/app). It returns an error when a server could not
+	// start or died on its own → exit status 1; that includes an access
+	// log file that can't be opened. A forced shutdown exits with
+	// exitForcedShutdown, so scripts can tell it from a clean stop (0).
 	//---------------------------------------------------------------------------
-	slog.Info("shutting down the server")
-
+	if err := api.Run(ctx); err != nil {
+		slog.Error("server error", slog.String("error", err.Error()))
+		cancel()
 
+		if errors.Is(err, app.ErrForcedShutdown) {
+			os.Exit(exitForcedShutdown)
+		}
+		os.Exit(1)
+	}
+}
 
-	//---------------------------------------------------------------------------
-	// STEP 10 → Create context with timeout for graceful shutdown
-	//
-	// context.WithTimeout:
-	//   - Allows ongoing requests to finish within N seconds
-	//   - If timeout expires → force shutdown
-	//
-	// 5 * time.Second:
-	//   Maximum wait duration for open connections to close cleanly.
-	//---------------------------------------------------------------------------
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// exitForcedShutdown is the exit status after a forced shutdown: 128 + SIGINT,
+// what shells report for a program interrupted by CTRL+C.
+const exitForcedShutdown = 130
+
+//---------------------------------------------------------------------------
+// reload → SIGHUP: reads the config again (same file / environment as at
+// startup) and applies what can change at runtime
+//
+//   log.level           → here, through the logger's LevelVar
+//   cors, rate limits   → api.Reload, which also logs every other changed
+//                         setting as ignored on reload
+//
+// A config that fails to load or validate is reported and the running one
+// stays in effect: a typo must not take the server down.
+//---------------------------------------------------------------------------
+func reload(api *app.App, level *slog.LevelVar) {
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("config reload failed, keeping the running config", slog.String("error", err.Error()))
+		return
+	}
 
+	// config.Validate already rejected unknown names
+	var newLevel slog.Level
+	newLevel.UnmarshalText([]byte(cfg.Log.Level))
+	level.Set(newLevel)
 
+	api.Reload(cfg)
+}
 
-	//---------------------------------------------------------------------------
-	// STEP 11 → Gracefully shut down server
-	//
-	// server.Shutdown(ctx):
-	//   ✔ stops accepting new requests
-	//   ✔ finishes ongoing requests
-	//   ✔ closes idle connections
-	//   ✔ respects timeout
-	//---------------------------------------------------------------------------
-	if err := server.Shutdown(ctx); err != nil {
-		slog.Error("Failed to shutdown server", slog.String("error", err.Error()))
+//---------------------------------------------------------------------------
+// newStorage → picks the storage backend from storage.driver
+//
+// config.Validate already rejected unknown drivers and missing settings
+// (storage_path for sqlite, storage.dsn for postgres).
+//---------------------------------------------------------------------------
+func newStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.Storage.Driver {
+	case config.DriverPostgres:
+		return postgres.New(cfg)
+	case config.DriverMemory:
+		slog.Warn("storage.driver is memory: students are lost when the server stops")
+		return memory.New(cfg), nil
+	default:
+		return sqlite.New(cfg)
 	}
+}
 
+// serviceName is attached to every log record so logs from several
+// services can be told apart in one place.
+const serviceName = "students-api"
+
+//---------------------------------------------------------------------------
+// newLogger → builds the application logger from the "log" config section
+//
+//   level  → debug / info / warn / error, held in level so a reload can
+//            change it
+//   format → "json" or "text"; when empty, JSON in production (for log
+//            collectors) and human-friendly text everywhere else
+//
+// Every record carries "service" and "env" as default attributes.
+//---------------------------------------------------------------------------
+func newLogger(cfg *config.Config, level *slog.LevelVar) *slog.Logger {
+	var lvl slog.Level
+	// config.Validate already rejected unknown names
+	lvl.UnmarshalText([]byte(cfg.Log.Level))
+	level.Set(lvl)
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	format := cfg.Log.Format
+	if format == "" {
+		format = "text"
+		if cfg.Env == "production" {
+			format = "json"
+		}
+	}
 
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
 
-	//---------------------------------------------------------------------------
-	// STEP 12 → Confirm clean shutdown
-	//---------------------------------------------------------------------------
-	slog.Info("server shutdown successfully")
+	return slog.New(handler).With(
+		slog.String("service", serviceName),
+		slog.String("env", cfg.Env),
+	)
 }
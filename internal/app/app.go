@@ -0,0 +1,551 @@
+package app // app package wires config, storage and HTTP servers into one runnable unit
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context     → Run stops when its context is cancelled
+   - crypto/tls  → TLS certificate loading and minimum protocol version
+   - errors      → errors.Is → detect a shutdown that hit its deadline
+   - fmt         → wrap startup / serve errors with the server's name
+   - log/slog    → structured logging (new standard logger)
+   - net         → bind listeners before serving
+   - net/http    → http.Server
+   - sync        → the background jobs are waited for at shutdown,
+                   Reload swaps the config
+   - sync/atomic → shutdown flag read by the /ready handler
+   - time        → uptime, shutdown delay and drain duration
+
+   - buildinfo   → version / commit on the startup log line
+   - cache       → read-through cache of GET /api/students/{id}
+   - config      → every server setting comes from here
+   - events      → student changes for the SSE stream and webhooks
+   - logging     → the access log file (log.access_log_path)
+   - middleware  → CORS and rate limit rules, kept for Reload
+   - metrics     → /metrics handler for the separate listener
+   - redisstore  → cache and rate limits shared between replicas
+   - storage     → Storage interface the handlers persist through
+   - webhooks    → change notifications, drained at shutdown
+   - go-redis    → the Redis client (redis.addr)
+*/
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/cache"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/events"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/redisstore"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/webhooks"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+App STRUCT
+-------------------------------------------------------------
+  - Everything main() used to hold in local variables.
+  - Build it with New, then call Run once.
+*/
+type App struct {
+	// cfg is the running config. Reload replaces it, under cfgMu, with the
+	// one it applied (startup-only settings included), so the next reload
+	// only reports what changed since; read it through currentConfig.
+	cfg       *config.Config
+	cfgMu     sync.Mutex
+	storage   storage.Storage
+	startedAt time.Time
+
+	// events feeds the SSE stream; webhooks is nil unless webhooks.urls
+	// is set. Both receive every change made through storage.
+	events   *events.Hub
+	webhooks *webhooks.Dispatcher
+
+	// redis is nil unless redis.addr is set; it then holds the student
+	// cache and the rate limit buckets instead of this process.
+	redis *redis.Client
+
+	// accessLog is nil unless log.access_log_path is set; Run opens it
+	// and closes it after the shutdown.
+	accessLog *logging.File
+
+	// closers are the cleanup steps run when Run returns (see onClose);
+	// jobs tracks the background jobs, which one of them waits for.
+	closers []closer
+	jobs    sync.WaitGroup
+
+	// cors and limiter (nil unless rate_limit.enabled) are kept here so
+	// Reload can change their rules on the running handler.
+	cors    *middleware.CORSPolicy
+	limiter *middleware.RateLimiter
+
+	// shuttingDown flips to true when Run starts shutting down; /ready
+	// reports 503 from then on.
+	shuttingDown atomic.Bool
+
+	// bound is closed once Run has bound the API listener (or failed to),
+	// addr is the address it got.
+	bound chan struct{}
+	addr  string
+
+	// forced is cancelled by ForceShutdown; it cuts the graceful shutdown
+	// short.
+	forced context.Context
+	force  context.CancelFunc
+}
+
+// ErrForcedShutdown is returned by Run when ForceShutdown cut the graceful
+// shutdown short.
+var ErrForcedShutdown = errors.New("shutdown forced before in-flight requests finished")
+
+/*
+New()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Creates the App. Nothing is bound or started yet, so New
+	    never fails; all startup errors come from Run.
+	  → storage is wrapped so every change of a student is
+	    published to the SSE hub, and to webhooks when configured
+	    (see events.NotifyStorage).
+	  → With cache.enabled, GetStudentById is cached in between
+	    (in Redis when redis.addr is set, else in memory). The
+	    events read their payloads through it, after it has
+	    invalidated the changed student.
+	  → The App owns storage from here on: Run closes it, last of
+	    everything, when it returns.
+*/
+func New(cfg *config.Config, storage storage.Storage) *App {
+	a := &App{
+		cfg:       cfg,
+		storage:   storage,
+		startedAt: time.Now(),
+		bound:     make(chan struct{}),
+		events:    events.NewHub(),
+	}
+	a.forced, a.force = context.WithCancel(context.Background())
+	a.onClose("storage", closerTimeout, func(context.Context) error { return storage.Close() })
+
+	if cfg.Redis.Enabled() {
+		a.redis = redisstore.NewClient(cfg.Redis)
+		a.onClose("redis", closerTimeout, func(context.Context) error { return a.redis.Close() })
+	}
+
+	a.cors = middleware.NewCORS(cfg.CORS)
+	if cfg.RateLimit.Enabled {
+		var store middleware.LimitStore // nil → in memory
+		if a.redis != nil {
+			store = redisstore.NewLimitStore(a.redis, cfg.Redis.KeyPrefix, cfg.RateLimit)
+		}
+		a.limiter = middleware.NewRateLimiter(cfg.RateLimit, store)
+	}
+
+	if cfg.Log.AccessLogPath != "" {
+		a.accessLog = logging.NewFile(cfg.Log.AccessLogPath, cfg.Log.AccessLogMaxSize)
+	}
+
+	if cfg.Cache.Enabled {
+		var store cache.Store = cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL)
+		if a.redis != nil {
+			store = redisstore.NewCache(a.redis, cfg.Redis.KeyPrefix, cfg.Cache.TTL)
+		}
+		storage = cache.Storage(storage, store)
+	}
+
+	publishers := []events.Publisher{a.events}
+	if cfg.Webhooks.Enabled() {
+		a.webhooks = webhooks.New(cfg.Webhooks)
+		publishers = append(publishers, a.webhooks)
+	}
+	a.storage = events.NotifyStorage(storage, publishers...)
+
+	return a
+}
+
+/*
+Addr()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the address the API listener is bound to, e.g.
+	    "[::]:39907" for addr ":0".
+	  → Blocks until Run has bound the listener; returns "" when
+	    Run failed before that.
+*/
+func (a *App) Addr() string {
+	<-a.bound
+	return a.addr
+}
+
+/*
+ForceShutdown()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Ends a graceful shutdown in progress right away: the
+	    shutdown_delay and the wait for in-flight requests are cut
+	    short, and their connections closed (server.Close). Run
+	    then returns ErrForcedShutdown.
+	  → main calls it on a second SIGINT / SIGTERM, for a request
+	    stuck longer than the operator wants to wait.
+	  → Called before the shutdown started, it takes effect as
+	    soon as the shutdown does. Safe to call more than once.
+*/
+func (a *App) ForceShutdown() {
+	a.force()
+}
+
+/*
+ReopenAccessLog()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reopens log.access_log_path, after logrotate moved the
+	    file away (main calls it on SIGHUP). Lines go to the new
+	    file from then on.
+	  → No-op without an access log; fails before Run opened it or
+	    after Run returned.
+*/
+func (a *App) ReopenAccessLog() error {
+	if a.accessLog == nil {
+		return nil
+	}
+
+	return a.accessLog.Open()
+}
+
+/*
+Run()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Starts the API server (and the side listeners), serves
+	    until ctx is cancelled, then shuts down gracefully.
+
+	RETURN VALUE:
+	  → nil after a clean shutdown triggered by ctx
+	  → error when a server could not start or stopped on its own
+	    (the graceful shutdown still runs in that case)
+	  → ErrForcedShutdown (possibly joined with the above) when
+	    ForceShutdown cut the shutdown short
+
+	CLEANUP:
+	  → Either way the cleanup steps run before it returns (see
+	    runClosers), in this order: background jobs, webhook
+	    queue, access log, Redis, storage.
+	  → Event streams are ended earlier, as soon as the shutdown
+	    starts: Shutdown would wait for them otherwise.
+*/
+func (a *App) Run(ctx context.Context) error {
+	cfg := a.currentConfig()
+	tlsCfg := cfg.HTTPServer.TLS
+
+	// startFailed ends a Run that never served: what New set up is still
+	// cleaned up.
+	startFailed := func(err error) error {
+		close(a.bound)
+		a.runClosers(cfg.HTTPServer.CleanupTimeout)
+		return err
+	}
+
+	// The access log is opened first: the handler built below writes to it,
+	// and a path that can't be written must stop the startup.
+	if a.accessLog != nil {
+		if err := a.accessLog.Open(); err != nil {
+			return startFailed(fmt.Errorf("cannot open access log (log.access_log_path=%s): %w", a.accessLog.Path(), err))
+		}
+		a.onClose("access log", closerTimeout, func(context.Context) error { return a.accessLog.Close() })
+	}
+
+	//---------------------------------------------------------------------------
+	// STEP 1 → Create HTTP Server instance
+	//
+	// http.Server struct holds:
+	//   Addr    → Address where server listens (like ":8080")
+	//   Handler → Router (wrapped in middleware) handling all requests
+	//   *Timeout → limits on how long a client may take (slow-loris protection)
+	//
+	// Event streams never go idle, so Shutdown would wait on them until its
+	// deadline: RegisterOnShutdown ends them as soon as Shutdown starts.
+	//---------------------------------------------------------------------------
+	server := &http.Server{
+		Addr:              cfg.HTTPServer.Addr,
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: cfg.HTTPServer.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPServer.ReadTimeout,
+		WriteTimeout:      cfg.HTTPServer.WriteTimeout,
+		IdleTimeout:       cfg.HTTPServer.IdleTimeout,
+	}
+	server.RegisterOnShutdown(a.events.Close)
+
+	slog.Info("http server timeouts",
+		slog.Duration("read_header_timeout", server.ReadHeaderTimeout),
+		slog.Duration("read_timeout", server.ReadTimeout),
+		slog.Duration("write_timeout", server.WriteTimeout),
+		slog.Duration("idle_timeout", server.IdleTimeout),
+	)
+
+	//---------------------------------------------------------------------------
+	// STEP 2 → TLS and side listeners
+	//
+	// TLS: load the certificate NOW so a bad path fails at startup, not on
+	// the first handshake. MinVersion rejects the broken TLS 1.0 / 1.1.
+	//
+	// Side servers are extra plain-HTTP listeners started and stopped
+	// together with server (pprof, metrics, http→https redirect).
+	//---------------------------------------------------------------------------
+	var sideServers []sideServer
+
+	// pprof lives only on its own internal listener, never on the API port
+	if cfg.Debug.PprofEnabled {
+		if cfg.Env == "production" {
+			slog.Warn("!!! pprof is ENABLED in production: profiling endpoints expose internals, keep debug.addr private !!!",
+				slog.String("addr", cfg.Debug.Addr),
+			)
+		}
+
+		sideServers = append(sideServers, a.newSideServer("pprof", cfg.Debug.Addr, pprofHandler()))
+	}
+
+	if cfg.Metrics.Addr != "" {
+		sideServers = append(sideServers, a.newSideServer("metrics", cfg.Metrics.Addr, metrics.Handler()))
+	}
+
+	if tlsCfg.Enabled {
+		cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return startFailed(fmt.Errorf("cannot load TLS certificate (cert_file=%s, key_file=%s): %w",
+				tlsCfg.CertFile, tlsCfg.KeyFile, err))
+		}
+
+		server.TLSConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+
+		if tlsCfg.RedirectHTTP {
+			sideServers = append(sideServers,
+				a.newSideServer("https redirect", tlsCfg.RedirectAddr, redirectToHTTPS(cfg.HTTPServer.Addr)))
+		}
+	}
+
+	//---------------------------------------------------------------------------
+	// STEP 3 → Bind the ports before serving
+	//
+	// ListenAndServe binds AND serves, so "port already in use" would only
+	// show up inside the goroutine while we wait forever on a dead server.
+	// Binding here turns that into an immediate, clear error.
+	//---------------------------------------------------------------------------
+	listener, err := net.Listen("tcp", cfg.HTTPServer.Addr)
+	if err != nil {
+		return startFailed(fmt.Errorf("cannot start server: %w", err))
+	}
+
+	sideListeners := make([]net.Listener, len(sideServers))
+	for i, side := range sideServers {
+		sideListeners[i], err = net.Listen("tcp", side.server.Addr)
+		if err != nil {
+			listener.Close()
+			for _, l := range sideListeners[:i] {
+				l.Close()
+			}
+			return startFailed(fmt.Errorf("cannot start %s server: %w", side.name, err))
+		}
+	}
+
+	// listener.Addr is the address actually bound: with addr ":0" the
+	// kernel picks a free port and this is the only place it shows up.
+	a.addr = listener.Addr().String()
+	close(a.bound)
+
+	//---------------------------------------------------------------------------
+	// STEP 4 → Serve in separate goroutines
+	//
+	// serveErr:
+	//   A server that stops for any reason other than Shutdown reports here,
+	//   which ends the wait in STEP 5 just like a cancelled ctx does.
+	//---------------------------------------------------------------------------
+	serveErr := make(chan error, 1+len(sideServers))
+
+	go func() {
+		build := buildinfo.Get()
+		slog.Info("server started",
+			slog.String("addr", a.addr),
+			slog.Bool("tls", tlsCfg.Enabled),
+			slog.String("version", build.Version),
+			slog.String("commit", build.Commit),
+			slog.String("build_time", build.BuildTime),
+		)
+
+		// With TLS the certificate is already in server.TLSConfig, so the
+		// file arguments stay empty.
+		var err error
+		if tlsCfg.Enabled {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+
+		// http.ErrServerClosed → normal shutdown, anything else is a failure
+		if err != nil && err != http.ErrServerClosed {
+			serveErr <- fmt.Errorf("server: %w", err)
+		}
+	}()
+
+	for i, side := range sideServers {
+		go func() {
+			slog.Info(side.name+" server started", slog.String("addr", sideListeners[i].Addr().String()))
+
+			if err := side.server.Serve(sideListeners[i]); err != nil && err != http.ErrServerClosed {
+				serveErr <- fmt.Errorf("%s server: %w", side.name, err)
+			}
+		}()
+	}
+
+	// Webhooks queued by the last requests are delivered after the drain,
+	// within closerTimeout.
+	if a.webhooks != nil {
+		a.webhooks.Start()
+		a.onClose("webhook queue", closerTimeout, a.webhooks.Close)
+		slog.Info("webhooks enabled", slog.Int("urls", len(cfg.Webhooks.URLs)), slog.Int("workers", cfg.Webhooks.Workers))
+	}
+
+	// Background jobs stop with ctx; the cleanup waits for the one running,
+	// so the storage isn't closed under it.
+	a.jobs.Go(func() { a.purgeIdempotencyKeys(ctx, cfg.API.IdempotencyTTL) })
+
+	if cfg.Purge.Enabled {
+		a.jobs.Go(func() { a.purgeDeletedStudents(ctx, cfg.Purge) })
+		slog.Info("purge of soft-deleted students enabled",
+			slog.Duration("interval", cfg.Purge.Interval),
+			slog.Duration("retention", cfg.Purge.Retention),
+		)
+	}
+	a.onClose("background jobs", closerTimeout, func(context.Context) error {
+		a.jobs.Wait()
+		return nil
+	})
+
+	// Redis being down is survivable (cache misses, no rate limiting), so
+	// it is only reported.
+	if a.redis != nil {
+		if err := a.redis.Ping(ctx).Err(); err != nil {
+			slog.Warn("redis unreachable, cache and rate limits degrade until it is back",
+				slog.String("addr", cfg.Redis.Addr), slog.String("error", err.Error()))
+		} else {
+			slog.Info("redis connected", slog.String("addr", cfg.Redis.Addr))
+		}
+	}
+
+	//---------------------------------------------------------------------------
+	// STEP 5 → Wait for ctx to be cancelled (or a server to fail)
+	//---------------------------------------------------------------------------
+	var runErr error
+
+	select {
+	case <-ctx.Done():
+	case runErr = <-serveErr:
+	}
+
+	//---------------------------------------------------------------------------
+	// STEP 6 → Stop reporting ready
+	//
+	// Fail readiness first and keep serving for shutdown_delay, so the load
+	// balancer stops sending new requests before we stop accepting them.
+	//---------------------------------------------------------------------------
+	slog.Info("shutting down the server")
+
+	a.shuttingDown.Store(true)
+
+	if delay := cfg.HTTPServer.ShutdownDelay; delay > 0 {
+		slog.Info("draining: readiness now reports 503", slog.Duration("shutdown_delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-a.forced.Done():
+		}
+	}
+
+	//---------------------------------------------------------------------------
+	// STEP 7 → Gracefully shut down the servers
+	//
+	// server.Shutdown(shutdownCtx):
+	//   ✔ stops accepting new requests
+	//   ✔ finishes ongoing requests
+	//   ✔ closes idle connections
+	//   ✔ respects http_server.shutdown_timeout
+	//
+	// ctx is already cancelled here, so the deadline hangs off a fresh
+	// context. If it is hit, some requests are still running and
+	// server.Close() force-closes their connections.
+	//
+	// ForceShutdown (a second signal) cancels shutdownCtx early, with the
+	// same outcome as the deadline but without waiting for it.
+	//---------------------------------------------------------------------------
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+	stopForce := context.AfterFunc(a.forced, cancel)
+	defer stopForce()
+
+	for _, side := range sideServers {
+		if side.server.Shutdown(shutdownCtx) != nil {
+			side.server.Close()
+		}
+	}
+
+	drainStart := time.Now()
+	err = server.Shutdown(shutdownCtx)
+	drained := time.Since(drainStart)
+
+	forced := a.forced.Err() != nil
+
+	if forced {
+		slog.Warn("forced shutdown: closing remaining connections now", slog.Duration("drain_duration", drained))
+
+		if err := server.Close(); err != nil {
+			slog.Error("Failed to close server", slog.String("error", err.Error()))
+		}
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("shutdown timeout hit, forcing remaining connections closed",
+			slog.Duration("drain_duration", drained),
+			slog.Duration("shutdown_timeout", cfg.HTTPServer.ShutdownTimeout),
+		)
+
+		if err := server.Close(); err != nil {
+			slog.Error("Failed to close server", slog.String("error", err.Error()))
+		}
+	} else if err != nil {
+		slog.Error("Failed to shutdown server", slog.String("error", err.Error()))
+	} else {
+		slog.Info("in-flight requests drained", slog.Duration("drain_duration", drained))
+	}
+
+	//---------------------------------------------------------------------------
+	// STEP 8 → Clean up what the requests used (see onClose)
+	//
+	// Only now: the last requests may have used every one of them. The
+	// cleanup has its own deadline, http_server.cleanup_timeout.
+	//---------------------------------------------------------------------------
+	a.runClosers(cfg.HTTPServer.CleanupTimeout)
+
+	// also when forced during the cleanup
+	if a.forced.Err() != nil {
+		slog.Warn("server shut down forcefully")
+		return errors.Join(runErr, ErrForcedShutdown)
+	}
+
+	slog.Info("server shutdown successfully")
+
+	return runErr
+}
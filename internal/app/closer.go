@@ -0,0 +1,95 @@
+package app
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → each step gets its own deadline
+   - log/slog → one line per step: done, failed or abandoned
+   - time     → step timeouts, how long a step took
+*/
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// closerTimeout is how long one cleanup step may take.
+const closerTimeout = 5 * time.Second
+
+/*
+closer STRUCT
+-------------------------------------------------------------
+  - One cleanup step of the shutdown, registered with onClose.
+  - close gets a context that ends after timeout (or with the
+    whole cleanup, whichever comes first).
+*/
+type closer struct {
+	name    string
+	timeout time.Duration
+	close   func(ctx context.Context) error
+}
+
+// onClose registers a cleanup step for when Run returns. Steps run in
+// reverse order of registration, so register a component before the ones
+// using it: the storage is closed after the jobs writing to it stopped.
+func (a *App) onClose(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	a.closers = append(a.closers, closer{name: name, timeout: timeout, close: fn})
+}
+
+/*
+runClosers()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Runs the cleanup steps, last registered first, once the
+	    servers are shut down (or failed to start).
+	  → The whole cleanup is bounded by timeout (Run passes
+	    http_server.cleanup_timeout), and ForceShutdown cuts it
+	    short too.
+
+	RULES:
+	  → A step that fails is logged and the next ones still run.
+	  → A step still running after its timeout is logged and left
+	    behind in its goroutine: a hanging one can't hold up the
+	    others, nor the exit, past the cleanup deadline.
+	  → Once that deadline has passed, the remaining steps are
+	    skipped (and logged).
+*/
+func (a *App) runClosers(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stopForce := context.AfterFunc(a.forced, cancel)
+	defer stopForce()
+
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		step := a.closers[i]
+		if ctx.Err() != nil {
+			slog.Error("cleanup skipped: no time left", slog.String("step", step.name))
+			continue
+		}
+
+		stepCtx, cancelStep := context.WithTimeout(ctx, step.timeout)
+		start := time.Now()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- step.close(stepCtx)
+		}()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				slog.Error("cleanup failed", slog.String("step", step.name), slog.String("error", err.Error()))
+			} else {
+				slog.Info("cleaned up", slog.String("step", step.name), slog.Duration("duration", time.Since(start)))
+			}
+		case <-stepCtx.Done():
+			slog.Error("cleanup abandoned: step did not finish in time",
+				slog.String("step", step.name),
+				slog.Duration("timeout", step.timeout),
+			)
+		}
+		cancelStep()
+	}
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newClosingApp is the part of an App runClosers uses.
+func newClosingApp() *App {
+	a := &App{}
+	a.forced, a.force = context.WithCancel(context.Background())
+
+	return a
+}
+
+// recorder is a set of fake cleanup steps noting the order they ran in.
+type recorder struct {
+	mu  sync.Mutex
+	ran []string
+}
+
+func (r *recorder) step(name string, err error) func(context.Context) error {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.ran = append(r.ran, name)
+
+		return err
+	}
+}
+
+func (r *recorder) order() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.ran)
+}
+
+// hang is a step that only returns when the test ends.
+func hang(t *testing.T) func(context.Context) error {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	return func(context.Context) error {
+		<-release
+		return nil
+	}
+}
+
+func TestRunClosersOrder(t *testing.T) {
+	a := newClosingApp()
+	var r recorder
+	a.onClose("storage", time.Second, r.step("storage", nil))
+	a.onClose("webhook queue", time.Second, r.step("webhook queue", errors.New("queue still full")))
+	a.onClose("background jobs", time.Second, r.step("background jobs", nil))
+
+	a.runClosers(time.Second)
+
+	// last registered first; a failing step doesn't stop the next ones
+	want := []string{"background jobs", "webhook queue", "storage"}
+	if got := r.order(); !slices.Equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+}
+
+func TestRunClosersStepTimeout(t *testing.T) {
+	a := newClosingApp()
+	var r recorder
+	a.onClose("storage", time.Second, r.step("storage", nil))
+	a.onClose("webhook queue", 20*time.Millisecond, hang(t))
+
+	start := time.Now()
+	a.runClosers(5 * time.Second)
+
+	// the hanging step is abandoned after its own timeout, the next one runs
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("runClosers took %s, want about the 20ms step timeout", took)
+	}
+	if got := r.order(); !slices.Equal(got, []string{"storage"}) {
+		t.Errorf("ran %v, want [storage]", got)
+	}
+}
+
+func TestRunClosersGlobalTimeout(t *testing.T) {
+	a := newClosingApp()
+	var r recorder
+	a.onClose("storage", time.Hour, r.step("storage", nil))
+	a.onClose("webhook queue", time.Hour, hang(t))
+
+	start := time.Now()
+	a.runClosers(50 * time.Millisecond)
+
+	// a step that would wait longer than the whole cleanup may gets cut at
+	// the cleanup deadline, and the steps after it are skipped
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("runClosers took %s, want about the 50ms cleanup timeout", took)
+	}
+	if got := r.order(); len(got) != 0 {
+		t.Errorf("ran %v after the deadline, want nothing", got)
+	}
+}
+
+func TestRunClosersForced(t *testing.T) {
+	a := newClosingApp()
+	a.onClose("webhook queue", time.Hour, hang(t))
+
+	done := make(chan struct{})
+	go func() {
+		a.runClosers(time.Hour)
+		close(done)
+	}()
+	a.ForceShutdown()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runClosers still running after ForceShutdown")
+	}
+}
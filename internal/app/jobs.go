@@ -0,0 +1,100 @@
+package app
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → jobs stop when Run's context is cancelled
+   - errors   → a purge stopped by that cancellation is no failure
+   - log/slog → one log line per run that removed something
+   - time     → ticker interval, expiry cut-off
+   - config   → the "purge" config section
+   - storage  → PurgeInBatches
+*/
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+)
+
+// maxPurgeInterval caps how long expired idempotency keys linger.
+const maxPurgeInterval = time.Hour
+
+/*
+purgeIdempotencyKeys()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Deletes expired Idempotency-Key records so the table does
+	    not grow forever. Reserve already ignores expired records,
+	    so this only reclaims space.
+	  → Runs every ttl (api.idempotency_ttl, at most every hour)
+	    until ctx is cancelled; failures are logged and retried
+	    next tick.
+*/
+func (a *App) purgeIdempotencyKeys(ctx context.Context, ttl time.Duration) {
+	interval := min(ttl, maxPurgeInterval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		purged, err := a.storage.PurgeExpiredIdempotencyKeys(ctx, time.Now().UTC())
+		if err != nil {
+			slog.Error("purging expired idempotency keys failed", slog.String("error", err.Error()))
+			continue
+		}
+		if purged > 0 {
+			slog.Info("purged expired idempotency keys", slog.Int64("count", purged))
+		}
+	}
+}
+
+/*
+purgeDeletedStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Permanently removes the students soft-deleted longer ago
+	    than purge.retention, so they don't pile up forever.
+	    Until then they can still be restored.
+	  → Runs every purge.interval until ctx is cancelled, only
+	    with purge.enabled (see Run); POST /api/admin/purge does
+	    the same on demand.
+	  → Deletes purge.batch_size students per statement, and
+	    checks ctx between batches, so a shutdown stops it
+	    between two of them. Failures are logged and retried
+	    next tick.
+*/
+func (a *App) purgeDeletedStudents(ctx context.Context, cfg config.Purge) {
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		before := time.Now().UTC().Add(-cfg.Retention)
+		purged, err := storage.PurgeInBatches(ctx, a.storage, before, cfg.BatchSize)
+		if purged > 0 {
+			slog.Info("purged soft-deleted students", slog.Int64("count", purged), slog.Time("deleted_before", before))
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("purging soft-deleted students failed", slog.Int64("purged", purged), slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+)
+
+// purgeCounter reports how many keys each purge of the job removed.
+type purgeCounter struct {
+	storage.Storage
+	purged chan int64
+}
+
+func (p *purgeCounter) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	n, err := p.Storage.PurgeExpiredIdempotencyKeys(ctx, now)
+	p.purged <- n
+
+	return n, err
+}
+
+func TestPurgeIdempotencyKeys(t *testing.T) {
+	const ttl = 20 * time.Millisecond
+	store := &purgeCounter{Storage: memory.New(&config.Config{}), purged: make(chan int64, 100)}
+	a := &App{storage: store}
+
+	ctx := context.Background()
+	for key, expires := range map[string]time.Time{
+		"k1":   time.Now().Add(ttl),
+		"k2":   time.Now().Add(ttl),
+		"live": time.Now().Add(time.Hour),
+	} {
+		if _, ok, err := store.ReserveIdempotencyKey(ctx, key, "hash", expires); !ok || err != nil {
+			t.Fatalf("reserving %s = %v, %v", key, ok, err)
+		}
+	}
+
+	jobCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		a.purgeIdempotencyKeys(jobCtx, ttl)
+		close(done)
+	}()
+
+	// the job ticks every ttl; by the first tick k1 and k2 have expired
+	select {
+	case n := <-store.purged:
+		if n != 2 {
+			t.Errorf("first purge removed %d keys, want 2", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job never purged")
+	}
+
+	stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job did not stop with its context")
+	}
+
+	// the live key is still held
+	if _, ok, err := store.ReserveIdempotencyKey(ctx, "live", "hash", time.Now().Add(time.Hour)); ok || err != nil {
+		t.Errorf("reserving the live key again = %v, %v; want it still held", ok, err)
+	}
+}
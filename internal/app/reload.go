@@ -0,0 +1,116 @@
+package app
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - log/slog → what was applied and what was ignored
+   - reflect  → find the settings that changed
+   - strings  → yaml key names from struct tags
+   - config   → the reloaded configuration
+*/
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+)
+
+/*
+Reload()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Applies the settings of next (a freshly loaded and
+	    validated config, see main's SIGHUP handling) that can
+	    change while serving:
+	      - cors.*
+	      - rate_limit.requests_per_second, burst, trust_proxy
+	  → log.level is applied by main, which owns the logger.
+
+	RULES:
+	  → Every other setting that differs from the running config
+	    is logged as "ignored on reload": listeners, storage,
+	    auth… are only read at startup, a restart applies them.
+	  → rate_limit.enabled too: the limiter is part of the chain
+	    or not, from startup on.
+	  → next becomes the running config, so a setting ignored once
+	    is not reported again by the reloads after it.
+*/
+func (a *App) Reload(next *config.Config) {
+	a.cors.SetConfig(next.CORS)
+	if a.limiter != nil {
+		a.limiter.SetConfig(next.RateLimit)
+	}
+
+	slog.Info("config reloaded",
+		slog.String("log_level", next.Log.Level),
+		slog.Any("cors_allowed_origins", next.CORS.AllowedOrigins),
+		slog.Float64("rate_limit_requests_per_second", next.RateLimit.RequestsPerSecond),
+		slog.Int("rate_limit_burst", next.RateLimit.Burst),
+	)
+
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+
+	for _, setting := range a.ignoredOnReload(a.cfg, next) {
+		slog.Warn("setting changed but ignored on reload, restart to apply it", slog.String("setting", setting))
+	}
+	a.cfg = next
+}
+
+// currentConfig returns the running config, the last one Reload applied.
+func (a *App) currentConfig() *config.Config {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+
+	return a.cfg
+}
+
+// ignoredOnReload lists (as yaml paths, e.g. "http_server.addr") the settings
+// of next that differ from the running config and that Reload can't apply.
+func (a *App) ignoredOnReload(current, next *config.Config) []string {
+	running, reloaded := *current, *next
+
+	// the live settings compare equal by construction
+	for _, cfg := range []*config.Config{&running, &reloaded} {
+		cfg.Log.Level = ""
+		cfg.CORS = config.CORS{}
+		if a.limiter != nil {
+			cfg.RateLimit = config.RateLimit{Enabled: cfg.RateLimit.Enabled}
+		}
+	}
+
+	return diffFields("", reflect.ValueOf(running), reflect.ValueOf(reloaded))
+}
+
+// diffFields walks two values of the same struct type and returns the yaml
+// paths of the leaf fields that differ. Values are never returned: some are
+// secrets.
+func diffFields(prefix string, old, new reflect.Value) []string {
+	var changed []string
+
+	for i := range old.NumField() {
+		field := old.Type().Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			changed = append(changed, diffFields(name, old.Field(i), new.Field(i))...)
+			continue
+		}
+
+		if !reflect.DeepEqual(old.Field(i).Interface(), new.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+
+	return changed
+}
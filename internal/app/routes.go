@@ -0,0 +1,333 @@
+package app
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - log/slog   → warn when auth is disabled, the access logger
+   - net/http   → ServeMux and the middleware chain
+   - strings    → match the event stream paths
+   - buildinfo  → the build reported by /health
+   - admin      → maintenance endpoints (purge)
+   - config     → role names of the protected routes
+   - course     → course CRUD handlers
+   - docs       → OpenAPI document + Swagger UI
+   - health     → /health and /ready probes
+   - login      → token endpoints for the users of the config
+   - student    → student CRUD handlers
+   - middleware → request id, logging, metrics, auth, …
+   - router     → ServeMux wrapper with JSON 404 / 405 answers
+   - metrics    → /metrics handler
+*/
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/admin"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/course"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/docs"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/health"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/login"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/student"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/router"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+)
+
+// API versions. Student routes are registered relative to a version group
+// (see registerV1), so this is the only place the prefixes are spelled out.
+const (
+	apiPrefix = "/api"
+	apiV1     = "v1"
+)
+
+/*
+Handler()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Builds the complete handler tree: every route wrapped in
+	    the full middleware chain, exactly what Run serves.
+	  → Exported so it can be served by httptest.NewServer
+	    without binding the configured address.
+*/
+func (a *App) Handler() http.Handler {
+	cfg := a.currentConfig()
+	storage := a.storage
+
+	//---------------------------------------------------------------------------
+	// STEP 1 → Setup router (HTTP multiplexer)
+	// router.New wraps http.NewServeMux, which maps routes to handler functions.
+	// This router will receive and route all HTTP requests; the ones matching
+	// no route get a JSON 404 (unknown path) or 405 + Allow (wrong method).
+	//---------------------------------------------------------------------------
+	mux := router.New()
+
+	//---------------------------------------------------------------------------
+	// STEP 2 → Register route handlers
+	//
+	// HandleFunc pattern: mux.HandleFunc("METHOD /PATH", handlerFunc)
+	// This requires Go 1.22+ (new HTTP pattern matching)
+	//
+	// Mutating routes (POST/PUT/PATCH/DELETE) are wrapped in requireAdmin so
+	// they need a valid bearer token or X-API-Key (401 otherwise) whose role
+	// is admin (403 otherwise). The student and course GETs go through
+	// requireReader: the same credentials, with the role teacher or admin.
+	// The export, which dumps the whole roster at once, and the audit trail
+	// are admin only, and so is the list with ?include_deleted=, which
+	// surfaces soft-deleted students. With auth disabled (only allowed in
+	// dev) all of them let everything through.
+	//
+	// POST /students honours Idempotency-Key (api.idempotency_ttl); the
+	// middleware sits inside requireAdmin because keys are per caller.
+	//
+	// GET /students/events streams changes (SSE) to dashboards; teacher or
+	// admin like the list it mirrors.
+	//
+	// GET /students/{id}/audit is the change history of a student (who, what,
+	// before / after); admin only like the export. GET /students/audit lists
+	// the summaries of bulk deletes.
+	//
+	// POST /students/{id}/status is the only way to change a status once the
+	// student exists, so the lifecycle transitions can be enforced (409).
+	//
+	// POST / DELETE /students/{id}/tags/{tag} add or remove one tag without
+	// sending the whole student; both are idempotent, and an 11th tag is a
+	// 409. ?tag= (repeatable, all must match) filters the lists.
+	//
+	// DELETE /students (the collection) soft-deletes every student matching
+	// the query filter, which is required; ?dry_run=true only counts them.
+	//
+	// PUT /students (the collection) is create-or-update by email for sync
+	// jobs: 201 or 200 with "result":"created" / "updated".
+	//
+	// Courses live next to the students: CRUD under /courses, writes admin
+	// only, reads teacher or admin. POST /students/{id}/enrollments enrolls a
+	// student (409 when the course is full or the student already in it),
+	// GET lists the student's courses (readable like the student), DELETE
+	// …/enrollments/{course_id} frees the seat. Deleting an enrolled student
+	// is a 409 or removes the enrollments, see storage.on_student_delete.
+	// GET /courses/{id}/students is the other side, the course roster:
+	// readable like GET /students, which shows the same fields.
+	//
+	// PUT /students/{id}/photo uploads a profile photo (JPEG or PNG, at most
+	// photos.max_bytes), as the raw image or a multipart form: it is the one
+	// body that is not JSON, and may be larger than max_body_bytes, so
+	// RequireJSON and MaxBodyBytes skip it (isPhotoUpload). GET serves it,
+	// readable like the student.
+	//
+	// POST /students/check-duplicates tells which entries of a roster already
+	// exist before it is imported. It changes nothing but tells which emails
+	// are registered, so it is admin only too.
+	//
+	// Student and course routes live under /api/v1. The pre-versioning /api/students…
+	// paths serve the same v1 handlers as a deprecated alias (Deprecation +
+	// Link headers) until clients have moved. A v2 would get its own group
+	// and register function, leaving v1 untouched.
+	//---------------------------------------------------------------------------
+	requireAdmin := func(h http.Handler) http.Handler { return h }
+	requireReader := requireAdmin
+	if cfg.Auth.Enabled() {
+		requireAuth := middleware.Auth(cfg.Auth)
+		requireAdmin = func(h http.Handler) http.Handler {
+			return requireAuth(middleware.RequireRole(h, config.RoleAdmin))
+		}
+		requireReader = func(h http.Handler) http.Handler {
+			return requireAuth(middleware.RequireRole(h, config.RoleTeacher, config.RoleAdmin))
+		}
+	} else {
+		slog.Warn("auth is disabled: mutating endpoints are NOT protected")
+	}
+
+	// Probes for load balancers: public, and exempt from rate limiting.
+	// /health = the process is alive, /ready = it can serve traffic
+	// (storage reachable, not shutting down).
+	mux.HandleFunc("GET /health", health.New(a.startedAt, buildinfo.Get()))
+	mux.HandleFunc("GET /ready", health.Ready(storage, &a.shuttingDown))
+
+	// Prometheus metrics: on the API port unless metrics.addr gives them
+	// their own (internal) listener, see Run.
+	if cfg.Metrics.Addr == "" {
+		mux.Handle("GET /metrics", metrics.Handler())
+	}
+
+	// Token endpoints (public, they are how credentials are obtained): the
+	// users of auth.users log in with a password and get an access token for
+	// the routes above plus a refresh token to renew it. Not versioned: they
+	// are about the caller, not the student resources. Failed logins are
+	// counted per client IP on top of the rate limit.
+	if cfg.Auth.LoginEnabled() {
+		mux.HandleFunc("POST "+apiPrefix+"/auth/login", login.New(cfg.Auth, cfg.RateLimit.TrustProxy))
+		mux.HandleFunc("POST "+apiPrefix+"/auth/refresh", login.Refresh(cfg.Auth))
+	} else if len(cfg.Auth.Users) > 0 {
+		slog.Warn("auth.users are set but auth is disabled or jwt_secret is empty: login is NOT served")
+	}
+
+	// Maintenance (admin only): POST /api/admin/purge removes the students
+	// soft-deleted longer ago than purge.retention for good, on demand; the
+	// purge job does the same on a timer when purge.enabled. Not versioned,
+	// like the token endpoints: it is about the server, not a resource.
+	mux.Handle("POST "+apiPrefix+"/admin/purge", requireAdmin(admin.Purge(storage, cfg.Purge)))
+
+	// The API contract (public): the OpenAPI document and a browsable page.
+	mux.HandleFunc("GET "+docs.SpecPath, docs.Spec())
+	mux.HandleFunc("GET "+apiPrefix+"/docs", docs.UI())
+
+	v1Prefix := apiPrefix + "/" + apiV1
+	a.registerV1(mux.Group(v1Prefix, middleware.APIVersion(apiV1)), requireAdmin, requireReader)
+	a.registerV1(mux.Group(apiPrefix, middleware.APIVersion(apiV1), middleware.Deprecated(apiPrefix, v1Prefix)), requireAdmin, requireReader)
+
+	//---------------------------------------------------------------------------
+	// STEP 3 → Wrap the router with middleware
+	//
+	// Middleware runs BEFORE every handler (outermost first):
+	//   RequestID    → X-Request-ID + request-scoped logger in the context
+	//   Logging      → one structured log line per request (to
+	//                  log.access_log_path when set), plus a WARN for
+	//                  slow ones (log.slow_request_threshold, default 1s)
+	//   Metrics      → Prometheus request count / in-flight / latency
+	//   Negotiate    → errors as application/problem+json when Accept prefers it
+	//   Recover      → turns handler panics into a JSON 500
+	//   CORS         → browser cross-origin rules + preflight answers (cors.*)
+	//   RateLimit    → per-client-IP token bucket, 429 when exhausted
+	//                  (rate_limit.*, off unless rate_limit.enabled);
+	//                  buckets in Redis when redis.addr is set
+	//
+	// CORS and RateLimit rules come from a.cors / a.limiter, which Reload
+	// updates in place.
+	//   Concurrency  → caps concurrent writes / reads, 503 + Retry-After
+	//                  after concurrency.max_wait (off unless max_writes or
+	//                  max_reads is set); outside Timeout, so the wait for a
+	//                  slot doesn't eat into the request's deadline
+	//   MaxBodyBytes → caps request bodies of POST/PUT/PATCH/DELETE
+	//                  (http_server.max_body_bytes, default 1MB); photo
+	//                  uploads have their own limit (photos.max_bytes)
+	//   Timeout      → deadline on the request context, which cancels slow
+	//                  storage calls (http_server.request_timeout, default 10s);
+	//                  skipped for event streams, which stay open
+	//   RequireJSON  → 415 unless POST/PUT/PATCH bodies are application/json
+	//                  (photo uploads excepted)
+	//   DebugBodies  → request / response bodies at DEBUG, redacted
+	//                  (log.debug_body_max_bytes, log.redact_fields); never
+	//                  in production nor for /api/auth/…, skipped for
+	//                  event streams
+	//---------------------------------------------------------------------------
+	// The access log file gets JSON lines whatever log.format says: it is
+	// meant for log shippers, not for reading in a terminal.
+	var accessLogger *slog.Logger
+	if a.accessLog != nil {
+		accessLogger = slog.New(slog.NewJSONHandler(a.accessLog, nil))
+	}
+
+	var handler http.Handler = middleware.RecordRoute(mux)
+	handler = unless(isEventStream, middleware.DebugBodies(cfg.Log, cfg.Env), handler)
+	handler = unless(isPhotoUpload, middleware.RequireJSON, handler)
+	handler = unless(isEventStream, middleware.Timeout(cfg.HTTPServer.RequestTimeout), handler)
+	handler = unless(isPhotoUpload, middleware.MaxBodyBytes(cfg.HTTPServer.MaxBodyBytes), handler)
+	handler = unless(isEventStream, middleware.ConcurrencyLimit(cfg.Concurrency), handler)
+	if a.limiter != nil {
+		handler = a.limiter.Middleware(handler)
+	}
+	handler = a.cors.Middleware(handler)
+	handler = middleware.Recover(handler)
+	handler = middleware.Negotiate(handler)
+	handler = middleware.Metrics(handler)
+	handler = middleware.Logging(cfg.Log.SlowRequestThreshold, accessLogger)(handler)
+	handler = middleware.RequestIDMiddleware(handler)
+
+	return handler
+}
+
+// registerV1 registers the v1 student and course routes on g (paths relative to the
+// version prefix). requireAdmin / requireReader authenticate and check the
+// role (admin / teacher or admin).
+func (a *App) registerV1(g *router.Group, requireAdmin, requireReader func(http.Handler) http.Handler) {
+	cfg := a.currentConfig()
+	storage := a.storage
+
+	g.Handle("POST /students", requireAdmin(middleware.Idempotency(storage, cfg.API.IdempotencyTTL)(student.New(storage, cfg.API.PhoneCountry))))
+	g.Handle("PUT /students", requireAdmin(student.Upsert(storage, cfg.API.PhoneCountry)))
+	g.Handle("POST /students/bulk", requireAdmin(student.Bulk(storage, cfg.API.PhoneCountry)))
+	g.Handle("POST /students/check-duplicates", requireAdmin(student.CheckDuplicates(storage)))
+	g.Handle("GET /students", authIf(includesDeleted, requireAdmin, requireReader, student.GetList(storage)))
+	g.Handle("GET /students/search", requireReader(student.Search(storage)))
+	g.Handle("GET /students/events", requireReader(student.Events(a.events, cfg.API.EventHeartbeat)))
+	g.Handle("GET /students/stats", requireReader(student.Stats(storage, cfg.API.StatsCacheTTL)))
+	g.Handle("GET /students/export", requireAdmin(student.Export(storage)))
+	g.Handle("GET /students/{id}", requireReader(student.GetById(storage)))
+	g.Handle("PUT /students/{id}", requireAdmin(student.Update(storage, cfg.API.RequireIfMatch, cfg.API.PhoneCountry)))
+	g.Handle("PATCH /students/{id}", requireAdmin(student.Patch(storage, cfg.API.RequireIfMatch, cfg.API.PhoneCountry)))
+	g.Handle("DELETE /students", requireAdmin(student.DeleteMatching(storage)))
+	g.Handle("GET /students/audit", requireAdmin(student.Audit(storage)))
+	g.Handle("DELETE /students/{id}", requireAdmin(student.Delete(storage)))
+	g.Handle("POST /students/{id}/restore", requireAdmin(student.Restore(storage)))
+	g.Handle("POST /students/{id}/status", requireAdmin(student.SetStatus(storage)))
+	g.Handle("POST /students/{id}/tags/{tag}", requireAdmin(student.AddTag(storage)))
+	g.Handle("DELETE /students/{id}/tags/{tag}", requireAdmin(student.RemoveTag(storage)))
+	g.Handle("GET /students/{id}/audit", requireAdmin(student.Audit(storage)))
+	g.Handle("POST /students/{id}/enrollments", requireAdmin(student.Enroll(storage)))
+	g.Handle("GET /students/{id}/enrollments", requireReader(student.Enrollments(storage)))
+	g.Handle("DELETE /students/{id}/enrollments/{course_id}", requireAdmin(student.Unenroll(storage)))
+	g.Handle("PUT /students/{id}/photo", requireAdmin(student.PutPhoto(storage, cfg.Photos.MaxBytes)))
+	g.Handle("GET /students/{id}/photo", requireReader(student.Photo(storage)))
+
+	g.Handle("POST /courses", requireAdmin(course.New(storage)))
+	g.Handle("GET /courses", requireReader(course.GetList(storage)))
+	g.Handle("GET /courses/{id}", requireReader(course.GetById(storage)))
+	g.Handle("GET /courses/{id}/students", requireReader(course.Students(storage)))
+	g.Handle("PUT /courses/{id}", requireAdmin(course.Update(storage)))
+	g.Handle("DELETE /courses/{id}", requireAdmin(course.Delete(storage)))
+}
+
+// authIf sends the requests matching cond through guard and the others
+// through otherwise, for routes where some variants need more than the
+// route itself (admin for a teacher-readable list…).
+func authIf(cond func(*http.Request) bool, guard, otherwise func(http.Handler) http.Handler, next http.Handler) http.Handler {
+	guarded := guard(next)
+	wrapped := otherwise(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cond(r) {
+			guarded.ServeHTTP(w, r)
+			return
+		}
+
+		wrapped.ServeHTTP(w, r)
+	})
+}
+
+// unless serves the requests matching cond directly and sends the others
+// through mw.
+func unless(cond func(*http.Request) bool, mw func(http.Handler) http.Handler, next http.Handler) http.Handler {
+	wrapped := mw(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cond(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		wrapped.ServeHTTP(w, r)
+	})
+}
+
+// isEventStream matches the SSE routes (v1 and the deprecated alias).
+func isEventStream(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/students/events")
+}
+
+// isPhotoUpload matches PUT /students/{id}/photo (v1 and the deprecated
+// alias), whose body is an image.
+func isPhotoUpload(r *http.Request) bool {
+	return r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/photo") && strings.Contains(r.URL.Path, "/students/")
+}
+
+// includesDeleted matches list requests that ask for soft-deleted students.
+// Any value counts (even false), so a typo can't bypass the admin check; the
+// handler validates the value itself.
+func includesDeleted(r *http.Request) bool {
+	return r.URL.Query().Has("include_deleted")
+}
@@ -0,0 +1,90 @@
+package app_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+)
+
+// TestRouteRoles sends every caller to every route with auth on: nobody
+// gets in without credentials, a teacher reads students and courses but
+// changes nothing (and sees neither the export, the audit trail nor the
+// soft-deleted students), an admin does everything.
+func TestRouteRoles(t *testing.T) {
+	const (
+		students = "/api/v1/students"
+		courses  = "/api/v1/courses"
+	)
+	student := map[string]any{"name": "Bob Lee", "email": "bob@example.com", "age": 30}
+	course := map[string]any{"code": "CS102", "title": "Data Structures", "capacity": 10}
+
+	tests := []struct {
+		method  string
+		path    string
+		body    any
+		teacher int // status a teacher gets; admin gets the 2xx in admin
+		admin   int
+	}{
+		// reads
+		{http.MethodGet, students, nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, students + "/1", nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, students + "/search?q=ann", nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, students + "/stats", nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, students + "/1/enrollments", nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, courses, nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, courses + "/1", nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, courses + "/1/students", nil, http.StatusOK, http.StatusOK},
+		{http.MethodGet, students + "?include_deleted=true", nil, http.StatusForbidden, http.StatusOK},
+		{http.MethodGet, students + "?include_deleted=false", nil, http.StatusForbidden, http.StatusOK},
+		{http.MethodGet, students + "/export", nil, http.StatusForbidden, http.StatusOK},
+		{http.MethodGet, students + "/audit", nil, http.StatusForbidden, http.StatusOK},
+		{http.MethodGet, students + "/1/audit", nil, http.StatusForbidden, http.StatusOK},
+
+		// writes
+		{http.MethodPost, students, student, http.StatusForbidden, http.StatusCreated},
+		{http.MethodPatch, students + "/1", map[string]any{"age": 21}, http.StatusForbidden, http.StatusOK},
+		{http.MethodPost, students + "/1/tags/honours", nil, http.StatusForbidden, http.StatusOK},
+		{http.MethodPost, students + "/1/enrollments", map[string]any{"course_id": 1}, http.StatusForbidden, http.StatusCreated},
+		{http.MethodDelete, students + "/1", nil, http.StatusForbidden, http.StatusNoContent},
+		{http.MethodPost, courses, course, http.StatusForbidden, http.StatusCreated},
+		{http.MethodDelete, courses + "/1", nil, http.StatusForbidden, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			// a fresh server per route, so an admin write cannot change
+			// what the next route finds
+			srv := apptest.Server(t, apptest.WithAuth(apptest.Config(t)))
+			admin := []string{"X-API-Key", apptest.AdminKey}
+			teacher := []string{"X-API-Key", apptest.TeacherKey}
+
+			seed := apptest.Do(t, srv, http.MethodPost, students,
+				map[string]any{"name": "Ann Lee", "email": "ann@example.com", "age": 20}, admin...)
+			if seed.Status != http.StatusCreated {
+				t.Fatalf("seeding the student: status %d, body %s", seed.Status, seed.Body)
+			}
+			seed = apptest.Do(t, srv, http.MethodPost, courses,
+				map[string]any{"code": "CS101", "title": "Algorithms", "capacity": 10}, admin...)
+			if seed.Status != http.StatusCreated {
+				t.Fatalf("seeding the course: status %d, body %s", seed.Status, seed.Body)
+			}
+
+			// admin last: it is the one whose write goes through
+			for _, caller := range []struct {
+				name   string
+				header []string
+				want   int
+			}{
+				{"anonymous", nil, http.StatusUnauthorized},
+				{"teacher", teacher, tt.teacher},
+				{"admin", admin, tt.admin},
+			} {
+				res := apptest.Do(t, srv, tt.method, tt.path, tt.body, caller.header...)
+				if res.Status != caller.want {
+					t.Errorf("%s: status %d, want %d (body %s)", caller.name, res.Status, caller.want, res.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package app_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/app"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+)
+
+// loopback is addr (from App.Addr, "[::]:39907") with a host to dial.
+func loopback(t *testing.T, addr string) string {
+	t.Helper()
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil || port == "" || port == "0" {
+		t.Fatalf("Addr() = %q, want a bound host:port", addr)
+	}
+
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+// TestRunOnFreePort checks Run on addr ":0": Addr reports the port the
+// kernel picked, the API answers there, and cancelling ctx shuts it down
+// cleanly.
+func TestRunOnFreePort(t *testing.T) {
+	cfg := apptest.Config(t)
+	a := app.New(cfg, memory.New(cfg))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+
+	base := "http://" + loopback(t, a.Addr())
+	res, err := http.Get(base + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("GET /health: status %d, want 200", res.StatusCode)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run: %v, want nil after the shutdown", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run still running after ctx was cancelled")
+	}
+
+	if res, err := http.Get(base + "/health"); err == nil {
+		res.Body.Close()
+		t.Error("the API still answers after Run returned")
+	}
+}
+
+// TestRunAddrInUse checks a port that can't be bound fails Run at once,
+// with Addr then reporting nothing instead of blocking.
+func TestRunAddrInUse(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	cfg := apptest.Config(t)
+	cfg.HTTPServer.Addr = taken.Addr().String()
+	a := app.New(cfg, memory.New(cfg))
+
+	done := make(chan error, 1)
+	go func() { done <- a.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "address already in use") {
+			t.Errorf("Run: %v, want address already in use", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run is serving on a taken port")
+	}
+	if addr := a.Addr(); addr != "" {
+		t.Errorf("Addr() = %q after a failed Run, want \"\"", addr)
+	}
+}
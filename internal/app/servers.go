@@ -0,0 +1,84 @@
+package app
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net            → split host:port when building redirect URLs
+   - net/http       → side servers and their handlers
+   - net/http/pprof → profiling endpoints for the debug listener
+*/
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+)
+
+// sideServer is an extra plain-HTTP listener (pprof, metrics, https redirect)
+// that runs next to the API server and is shut down with it.
+type sideServer struct {
+	name   string
+	server *http.Server
+}
+
+func (a *App) newSideServer(name, addr string, handler http.Handler) sideServer {
+	return sideServer{
+		name: name,
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: a.currentConfig().HTTPServer.ReadHeaderTimeout,
+		},
+	}
+}
+
+/*
+redirectToHTTPS()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Handler for the plain-HTTP listener: every request gets
+	    "301 Moved Permanently" to the same host and path on the
+	    HTTPS listener.
+	  → The port of httpsAddr is kept unless it is 443.
+*/
+func redirectToHTTPS(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+/*
+pprofHandler()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The net/http/pprof endpoints on a private mux.
+
+	WHY NOT http.DefaultServeMux?
+	  → Importing net/http/pprof registers its handlers there as a
+	    side effect; we never serve that mux, so they are mounted
+	    here explicitly and only reachable through the debug listener.
+*/
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return mux
+}
@@ -0,0 +1,74 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/app"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/sqlite"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// TestSQLiteConcurrentCreates sends 200 creates, 50 at a time, to the API
+// over SQLite with the documented settings (WAL, busy_timeout, max_writes
+// 1): every one succeeds, none is a "database is locked" 500.
+func TestSQLiteConcurrentCreates(t *testing.T) {
+	const (
+		creates = 200
+		clients = 50
+	)
+
+	cfg := apptest.Config(t)
+	cfg.Storage.Driver = config.DriverSQLite
+	cfg.StoragePath = filepath.Join(t.TempDir(), "students.db")
+	cfg.Concurrency.MaxWrites = 1
+
+	store, err := sqlite.New(cfg)
+	if err != nil {
+		t.Fatalf("opening %s: %v", cfg.StoragePath, err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	srv := httptest.NewServer(app.New(cfg, store).Handler())
+	t.Cleanup(srv.Close)
+
+	next := make(chan int)
+	go func() {
+		for i := range creates {
+			next <- i
+		}
+		close(next)
+	}()
+
+	var mu sync.Mutex
+	failures := map[string]int{}
+	var wg sync.WaitGroup
+	for range clients {
+		wg.Go(func() {
+			for i := range next {
+				body := map[string]any{"name": "Ann Lee", "email": fmt.Sprintf("ann%d@example.com", i), "age": 20}
+				res := apptest.Do(t, srv, http.MethodPost, "/api/v1/students", body)
+				if res.Status != http.StatusCreated {
+					mu.Lock()
+					failures[fmt.Sprintf("%d %s", res.Status, strings.TrimSpace(string(res.Body)))]++
+					mu.Unlock()
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		t.Errorf("failed creates: %v", failures)
+	}
+	if n, err := store.CountStudents(t.Context(), types.StudentFilter{}); err != nil || n != creates {
+		t.Errorf("%d students stored, %v; want %d", n, err, creates)
+	}
+}
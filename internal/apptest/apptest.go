@@ -0,0 +1,177 @@
+package apptest // apptest package builds the real API over in-memory storage for tests
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes             → request bodies
+   - encoding/json     → encode request bodies, decode responses
+   - io                → read response bodies
+   - net/http          → build requests
+   - net/http/httptest → serve the handler on a loopback port
+   - os, path/filepath → the YAML file the config is loaded from
+   - testing           → helpers report failures on the caller's t
+   - cleanenv          → same YAML + env-default parsing as config.Load
+
+   - app     → the handler tree Run serves
+   - config  → Config, as the binary would load it
+   - memory  → the storage the tests run against
+*/
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/app"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// baseConfig is the smallest valid config: a dev server on a free port over
+// memory storage, auth off. Every other setting has its env-default.
+const baseConfig = `
+env: dev
+storage:
+  driver: memory
+http_server:
+  addr: ":0"
+`
+
+// API keys set by WithAuth, one per role.
+const (
+	AdminKey   = "test-admin-key"
+	TeacherKey = "test-teacher-key"
+)
+
+/*
+Config()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Loads baseConfig the way config.Load loads a file
+	    (env-default tags applied, then Validate), so tests start
+	    from the defaults the binary has.
+	  → Tests change the fields they need on the result before
+	    passing it to Server.
+*/
+func Config(t testing.TB) *config.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(baseConfig), 0o600); err != nil {
+		t.Fatalf("writing test config: %v", err)
+	}
+
+	var cfg config.Config
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		t.Fatalf("reading test config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("test config is invalid: %v", err)
+	}
+
+	return &cfg
+}
+
+// WithAuth turns auth on with one API key per role: AdminKey and TeacherKey.
+func WithAuth(cfg *config.Config) *config.Config {
+	cfg.Auth.APIKeys = map[string]string{"admin": AdminKey, "teacher": TeacherKey}
+	cfg.Auth.APIKeyRoles = map[string]string{"admin": config.RoleAdmin, "teacher": config.RoleTeacher}
+
+	return cfg
+}
+
+// Handler is the complete handler tree of an App over a fresh memory store.
+func Handler(cfg *config.Config) http.Handler {
+	return app.New(cfg, memory.New(cfg)).Handler()
+}
+
+// Server serves Handler(cfg) until the test ends.
+func Server(t testing.TB, cfg *config.Config) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(Handler(cfg))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+/*
+Response STRUCT
+-------------------------------------------------------------
+  - What Do got back, with the body already read.
+*/
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the body into v, failing the test when it isn't JSON.
+func (r *Response) JSON(t testing.TB, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("response body is not JSON (%v): %s", err, r.Body)
+	}
+}
+
+/*
+Do()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Sends one request to srv and reads the whole answer.
+	  → body is sent as is when it is a string or []byte, JSON
+	    encoded otherwise; nil sends none. A body gets
+	    Content-Type: application/json unless header sets one.
+	  → header is name / value pairs: "If-Match", `W/"1-1"`.
+*/
+func Do(t testing.TB, srv *httptest.Server, method, path string, body any, header ...string) *Response {
+	t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("encoding request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, srv.URL+path, reader)
+	if err != nil {
+		t.Fatalf("building %s %s: %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("reading the answer of %s %s: %v", method, path, err)
+	}
+
+	return &Response{Status: res.StatusCode, Header: res.Header, Body: data}
+}
@@ -0,0 +1,73 @@
+package auth // auth package signs and parses the JWTs the API accepts
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - time   → expiry / issue time of a token
+   - jwt/v5 → HS256 signing and verification
+*/
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// UseRefresh is the "token_use" claim of refresh tokens. Access tokens
+// (and tokens issued by anyone else) don't carry the claim.
+const UseRefresh = "refresh"
+
+/*
+Claims STRUCT
+-------------------------------------------------------------
+  - What the API reads from a token: the registered claims
+    ("sub", "exp", "iat"…) plus the caller's role
+    (config.RoleAdmin / config.RoleTeacher).
+  - Use tells a refresh token (UseRefresh) from an access
+    token: middleware.Auth rejects the former, so a long-lived
+    refresh token can't be used to call the API.
+*/
+type Claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role,omitempty"`
+	Use  string `json:"token_use,omitempty"`
+}
+
+// NewParser returns the parser of every token the API reads: HS256 only
+// (no "none", no algorithm confusion) and "exp" mandatory.
+func NewParser() *jwt.Parser {
+	return jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+}
+
+/*
+Sign()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Issues an HS256 token for subject, valid for ttl from
+	    now. role is left out of refresh tokens (use UseRefresh):
+	    the refresh endpoint looks the current role up instead.
+
+	RETURN VALUE:
+	  → the signed token and when it expires
+*/
+func Sign(secret []byte, subject, role, use string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Role: role,
+		Use:  use,
+	})
+
+	signed, err := token.SignedString(secret)
+	return signed, expiresAt, err
+}
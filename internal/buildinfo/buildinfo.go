@@ -0,0 +1,108 @@
+package buildinfo // buildinfo package tells which build of the server is running
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - fmt           → the --version line
+   - runtime/debug → version and VCS details recorded by the go tool
+*/
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Version, Commit and BuildTime are set by release builds:
+//
+//	go build -ldflags "-X github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo.Version=v1.2.3 \
+//	  -X github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Left empty, Get falls back to what the go tool recorded in the binary.
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+/*
+Info STRUCT
+-------------------------------------------------------------
+  - The build, as reported by --version, /health and the
+    startup log line.
+  - Version is "dev" when nothing better is known; Commit and
+    BuildTime may be empty.
+*/
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+/*
+Get()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the build info: the -ldflags values first, then
+	    for the ones not set, runtime/debug.ReadBuildInfo:
+	      - the module version ("go install …@v1.2.3")
+	      - vcs.revision / vcs.time (builds inside a git checkout),
+	        the commit suffixed "-dirty" with uncommitted changes
+	  → vcs.time is the time of the commit, not of the build: the
+	    closest the go tool records.
+*/
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+
+		var revision, vcsTime string
+		modified := false
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.time":
+				vcsTime = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+
+		if info.Commit == "" && revision != "" {
+			info.Commit = revision
+			if modified {
+				info.Commit += "-dirty"
+			}
+		}
+		if info.BuildTime == "" {
+			info.BuildTime = vcsTime
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+
+	return info
+}
+
+// String formats the info on one line, for --version:
+// "v1.2.3 (commit 1a2b3c…, built 2024-05-01T10:30:00Z)".
+func (i Info) String() string {
+	s := i.Version
+	switch {
+	case i.Commit != "" && i.BuildTime != "":
+		s += fmt.Sprintf(" (commit %s, built %s)", i.Commit, i.BuildTime)
+	case i.Commit != "":
+		s += fmt.Sprintf(" (commit %s)", i.Commit)
+	case i.BuildTime != "":
+		s += fmt.Sprintf(" (built %s)", i.BuildTime)
+	}
+
+	return s
+}
@@ -0,0 +1,397 @@
+package cache_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/cache"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/sqlite"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+func newStudent(name, email string, age int) types.Student {
+	return types.Student{Name: name, Email: email, Age: age, Status: types.StatusActive}
+}
+
+// lookups is the student_cache_lookups_total counter for result ("hit" or
+// "miss"). The registry is the package's, so tests compare two reads.
+func lookups(t *testing.T, result string) float64 {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	series := `student_cache_lookups_total{result="` + result + `"} `
+	lines := bufio.NewScanner(rec.Body)
+	for lines.Scan() {
+		if value, found := strings.CutPrefix(lines.Text(), series); found {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("/metrics line %q: %v", lines.Text(), err)
+			}
+			return n
+		}
+	}
+
+	return 0
+}
+
+// cached returns memory storage holding Ann (ID 1), the LRU and the cached
+// storage in front of both.
+func cached(t *testing.T, size int, ttl time.Duration) (*memory.Memory, cache.Store, storage.Storage) {
+	t.Helper()
+
+	mem := memory.New(&config.Config{})
+	if _, err := mem.CreateStudent(context.Background(), newStudent("Ann Lee", "ann@example.com", 20)); err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+	store := cache.NewLRU(size, ttl)
+
+	return mem, store, cache.Storage(mem, store)
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRU(2, time.Minute)
+	add := func(id int64) {
+		t.Helper()
+		generation, _ := store.Snapshot(ctx, id)
+		if err := store.Add(ctx, types.Student{Id: id, Name: fmt.Sprint("student ", id)}, generation); err != nil {
+			t.Fatalf("Add(%d): %v", id, err)
+		}
+	}
+	has := func(id int64) bool {
+		_, found, err := store.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("Get(%d): %v", id, err)
+		}
+		return found
+	}
+
+	if has(1) {
+		t.Fatal("Get on an empty cache found student 1")
+	}
+
+	// at the size limit, adding evicts the least recently used: 1 was
+	// read after 2 was added, so 2 goes
+	add(1)
+	add(2)
+	if !has(1) {
+		t.Fatal("student 1 was not cached")
+	}
+	add(3)
+	if has(2) {
+		t.Error("student 2, the least recently used, was not evicted")
+	}
+	if !has(1) || !has(3) {
+		t.Error("students 1 and 3 should still be cached")
+	}
+
+	// an invalidation between Snapshot and Add keeps the read out
+	stale, _ := store.Snapshot(ctx, 4)
+	if err := store.Invalidate(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(ctx, types.Student{Id: 4}, stale); err != nil {
+		t.Fatal(err)
+	}
+	if has(4) {
+		t.Error("Add with a stale generation cached the student")
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRU(10, 50*time.Millisecond)
+
+	if err := store.Add(ctx, types.Student{Id: 1}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := store.Get(ctx, 1); !found {
+		t.Fatal("student 1 was not cached")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, found, _ := store.Get(ctx, 1); found {
+		t.Error("Get after the ttl found the student")
+	}
+}
+
+// TestStorageLookups checks the first read is a miss that reaches the
+// storage, the next ones hits served from the cache, and each is counted.
+func TestStorageLookups(t *testing.T) {
+	ctx := context.Background()
+	mem, _, s := cached(t, 10, time.Minute)
+	hits, misses := lookups(t, "hit"), lookups(t, "miss")
+
+	for range 3 {
+		got, err := s.GetStudentById(ctx, 1)
+		if err != nil || got.Name != "Ann Lee" {
+			t.Fatalf("GetStudentById = %+v, %v", got, err)
+		}
+	}
+	if got := lookups(t, "miss") - misses; got != 1 {
+		t.Errorf("%v misses, want 1", got)
+	}
+	if got := lookups(t, "hit") - hits; got != 2 {
+		t.Errorf("%v hits, want 2", got)
+	}
+
+	// a change behind the cache's back is not seen: the reads were hits
+	name := "Ann Smith"
+	if _, err := mem.PatchStudent(ctx, 1, types.StudentPatch{Name: &name}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetStudentById(ctx, 1); got.Name != "Ann Lee" {
+		t.Errorf("name %q, want the cached Ann Lee", got.Name)
+	}
+
+	// misses are not cached
+	if _, err := s.GetStudentById(ctx, 99); err == nil {
+		t.Fatal("GetStudentById(99) found a student")
+	}
+	if got := lookups(t, "miss") - misses; got != 2 {
+		t.Errorf("%v misses after a 404, want 2", got)
+	}
+}
+
+// TestStorageInvalidates runs every write path on a cached student: each one
+// drops it, so the next read sees the write.
+func TestStorageInvalidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(ctx context.Context, mem *memory.Memory) error
+		write func(ctx context.Context, s storage.Storage) error
+	}{
+		{"UpdateStudent", nil, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.UpdateStudent(ctx, 1, newStudent("Ann Smith", "ann@example.com", 21), 1)
+			return err
+		}},
+		{"PatchStudent", nil, func(ctx context.Context, s storage.Storage) error {
+			age := 21
+			_, err := s.PatchStudent(ctx, 1, types.StudentPatch{Age: &age}, 1)
+			return err
+		}},
+		{"SetStudentStatus", nil, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.SetStudentStatus(ctx, 1, types.StatusSuspended)
+			return err
+		}},
+		{"AddStudentTag", nil, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.AddStudentTag(ctx, 1, "honours")
+			return err
+		}},
+		{"RemoveStudentTag", func(ctx context.Context, mem *memory.Memory) error {
+			_, err := mem.AddStudentTag(ctx, 1, "honours")
+			return err
+		}, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.RemoveStudentTag(ctx, 1, "honours")
+			return err
+		}},
+		{"UpsertStudent", nil, func(ctx context.Context, s storage.Storage) error {
+			_, _, err := s.UpsertStudent(ctx, newStudent("Ann Smith", "ann@example.com", 21))
+			return err
+		}},
+		{"DeleteStudent", nil, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.DeleteStudent(ctx, 1)
+			return err
+		}},
+		{"DeleteStudents", nil, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.DeleteStudents(ctx, types.StudentFilter{Email: "ann@example.com"})
+			return err
+		}},
+		{"RestoreStudent", func(ctx context.Context, mem *memory.Memory) error {
+			_, err := mem.DeleteStudent(ctx, 1)
+			return err
+		}, func(ctx context.Context, s storage.Storage) error {
+			_, err := s.RestoreStudent(ctx, 1)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mem, store, s := cached(t, 10, time.Minute)
+			if tt.setup != nil {
+				if err := tt.setup(ctx, mem); err != nil {
+					t.Fatalf("setup: %v", err)
+				}
+			}
+
+			// cache student 1 as it was, even when the setup deleted it
+			if err := store.Add(ctx, types.Student{Id: 1, Name: "Ann Lee"}, 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.write(ctx, s); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if _, found, _ := store.Get(ctx, 1); found {
+				t.Errorf("student 1 is still cached after %s", tt.name)
+			}
+		})
+	}
+
+	// the bulk import drops the IDs it creates
+	t.Run("CreateStudents", func(t *testing.T) {
+		ctx := context.Background()
+		_, store, s := cached(t, 10, time.Minute)
+		for _, id := range []int64{2, 3} {
+			if err := store.Add(ctx, types.Student{Id: id}, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		results, err := s.CreateStudents(ctx, []types.Student{
+			newStudent("Bob Lee", "bob@example.com", 30),
+			newStudent("Cat Lee", "cat@example.com", 40),
+		}, true)
+		if err != nil || len(results) != 2 || results[0].Id != 2 || results[1].Id != 3 {
+			t.Fatalf("CreateStudents = %+v, %v; want IDs 2 and 3", results, err)
+		}
+		for _, id := range []int64{2, 3} {
+			if _, found, _ := store.Get(ctx, id); found {
+				t.Errorf("student %d is still cached after the import", id)
+			}
+		}
+	})
+}
+
+// TestStorageConcurrentInvalidation reads a student from many goroutines
+// while it is updated: once the updates are done the cache serves the last
+// one, never a row a read fetched before an update and cached after its
+// invalidation. Run with -race.
+func TestStorageConcurrentInvalidation(t *testing.T) {
+	const (
+		readers = 8
+		updates = 200
+	)
+	ctx := context.Background()
+	_, _, s := cached(t, 10, time.Minute)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for range readers {
+		wg.Go(func() {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := s.GetStudentById(ctx, 1); err != nil {
+					t.Errorf("GetStudentById: %v", err)
+					return
+				}
+			}
+		})
+	}
+
+	for i := range updates {
+		if _, err := s.UpdateStudent(ctx, 1, newStudent("Ann Lee", "ann@example.com", i%150+1), i+1); err != nil {
+			t.Fatalf("update %d: %v", i+1, err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	got, err := s.GetStudentById(ctx, 1)
+	if err != nil || got.Version != updates+1 || got.Age != (updates-1)%150+1 {
+		t.Errorf("after the updates: version %d, age %d, %v; want version %d, age %d",
+			got.Version, got.Age, err, updates+1, (updates-1)%150+1)
+	}
+}
+
+// TestCacheConfig checks GET /students/{id} goes through the cache only with
+// cache.enabled, and a PATCH through the API is seen on the next read.
+func TestCacheConfig(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprint("enabled=", enabled), func(t *testing.T) {
+			cfg := apptest.Config(t)
+			cfg.Cache.Enabled = enabled
+			srv := apptest.Server(t, cfg)
+			const path = "/api/v1/students/1"
+
+			res := apptest.Do(t, srv, http.MethodPost, "/api/v1/students",
+				map[string]any{"name": "Ann Lee", "email": "ann@example.com", "age": 20})
+			if res.Status != http.StatusCreated {
+				t.Fatalf("create: status %d, body %s", res.Status, res.Body)
+			}
+
+			// the create read the student back, which cached it: with the
+			// cache both GETs are hits
+			hits, misses := lookups(t, "hit"), lookups(t, "miss")
+			for range 2 {
+				if res := apptest.Do(t, srv, http.MethodGet, path, nil); res.Status != http.StatusOK {
+					t.Fatalf("get: status %d, body %s", res.Status, res.Body)
+				}
+			}
+			wantHits, wantMisses := 0.0, 0.0
+			if enabled {
+				wantHits = 2
+			}
+			if got := lookups(t, "hit") - hits; got != wantHits {
+				t.Errorf("%v hits, want %v", got, wantHits)
+			}
+			if got := lookups(t, "miss") - misses; got != wantMisses {
+				t.Errorf("%v misses, want %v", got, wantMisses)
+			}
+
+			if res := apptest.Do(t, srv, http.MethodPatch, path, map[string]any{"age": 21}); res.Status != http.StatusOK {
+				t.Fatalf("patch: status %d, body %s", res.Status, res.Body)
+			}
+			var got types.Student
+			apptest.Do(t, srv, http.MethodGet, path, nil).JSON(t, &got)
+			if got.Age != 21 {
+				t.Errorf("age %d after the patch, want 21", got.Age)
+			}
+		})
+	}
+}
+
+// BenchmarkGetStudentById reads one student from SQLite, straight and
+// through the in-process cache.
+func BenchmarkGetStudentById(b *testing.B) {
+	cfg := apptest.Config(b)
+	cfg.Storage.Driver = config.DriverSQLite
+	cfg.StoragePath = filepath.Join(b.TempDir(), "students.db")
+
+	db, err := sqlite.New(cfg)
+	if err != nil {
+		b.Fatalf("opening %s: %v", cfg.StoragePath, err)
+	}
+	b.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	id, err := db.CreateStudent(ctx, newStudent("Ann Lee", "ann@example.com", 20))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bb := range []struct {
+		name string
+		s    storage.Storage
+	}{
+		{"uncached", db},
+		{"cached", cache.Storage(db, cache.NewLRU(10, time.Minute))},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := bb.s.GetStudentById(ctx, id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
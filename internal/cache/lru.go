@@ -0,0 +1,164 @@
+package cache // cache package keeps hot students in memory in front of storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - container/list → recency order of the LRU
+   - context        → part of the Store signatures
+   - sync           → handlers read and write concurrently
+   - time           → entry expiry
+   - types          → the cached Student values
+*/
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+Store INTERFACE
+-------------------------------------------------------------
+  - Where cached students live: this process (NewLRU, the
+    default) or Redis, shared by every replica (redisstore).
+  - Snapshot / Add implement the race protection described on
+    Add(): it only stores the student when no invalidation of
+    its ID happened since the Snapshot taken before the read.
+  - Errors mean "the cache is unavailable": Storage treats them
+    as misses and logs them, requests never fail because of
+    the cache.
+*/
+type Store interface {
+	Get(ctx context.Context, id int64) (types.Student, bool, error)
+	Snapshot(ctx context.Context, id int64) (uint64, error)
+	Add(ctx context.Context, student types.Student, generation uint64) error
+	Invalidate(ctx context.Context, id int64) error
+}
+
+/*
+lru STRUCT
+-------------------------------------------------------------
+  - The in-process Store.
+  - At most size students, each valid for ttl; adding one more
+    evicts the least recently used.
+  - order holds *entry values, most recently used at the front;
+    items finds an ID's element in O(1).
+  - generation counts invalidations (of any ID), see Add.
+  - mu guards everything: even a lookup reorders the list, so
+    there is no read-only path for an RWMutex to share.
+*/
+type lru struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	order      *list.List
+	items      map[int64]*list.Element
+	generation uint64
+}
+
+type entry struct {
+	id      int64
+	student types.Student
+	expires time.Time
+}
+
+// NewLRU returns an in-process Store of at most size students, each kept
+// for ttl.
+func NewLRU(size int, ttl time.Duration) Store {
+	return &lru{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[int64]*list.Element, size),
+	}
+}
+
+// Get returns the cached student with id, if present and not expired.
+func (c *lru) Get(ctx context.Context, id int64) (types.Student, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[id]
+	if !ok {
+		return types.Student{}, false, nil
+	}
+
+	e := elem.Value.(*entry)
+	if time.Now().After(e.expires) {
+		c.remove(elem)
+		return types.Student{}, false, nil
+	}
+
+	c.order.MoveToFront(elem)
+	return e.student, true, nil
+}
+
+// Snapshot returns the current generation, to pass to Add.
+func (c *lru) Snapshot(ctx context.Context, id int64) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation, nil
+}
+
+/*
+Add()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Caches student, read from storage after Snapshot returned
+	    generation.
+
+	WHY THE GENERATION:
+	  → A read racing with an update can fetch the old row, then
+	    lose the CPU while the update commits and invalidates. If
+	    it cached the old row now, it would serve it for a whole
+	    ttl. Any invalidation since the snapshot means the read
+	    may be stale, so it is not cached.
+*/
+func (c *lru) Add(ctx context.Context, student types.Student, generation uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return nil
+	}
+
+	expires := time.Now().Add(c.ttl)
+
+	if elem, ok := c.items[student.Id]; ok {
+		elem.Value = &entry{id: student.Id, student: student, expires: expires}
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[student.Id] = c.order.PushFront(&entry{id: student.Id, student: student, expires: expires})
+
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Invalidate drops the cached student with id (if any).
+func (c *lru) Invalidate(ctx context.Context, id int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if elem, ok := c.items[id]; ok {
+		c.remove(elem)
+	}
+
+	return nil
+}
+
+// remove unlinks elem. c.mu must be held.
+func (c *lru) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry).id)
+}
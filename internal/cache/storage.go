@@ -0,0 +1,154 @@
+package cache
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → passed through to the wrapped storage
+   - log/slog → cache errors are logged, never returned
+   - metrics  → hit / miss counter
+   - storage  → the interface we decorate
+   - types    → Student / StudentPatch
+*/
+import (
+	"context"
+	"log/slog"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+Storage()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Wraps a storage.Storage with a read-through cache of
+	    GetStudentById (GET /api/students/{id}) kept in store;
+	    every other read goes straight to next.
+	  → Only wired when cache.enabled (see app.New).
+
+	INVALIDATION:
+	  → Update, Patch, SetStudentStatus, Add/RemoveStudentTag,
+	    Delete and Restore drop the student's ID once the write
+	    returns, whatever its outcome.
+	  → UpsertStudent drops the ID it reports, created or
+	    updated (an error can't name the student, and changed
+	    nothing).
+	  → DeleteStudents drops every ID it deleted.
+	  → CreateStudents (bulk import) drops the created IDs too.
+	    IDs are never reused, so nothing should be cached under
+	    them, but every write path invalidates so no future one
+	    is forgotten.
+	  → Misses are not cached: a student created right after a
+	    404 is found on the next request.
+	  → With the in-process store each replica has its own cache
+	    and may serve a student changed through another one for
+	    up to cache.ttl; with Redis they share it.
+*/
+func Storage(next storage.Storage, store Store) storage.Storage {
+	return &cachedStorage{Storage: next, store: store}
+}
+
+type cachedStorage struct {
+	storage.Storage
+	store Store
+}
+
+func (s *cachedStorage) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	student, ok, err := s.store.Get(ctx, id)
+	if err != nil {
+		slog.Warn("student cache read failed", slog.Int64("id", id), slog.String("error", err.Error()))
+	}
+	if ok {
+		metrics.CacheLookup(true)
+		return student, nil
+	}
+	metrics.CacheLookup(false)
+
+	generation, err := s.store.Snapshot(ctx, id)
+	if err != nil {
+		// no snapshot → no safe Add, just read through
+		return s.Storage.GetStudentById(ctx, id)
+	}
+
+	student, err = s.Storage.GetStudentById(ctx, id)
+	if err == nil {
+		if err := s.store.Add(ctx, student, generation); err != nil {
+			slog.Warn("student cache write failed", slog.Int64("id", id), slog.String("error", err.Error()))
+		}
+	}
+
+	return student, err
+}
+
+func (s *cachedStorage) CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]storage.BulkResult, error) {
+	results, err := s.Storage.CreateStudents(ctx, students, atomic)
+	for _, result := range results {
+		if result.Err == nil {
+			s.invalidate(ctx, result.Id)
+		}
+	}
+	return results, err
+}
+
+func (s *cachedStorage) UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error) {
+	id, created, err := s.Storage.UpsertStudent(ctx, student)
+	if err == nil {
+		s.invalidate(ctx, id)
+	}
+	return id, created, err
+}
+
+func (s *cachedStorage) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.UpdateStudent(ctx, id, student, version)
+}
+
+func (s *cachedStorage) PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.PatchStudent(ctx, id, patch, version)
+}
+
+func (s *cachedStorage) SetStudentStatus(ctx context.Context, id int64, status string) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.SetStudentStatus(ctx, id, status)
+}
+
+func (s *cachedStorage) AddStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.AddStudentTag(ctx, id, tag)
+}
+
+func (s *cachedStorage) RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.RemoveStudentTag(ctx, id, tag)
+}
+
+func (s *cachedStorage) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.DeleteStudent(ctx, id)
+}
+
+func (s *cachedStorage) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	deleted, err := s.Storage.DeleteStudents(ctx, filter)
+	for _, student := range deleted {
+		s.invalidate(ctx, student.Id)
+	}
+	return deleted, err
+}
+
+func (s *cachedStorage) RestoreStudent(ctx context.Context, id int64) (bool, error) {
+	defer s.invalidate(ctx, id)
+	return s.Storage.RestoreStudent(ctx, id)
+}
+
+// invalidate drops id from the store. The write already happened, so it
+// runs even when the client went away; a failure leaves a stale entry for
+// up to cache.ttl, which is worth an error in the log.
+func (s *cachedStorage) invalidate(ctx context.Context, id int64) {
+	if err := s.store.Invalidate(context.WithoutCancel(ctx), id); err != nil {
+		slog.Error("student cache invalidation failed", slog.Int64("id", id), slog.String("error", err.Error()))
+	}
+}
@@ -1,20 +1,772 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"io/fs"
 	"log"
+	"log/slog"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/phone"
 	"github.com/ilyakaznacheev/cleanenv"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // HTTPServer groups settings related to the HTTP server (address, ports, TLS, etc.).
-// We only have Addr for now, but grouping helps keep configuration organized.
+// Grouping helps keep configuration organized. Every field can also be set
+// from the environment with the HTTP_SERVER_ prefix (HTTP_SERVER_ADDR, …).
 type HTTPServer struct {
 	// `yaml:"addr"` tells the YAML parser (cleanenv in our case) which YAML key
 	// maps to this field. When the YAML file contains `http_server:\n  addr: ...`
 	// it will fill this Addr value.
-	Addr string `yaml:"addr" env-required:"true"`
+	Addr string `yaml:"addr" env:"ADDR" env-required:"true"`
+
+	// MaxBodyBytes caps the size of request bodies on mutating endpoints.
+	// Anything larger is rejected with 413 before the JSON decoder reads it.
+	// Defaults to 1MB when the YAML key is missing.
+	MaxBodyBytes int64 `yaml:"max_body_bytes" env:"MAX_BODY_BYTES" env-default:"1048576"`
+
+	// Timeouts are written as Go durations ("15s", "1m"). Without them a
+	// slow client (slow-loris) could hold a connection open forever.
+	//   - ReadHeaderTimeout: time allowed to send the request headers
+	//   - ReadTimeout:       time allowed to send the whole request
+	//   - WriteTimeout:      time allowed to produce the response
+	//   - IdleTimeout:       how long a keep-alive connection may sit idle
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"READ_HEADER_TIMEOUT" env-default:"5s"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"READ_TIMEOUT" env-default:"10s"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT" env-default:"15s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT" env-default:"60s"`
+
+	// RequestTimeout is the deadline put on every request's context. Storage
+	// calls still running when it passes are cancelled, and a handler that
+	// hasn't answered by then gets a 503 sent for it. 0 disables it.
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT" env-default:"10s"`
+
+	// ShutdownTimeout is how long graceful shutdown waits for in-flight
+	// requests before the remaining connections are closed forcefully.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"5s"`
+
+	// CleanupTimeout bounds what runs after the requests are drained:
+	// waiting for the background jobs, delivering the queued webhooks,
+	// closing the access log, Redis and the storage, in that order. A step
+	// still running when it passes is abandoned and the process exits.
+	CleanupTimeout time.Duration `yaml:"cleanup_timeout" env:"CLEANUP_TIMEOUT" env-default:"10s"`
+
+	// ShutdownDelay is how long /ready reports 503 before Shutdown starts,
+	// giving the load balancer time to stop routing new traffic here.
+	// 0 (the default) shuts down immediately; a few seconds suits Kubernetes.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" env-default:"0s"`
+
+	// TLS switches the server to HTTPS (HTTP_SERVER_TLS_* in the environment).
+	TLS TLS `yaml:"tls" env-prefix:"TLS_"`
+}
+
+// TLS configures HTTPS. The certificate and key are loaded at startup, so a
+// missing or unreadable file stops the server before it accepts connections.
+// RedirectHTTP starts a second, plain-HTTP listener on RedirectAddr that
+// answers every request with a 301 to the https:// URL.
+type TLS struct {
+	Enabled      bool   `yaml:"enabled" env:"ENABLED"`
+	CertFile     string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile      string `yaml:"key_file" env:"KEY_FILE"`
+	RedirectHTTP bool   `yaml:"redirect_http" env:"REDIRECT_HTTP"`
+	RedirectAddr string `yaml:"redirect_addr" env:"REDIRECT_ADDR" env-default:":80"`
+}
+
+// validate checks that an enabled TLS section is complete.
+func (t TLS) validate() error {
+	if !t.Enabled {
+		return nil
+	}
+
+	var errs []error
+
+	if t.CertFile == "" || t.KeyFile == "" {
+		errs = append(errs, errors.New("http_server.tls: cert_file and key_file are required when enabled"))
+	}
+
+	if t.RedirectHTTP {
+		if _, _, err := net.SplitHostPort(t.RedirectAddr); err != nil {
+			errs = append(errs, fmt.Errorf("http_server.tls.redirect_addr: %q is not host:port: %w", t.RedirectAddr, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CORS controls which browser origins may call the API. Leaving
+// AllowedOrigins empty disables CORS handling entirely (same-origin only).
+// "*" allows any origin, but browsers refuse "*" together with credentials,
+// so that combination is rejected when the config is loaded.
+type CORS struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS" env-separator:","`
+	AllowedMethods   []string `yaml:"allowed_methods" env:"ALLOWED_METHODS" env-default:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders   []string `yaml:"allowed_headers" env:"ALLOWED_HEADERS" env-default:"Content-Type,Authorization,X-API-Key,X-Request-ID"`
+	MaxAge           int      `yaml:"max_age" env:"MAX_AGE" env-default:"600"`
+	AllowCredentials bool     `yaml:"allow_credentials" env:"ALLOW_CREDENTIALS"`
+}
+
+// validate reports settings browsers would refuse anyway.
+func (c CORS) validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return errors.New("cors: allowed_origins \"*\" cannot be combined with allow_credentials")
+		}
+	}
+
+	return nil
+}
+
+// RateLimit configures the per-client token bucket. Each client IP may make
+// Burst requests at once and then RequestsPerSecond on average. TrustProxy
+// makes the limiter key on X-Forwarded-For, which is only safe behind a
+// reverse proxy that sets that header itself.
+type RateLimit struct {
+	Enabled           bool    `yaml:"enabled" env:"ENABLED"`
+	RequestsPerSecond float64 `yaml:"requests_per_second" env:"REQUESTS_PER_SECOND" env-default:"10"`
+	Burst             int     `yaml:"burst" env:"BURST" env-default:"20"`
+	TrustProxy        bool    `yaml:"trust_proxy" env:"TRUST_PROXY"`
+}
+
+// validate rejects values that would make the token bucket meaningless.
+func (r RateLimit) validate() error {
+	if !r.Enabled {
+		return nil
+	}
+
+	if r.RequestsPerSecond <= 0 || r.Burst < 1 {
+		return errors.New("rate_limit: requests_per_second and burst must be positive")
+	}
+
+	return nil
+}
+
+// Auth holds the credentials used to protect mutating endpoints.
+//
+//   - JWTSecret signs and verifies HS256 bearer tokens; prefer setting it via
+//     the JWT_SECRET environment variable instead of committing it to YAML.
+//   - APIKeys maps a client label to its secret key for service-to-service
+//     calls using the X-API-Key header. From the environment use
+//     API_KEYS="billing:secret1,lms:secret2".
+//   - APIKeyRoles maps every client label of APIKeys to its role (RoleAdmin
+//     or RoleTeacher). A key without an entry is refused at startup; keys
+//     used to default to admin, so configs from before roles must now list
+//     them: API_KEY_ROLES="billing:admin,lms:teacher". A token's role is its
+//     "role" claim.
+//   - Users are the accounts of POST /api/auth/login, keyed by username
+//     (YAML only). Tokens issued there are signed with JWTSecret, which is
+//     then required outside dev.
+//   - AccessTokenTTL / RefreshTokenTTL: lifetime of the tokens issued by the
+//     login and refresh endpoints.
+//   - LoginMaxFailures failed logins from one client IP within
+//     LoginFailureWindow get that IP 429s until the window is over; this is
+//     separate from rate_limit, which counts every request.
+//   - Disabled turns the check off entirely; only allowed when env is dev.
+type Auth struct {
+	Disabled           bool                `yaml:"disabled" env:"AUTH_DISABLED"`
+	JWTSecret          string              `yaml:"jwt_secret" env:"JWT_SECRET"`
+	APIKeys            map[string]string   `yaml:"api_keys" env:"API_KEYS" env-separator:","`
+	APIKeyRoles        map[string]string   `yaml:"api_key_roles" env:"API_KEY_ROLES" env-separator:","`
+	Users              map[string]AuthUser `yaml:"users"`
+	AccessTokenTTL     time.Duration       `yaml:"access_token_ttl" env:"AUTH_ACCESS_TOKEN_TTL" env-default:"15m"`
+	RefreshTokenTTL    time.Duration       `yaml:"refresh_token_ttl" env:"AUTH_REFRESH_TOKEN_TTL" env-default:"24h"`
+	LoginMaxFailures   int                 `yaml:"login_max_failures" env:"AUTH_LOGIN_MAX_FAILURES" env-default:"5"`
+	LoginFailureWindow time.Duration       `yaml:"login_failure_window" env:"AUTH_LOGIN_FAILURE_WINDOW" env-default:"15m"`
+}
+
+// AuthUser is an account of the login endpoint. PasswordHash is a bcrypt
+// hash, never the password itself; one can be made with
+//
+//	htpasswd -nbBC 12 "" 'the password' | cut -d: -f2
+type AuthUser struct {
+	PasswordHash string `yaml:"password_hash"`
+	Role         string `yaml:"role"`
+}
+
+// Roles a caller can have (token "role" claim, Auth.APIKeyRoles). Admins may
+// do everything; teachers may read, including the authenticated reads.
+const (
+	RoleAdmin   = "admin"
+	RoleTeacher = "teacher"
+)
+
+// Enabled reports whether mutating endpoints require credentials. Dev
+// configs without any credentials run unprotected, like Disabled.
+func (a Auth) Enabled() bool {
+	return !a.Disabled && (a.JWTSecret != "" || len(a.APIKeys) > 0)
+}
+
+// LoginEnabled reports whether the login / refresh endpoints are served:
+// there are users and a secret to sign their tokens with.
+func (a Auth) LoginEnabled() bool {
+	return !a.Disabled && a.JWTSecret != "" && len(a.Users) > 0
+}
+
+// validate refuses to run an unprotected API anywhere but local dev, and
+// keys without a role, roles for unknown keys or unknown roles anywhere. Users need a secret to
+// sign their tokens outside dev; in dev the login endpoint is just off.
+func (a Auth) validate(env string) error {
+	for _, label := range slices.Sorted(maps.Keys(a.APIKeys)) {
+		if _, ok := a.APIKeyRoles[label]; !ok {
+			return fmt.Errorf("auth.api_key_roles: the api key %q has no role, set %s or %s (keys no longer default to %s)",
+				label, RoleAdmin, RoleTeacher, RoleAdmin)
+		}
+	}
+	for label, role := range a.APIKeyRoles {
+		if _, ok := a.APIKeys[label]; !ok {
+			return fmt.Errorf("auth.api_key_roles: %q is not a label of auth.api_keys", label)
+		}
+		if role != RoleAdmin && role != RoleTeacher {
+			return fmt.Errorf("auth.api_key_roles.%s: %q must be %s or %s", label, role, RoleAdmin, RoleTeacher)
+		}
+	}
+
+	for username, user := range a.Users {
+		if username == "" {
+			return errors.New("auth.users: usernames must not be empty")
+		}
+		if _, err := bcrypt.Cost([]byte(user.PasswordHash)); err != nil {
+			return fmt.Errorf("auth.users.%s.password_hash: not a bcrypt hash", username)
+		}
+		if user.Role != RoleAdmin && user.Role != RoleTeacher {
+			return fmt.Errorf("auth.users.%s.role: %q must be %s or %s", username, user.Role, RoleAdmin, RoleTeacher)
+		}
+	}
+
+	if a.AccessTokenTTL <= 0 || a.RefreshTokenTTL <= 0 {
+		return errors.New("auth: access_token_ttl and refresh_token_ttl must be positive")
+	}
+	if a.LoginMaxFailures < 1 || a.LoginFailureWindow <= 0 {
+		return errors.New("auth: login_max_failures and login_failure_window must be positive")
+	}
+
+	if env == "dev" {
+		return nil
+	}
+
+	if len(a.Users) > 0 && a.JWTSecret == "" {
+		return errors.New("auth: jwt_secret (or JWT_SECRET) is required to sign the tokens of auth.users")
+	}
+
+	if a.Disabled {
+		return errors.New("auth: disabled is only allowed when env is dev")
+	}
+
+	if a.JWTSecret == "" && len(a.APIKeys) == 0 {
+		return errors.New("auth: jwt_secret (or JWT_SECRET) or api_keys is required outside dev")
+	}
+
+	return nil
+}
+
+// Log configures the application logger.
+//   - Level:  debug, info, warn or error
+//   - Format: json or text; empty means json in production and text elsewhere
+//   - SlowRequestThreshold: requests slower than this get a WARN "slow
+//     request" line; 0 disables it
+//   - AccessLogPath: when set, the per-request lines go to this file (JSON)
+//     instead of stderr; the file is reopened on SIGHUP (logrotate)
+//   - AccessLogMaxSize: bytes after which the access log is rotated by the
+//     server itself (renamed with a timestamp suffix); 0 leaves rotation
+//     to logrotate
+//   - DebugBodyMaxBytes: at level debug and outside production, request
+//     and response bodies up to this size are logged (see
+//     middleware.DebugBodies); 0 never logs them
+//   - RedactFields: JSON keys whose values are replaced by "[REDACTED]" in
+//     those logged bodies, at any depth, case-insensitively
+type Log struct {
+	Level                string        `yaml:"level" env:"LEVEL" env-default:"info"`
+	Format               string        `yaml:"format" env:"FORMAT"`
+	SlowRequestThreshold time.Duration `yaml:"slow_request_threshold" env:"SLOW_REQUEST_THRESHOLD" env-default:"1s"`
+	AccessLogPath        string        `yaml:"access_log_path" env:"ACCESS_LOG_PATH"`
+	AccessLogMaxSize     int64         `yaml:"access_log_max_size" env:"ACCESS_LOG_MAX_SIZE" env-default:"104857600"`
+	DebugBodyMaxBytes    int64         `yaml:"debug_body_max_bytes" env:"DEBUG_BODY_MAX_BYTES" env-default:"8192"`
+	RedactFields         []string      `yaml:"redact_fields" env:"REDACT_FIELDS" env-separator:"," env-default:"password,password_hash,token,access_token,refresh_token,secret,api_key,authorization"`
+}
+
+// validate rejects unknown level/format names and negative thresholds /
+// sizes.
+func (l Log) validate() error {
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, l.Level) {
+		return fmt.Errorf("log.level: %q must be one of debug, info, warn, error", l.Level)
+	}
+
+	if l.Format != "" && l.Format != "json" && l.Format != "text" {
+		return fmt.Errorf("log.format: %q must be json or text", l.Format)
+	}
+	if l.SlowRequestThreshold < 0 {
+		return fmt.Errorf("log.slow_request_threshold: must not be negative, got %s", l.SlowRequestThreshold)
+	}
+	if l.AccessLogMaxSize < 0 {
+		return fmt.Errorf("log.access_log_max_size: must not be negative, got %d", l.AccessLogMaxSize)
+	}
+	if l.DebugBodyMaxBytes < 0 {
+		return fmt.Errorf("log.debug_body_max_bytes: must not be negative, got %d", l.DebugBodyMaxBytes)
+	}
+
+	return nil
+}
+
+// Storage selects the database backend.
+//   - Driver: "sqlite" (default, file at Config.StoragePath), "postgres",
+//     or "memory" (nothing persisted, for demos)
+//   - DSN:    Postgres connection string, e.g.
+//     "postgres://user:pass@db:5432/students?sslmode=disable"
+//   - SQLite: connection settings of the sqlite driver
+//   - MaxOpenConns: cap on the connections of the pool (sqlite and
+//     postgres); 0 means unlimited
+//   - MaxIdleConns: connections kept open between requests; at most
+//     MaxOpenConns when that is set
+//   - ConnMaxLifetime: connections older than this are closed and
+//     replaced (e.g. to follow a Postgres failover); 0 keeps them forever
+//   - Retry: retries of calls failing with a transient database error
+//   - OnStudentDelete: what deleting a student enrolled in courses does,
+//     "block" (default, 409 until the enrollments are removed) or
+//     "cascade" (the enrollments are removed with the student)
+//   - IDScheme: the IDs clients see, "serial" (default, the row IDs 1, 2,
+//     3…) or "uuid" (a UUID per student, so IDs don't give away how many
+//     there are; see storage.IDStore)
+type Storage struct {
+	Driver          string        `yaml:"driver" env:"DRIVER" env-default:"sqlite"`
+	DSN             string        `yaml:"dsn" env:"DSN"`
+	SQLite          SQLite        `yaml:"sqlite" env-prefix:"SQLITE_"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"MAX_IDLE_CONNS" env-default:"2"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"CONN_MAX_LIFETIME"`
+	Retry           StorageRetry  `yaml:"retry" env-prefix:"RETRY_"`
+	OnStudentDelete string        `yaml:"on_student_delete" env:"ON_STUDENT_DELETE" env-default:"block"`
+	IDScheme        string        `yaml:"id_scheme" env:"ID_SCHEME" env-default:"serial"`
+}
+
+// StorageRetry configures storage.Retry (STORAGE_RETRY_… from the
+// environment).
+//   - Attempts: calls in total, the first one included; 1 disables retries
+//   - BaseDelay: wait before the first retry, doubled for every next one
+//   - MaxDelay: cap on that wait
+//
+// Waits are jittered: the actual one is random between 0 and the above.
+type StorageRetry struct {
+	Attempts  int           `yaml:"attempts" env:"ATTEMPTS" env-default:"3"`
+	BaseDelay time.Duration `yaml:"base_delay" env:"BASE_DELAY" env-default:"20ms"`
+	MaxDelay  time.Duration `yaml:"max_delay" env:"MAX_DELAY" env-default:"500ms"`
+}
+
+// validate requires at least one attempt and base_delay <= max_delay.
+func (r StorageRetry) validate() error {
+	if r.Attempts < 1 {
+		return fmt.Errorf("storage.retry.attempts: must be at least 1, got %d", r.Attempts)
+	}
+	if r.BaseDelay < 0 || r.MaxDelay < 0 {
+		return fmt.Errorf("storage.retry: delays must not be negative, got base_delay %s, max_delay %s", r.BaseDelay, r.MaxDelay)
+	}
+	if r.BaseDelay > r.MaxDelay {
+		return fmt.Errorf("storage.retry.base_delay: %s must not exceed max_delay (%s)", r.BaseDelay, r.MaxDelay)
+	}
+
+	return nil
+}
+
+// validatePool rejects negative pool settings and more idle connections
+// than may be open, and checks the retry settings of the SQL backends.
+func (s Storage) validatePool() error {
+	var errs []error
+
+	if s.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf("storage.max_open_conns: must not be negative, got %d", s.MaxOpenConns))
+	}
+	if s.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("storage.max_idle_conns: must not be negative, got %d", s.MaxIdleConns))
+	}
+	if s.MaxOpenConns > 0 && s.MaxIdleConns > s.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("storage.max_idle_conns: %d must not exceed storage.max_open_conns (%d)", s.MaxIdleConns, s.MaxOpenConns))
+	}
+	if s.ConnMaxLifetime < 0 {
+		errs = append(errs, fmt.Errorf("storage.conn_max_lifetime: must not be negative, got %s", s.ConnMaxLifetime))
+	}
+	if err := s.Retry.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// SQLite holds the pragmas applied to every connection to the database
+// file (STORAGE_SQLITE_… from the environment).
+//   - JournalMode: WAL lets readers work while one writer commits
+//   - Synchronous: NORMAL is safe with WAL and much faster than FULL
+//   - ForeignKeys: enforce REFERENCES constraints (off in SQLite by default)
+//   - BusyTimeout: how long a connection waits for a lock held by another
+//     one before failing with "database is locked"; 0 fails at once
+type SQLite struct {
+	JournalMode string        `yaml:"journal_mode" env:"JOURNAL_MODE" env-default:"WAL"`
+	Synchronous string        `yaml:"synchronous" env:"SYNCHRONOUS" env-default:"NORMAL"`
+	ForeignKeys bool          `yaml:"foreign_keys" env:"FOREIGN_KEYS" env-default:"true"`
+	BusyTimeout time.Duration `yaml:"busy_timeout" env:"BUSY_TIMEOUT" env-default:"5s"`
+}
+
+// validate accepts the modes SQLite knows, in any case.
+func (s SQLite) validate() error {
+	var errs []error
+
+	journalModes := []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	if !slices.Contains(journalModes, strings.ToUpper(s.JournalMode)) {
+		errs = append(errs, fmt.Errorf("storage.sqlite.journal_mode: %q must be one of %s", s.JournalMode, strings.Join(journalModes, ", ")))
+	}
+
+	synchronous := []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+	if !slices.Contains(synchronous, strings.ToUpper(s.Synchronous)) {
+		errs = append(errs, fmt.Errorf("storage.sqlite.synchronous: %q must be one of %s", s.Synchronous, strings.Join(synchronous, ", ")))
+	}
+
+	if s.BusyTimeout < 0 {
+		errs = append(errs, fmt.Errorf("storage.sqlite.busy_timeout: must not be negative, got %s", s.BusyTimeout))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Storage drivers accepted in Storage.Driver.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
+// Values of Storage.OnStudentDelete.
+const (
+	OnDeleteBlock   = "block"
+	OnDeleteCascade = "cascade"
+)
+
+// Values of Storage.IDScheme.
+const (
+	IDSchemeSerial = "serial"
+	IDSchemeUUID   = "uuid"
+)
+
+// validateStorage checks that the selected driver has what it needs.
+func (cfg *Config) validateStorage() error {
+	if cfg.Storage.OnStudentDelete != OnDeleteBlock && cfg.Storage.OnStudentDelete != OnDeleteCascade {
+		return fmt.Errorf("storage.on_student_delete: %q must be %s or %s", cfg.Storage.OnStudentDelete, OnDeleteBlock, OnDeleteCascade)
+	}
+	if cfg.Storage.IDScheme != IDSchemeSerial && cfg.Storage.IDScheme != IDSchemeUUID {
+		return fmt.Errorf("storage.id_scheme: %q must be %s or %s", cfg.Storage.IDScheme, IDSchemeSerial, IDSchemeUUID)
+	}
+
+	switch cfg.Storage.Driver {
+	case DriverSQLite:
+		if cfg.StoragePath == "" {
+			return errors.New("storage_path: required when storage.driver is sqlite")
+		}
+		if err := checkWritableDir(filepath.Dir(cfg.StoragePath)); err != nil {
+			return fmt.Errorf("storage_path: %w", err)
+		}
+		if err := cfg.Storage.SQLite.validate(); err != nil {
+			return err
+		}
+		if err := cfg.Storage.validatePool(); err != nil {
+			return err
+		}
+	case DriverPostgres:
+		if cfg.Storage.DSN == "" {
+			return errors.New("storage.dsn: required when storage.driver is postgres")
+		}
+		if err := cfg.Storage.validatePool(); err != nil {
+			return err
+		}
+	case DriverMemory:
+		// Nothing to configure
+	default:
+		return fmt.Errorf("storage.driver: %q must be one of %s, %s, %s", cfg.Storage.Driver, DriverSQLite, DriverPostgres, DriverMemory)
+	}
+
+	return nil
+}
+
+// Metrics configures the Prometheus /metrics endpoint.
+//   - Addr: empty serves /metrics on the API listener; "127.0.0.1:9090"
+//     (or any host:port) starts a separate listener so metrics are not
+//     reachable through the public port.
+type Metrics struct {
+	Addr string `yaml:"addr" env:"ADDR"`
+}
+
+func (m Metrics) validate() error {
+	if m.Addr == "" {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(m.Addr); err != nil {
+		return fmt.Errorf("metrics.addr: %q is not host:port: %w", m.Addr, err)
+	}
+
+	return nil
+}
+
+// Debug configures diagnostics that must never be public.
+//   - PprofEnabled: serve net/http/pprof under /debug/pprof/
+//   - Addr:         the internal listener pprof runs on; it is never
+//     mounted on the API port. Defaults to loopback only.
+type Debug struct {
+	PprofEnabled bool   `yaml:"pprof_enabled" env:"PPROF_ENABLED"`
+	Addr         string `yaml:"addr" env:"ADDR" env-default:"127.0.0.1:6060"`
+}
+
+func (d Debug) validate() error {
+	if !d.PprofEnabled {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(d.Addr); err != nil {
+		return fmt.Errorf("debug.addr: %q is not host:port: %w", d.Addr, err)
+	}
+
+	return nil
+}
+
+// API holds behaviour switches of the student endpoints.
+//   - RequireIfMatch: PUT/PATCH must name the version they edit (If-Match
+//     or "version" in the body), otherwise they get 428 Precondition
+//     Required. Off by default so existing clients keep working; they
+//     then overwrite whatever version is current.
+//   - IdempotencyTTL: how long an Idempotency-Key of POST /api/students is
+//     remembered; a retry within that window gets the recorded response.
+//   - StatsCacheTTL: how long GET /api/students/stats reuses its last
+//     result; 0 recomputes it on every request.
+//   - EventHeartbeat: how often GET /api/students/events writes a comment
+//     on an idle stream, so proxies don't close silent connections.
+//   - PhoneCountry: the country (ISO 3166 alpha-2, "GB") of the phone
+//     numbers written without "+" or "00", e.g. "020 7946 0958". Empty
+//     means such numbers are rejected.
+type API struct {
+	RequireIfMatch bool          `yaml:"require_if_match" env:"REQUIRE_IF_MATCH"`
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	StatsCacheTTL  time.Duration `yaml:"stats_cache_ttl" env:"STATS_CACHE_TTL" env-default:"5s"`
+	EventHeartbeat time.Duration `yaml:"event_heartbeat" env:"EVENT_HEARTBEAT" env-default:"15s"`
+	PhoneCountry   string        `yaml:"phone_country" env:"PHONE_COUNTRY"`
+}
+
+func (a API) validate() error {
+	if a.IdempotencyTTL <= 0 {
+		return fmt.Errorf("api.idempotency_ttl: must be positive, got %s", a.IdempotencyTTL)
+	}
+	if a.StatsCacheTTL < 0 {
+		return fmt.Errorf("api.stats_cache_ttl: must not be negative, got %s", a.StatsCacheTTL)
+	}
+	if a.EventHeartbeat <= 0 {
+		return fmt.Errorf("api.event_heartbeat: must be positive, got %s", a.EventHeartbeat)
+	}
+	if a.PhoneCountry != "" && !phone.IsCountry(a.PhoneCountry) {
+		return fmt.Errorf("api.phone_country: %q is not a country phone numbers can be read for", a.PhoneCountry)
+	}
+
+	return nil
+}
+
+// Cache configures the read-through cache of GET /api/students/{id}: each
+// student is kept for TTL. Off unless Enabled. In process memory it holds at
+// most Size students; with redis.addr set it lives in Redis instead (Size
+// unused, eviction is Redis' maxmemory policy).
+type Cache struct {
+	Enabled bool          `yaml:"enabled" env:"ENABLED"`
+	Size    int           `yaml:"size" env:"SIZE" env-default:"1000"`
+	TTL     time.Duration `yaml:"ttl" env:"TTL" env-default:"1m"`
+}
+
+func (c Cache) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Size < 1 || c.TTL <= 0 {
+		return fmt.Errorf("cache: size and ttl must be positive, got size=%d ttl=%s", c.Size, c.TTL)
+	}
+
+	return nil
+}
+
+// Photos configures the profile photos of PUT / GET
+// /api/students/{id}/photo.
+//   - Store: where the bytes live, "database" (default, next to the
+//     students: a blob column for sqlite and postgres) or "disk" (one file
+//     per student in Dir)
+//   - Dir: the directory of the "disk" store; default "photos" next to
+//     storage_path
+//   - MaxBytes: the largest photo accepted (413 above), default 2MB. Photo
+//     uploads are not bound by http_server.max_body_bytes.
+type Photos struct {
+	Store    string `yaml:"store" env:"STORE" env-default:"database"`
+	Dir      string `yaml:"dir" env:"DIR"`
+	MaxBytes int64  `yaml:"max_bytes" env:"MAX_BYTES" env-default:"2097152"`
+}
+
+// Values of Photos.Store.
+const (
+	PhotoStoreDatabase = "database"
+	PhotoStoreDisk     = "disk"
+)
+
+// DiskDir is the directory of the "disk" store: Dir, else "photos" next to
+// storagePath; "" when neither is set.
+func (p Photos) DiskDir(storagePath string) string {
+	if p.Dir != "" || storagePath == "" {
+		return p.Dir
+	}
+
+	return filepath.Join(filepath.Dir(storagePath), "photos")
+}
+
+// validate checks the store name and the size limit; "disk" needs a
+// directory.
+func (p Photos) validate(storagePath string) error {
+	if p.Store != PhotoStoreDatabase && p.Store != PhotoStoreDisk {
+		return fmt.Errorf("photos.store: %q must be %s or %s", p.Store, PhotoStoreDatabase, PhotoStoreDisk)
+	}
+	if p.MaxBytes <= 0 {
+		return fmt.Errorf("photos.max_bytes: must be positive, got %d", p.MaxBytes)
+	}
+	if p.Store == PhotoStoreDisk && p.DiskDir(storagePath) == "" {
+		return errors.New("photos.dir: required when photos.store is disk and storage_path is not set")
+	}
+
+	return nil
+}
+
+// Purge configures the permanent removal of soft-deleted students.
+//   - Enabled: run it in the background every Interval (default 1h); off
+//     by default, deleted students are then kept until POST
+//     /api/admin/purge
+//   - Retention: how long a student stays soft-deleted (and restorable)
+//     before it may be purged, default 720h (30 days)
+//   - BatchSize: students removed per statement (default 500), so a large
+//     purge never holds the table for long
+type Purge struct {
+	Enabled   bool          `yaml:"enabled" env:"ENABLED"`
+	Interval  time.Duration `yaml:"interval" env:"INTERVAL" env-default:"1h"`
+	Retention time.Duration `yaml:"retention" env:"RETENTION" env-default:"720h"`
+	BatchSize int           `yaml:"batch_size" env:"BATCH_SIZE" env-default:"500"`
+}
+
+// validate requires positive settings, enabled or not: POST
+// /api/admin/purge uses them too.
+func (p Purge) validate() error {
+	if p.Interval <= 0 || p.Retention <= 0 {
+		return fmt.Errorf("purge: interval and retention must be positive, got interval=%s retention=%s", p.Interval, p.Retention)
+	}
+	if p.BatchSize < 1 {
+		return fmt.Errorf("purge.batch_size: must be at least 1, got %d", p.BatchSize)
+	}
+
+	return nil
+}
+
+// Concurrency caps how many requests are served at once, to keep SQLite
+// out of "database is locked" under many concurrent writers. MaxWrites
+// bounds POST/PUT/PATCH/DELETE, MaxReads the other methods (probes and
+// event streams excepted); 0 leaves that class unlimited. A request over
+// the cap waits up to MaxWait for a slot, then gets 503 with Retry-After.
+// SQLite has a single writer, so max_writes 1 is the safe value there.
+type Concurrency struct {
+	MaxWrites int           `yaml:"max_writes" env:"MAX_WRITES"`
+	MaxReads  int           `yaml:"max_reads" env:"MAX_READS"`
+	MaxWait   time.Duration `yaml:"max_wait" env:"MAX_WAIT" env-default:"2s"`
+}
+
+func (c Concurrency) validate() error {
+	if c.MaxWrites < 0 || c.MaxReads < 0 || c.MaxWait < 0 {
+		return fmt.Errorf("concurrency: max_writes, max_reads and max_wait must not be negative, got %d, %d, %s",
+			c.MaxWrites, c.MaxReads, c.MaxWait)
+	}
+
+	return nil
+}
+
+// Redis, when Addr (host:port) is set, holds the student cache and the rate
+// limiter buckets, so every replica shares them; unset, both stay in process
+// memory. KeyPrefix namespaces the keys when the server is shared. Timeout
+// bounds dialing and every command: a slow Redis counts as unavailable,
+// which the cache and the limiter survive (miss / request let through).
+type Redis struct {
+	Addr      string        `yaml:"addr" env:"ADDR"`
+	Password  string        `yaml:"password" env:"PASSWORD"`
+	DB        int           `yaml:"db" env:"DB"`
+	KeyPrefix string        `yaml:"key_prefix" env:"KEY_PREFIX" env-default:"students-api:"`
+	Timeout   time.Duration `yaml:"timeout" env:"TIMEOUT" env-default:"200ms"`
+}
+
+// Enabled reports whether a Redis server is configured.
+func (r Redis) Enabled() bool {
+	return r.Addr != ""
+}
+
+func (r Redis) validate() error {
+	if !r.Enabled() {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(r.Addr); err != nil {
+		return fmt.Errorf("redis.addr: %q is not host:port: %w", r.Addr, err)
+	}
+	if r.Timeout <= 0 {
+		return fmt.Errorf("redis.timeout: must be positive, got %s", r.Timeout)
+	}
+
+	return nil
+}
+
+// Webhooks lists the endpoints notified when students change. No URLs
+// disables webhooks. Every delivery is signed with Secret (HMAC-SHA256 of
+// the body), retried up to MaxAttempts times with exponential backoff, and
+// given Timeout per attempt. Workers deliver concurrently from a queue of
+// QueueSize events; a full queue drops new events (logged).
+type Webhooks struct {
+	URLs        []string      `yaml:"urls" env:"URLS" env-separator:","`
+	Secret      string        `yaml:"secret" env:"SECRET"`
+	MaxAttempts int           `yaml:"max_attempts" env:"MAX_ATTEMPTS" env-default:"5"`
+	Timeout     time.Duration `yaml:"timeout" env:"TIMEOUT" env-default:"5s"`
+	Workers     int           `yaml:"workers" env:"WORKERS" env-default:"4"`
+	QueueSize   int           `yaml:"queue_size" env:"QUEUE_SIZE" env-default:"1000"`
+}
+
+// Enabled reports whether any webhook URL is configured.
+func (w Webhooks) Enabled() bool {
+	return len(w.URLs) > 0
+}
+
+// validate requires a secret (receivers must be able to verify deliveries)
+// and absolute http(s) URLs.
+func (w Webhooks) validate() error {
+	if !w.Enabled() {
+		return nil
+	}
+
+	var errs []error
+	if w.Secret == "" {
+		errs = append(errs, errors.New("webhooks.secret: required when webhooks.urls is set"))
+	}
+	for _, raw := range w.URLs {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("webhooks.urls: %q is not an absolute http(s) URL", raw))
+		}
+	}
+	if w.MaxAttempts < 1 || w.Workers < 1 || w.QueueSize < 1 || w.Timeout <= 0 {
+		errs = append(errs, errors.New("webhooks: max_attempts, workers, queue_size and timeout must be positive"))
+	}
+
+	return errors.Join(errs...)
 }
 
 // Config is the root configuration structure for the application.
@@ -30,61 +782,191 @@ type HTTPServer struct {
 // Example YAML that matches this struct:
 // env: production
 // storage_path: /var/data/app
+// storage:
+//
+//	driver: sqlite # or memory, or postgres with dsn: "postgres://…"
+//	sqlite:
+//	  busy_timeout: 5s
+//	max_open_conns: 10
+//
 // http_server:
 //
 //	addr: ":8080"
+//	max_body_bytes: 1048576
+//	read_timeout: "10s"
+//	write_timeout: "15s"
+//	request_timeout: "10s"
+//	tls:
+//	  enabled: true
+//	  cert_file: /etc/students-api/tls.crt
+//	  key_file: /etc/students-api/tls.key
+//
+// cors:
+//
+//	allowed_origins: ["https://app.example.com"]
+//	allow_credentials: true
+//
+// log:
+//
+//	level: info
+//	format: json
+//	slow_request_threshold: 1s
+//	access_log_path: /var/log/students-api/access.log
+//
+// rate_limit:
+//
+//	enabled: true
+//	requests_per_second: 10
+//	burst: 20
+//
+// api:
+//
+//	require_if_match: true
+//	idempotency_ttl: 24h
+//	stats_cache_ttl: 5s
+//	event_heartbeat: 15s
+//
+// webhooks:
+//
+//	urls: ["https://billing.internal/hooks/students"]
+//	secret: "shared-secret"
+//
+// cache:
+//
+//	enabled: true
+//	size: 1000
+//	ttl: 1m
+//
+// redis:
+//
+//	addr: "redis:6379"
+//
+// concurrency:
+//
+//	max_writes: 1
+//	max_wait: 2s
+//
+// photos:
+//
+//	store: disk
+//	max_bytes: 5242880
 type Config struct {
-	Env         string     `yaml:"env" env:"ENV" env-required:"true" env-default:"production"`
-	StoragePath string     `yaml:"storage_path" env:"STORAGE_PATH" env-required:"true"`
-	HTTPServer  HTTPServer `yaml:"http_server"`
+	Env         string      `yaml:"env" env:"ENV" env-required:"true" env-default:"production"`
+	StoragePath string      `yaml:"storage_path" env:"STORAGE_PATH"`
+	Storage     Storage     `yaml:"storage" env-prefix:"STORAGE_"`
+	HTTPServer  HTTPServer  `yaml:"http_server" env-prefix:"HTTP_SERVER_"`
+	CORS        CORS        `yaml:"cors" env-prefix:"CORS_"`
+	RateLimit   RateLimit   `yaml:"rate_limit" env-prefix:"RATE_LIMIT_"`
+	Auth        Auth        `yaml:"auth"`
+	Log         Log         `yaml:"log" env-prefix:"LOG_"`
+	Metrics     Metrics     `yaml:"metrics" env-prefix:"METRICS_"`
+	Debug       Debug       `yaml:"debug" env-prefix:"DEBUG_"`
+	API         API         `yaml:"api" env-prefix:"API_"`
+	Webhooks    Webhooks    `yaml:"webhooks" env-prefix:"WEBHOOKS_"`
+	Cache       Cache       `yaml:"cache" env-prefix:"CACHE_"`
+	Redis       Redis       `yaml:"redis" env-prefix:"REDIS_"`
+	Concurrency Concurrency `yaml:"concurrency" env-prefix:"CONCURRENCY_"`
+	Photos      Photos      `yaml:"photos" env-prefix:"PHOTOS_"`
+	Purge       Purge       `yaml:"purge" env-prefix:"PURGE_"`
 }
 
-// MustLoad loads configuration using the following precedence:
+// Sentinel errors returned (wrapped) by Load, so callers can tell the
+// failure modes apart with errors.Is.
+var (
+	// ErrPathNotSet means neither CONFIG_PATH nor -config was provided and
+	// none of the DefaultPaths exists.
+	ErrPathNotSet = errors.New("config path is not set; set CONFIG_PATH, pass -config or create ./config.yaml")
+
+	// ErrInvalid wraps every violation found after parsing.
+	ErrInvalid = errors.New("invalid config")
+)
+
+// DefaultPaths are probed, in order, when neither CONFIG_PATH nor -config is
+// given. The first file that exists is used.
+var DefaultPaths = []string{
+	"./config.yaml",
+	"./config/local.yaml",
+	"/etc/students-api/config.yaml",
+}
+
+// EnvOnly is the CONFIG_PATH value that selects env-only mode.
+const EnvOnly = "env"
+
+// Load loads configuration using the following precedence:
+// 0) If CONFIG_PATH=env or -config-from-env is given, no file is read at all
+//    and Config is populated purely from environment variables (see the env
+//    and env-prefix tags, e.g. HTTP_SERVER_ADDR, STORAGE_PATH, CORS_MAX_AGE).
 // 1) If CONFIG_PATH environment variable is set, that path is used.
 // 2) Otherwise it looks for a -config flag passed to the program (CLI flag).
-// 3) If neither is present, the function fatally exits the program.
+// 3) Otherwise the first existing file from DefaultPaths is used.
+// 4) If none is found, it returns ErrPathNotSet.
+//
+// Explicit sources always win, even when their file is missing: a typo in
+// CONFIG_PATH is reported instead of silently loading a default file.
+//
+// Within a single load, values are resolved as: environment variable >
+// YAML file > env-default tag.
 //
 // After a path is determined, the function checks the file exists and uses
-// cleanenv to parse the YAML file into the Config struct. If any critical
-// error occurs, the function logs the error and terminates the program.
+// cleanenv to parse the YAML file into the Config struct. Every error is
+// wrapped with context ("config file does not exist: <path>") while keeping
+// the cause reachable via errors.Is / errors.As (fs.ErrNotExist, ErrInvalid…).
 //
 // The function returns a pointer to a fully-populated Config on success.
-func MustLoad() *Config {
+func Load() (*Config, error) {
 	// Step A: try to read CONFIG_PATH environment variable first. This is useful
 	// in containerized deployments or when an operator prefers environment-based
 	// configuration.
 	configPath := os.Getenv("CONFIG_PATH")
 
+	source := "CONFIG_PATH"
+
+	flagPath, fromEnv := configFlags()
+
+	// Step A2: env-only mode (containers without a config file).
+	if configPath == EnvOnly || (configPath == "" && fromEnv) {
+		var cfg Config
+		if err := cleanenv.ReadEnv(&cfg); err != nil {
+			return nil, fmt.Errorf("cannot read config from environment: %w", err)
+		}
+
+		slog.Info("using config from environment only")
+
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+
+		return &cfg, nil
+	}
+
 	// Step B: if CONFIG_PATH is empty, fallback to reading the command-line flag.
-	//
-	// Why use the flag package here?
-	//  - flag.String returns a *string that will hold the flag value after flag.Parse()
-	//  - This allows the program to accept `-config /path/to/config.yaml` at startup
-	//  - Using flags is convenient for local development and for scripts
 	if configPath == "" {
-		// We store the pointer returned by flag.String in a variable named flagPtr.
-		// Note: we do NOT call flag.Parse() until after we've declared all flags.
-		flagPtr := flag.String("config", "", "path to the configuration file")
-		// Parse parses the command-line flags from os.Args. It must be called
-		// before we try to use the flag values (we dereference the pointer after
-		// Parse). If you don't call Parse, flag values will remain at their defaults.
-		flag.Parse()
+		configPath = flagPath
+		source = "-config flag"
+	}
 
-		// Dereference the pointer to get the actual config path string.
-		configPath = *flagPtr
+	// Step B2: nothing explicit → probe the well-known locations in order.
+	if configPath == "" {
+		configPath = firstExisting(DefaultPaths)
+		source = "default location"
 
 		// If still empty, we cannot proceed because we don't know where to load the
-		// configuration from. MustLoad is designed to fail-fast in this case.
+		// configuration from.
 		if configPath == "" {
-			log.Fatal("config path is not set; set CONFIG_PATH or pass -config")
+			return nil, ErrPathNotSet
 		}
 	}
 
+	slog.Info("using config file", slog.String("path", configPath), slog.String("source", source))
+
 	// Step C: verify that the configuration file exists at the provided path.
 	// os.Stat returns file info and an error. If the error indicates "file does
 	// not exist" then we stop early with a helpful message.
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		log.Fatalf("config file does not exist: %s", configPath)
+	if _, err := os.Stat(configPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("config file does not exist: %s: %w", configPath, err)
+		}
+		return nil, fmt.Errorf("cannot access config file %s: %w", configPath, err)
 	}
 
 	// Step D: read the configuration file into our Config struct.
@@ -94,13 +976,161 @@ func MustLoad() *Config {
 	// provides convenient features (env-required, env-default, etc.).
 	var cfg Config
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		// Any error reading/parsing the config is considered fatal. The error
-		// message is included so operators can diagnose issues quickly.
-		log.Fatalf("cannot read config file: %s", err.Error())
+		return nil, fmt.Errorf("cannot read config file %s: %w", configPath, err)
+	}
+
+	// Step E: reject values cleanenv can't check with tags.
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	// Return a pointer to the populated configuration.
-	return &cfg
+	return &cfg, nil
+}
+
+// MustLoad is Load for main: any error is logged and the program exits.
+// Configuration is critical, so failing fast and loudly is the right call
+// at startup; everything else should prefer Load.
+func MustLoad() *Config {
+	cfg, err := Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return cfg
+}
+
+// Environments lists the accepted values of Config.Env.
+var Environments = []string{"dev", "staging", "production"}
+
+// Validate checks values that parse fine but would only blow up at runtime:
+//   - Env is one of Environments
+//   - HTTPServer.Addr is a "host:port" (":8082", not "8082")
+//   - every duration is positive
+//   - the storage driver is known and configured; for sqlite the
+//     directory of StoragePath exists and is writable
+//   - the per-section rules (CORS, rate limit, auth)
+//
+// All violations are collected, so operators see everything at once. The
+// returned error wraps ErrInvalid.
+func (cfg *Config) Validate() error {
+	var errs []error
+
+	if !slices.Contains(Environments, cfg.Env) {
+		errs = append(errs, fmt.Errorf("env: %q must be one of %s", cfg.Env, strings.Join(Environments, ", ")))
+	}
+
+	if _, _, err := net.SplitHostPort(cfg.HTTPServer.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("http_server.addr: %q is not host:port: %w", cfg.HTTPServer.Addr, err))
+	}
+
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read_header_timeout", cfg.HTTPServer.ReadHeaderTimeout},
+		{"read_timeout", cfg.HTTPServer.ReadTimeout},
+		{"write_timeout", cfg.HTTPServer.WriteTimeout},
+		{"idle_timeout", cfg.HTTPServer.IdleTimeout},
+		{"shutdown_timeout", cfg.HTTPServer.ShutdownTimeout},
+		{"cleanup_timeout", cfg.HTTPServer.CleanupTimeout},
+	} {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("http_server.%s: must be positive, got %s", d.name, d.value))
+		}
+	}
+
+	if cfg.HTTPServer.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("http_server.request_timeout: must not be negative, got %s", cfg.HTTPServer.RequestTimeout))
+	}
+	if cfg.HTTPServer.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("http_server.shutdown_delay: must not be negative, got %s", cfg.HTTPServer.ShutdownDelay))
+	}
+
+	errs = append(errs,
+		cfg.validateStorage(),
+		cfg.HTTPServer.TLS.validate(),
+		cfg.Log.validate(),
+		cfg.Metrics.validate(),
+		cfg.Debug.validate(),
+		cfg.CORS.validate(),
+		cfg.RateLimit.validate(),
+		cfg.Auth.validate(cfg.Env),
+		cfg.API.validate(),
+		cfg.Webhooks.validate(),
+		cfg.Cache.validate(),
+		cfg.Redis.validate(),
+		cfg.Concurrency.validate(),
+		cfg.Photos.validate(cfg.StoragePath),
+		cfg.Purge.validate(),
+	)
+
+	// errors.Join drops the nil entries and returns nil if all are nil
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w:\n%w", ErrInvalid, err)
+	}
+
+	return nil
+}
+
+// checkWritableDir proves dir exists and is writable by creating and
+// removing a probe file (permission bits alone don't account for read-only
+// mounts).
+func checkWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	probe, err := os.CreateTemp(dir, ".students-api-probe-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
+
+// firstExisting returns the first path that exists, or "" if none does.
+func firstExisting(paths []string) string {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// configFlags returns the values of the -config and -config-from-env
+// command-line flags.
+//
+// Why use the flag package here?
+//   - flag.String returns a *string that will hold the flag value after flag.Parse()
+//   - This allows the program to accept `-config /path/to/config.yaml` at startup
+//   - Using flags is convenient for local development and for scripts
+//
+// The flags are registered only once (flag.String panics on redefinition), so
+// Load can safely be called more than once.
+func configFlags() (string, bool) {
+	if flag.Lookup("config") == nil {
+		flag.String("config", "", "path to the configuration file")
+		flag.Bool("config-from-env", false, "read the configuration from environment variables only")
+	}
+
+	// Parse parses the command-line flags from os.Args. It must be called
+	// before we try to use the flag values. If you don't call Parse, flag
+	// values will remain at their defaults.
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	fromEnv := flag.Lookup("config-from-env").Value.(flag.Getter).Get().(bool)
+
+	return flag.Lookup("config").Value.String(), fromEnv
 }
 
 /*
@@ -127,16 +1157,14 @@ Additional notes and rationale (why things are done this way):
      The struct tags document the expected keys and environment variables and
      make the wiring explicit.
 
-5) Why does MustLoad exit the program on error (using log.Fatal/log.Fatalf)?
+5) Why does MustLoad exit the program on error (using log.Fatal)?
    - Configuration is critical: if required values (like STORAGE_PATH) are
      missing the program probably can't operate correctly. Failing fast and
      loudly helps avoid undefined behavior later on.
+   - Load contains the actual logic and returns errors instead, so tests and
+     other callers can decide for themselves what a failure means.
 
-6) Suggested improvements (optional):
-   - Add better validation for fields that need constraints (e.g. ensure
-     StoragePath is writable, ensure HTTPServer.Addr is a valid address).
-   - Support default file paths (e.g. look for ./config.yaml or /etc/myapp/config.yaml)
-     before failing entirely.
-   - Return an error instead of calling log.Fatal in library code, and let the
-     caller choose whether to exit — this makes the package more reusable.
+6) Why validate after loading (Config.Validate)?
+   - A typo like addr: "8082" or a read-only storage directory would otherwise
+     only fail once the server starts serving or writes its first row.
 */
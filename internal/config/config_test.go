@@ -0,0 +1,257 @@
+package config_test
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+)
+
+const validYAML = `
+env: dev
+storage:
+  driver: memory
+http_server:
+  addr: ":8082"
+`
+
+// setup points Load at a fresh directory: no CONFIG_PATH, DefaultPaths
+// inside dir, cwd there too, and its log lines dropped.
+func setup(t *testing.T) (dir string) {
+	t.Helper()
+
+	dir = t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("CONFIG_PATH", "")
+
+	defaults := config.DefaultPaths
+	config.DefaultPaths = []string{filepath.Join(dir, "config.yaml")}
+	t.Cleanup(func() { config.DefaultPaths = defaults })
+
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	t.Cleanup(func() { slog.SetDefault(logger) })
+
+	return dir
+}
+
+// write puts content in dir/name and returns the path.
+func write(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare writes the files and sets the variables of the case
+		prepare func(t *testing.T, dir string)
+		err     error  // errors.Is target, nil when Load succeeds
+		errText string // part of the message
+		check   func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name:    "missing path",
+			prepare: func(t *testing.T, dir string) {},
+			err:     config.ErrPathNotSet,
+		},
+		{
+			name: "missing file",
+			prepare: func(t *testing.T, dir string) {
+				// an explicit path wins over an existing default file
+				write(t, dir, "config.yaml", validYAML)
+				t.Setenv("CONFIG_PATH", filepath.Join(dir, "typo.yaml"))
+			},
+			err:     fs.ErrNotExist,
+			errText: "config file does not exist: ",
+		},
+		{
+			name: "bad YAML",
+			prepare: func(t *testing.T, dir string) {
+				t.Setenv("CONFIG_PATH", write(t, dir, "bad.yaml", "env: [dev\nstorage:\n  driver: memory\n"))
+			},
+			errText: "cannot read config file ",
+		},
+		{
+			name: "invalid value",
+			prepare: func(t *testing.T, dir string) {
+				t.Setenv("CONFIG_PATH", write(t, dir, "moon.yaml", strings.Replace(validYAML, "env: dev", "env: moon", 1)))
+			},
+			err: config.ErrInvalid,
+		},
+		{
+			name: "api key without a role",
+			prepare: func(t *testing.T, dir string) {
+				t.Setenv("CONFIG_PATH", write(t, dir, "students.yaml", validYAML))
+				t.Setenv("API_KEYS", "billing:s3cret,lms:s3cret2")
+				t.Setenv("API_KEY_ROLES", "lms:teacher")
+			},
+			err:     config.ErrInvalid,
+			errText: `the api key "billing" has no role`,
+		},
+		{
+			name: "api keys with roles",
+			prepare: func(t *testing.T, dir string) {
+				t.Setenv("CONFIG_PATH", write(t, dir, "students.yaml", validYAML))
+				t.Setenv("API_KEYS", "billing:s3cret,lms:s3cret2")
+				t.Setenv("API_KEY_ROLES", "billing:admin,lms:teacher")
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Auth.APIKeyRoles["billing"] != config.RoleAdmin || cfg.Auth.APIKeyRoles["lms"] != config.RoleTeacher {
+					t.Errorf("api key roles %v", cfg.Auth.APIKeyRoles)
+				}
+			},
+		},
+		{
+			name: "CONFIG_PATH",
+			prepare: func(t *testing.T, dir string) {
+				t.Setenv("CONFIG_PATH", write(t, dir, "students.yaml", validYAML))
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Env != "dev" || cfg.Storage.Driver != "memory" || cfg.HTTPServer.Addr != ":8082" {
+					t.Errorf("loaded %s, %s, %s", cfg.Env, cfg.Storage.Driver, cfg.HTTPServer.Addr)
+				}
+			},
+		},
+		{
+			name: "default location",
+			prepare: func(t *testing.T, dir string) {
+				write(t, dir, "config.yaml", validYAML)
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.HTTPServer.Addr != ":8082" {
+					t.Errorf("addr %q, want the file's :8082", cfg.HTTPServer.Addr)
+				}
+			},
+		},
+		{
+			name: "env overrides the file and the defaults",
+			prepare: func(t *testing.T, dir string) {
+				t.Setenv("CONFIG_PATH", write(t, dir, "students.yaml", validYAML+"log:\n  level: warn\n"))
+				t.Setenv("HTTP_SERVER_ADDR", ":9090")
+				t.Setenv("LOG_LEVEL", "debug")
+				t.Setenv("CORS_MAX_AGE", "60")
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.HTTPServer.Addr != ":9090" || cfg.Log.Level != "debug" || cfg.CORS.MaxAge != 60 {
+					t.Errorf("addr %q, log level %q, CORS max age %d; want the variables' :9090, debug, 60",
+						cfg.HTTPServer.Addr, cfg.Log.Level, cfg.CORS.MaxAge)
+				}
+				// not overridden: the file, then the env-default
+				if cfg.Storage.Driver != "memory" || cfg.HTTPServer.ReadTimeout != 10*time.Second {
+					t.Errorf("driver %q, read timeout %s; want memory, 10s", cfg.Storage.Driver, cfg.HTTPServer.ReadTimeout)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setup(t)
+			tt.prepare(t, dir)
+
+			cfg, err := config.Load()
+			if tt.err == nil && tt.errText == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				tt.check(t, cfg)
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Load succeeded, want an error")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("error %v, want one wrapping %v", err, tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("error %q, want it to contain %q", err, tt.errText)
+			}
+		})
+	}
+}
+
+// TestLoadEnvOnly checks CONFIG_PATH=env reads the environment alone: the
+// files Load would otherwise pick are ignored, so a setting only they hold
+// falls back to its env-default, and a required one (the address) fails.
+func TestLoadEnvOnly(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		addr  string // "" when Load must fail
+		check func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "environment only",
+			env:  map[string]string{"ENV": "dev", "STORAGE_DRIVER": "memory", "HTTP_SERVER_ADDR": ":9090", "LOG_LEVEL": "debug"},
+			addr: ":9090",
+			check: func(t *testing.T, cfg *config.Config) {
+				if cfg.Env != "dev" || cfg.Storage.Driver != "memory" || cfg.Log.Level != "debug" {
+					t.Errorf("loaded %s, %s, %s; want the variables' dev, memory, debug", cfg.Env, cfg.Storage.Driver, cfg.Log.Level)
+				}
+				// the file says 30 and 30s; the env-defaults win here
+				if cfg.CORS.MaxAge != 600 || cfg.HTTPServer.ReadTimeout != 10*time.Second {
+					t.Errorf("CORS max age %d, read timeout %s; want the env-defaults 600, 10s", cfg.CORS.MaxAge, cfg.HTTPServer.ReadTimeout)
+				}
+			},
+		},
+		{
+			name: "required setting only in the files",
+			env:  map[string]string{"ENV": "dev", "STORAGE_DRIVER": "memory"},
+		},
+	}
+
+	// what Load would read without CONFIG_PATH=env
+	const fileYAML = `
+env: staging
+storage:
+  driver: memory
+http_server:
+  addr: ":8082"
+  read_timeout: 30s
+cors:
+  max_age: 30
+`
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setup(t)
+			write(t, dir, "config.yaml", fileYAML)
+			t.Setenv("CONFIG_PATH", config.EnvOnly)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load()
+			if tt.addr == "" {
+				if err == nil {
+					t.Fatalf("Load succeeded with addr %q, want an error", cfg.HTTPServer.Addr)
+				}
+				if !strings.Contains(err.Error(), `"Addr" is required`) {
+					t.Errorf("error %q, want the missing Addr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.HTTPServer.Addr != tt.addr {
+				t.Errorf("addr %q, want %q", cfg.HTTPServer.Addr, tt.addr)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package events // events package publishes student changes inside the process (SSE streams, webhooks)
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - encoding/json → payloads are encoded once, for every subscriber
+   - log/slog      → unencodable payloads, dropped slow subscribers
+   - sync          → the Hub is shared by every request
+*/
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+)
+
+// Event types, published by NotifyStorage.
+const (
+	StudentCreated  = "student.created"
+	StudentUpdated  = "student.updated"
+	StudentDeleted  = "student.deleted"
+	StudentRestored = "student.restored"
+)
+
+// Publisher receives every change; Publish must not block the request
+// that made the change.
+type Publisher interface {
+	Publish(eventType string, data any)
+}
+
+const (
+	// historySize is how many past events a reconnecting stream can
+	// catch up on (Last-Event-ID).
+	historySize = 256
+
+	// subscriberBuffer is how far a subscriber may fall behind before
+	// the Hub drops it.
+	subscriberBuffer = 64
+)
+
+/*
+Event STRUCT
+-------------------------------------------------------------
+  - One change, as delivered to subscribers.
+  - ID   → increments by one per event, starting at 1 when the
+           process starts (so it is only meaningful for resuming
+           a stream on the same instance)
+  - Type → one of the Student* types
+  - Data → the student, already JSON-encoded
+*/
+type Event struct {
+	ID   uint64
+	Type string
+	Data json.RawMessage
+}
+
+/*
+Hub STRUCT
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → In-process pub/sub: Publish fans every event out to the
+	    current subscribers and keeps the last historySize events
+	    for the ones that resume.
+
+	RULES:
+	  → Publish never blocks: a subscriber whose buffer is full is
+	    dropped (its channel closed). It reconnects with
+	    Last-Event-ID and catches up from the history.
+	  → Close ends every subscription, for graceful shutdown; a
+	    Subscribe after it gets an already closed channel.
+*/
+type Hub struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []Event
+	subs    map[*Subscription]struct{}
+	closed  bool
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription is one subscriber of a Hub.
+type Subscription struct {
+	hub *Hub
+
+	// Backlog holds the events after the requested Last-Event-ID, oldest
+	// first; C delivers everything published after them.
+	Backlog []Event
+	C       <-chan Event
+
+	ch chan Event
+}
+
+// Publish implements Publisher.
+func (h *Hub) Publish(eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("event cannot be encoded", slog.String("event", eventType), slog.String("error", err.Error()))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.lastID++
+	event := Event{ID: h.lastID, Type: eventType, Data: encoded}
+
+	h.history = append(h.history, event)
+	if len(h.history) > historySize {
+		h.history = h.history[len(h.history)-historySize:]
+	}
+
+	for sub := range h.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			slog.Warn("event subscriber too slow, dropped", slog.Uint64("event_id", event.ID))
+			h.remove(sub)
+		}
+	}
+}
+
+/*
+Subscribe()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Registers a subscriber. With resume set, Backlog holds the
+	    events still in the history with an ID above lastID, and
+	    the snapshot and registration happen under one lock, so no
+	    event falls between them.
+	  → Always Close the Subscription (defer) when done.
+*/
+func (h *Hub) Subscribe(lastID uint64, resume bool) *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{hub: h, C: ch, ch: ch}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(ch)
+		return sub
+	}
+
+	if resume {
+		for _, event := range h.history {
+			if event.ID > lastID {
+				sub.Backlog = append(sub.Backlog, event)
+			}
+		}
+	}
+
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close unsubscribes; safe to call more than once, and after Hub.Close.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+// Close ends every subscription and stops accepting events.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subs {
+		h.remove(sub)
+	}
+}
+
+// remove closes sub's channel exactly once. h.mu must be held.
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
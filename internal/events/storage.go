@@ -0,0 +1,179 @@
+package events
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → reads for event payloads outlive the request
+   - log/slog → a payload that cannot be read is logged, not fatal
+   - time     → timeout of those reads
+   - storage  → the Storage interface we decorate
+   - types    → Student / StudentPatch
+*/
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// payloadTimeout bounds the read of a changed student for its event.
+const payloadTimeout = 5 * time.Second
+
+/*
+NotifyStorage()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Wraps a storage.Storage so every successful change of a
+	    student is published to every publisher (the SSE Hub,
+	    webhooks). Handlers stay unaware of them, like they are of
+	    metrics.
+	  → Payloads are the student read back after the change, so
+	    they carry the new version and timestamps; a delete sends
+	    the student as it was just before.
+	  → Reads and PurgeDeletedStudents (rows already deleted)
+	    pass straight through.
+*/
+func NotifyStorage(next storage.Storage, publishers ...Publisher) storage.Storage {
+	return &notifyingStorage{Storage: next, publishers: publishers}
+}
+
+type notifyingStorage struct {
+	storage.Storage
+	publishers []Publisher
+}
+
+func (s *notifyingStorage) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+	id, err := s.Storage.CreateStudent(ctx, student)
+	if err == nil {
+		s.publish(ctx, StudentCreated, id)
+	}
+	return id, err
+}
+
+func (s *notifyingStorage) CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]storage.BulkResult, error) {
+	results, err := s.Storage.CreateStudents(ctx, students, atomic)
+	if err == nil {
+		for _, result := range results {
+			if result.Err == nil {
+				s.publish(ctx, StudentCreated, result.Id)
+			}
+		}
+	}
+	return results, err
+}
+
+func (s *notifyingStorage) UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error) {
+	id, created, err := s.Storage.UpsertStudent(ctx, student)
+	if err == nil {
+		eventType := StudentUpdated
+		if created {
+			eventType = StudentCreated
+		}
+		s.publish(ctx, eventType, id)
+	}
+	return id, created, err
+}
+
+func (s *notifyingStorage) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+	updated, err := s.Storage.UpdateStudent(ctx, id, student, version)
+	if updated && err == nil {
+		s.publish(ctx, StudentUpdated, id)
+	}
+	return updated, err
+}
+
+func (s *notifyingStorage) PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error) {
+	updated, err := s.Storage.PatchStudent(ctx, id, patch, version)
+	if updated && err == nil {
+		s.publish(ctx, StudentUpdated, id)
+	}
+	return updated, err
+}
+
+// SetStudentStatus is published as an update: the payload carries the new
+// status.
+func (s *notifyingStorage) SetStudentStatus(ctx context.Context, id int64, status string) (bool, error) {
+	changed, err := s.Storage.SetStudentStatus(ctx, id, status)
+	if changed && err == nil {
+		s.publish(ctx, StudentUpdated, id)
+	}
+	return changed, err
+}
+
+// AddStudentTag and RemoveStudentTag are published as updates when they
+// changed the tags.
+func (s *notifyingStorage) AddStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	added, err := s.Storage.AddStudentTag(ctx, id, tag)
+	if added && err == nil {
+		s.publish(ctx, StudentUpdated, id)
+	}
+	return added, err
+}
+
+func (s *notifyingStorage) RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	removed, err := s.Storage.RemoveStudentTag(ctx, id, tag)
+	if removed && err == nil {
+		s.publish(ctx, StudentUpdated, id)
+	}
+	return removed, err
+}
+
+func (s *notifyingStorage) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+	// Read first: a soft-deleted student can't be fetched afterwards
+	before, readErr := s.Storage.GetStudentById(ctx, id)
+
+	deleted, err := s.Storage.DeleteStudent(ctx, id)
+	if deleted && err == nil {
+		if readErr != nil {
+			slog.Error("webhook payload unavailable", slog.String("event", StudentDeleted), slog.Int64("id", id), slog.String("error", readErr.Error()))
+		} else {
+			s.publishAll(StudentDeleted, before)
+		}
+	}
+	return deleted, err
+}
+
+// DeleteStudents publishes a "deleted" event per student, with the
+// snapshots storage returns (taken just before the delete).
+func (s *notifyingStorage) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	deleted, err := s.Storage.DeleteStudents(ctx, filter)
+	if err == nil {
+		for _, student := range deleted {
+			s.publishAll(StudentDeleted, student)
+		}
+	}
+	return deleted, err
+}
+
+func (s *notifyingStorage) RestoreStudent(ctx context.Context, id int64) (bool, error) {
+	restored, err := s.Storage.RestoreStudent(ctx, id)
+	if restored && err == nil {
+		s.publish(ctx, StudentRestored, id)
+	}
+	return restored, err
+}
+
+// publish reads student id back and publishes it as eventType. The write
+// already happened, so the read must not fail because the client went away.
+func (s *notifyingStorage) publish(ctx context.Context, eventType string, id int64) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), payloadTimeout)
+	defer cancel()
+
+	student, err := s.Storage.GetStudentById(ctx, id)
+	if err != nil {
+		slog.Error("webhook payload unavailable", slog.String("event", eventType), slog.Int64("id", id), slog.String("error", err.Error()))
+		return
+	}
+
+	s.publishAll(eventType, student)
+}
+
+func (s *notifyingStorage) publishAll(eventType string, student types.Student) {
+	for _, publisher := range s.publishers {
+		publisher.Publish(eventType, student)
+	}
+}
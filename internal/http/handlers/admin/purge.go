@@ -0,0 +1,88 @@
+package admin // admin package holds the maintenance endpoints under /api/admin
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → a purge cut short by the request deadline
+   - errors   → errors.Is on that deadline
+   - fmt      → the message of a partial purge
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+   - time     → the retention cut-off
+
+   - config   → the "purge" config section (retention, batch size)
+   - logging  → request-scoped logger (carries request_id)
+   - storage  → PurgeInBatches
+   - response → custom helper for sending JSON responses
+*/
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+PurgeResult STRUCT
+-------------------------------------------------------------
+  - Body of POST /api/admin/purge.
+  - Purged is how many students were removed for good;
+    DeletedBefore the cut-off: now minus purge.retention.
+*/
+type PurgeResult struct {
+	Purged        int64     `json:"purged"`
+	DeletedBefore time.Time `json:"deleted_before"`
+}
+
+/*
+Purge()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "POST /api/admin/purge":
+	    permanently removes the students soft-deleted longer ago
+	    than purge.retention, right now, what the purge job does
+	    every purge.interval when purge.enabled.
+	  → No body. The students go in batches of purge.batch_size
+	    (see storage.PurgeInBatches); their audit trail stays.
+
+	RESPONSES:
+	  → 200 with PurgeResult, purged 0 when nothing was due
+	  → 503 when the request deadline hit between two batches:
+	    the batches before it are gone, calling again purges the
+	    rest
+	  → 500 if storage fails
+*/
+func Purge(store storage.Storage, cfg config.Purge) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := logging.FromContext(r.Context())
+		before := time.Now().UTC().Add(-cfg.Retention)
+
+		purged, err := storage.PurgeInBatches(r.Context(), store, before, cfg.BatchSize)
+		if purged > 0 {
+			logger.Info("purged soft-deleted students", slog.Int64("count", purged), slog.Time("deleted_before", before))
+		}
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger.Warn("purge timed out", slog.Int64("purged", purged))
+			response.WriteJson(w, http.StatusServiceUnavailable,
+				response.Timeout(fmt.Sprintf("request timed out after purging %d students; call again for the rest", purged)))
+			return
+		}
+		if err != nil {
+			logger.Error("purge failed", slog.Int64("purged", purged), slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, PurgeResult{Purged: purged, DeletedBefore: before})
+	}
+}
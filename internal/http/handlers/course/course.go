@@ -0,0 +1,388 @@
+package course // course package serves the CRUD endpoints of the courses students enroll in
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context       → timeouts of the storage calls
+   - encoding/json → request bodies
+   - errors        → match storage errors with errors.Is
+   - fmt           → formatting messages, the Location header
+   - io            → io.EOF, an empty body
+   - log/slog      → structured logging (new standard logger)
+   - net/http      → handlers, status codes
+   - strconv       → parse {id}, ?limit=, ?offset=
+   - logging       → request-scoped logger (carries request_id)
+   - storage       → Storage interface the handlers persist through
+   - types         → Course struct
+   - response      → JSON bodies, the same ones as the student routes
+   - validation    → shared validator instance (json field names)
+   - validator/v10 → ValidationErrors type for readable messages
+*/
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// Pagination of GET /api/courses, same defaults as the student list.
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+/*
+New()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "POST /api/courses".
+	  → Body is {"code","title","capacity"}; the code is stored
+	    without whitespace and upper-cased ("cs 101" → "CS101").
+
+	RESPONSES:
+	  → 201, Location, the stored course
+	  → 409 when another course has the code
+	  → 400 on an invalid body
+*/
+func New(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: decode, normalize, validate
+		course, ok := decode(w, r)
+		if !ok {
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("creating a course", slog.String("code", course.Code))
+
+		// STEP 2: persist it
+		id, err := storage.CreateCourse(r.Context(), course)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		// STEP 3: send back what is stored
+		course, err = storage.GetCourseById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		w.Header().Set("Location", fmt.Sprintf("%s/%d", r.URL.Path, id))
+		response.WriteJson(w, http.StatusCreated, course)
+	}
+}
+
+/*
+GetById()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/courses/{id}":
+	    the course, with "enrolled", how many seats are taken.
+
+	RESPONSES:
+	  → 200 with the course
+	  → 404 when no course has this ID
+	  → 400 when {id} is malformed
+*/
+func GetById(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		course, err := storage.GetCourseById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, course)
+	}
+}
+
+/*
+GetList()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/courses": one
+	    page of the courses ordered by ID, with the same
+	    ?limit= / ?offset= and "meta" as the student list.
+
+	RESPONSES:
+	  → 200 {"data":[…], "meta":{"total","limit","offset"}}
+	  → 400 on a malformed limit / offset
+*/
+func GetList(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which page
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+
+		// STEP 2: the page and the total
+		courses, err := storage.ListCourses(r.Context(), limit, offset)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		total, err := storage.CountCourses(r.Context())
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		// a nil slice encodes as null → always send []
+		if courses == nil {
+			courses = []types.Course{}
+		}
+
+		response.WriteJsonWithMeta(w, http.StatusOK, courses, response.Meta{Total: total, Limit: limit, Offset: offset})
+	}
+}
+
+/*
+Update()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "PUT /api/courses/{id}":
+	    replaces the code, title and capacity.
+
+	RESPONSES:
+	  → 200 with the updated course
+	  → 409 when another course has the code, or when the new
+	    capacity is below the students already enrolled
+	  → 404 when no course has this ID
+	  → 400 when {id} or the body is malformed
+*/
+func Update(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which course, which values
+		id, err := parseID(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		course, ok := decode(w, r)
+		if !ok {
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("updating a course", slog.Int64("id", id))
+
+		// STEP 2: storage checks the capacity against the enrollments
+		updated, err := storage.UpdateCourse(r.Context(), id, course)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !updated {
+			writeNotFound(w, id)
+			return
+		}
+
+		// STEP 3: send back the course as it is now
+		course, err = storage.GetCourseById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, course)
+	}
+}
+
+/*
+Delete()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "DELETE /api/courses/{id}".
+	  → A hard delete, unlike students: the course and every
+	    enrollment in it are gone.
+
+	RESPONSES:
+	  → 204 (no body) when the course was deleted
+	  → 404 when no course has this ID
+	  → 400 when {id} is malformed
+*/
+func Delete(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("deleting a course", slog.Int64("id", id))
+
+		deleted, err := storage.DeleteCourse(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !deleted {
+			writeNotFound(w, id)
+			return
+		}
+
+		response.WriteNoContent(w)
+	}
+}
+
+/*
+writeStorageError()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Translates a storage error into the right HTTP response,
+	    like the student package's function of the same name.
+
+	MAPPING:
+	  - storage.ErrCourseNotFound        → 404 (code "not_found")
+	  - storage.ErrDuplicateCourseCode,
+	    storage.ErrCapacityBelowEnrolled → 409 (code "conflict")
+	  - context.DeadlineExceeded         → 503 (code "timeout")
+	  - anything else                    → 500 (code "internal"),
+	    the real error is only logged
+*/
+func writeStorageError(w http.ResponseWriter, r *http.Request, id int64, err error) {
+	switch {
+	case errors.Is(err, storage.ErrCourseNotFound):
+		writeNotFound(w, id)
+
+	case errors.Is(err, storage.ErrDuplicateCourseCode), errors.Is(err, storage.ErrCapacityBelowEnrolled):
+		response.WriteJson(w, http.StatusConflict, response.Conflict(err.Error()))
+
+	case errors.Is(err, context.DeadlineExceeded):
+		logging.FromContext(r.Context()).Warn("storage call timed out", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusServiceUnavailable, response.Timeout("request timed out"))
+
+	default:
+		logging.FromContext(r.Context()).Error("storage error", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+	}
+}
+
+// writeNotFound sends the 404 of a course ID that matches nothing.
+func writeNotFound(w http.ResponseWriter, id int64) {
+	response.WriteJson(w, http.StatusNotFound, response.NotFound(fmt.Sprintf("course with id %d not found", id)))
+}
+
+// parseID reads {id} from the path: a positive integer.
+func parseID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid id %q", r.PathValue("id"))
+	}
+
+	return id, nil
+}
+
+// parsePagination reads ?limit= (default defaultLimit, capped at maxLimit)
+// and ?offset= (default 0), with the student list's rules.
+func parsePagination(r *http.Request) (int, int, error) {
+	limit := defaultLimit
+	offset := 0
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+		limit = min(n, maxLimit)
+	}
+
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
+
+/*
+decode()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads a course body, puts it in stored form
+	    (Course.Normalize) and validates it; on failure the 400
+	    (413 for a body over the limit) is written and false
+	    returned.
+	  → Unknown keys are rejected like on the student routes;
+	    id, enrolled and the timestamps are accepted but ignored,
+	    storage assigns them.
+*/
+func decode(w http.ResponseWriter, r *http.Request) (types.Course, bool) {
+	var course types.Course
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&course); err != nil {
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return types.Course{}, false
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.WriteJson(w, http.StatusRequestEntityTooLarge, response.GeneralError(
+				fmt.Errorf("request body must not be larger than %d bytes", maxBytesErr.Limit),
+			))
+			return types.Course{}, false
+		}
+
+		// {"capacity": 20.5} → "capacity must be an integer"
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			response.WriteJson(w, http.StatusBadRequest, response.TypeError(typeErr))
+			return types.Course{}, false
+		}
+
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid course body: %w", err)))
+		return types.Course{}, false
+	}
+
+	course.Normalize()
+
+	if err := validation.Struct(course); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+		return types.Course{}, false
+	}
+
+	return course, true
+}
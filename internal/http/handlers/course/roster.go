@@ -0,0 +1,151 @@
+package course
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - fmt      → formatting messages
+   - net/http → handlers, status codes
+   - slices   → check ?sort= / ?include= against the known values
+   - strings  → split ?include=, the "-" of a descending ?sort=
+   - time     → enrolled_at
+*/
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// includeEnrollment is the ?include= value that adds "enrollment" to each
+// roster entry; the only one there is so far.
+const includeEnrollment = "enrollment"
+
+// rosterEntry is one student of GET /api/courses/{id}/students: the
+// student as on the student routes, plus "enrollment" when asked for.
+type rosterEntry struct {
+	types.Student
+	Enrollment *rosterEnrollment `json:"enrollment,omitempty"`
+}
+
+// rosterEnrollment is the "enrollment" of a roster entry.
+type rosterEnrollment struct {
+	EnrolledAt time.Time `json:"enrolled_at"`
+}
+
+/*
+Students()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "GET /api/courses/{id}/students": one page of the live
+	    students enrolled in the course.
+	  → Storage reads the page in one JOIN; "total" is the
+	    course's "enrolled".
+
+	QUERY PARAMETERS:
+	  - limit / offset → same rules as GET /api/courses
+	  - sort           → id (default), name, email or
+	                     enrolled_at; a leading "-" reverses it
+	                     ("-enrolled_at" = most recent first)
+	  - include        → "enrollment" adds
+	                     "enrollment":{"enrolled_at"} to each entry
+
+	RESPONSES:
+	  → 200 {"data":[…], "meta":{"total","limit","offset"}}
+	  → 404 when no course has this ID
+	  → 400 when {id}, limit, offset, sort or include is malformed
+*/
+func Students(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which course, which page, in which order
+		id, err := parseID(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+
+		sort, err := parseRosterSort(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		withEnrollment, err := parseInclude(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// STEP 2: the course (404, and the total), then the page
+		course, err := storage.GetCourseById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		roster, err := storage.ListCourseStudents(r.Context(), id, sort, limit, offset)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		// STEP 3: the entries, never null
+		entries := make([]rosterEntry, 0, len(roster))
+		for _, student := range roster {
+			entry := rosterEntry{Student: student.Student}
+			if withEnrollment {
+				entry.Enrollment = &rosterEnrollment{EnrolledAt: student.EnrolledAt}
+			}
+			entries = append(entries, entry)
+		}
+
+		response.WriteJsonWithMeta(w, http.StatusOK, entries, response.Meta{Total: course.Enrolled, Limit: limit, Offset: offset})
+	}
+}
+
+// parseRosterSort reads ?sort=: one of types.RosterSorts, descending with a
+// leading "-". Defaults to ascending ID.
+func parseRosterSort(r *http.Request) (types.RosterSort, error) {
+	raw := r.URL.Query().Get("sort")
+	if raw == "" {
+		return types.RosterSort{Field: types.RosterSortID}, nil
+	}
+
+	field, desc := strings.CutPrefix(raw, "-")
+	if !slices.Contains(types.RosterSorts, field) {
+		return types.RosterSort{}, fmt.Errorf("sort must be one of %s, optionally prefixed with -", strings.Join(types.RosterSorts, ", "))
+	}
+
+	return types.RosterSort{Field: field, Desc: desc}, nil
+}
+
+// parseInclude reads ?include=, a comma-separated list, and reports whether
+// it asks for the enrollment. Unknown values are rejected, not ignored.
+func parseInclude(r *http.Request) (bool, error) {
+	raw := r.URL.Query().Get("include")
+	if raw == "" {
+		return false, nil
+	}
+
+	for _, value := range strings.Split(raw, ",") {
+		if strings.TrimSpace(value) != includeEnrollment {
+			return false, fmt.Errorf("include must be %q", includeEnrollment)
+		}
+	}
+
+	return true, nil
+}
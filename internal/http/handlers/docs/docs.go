@@ -0,0 +1,62 @@
+package docs // docs package serves the OpenAPI contract of the API and a Swagger UI page for it
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - embed    → openapi.json is compiled into the binary
+   - net/http → handlers
+*/
+import (
+	_ "embed"
+	"net/http"
+)
+
+/*
+openapi.json
+-------------------------------------------------------------
+  - Hand-maintained OpenAPI 3 document: every route, its
+    parameters, the student schemas and the error shapes.
+  - Update it together with types.Student, the handlers' query
+    parameters and response.Response: nothing generates it.
+*/
+//go:embed openapi.json
+var spec []byte
+
+// SpecPath is where Spec is mounted; the Swagger UI page loads it from there.
+const SpecPath = "/api/openapi.json"
+
+// Spec serves the embedded OpenAPI document.
+func Spec() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
+
+// uiPage renders Swagger UI from its published bundle, so no assets need
+// vendoring; the browser needs access to unpkg.com.
+const uiPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Students API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => { window.ui = SwaggerUIBundle({ url: "` + SpecPath + `", dom_id: "#swagger-ui" }); };
+  </script>
+</body>
+</html>
+`
+
+// UI serves the Swagger UI page for the spec.
+func UI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(uiPage))
+	}
+}
@@ -0,0 +1,100 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/docs"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// schema is the part of an OpenAPI object schema the test compares.
+type schema struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// served is the document Spec serves.
+func served(t *testing.T) map[string]schema {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	docs.Spec()(rec, httptest.NewRequest(http.MethodGet, docs.SpecPath, nil))
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]schema `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+
+	return doc.Components.Schemas
+}
+
+// jsonFields is the JSON names of v's fields, and the ones always written
+// (no omitempty).
+func jsonFields(v any) (names, always []string) {
+	typ := reflect.TypeOf(v)
+	for i := range typ.NumField() {
+		tag := typ.Field(i).Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" || name == "" {
+			continue
+		}
+		names = append(names, name)
+		if !strings.Contains(opts, "omitempty") {
+			always = append(always, name)
+		}
+	}
+
+	return names, always
+}
+
+// TestSchemasMatchTypes checks the hand-maintained schemas against the
+// structs the handlers write: the same property names as the json tags,
+// and the fields without omitempty listed as required.
+func TestSchemasMatchTypes(t *testing.T) {
+	schemas := served(t)
+
+	tests := []struct {
+		schema string
+		typ    any
+	}{
+		{"Student", types.Student{}},
+		{"Course", types.Course{}},
+		{"StudentStats", types.StudentStats{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			s, ok := schemas[tt.schema]
+			if !ok {
+				t.Fatalf("no %s schema", tt.schema)
+			}
+
+			names, always := jsonFields(tt.typ)
+			props := make([]string, 0, len(s.Properties))
+			for name := range s.Properties {
+				props = append(props, name)
+			}
+			slices.Sort(names)
+			slices.Sort(props)
+			if !slices.Equal(props, names) {
+				t.Errorf("properties %v, want the json tags %v", props, names)
+			}
+
+			required := slices.Sorted(slices.Values(s.Required))
+			slices.Sort(always)
+			if !slices.Equal(required, always) {
+				t.Errorf("required %v, want the fields without omitempty %v", required, always)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package health // health package holds the liveness and readiness probe endpoints
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context     → short timeout for the storage ping
+   - errors      → fixed, client-safe readiness failure reasons
+   - slog        → structured logging (new standard logger)
+   - net/http    → for HTTP handler, status codes
+   - sync/atomic → shutdown flag shared with main
+   - time        → uptime since the process started
+
+   - buildinfo   → version / commit reported by /health
+   - logging     → request-scoped logger (carries request_id)
+   - storage     → Storage interface we ping for readiness
+   - response    → custom helper for sending JSON responses
+*/
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Status STRUCT
+-------------------------------------------------------------
+  - Body of GET /health.
+  - Uptime is a Go duration string such as "3h12m5s".
+  - version, commit and build_time come from buildinfo.
+*/
+type Status struct {
+	Status string `json:"status"`
+	Uptime string `json:"uptime"`
+	buildinfo.Info
+}
+
+/*
+New()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Liveness probe: answers 200 as long as the process can
+	    serve HTTP at all.
+	  → Touches nothing else (no storage, no auth), so it stays
+	    cheap enough to be called every few seconds.
+
+	PARAMETERS:
+	  - startedAt → captured once in main, when the process started
+	  - build     → the build reported to whoever is probing
+*/
+func New(startedAt time.Time, build buildinfo.Info) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, Status{
+			Status: response.StatusOk,
+			Uptime: time.Since(startedAt).Round(time.Second).String(),
+			Info:   build,
+		})
+	}
+}
+
+// readyTimeout bounds the storage ping so a hung database makes the probe
+// fail fast instead of piling up probe requests.
+const readyTimeout = 2 * time.Second
+
+var (
+	errShuttingDown       = errors.New("shutting down")
+	errStorageUnavailable = errors.New("storage unavailable")
+)
+
+/*
+Ready()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Readiness probe: 200 only when this instance should get
+	    traffic, 503 otherwise.
+
+	NOT READY WHEN:
+	  → shuttingDown is set (main flips it before Shutdown, so the
+	    load balancer drains us while requests still succeed)
+	  → storage.Ping fails or takes longer than readyTimeout
+
+	NOTE:
+	  → The client only sees a fixed reason; the real ping error
+	    (file paths, driver details) goes to the log.
+*/
+func Ready(storage storage.Storage, shuttingDown *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if shuttingDown.Load() {
+			response.WriteJson(w, http.StatusServiceUnavailable, response.GeneralError(errShuttingDown))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+
+		if err := storage.Ping(ctx); err != nil {
+			logging.FromContext(r.Context()).Warn("readiness check failed", slog.String("error", err.Error()))
+			response.WriteJson(w, http.StatusServiceUnavailable, response.GeneralError(errStorageUnavailable))
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, map[string]string{"status": response.StatusOk})
+	}
+}
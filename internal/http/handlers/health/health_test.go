@@ -0,0 +1,109 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/buildinfo"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/health"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+)
+
+// TestHealth checks the /health body probes and dashboards parse: status,
+// the uptime rounded to the second, and the build, whose commit and time
+// are left out when unknown.
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name  string
+		build buildinfo.Info
+		keys  []string
+	}{
+		{
+			"release build", buildinfo.Info{Version: "v1.4.0", Commit: "3f2c1ab", BuildTime: "2026-10-01T12:00:00Z"},
+			[]string{"build_time", "commit", "status", "uptime", "version"},
+		},
+		{"dev build", buildinfo.Info{Version: "dev"}, []string{"status", "uptime", "version"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := health.New(time.Now().Add(-90*time.Second-200*time.Millisecond), tt.build)
+			rec := httptest.NewRecorder()
+			h(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("status %d, Content-Type %q; want 200 JSON", rec.Code, rec.Header().Get("Content-Type"))
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %s: %v", rec.Body, err)
+			}
+			if keys := slices.Sorted(maps.Keys(body)); !slices.Equal(keys, tt.keys) {
+				t.Errorf("keys %v, want %v", keys, tt.keys)
+			}
+			if body["status"] != "OK" || body["uptime"] != "1m30s" || body["version"] != tt.build.Version ||
+				body["commit"] != tt.build.Commit || body["build_time"] != tt.build.BuildTime {
+				t.Errorf("body %v", body)
+			}
+		})
+	}
+}
+
+// failingPing is a store whose database is unreachable.
+type failingPing struct{ storage.Storage }
+
+func (failingPing) Ping(context.Context) error {
+	return errors.New("dial tcp 10.0.3.7:5432: connection refused")
+}
+
+// TestReady checks /ready is 200 only with storage up and no shutdown
+// started, and that a failure names a fixed reason, never the ping error.
+func TestReady(t *testing.T) {
+	up := memory.New(&config.Config{})
+
+	tests := []struct {
+		name         string
+		storage      storage.Storage
+		shuttingDown bool
+		status       int
+		want         string
+	}{
+		{"ready", up, false, http.StatusOK, `{"status":"OK"}`},
+		{"shutting down", up, true, http.StatusServiceUnavailable, `{"status":"Error","error":"shutting down"}`},
+		{"storage down", failingPing{up}, false, http.StatusServiceUnavailable, `{"status":"Error","error":"storage unavailable"}`},
+	}
+
+	// the failed ping is logged
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shuttingDown atomic.Bool
+			shuttingDown.Store(tt.shuttingDown)
+
+			rec := httptest.NewRecorder()
+			health.Ready(tt.storage, &shuttingDown)(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package login
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - sync → the counters are shared by every request
+   - time → the failure window
+*/
+import (
+	"sync"
+	"time"
+)
+
+/*
+failures STRUCT
+-------------------------------------------------------------
+  - Counts failed logins per client IP over a fixed window:
+    the first failure opens it, max failures within it block
+    the IP until it is over.
+  - Only failures count, so an IP that logs in normally never
+    gets near the limit, while guessing passwords is capped at
+    max tries per window whatever rate_limit allows.
+  - In memory: a restart (or each instance behind a load
+    balancer) starts from zero, like the memory rate limiter.
+*/
+type failures struct {
+	max    int
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*failureEntry
+}
+
+type failureEntry struct {
+	count int
+	since time.Time
+}
+
+func newFailures(max int, window time.Duration) *failures {
+	return &failures{max: max, window: window, entries: make(map[string]*failureEntry)}
+}
+
+// blocked reports whether key used up its failures, and if so how long
+// until its window is over.
+func (f *failures) blocked(key string) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.entries[key]
+	if !ok {
+		return 0, false
+	}
+
+	left := f.window - time.Since(entry.since)
+	if left <= 0 {
+		delete(f.entries, key)
+		return 0, false
+	}
+
+	return left, entry.count >= f.max
+}
+
+// record counts a failed login of key. Expired windows are dropped on the
+// way, so the map only holds IPs that failed recently.
+func (f *failures) record(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range f.entries {
+		if now.Sub(entry.since) >= f.window {
+			delete(f.entries, k)
+		}
+	}
+
+	entry, ok := f.entries[key]
+	if !ok {
+		entry = &failureEntry{since: now}
+		f.entries[key] = entry
+	}
+	entry.count++
+}
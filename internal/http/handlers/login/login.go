@@ -0,0 +1,250 @@
+package login // login package issues access / refresh tokens to the users of the config
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - encoding/json → request bodies
+   - errors        → fixed, client-safe error messages
+   - log/slog      → failed logins
+   - math          → round Retry-After up to whole seconds
+   - net/http      → handlers, status codes
+   - strconv       → Retry-After header value
+   - sync          → the dummy hash is made once, on first use
+   - time          → token lifetimes in seconds
+   - auth          → token claims, signing and parsing
+   - config        → the "auth" config section (users, TTLs)
+   - middleware    → client IP of the failure counter
+   - logging       → request-scoped logger
+   - response      → JSON bodies
+   - validation    → required fields of the bodies
+   - bcrypt        → password checks
+   - validator/v10 → ValidationErrors type for readable messages
+   - jwt/v5        → the key of a refresh token being parsed
+*/
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/auth"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// One message for an unknown user and a wrong password, so the answer
+// doesn't tell which usernames exist.
+const (
+	msgInvalidCredentials = "invalid username or password"
+	msgInvalidRefresh     = "invalid refresh token"
+)
+
+var errTooManyFailures = errors.New("too many failed logins, retry later")
+
+// dummyHash is compared against when the username is unknown, so that
+// takes as long as a wrong password would.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Credentials is the body of POST /api/auth/login.
+type Credentials struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshRequest is the body of POST /api/auth/refresh.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+/*
+Tokens STRUCT
+-------------------------------------------------------------
+  - Answer of both endpoints, shaped like an OAuth2 token
+    response. ExpiresIn / RefreshExpiresIn are seconds.
+  - The access token goes in Authorization: Bearer <token>;
+    the refresh token only to POST /api/auth/refresh.
+*/
+type Tokens struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
+
+/*
+New()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → POST /api/auth/login: checks {"username","password"}
+	    against auth.users and answers 200 with Tokens, the
+	    access token carrying the user's role.
+
+	ON FAILURE:
+	  → 401 "invalid username or password", the same for an
+	    unknown user (checked against a dummy hash, so it takes
+	    as long) and for a wrong password.
+	  → After auth.login_max_failures failures from one client
+	    IP within auth.login_failure_window, that IP gets
+	    429 + Retry-After until the window is over, without its
+	    password being checked at all.
+
+	PARAMETERS:
+	  - cfg        → the "auth" config section
+	  - trustProxy → rate_limit.trust_proxy: the client IP may
+	                 come from X-Forwarded-For
+*/
+func New(cfg config.Auth, trustProxy bool) http.HandlerFunc {
+	failed := newFailures(cfg.LoginMaxFailures, cfg.LoginFailureWindow)
+	// made now, or the first unknown username would take twice as long
+	dummyHash()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := middleware.ClientIP(r, trustProxy)
+		if retryAfter, blocked := failed.blocked(ip); blocked {
+			w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(retryAfter.Seconds())), 1)))
+			response.WriteJson(w, http.StatusTooManyRequests, response.GeneralError(errTooManyFailures))
+			return
+		}
+
+		var creds Credentials
+		if !decode(w, r, &creds) {
+			return
+		}
+
+		user, known := cfg.Users[creds.Username]
+		hash := []byte(user.PasswordHash)
+		if !known {
+			hash = dummyHash()
+		}
+
+		if err := bcrypt.CompareHashAndPassword(hash, []byte(creds.Password)); err != nil || !known {
+			failed.record(ip)
+			logging.FromContext(r.Context()).Warn("login failed",
+				slog.String("username", creds.Username),
+				slog.String("ip", ip),
+			)
+			response.WriteJson(w, http.StatusUnauthorized, response.Unauthorized(msgInvalidCredentials))
+			return
+		}
+
+		writeTokens(w, r, cfg, creds.Username, user.Role)
+	}
+}
+
+/*
+Refresh()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → POST /api/auth/refresh: trades {"refresh_token"} for a
+	    new pair of tokens, so clients don't have to keep the
+	    password around to stay logged in.
+	  → The role is read from auth.users again: a user removed
+	    (or demoted) in the config gets no (or a lesser) token
+	    after the next restart, however long the refresh token
+	    was valid.
+
+	NOTES:
+	  → Tokens are stateless: the old refresh token stays valid
+	    until it expires; there is nothing to revoke it with
+	    short of changing jwt_secret.
+*/
+func Refresh(cfg config.Auth) http.HandlerFunc {
+	secret := []byte(cfg.JWTSecret)
+	parser := auth.NewParser()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req RefreshRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		var claims auth.Claims
+		_, err := parser.ParseWithClaims(req.RefreshToken, &claims, func(*jwt.Token) (any, error) {
+			return secret, nil
+		})
+		user, known := cfg.Users[claims.Subject]
+		if err != nil || claims.Use != auth.UseRefresh || !known {
+			response.WriteJson(w, http.StatusUnauthorized, response.Unauthorized(msgInvalidRefresh))
+			return
+		}
+
+		writeTokens(w, r, cfg, claims.Subject, user.Role)
+	}
+}
+
+// writeTokens signs a new access / refresh token pair for username and
+// answers 200 with them.
+func writeTokens(w http.ResponseWriter, r *http.Request, cfg config.Auth, username, role string) {
+	secret := []byte(cfg.JWTSecret)
+
+	access, _, err := auth.Sign(secret, username, role, "", cfg.AccessTokenTTL)
+	var refresh string
+	if err == nil {
+		refresh, _, err = auth.Sign(secret, username, "", auth.UseRefresh, cfg.RefreshTokenTTL)
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Error("signing tokens failed", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, Tokens{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        seconds(cfg.AccessTokenTTL),
+		RefreshToken:     refresh,
+		RefreshExpiresIn: seconds(cfg.RefreshTokenTTL),
+	})
+}
+
+func seconds(d time.Duration) int {
+	return int(d / time.Second)
+}
+
+// decode reads the JSON body into dst and validates it; false when a 400
+// (or 413) was already written.
+func decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.WriteJson(w, http.StatusRequestEntityTooLarge, response.GeneralError(err))
+			return false
+		}
+
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			response.WriteJson(w, http.StatusBadRequest, response.TypeError(typeErr))
+			return false
+		}
+
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("body must be a JSON object")))
+		return false
+	}
+
+	if err := validation.Struct(dst); err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.ValidationError(err.(validator.ValidationErrors)))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+*/
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Audit()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students/{id}/audit",
+	    and for "GET /api/students/audit": the summaries of the
+	    operations on many students (bulk deletes), which belong
+	    to no one student.
+	  → Lists who changed the student and how, oldest first, in
+	    the usual paginated envelope (?limit= / ?offset=):
+	      {"data":[{"id":1,"student_id":7,"action":"create","actor":"jane",
+	                "created_at":"…","after":{…}}, …],
+	       "meta":{"total":3,"limit":20,"offset":0}}
+
+	NOTES:
+	  → The history is kept after the student is deleted or even
+	    purged, so the ID is not checked against the students: an
+	    ID without events is an empty list, not a 404. Only a
+	    UUID has to be looked up (storage.IDStore), so the trail
+	    of a purged student is a 404 under id_scheme uuid.
+	  → Cursor pagination is not offered here: a student's trail
+	    stays short.
+*/
+func Audit(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which student (none for the collection), which page
+		id := int64(types.AuditStudentNone)
+		if r.PathValue("id") != "" {
+			parsed, err := parseID(r, storage)
+			if err != nil {
+				writeStorageError(w, r, 0, err)
+				return
+			}
+			id = parsed
+		}
+
+		limit, offset, err := parsePagination(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("getting the audit trail of a student", slog.Int64("id", id))
+
+		// STEP 2: the page and the total
+		events, err := storage.ListAuditEvents(r.Context(), id, limit, offset)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		total, err := storage.CountAuditEvents(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		// STEP 3: a nil slice encodes as null → always send []
+		if events == nil {
+			events = []types.AuditEvent{}
+		}
+		for i := range events {
+			events[i].StudentPublicId = publicID(r, events[i].StudentId)
+		}
+
+		response.WriteJsonWithMeta(w, http.StatusOK, events, response.Meta{Total: total, Limit: limit, Offset: offset})
+	}
+}
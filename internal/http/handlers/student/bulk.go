@@ -0,0 +1,269 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes         → decode every array item on its own
+   - encoding/json → RawMessage keeps the items undecoded at first
+   - errors        → map storage errors per item
+   - fmt           → formatting messages
+   - log/slog      → structured logging (new standard logger)
+   - net/http      → for HTTP handler, status codes
+   - strconv       → parse ?atomic=
+   - strings       → detect the "unknown field" decode error
+*/
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxBulkItems caps one POST /api/students/bulk; larger batches get 413.
+const maxBulkItems = 1000
+
+// Status of one item in a bulk response
+const (
+	bulkCreated = "created"
+	bulkFailed  = "failed"
+)
+
+/*
+bulkItemResult / bulkResponse STRUCTS
+-------------------------------------------------------------
+  - One result per array item, "index" is its position in the
+    request so clients can line results up with their input.
+  - code / error / errors follow the normal error response.
+*/
+type bulkItemResult struct {
+	Index  int                   `json:"index"`
+	Status string                `json:"status"`
+	Id     types.ID              `json:"id,omitempty"`
+	Code   string                `json:"code,omitempty"`
+	Error  string                `json:"error,omitempty"`
+	Errors []response.FieldError `json:"errors,omitempty"`
+}
+
+type bulkResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []bulkItemResult `json:"results"`
+}
+
+/*
+Bulk()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "POST /api/students/bulk".
+	  → Body is a JSON array of students; each one is validated
+	    like a single create, then all valid ones are inserted in
+	    one transaction.
+
+	QUERY PARAMETERS:
+	  - atomic=true → all or nothing: one invalid or conflicting
+	                  item means no student is created
+
+	RESPONSES:
+	  → 201 when every item was created
+	  → 207 Multi-Status otherwise, with one result per item
+	  → 400 if the body is not a JSON array (or is empty)
+	  → 413 if it holds more than maxBulkItems items
+*/
+func Bulk(storage storage.Storage, phoneCountry string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: read ?atomic=
+		atomic := false
+		if raw := r.URL.Query().Get("atomic"); raw != "" {
+			var err error
+			if atomic, err = strconv.ParseBool(raw); err != nil {
+				response.WriteJson(w, http.StatusBadRequest,
+					response.GeneralError(fmt.Errorf("atomic must be true or false")))
+				return
+			}
+		}
+
+		// STEP 2: decode the array, keeping the items raw for now
+		var items []json.RawMessage
+		if !decodeJSON(w, r, &items) {
+			return
+		}
+
+		if len(items) == 0 {
+			response.WriteJson(w, http.StatusBadRequest,
+				response.GeneralError(fmt.Errorf("at least one student is required")))
+			return
+		}
+
+		if len(items) > maxBulkItems {
+			response.WriteJson(w, http.StatusRequestEntityTooLarge,
+				response.GeneralError(fmt.Errorf("at most %d students per request, got %d", maxBulkItems, len(items))))
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("bulk creating students",
+			slog.Int("count", len(items)),
+			slog.Bool("atomic", atomic),
+		)
+
+		// STEP 3: decode + validate every item on its own
+		results := make([]bulkItemResult, len(items))
+		var valid []types.Student
+		var validIdx []int
+
+		for i, raw := range items {
+			results[i].Index = i
+
+			student, failure := decodeBulkItem(raw, phoneCountry)
+			if failure != nil {
+				results[i].Status = bulkFailed
+				results[i].Code = failure.Code
+				results[i].Error = failure.Error
+				results[i].Errors = failure.Errors
+				continue
+			}
+
+			valid = append(valid, student)
+			validIdx = append(validIdx, i)
+		}
+
+		// STEP 4: insert what is valid (nothing at all in atomic mode
+		// when any item was rejected)
+		switch {
+		case atomic && len(valid) < len(items):
+			abortBulk(r, results, validIdx)
+
+		case len(valid) > 0:
+			created, err := storage.CreateStudents(r.Context(), valid, atomic)
+			if err != nil {
+				writeStorageError(w, r, 0, err)
+				return
+			}
+
+			for k, result := range created {
+				i := validIdx[k]
+				if result.Err != nil {
+					setBulkError(r, &results[i], result.Err)
+					continue
+				}
+				results[i].Status = bulkCreated
+				results[i].Id = result.PublicId
+			}
+		}
+
+		// STEP 5: counts + status code
+		body := bulkResponse{Results: results}
+		for _, result := range results {
+			if result.Status == bulkCreated {
+				body.Succeeded++
+			} else {
+				body.Failed++
+			}
+		}
+
+		status := http.StatusCreated
+		if body.Failed > 0 {
+			status = http.StatusMultiStatus
+		}
+
+		response.WriteJson(w, status, body)
+	}
+}
+
+/*
+decodeBulkItem()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → decodeStudent for one array item: same unknown-field
+	    and validation rules, but the failure is returned as a
+	    Response instead of being written, because the other
+	    items still need an answer.
+*/
+func decodeBulkItem(raw json.RawMessage, phoneCountry string) (types.Student, *response.Response) {
+	var student types.Student
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&student); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			failure := response.TypeError(typeErr)
+			return student, &failure
+		}
+
+		msg := err.Error()
+		if field, found := strings.CutPrefix(msg, "json: unknown field "); found {
+			msg = "unknown field " + field
+		}
+
+		failure := response.GeneralError(errors.New(msg))
+		return student, &failure
+	}
+
+	// Assigned by storage, never taken from the body
+	student = types.Student{
+		Name: student.Name, Email: student.Email, Age: student.Age, DateOfBirth: student.DateOfBirth, Phone: student.Phone,
+		Tags: student.Tags, Status: student.Status,
+	}
+	student.Normalize()
+	student.NormalizePhone(phoneCountry)
+
+	if err := validation.Struct(student); err != nil {
+		failure := response.ValidationError(err.(validator.ValidationErrors))
+		return student, &failure
+	}
+
+	return student, nil
+}
+
+// abortBulk marks the valid items of a rejected atomic batch as not created.
+func abortBulk(r *http.Request, results []bulkItemResult, indexes []int) {
+	for _, i := range indexes {
+		setBulkError(r, &results[i], storage.ErrBatchAborted)
+	}
+}
+
+/*
+setBulkError()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → writeStorageError for one bulk item: known storage errors
+	    become their message, anything else is logged and hidden
+	    behind "internal server error".
+*/
+func setBulkError(r *http.Request, result *bulkItemResult, err error) {
+	result.Status = bulkFailed
+
+	switch {
+	case errors.Is(err, storage.ErrDuplicateEmail):
+		result.Code = response.CodeConflict
+		result.Error = err.Error()
+
+	case errors.Is(err, storage.ErrBatchAborted):
+		result.Error = err.Error()
+
+	default:
+		logging.FromContext(r.Context()).Error("storage error",
+			slog.Int("index", result.Index),
+			slog.String("error", err.Error()),
+		)
+		result.Code = response.CodeInternal
+		result.Error = "internal server error"
+	}
+}
@@ -0,0 +1,101 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+   - strconv  → parse ?dry_run=
+*/
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// bulkDeleteResponse is the body of DELETE /api/students: how many students
+// were deleted (or, with dry_run, would be).
+type bulkDeleteResponse struct {
+	DryRun bool `json:"dry_run"`
+	Count  int  `json:"count"`
+}
+
+/*
+DeleteMatching()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "DELETE /api/students"
+	    (the collection): soft-deletes every live student
+	    matching the filter of the query string, the same
+	    parameters as the list (name, email, email_domain,
+	    min_age, max_age), e.g.
+	      DELETE /api/students?max_age=17&email_domain=old.example.com
+	  → One transaction: all of them are deleted or none. Each
+	    gets its "delete" audit event (and webhook), and the
+	    operation a "bulk_delete" summary with the filter and the
+	    count (GET /api/students/audit).
+	  → ?dry_run=true only counts the students it would delete.
+
+	RESPONSES:
+	  → 200 {"dry_run":false,"count":n}, also when n is 0
+	  → 400 without any filter (never "delete everything"), or on
+	    a malformed one / dry_run
+	  → 409 when one of them is enrolled in courses and
+	    storage.on_student_delete is "block": none is deleted
+*/
+func DeleteMatching(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: what to delete, and whether for real
+		filter, err := parseFilter(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+
+		if filter.IsEmpty() {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(
+				"at least one filter (name, email, email_domain, min_age, max_age) is required",
+			))
+			return
+		}
+
+		dryRun := false
+		if raw := r.URL.Query().Get("dry_run"); raw != "" {
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.BadRequest("dry_run must be true or false"))
+				return
+			}
+		}
+
+		// STEP 2: count, or delete
+		if dryRun {
+			count, err := storage.CountStudents(r.Context(), filter)
+			if err != nil {
+				writeStorageError(w, r, 0, err)
+				return
+			}
+
+			response.WriteJson(w, http.StatusOK, bulkDeleteResponse{DryRun: true, Count: count})
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("deleting matching students")
+
+		deleted, err := storage.DeleteStudents(r.Context(), filter)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("students deleted", slog.Int("count", len(deleted)))
+
+		response.WriteJson(w, http.StatusOK, bulkDeleteResponse{Count: len(deleted)})
+	}
+}
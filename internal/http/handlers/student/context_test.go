@@ -0,0 +1,61 @@
+package student_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/app"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// slowStorage is a memory store whose ListStudents only returns once ctx is
+// done, with what ctx.Err() says then, like a query stuck on a lock.
+type slowStorage struct {
+	storage.Storage
+	err chan error
+}
+
+func (s *slowStorage) ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error) {
+	<-ctx.Done()
+	s.err <- ctx.Err()
+
+	return nil, ctx.Err()
+}
+
+// TestRequestContextReachesStorage checks the handler passes the request's
+// context to storage: when http_server.request_timeout runs out, the
+// storage call sees the deadline and the client gets the 503.
+func TestRequestContextReachesStorage(t *testing.T) {
+	cfg := apptest.Config(t)
+	cfg.HTTPServer.RequestTimeout = 50 * time.Millisecond
+
+	slow := &slowStorage{Storage: memory.New(cfg), err: make(chan error, 1)}
+	srv := httptest.NewServer(app.New(cfg, slow).Handler())
+	t.Cleanup(srv.Close)
+
+	res := apptest.Do(t, srv, http.MethodGet, students, nil)
+
+	select {
+	case err := <-slow.err:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("storage saw %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListStudents was never called")
+	}
+
+	if res.Status != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503", res.Status)
+	}
+	if got := string(res.Body); !strings.Contains(got, `"code":"timeout"`) {
+		t.Errorf("body %s, want the timeout code", got)
+	}
+}
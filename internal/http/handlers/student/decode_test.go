@@ -0,0 +1,75 @@
+package student_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+)
+
+// TestUnknownFields checks the bodies are decoded strictly: a key the
+// student has no field for is a 400 naming it, wherever the body is read,
+// instead of being dropped.
+func TestUnknownFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name: "misspelled key", method: http.MethodPost, path: students,
+			body:   `{"name": "Bob Lee", "emial": "bob@example.com", "age": 20}`,
+			status: http.StatusBadRequest,
+			want:   `{"status":"Error","code":"bad_request","error":"unknown field \"emial\""}`,
+		},
+		{
+			name: "extra nested object", method: http.MethodPost, path: students,
+			body:   `{"name": "Bob Lee", "email": "bob@example.com", "age": 20, "address": {"city": "York"}}`,
+			status: http.StatusBadRequest,
+			want:   `{"status":"Error","code":"bad_request","error":"unknown field \"address\""}`,
+		},
+		{
+			name: "misspelled key on update", method: http.MethodPut, path: students + "/1",
+			body:   `{"name": "Ann Lee", "email": "ann@example.com", "agee": 21}`,
+			status: http.StatusBadRequest,
+			want:   `{"status":"Error","code":"bad_request","error":"unknown field \"agee\""}`,
+		},
+		{
+			name: "misspelled key on patch", method: http.MethodPatch, path: students + "/1",
+			body:   `{"nmae": "Ann Other"}`,
+			status: http.StatusBadRequest,
+			want:   `{"status":"Error","code":"bad_request","error":"unknown field \"nmae\""}`,
+		},
+		{
+			name: "extra nested object in a bulk item", method: http.MethodPost, path: students + "/bulk",
+			body:   `[{"name": "Bob Lee", "email": "bob@example.com", "age": 20, "address": {"city": "York"}}]`,
+			status: http.StatusMultiStatus,
+			want:   `{"succeeded":0,"failed":1,"results":[{"index":0,"status":"failed","error":"unknown field \"address\""}]}`,
+		},
+		{
+			name: "correct payload", method: http.MethodPost, path: students,
+			body:   `{"name": "Bob Lee", "email": "bob@example.com", "age": 20}`,
+			status: http.StatusCreated,
+		},
+	}
+
+	srv := apptest.Server(t, apptest.Config(t))
+	createAnn(t, srv)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := apptest.Do(t, srv, tt.method, tt.path, tt.body)
+
+			if res.Status != tt.status {
+				t.Errorf("status %d, want %d (body %s)", res.Status, tt.status, res.Body)
+			}
+			if got := strings.TrimSpace(string(res.Body)); tt.want != "" && got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - fmt      → formatting messages
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+   - strings  → case-insensitive name keys
+*/
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// maxDuplicateChecks caps one POST /api/students/check-duplicates; larger
+// batches get 413. It also keeps the IN lists of the lookup well below the
+// databases' parameter limits.
+const maxDuplicateChecks = 1000
+
+// duplicateCheck is one entry of the request: the student about to be
+// imported.
+type duplicateCheck struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+/*
+duplicateResult / duplicateResponse STRUCTS
+-------------------------------------------------------------
+  - One result per entry, "index" is its position in the
+    request; name / email are the entry in stored form.
+  - exists: a live student has this email (its ID is "id"),
+    so importing the entry would fail with a conflict.
+  - name_match: live students (name_match_ids) have the same
+    name, ignoring case and extra whitespace: maybe the same
+    person under another address, for a human to decide.
+*/
+type duplicateResult struct {
+	Index        int        `json:"index"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Exists       bool       `json:"exists"`
+	Id           types.ID   `json:"id,omitempty"`
+	NameMatch    bool       `json:"name_match"`
+	NameMatchIds []types.ID `json:"name_match_ids,omitempty"`
+}
+
+type duplicateResponse struct {
+	Checked   int               `json:"checked"`
+	Existing  int               `json:"existing"`
+	NameMatch int               `json:"name_matches"`
+	Results   []duplicateResult `json:"results"`
+}
+
+/*
+CheckDuplicates()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "POST /api/students/check-duplicates".
+	  → Body is a JSON array of {"name","email"} (a roster about
+	    to be imported); the answer says, for each entry, whether
+	    a student already has its email and whether others have
+	    its name. Nothing is created.
+
+	HOW IT WORKS:
+	  → All emails and names go to storage in ONE query
+	    (FindStudentsByEmailOrName) instead of a lookup per
+	    entry; the candidates are then matched here.
+
+	RESPONSES:
+	  → 200 with one result per entry
+	  → 400 if the body is not a JSON array (or is empty), or an
+	    entry has neither name nor email
+	  → 413 if it holds more than maxDuplicateChecks entries
+*/
+func CheckDuplicates(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: decode the entries
+		var entries []duplicateCheck
+		if !decodeJSON(w, r, &entries) {
+			return
+		}
+
+		if len(entries) == 0 {
+			response.WriteJson(w, http.StatusBadRequest,
+				response.GeneralError(fmt.Errorf("at least one entry is required")))
+			return
+		}
+
+		if len(entries) > maxDuplicateChecks {
+			response.WriteJson(w, http.StatusRequestEntityTooLarge,
+				response.GeneralError(fmt.Errorf("at most %d entries per request, got %d", maxDuplicateChecks, len(entries))))
+			return
+		}
+
+		// STEP 2: stored form of every entry, each email / name asked once
+		var emails, names []string
+		seen := map[string]bool{}
+
+		for i := range entries {
+			entries[i].Name = types.NormalizeName(entries[i].Name)
+			entries[i].Email = types.NormalizeEmail(entries[i].Email)
+
+			if entries[i].Name == "" && entries[i].Email == "" {
+				response.WriteJson(w, http.StatusBadRequest,
+					response.GeneralError(fmt.Errorf("entry %d: name or email is required", i)))
+				return
+			}
+
+			if email := entries[i].Email; email != "" && !seen["email:"+email] {
+				seen["email:"+email] = true
+				emails = append(emails, email)
+			}
+			if name := nameKey(entries[i].Name); name != "" && !seen["name:"+name] {
+				seen["name:"+name] = true
+				names = append(names, name)
+			}
+		}
+
+		logging.FromContext(r.Context()).Info("checking students for duplicates", slog.Int("count", len(entries)))
+
+		// STEP 3: one lookup for everything
+		candidates, err := storage.FindStudentsByEmailOrName(r.Context(), emails, names)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		byEmail := map[string]types.ID{}
+		byName := map[string][]types.ID{}
+		for _, student := range candidates {
+			byEmail[types.NormalizeEmail(student.Email)] = student.PublicId
+			byName[nameKey(student.Name)] = append(byName[nameKey(student.Name)], student.PublicId)
+		}
+
+		// STEP 4: one result per entry
+		body := duplicateResponse{Checked: len(entries), Results: make([]duplicateResult, len(entries))}
+		for i, entry := range entries {
+			result := duplicateResult{Index: i, Name: entry.Name, Email: entry.Email}
+
+			if id, ok := byEmail[entry.Email]; ok && entry.Email != "" {
+				result.Exists = true
+				result.Id = id
+				body.Existing++
+			}
+			if ids := byName[nameKey(entry.Name)]; len(ids) > 0 && entry.Name != "" {
+				result.NameMatch = true
+				result.NameMatchIds = ids
+				body.NameMatch++
+			}
+
+			body.Results[i] = result
+		}
+
+		response.WriteJson(w, http.StatusOK, body)
+	}
+}
+
+// nameKey is what names are compared by: stored form, lower-cased.
+func nameKey(name string) string {
+	return strings.ToLower(types.NormalizeName(name))
+}
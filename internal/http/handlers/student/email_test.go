@@ -0,0 +1,88 @@
+package student_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// TestEmailCase checks emails are unique whatever their case: with
+// ann@example.com taken, every spelling of it is a conflict on each way of
+// writing a student, while a student may re-send its own address in
+// another case and gets it stored lower-cased.
+func TestEmailCase(t *testing.T) {
+	const conflict = `{"status":"Error","code":"conflict","error":"student with this email already exists"}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name: "create, upper-case", method: http.MethodPost, path: students,
+			body:   `{"name": "Ann Two", "email": "ANN@EXAMPLE.COM", "age": 20}`,
+			status: http.StatusConflict, want: conflict,
+		},
+		{
+			name: "create, mixed case and spaces", method: http.MethodPost, path: students,
+			body:   `{"name": "Ann Two", "email": " Ann@Example.com ", "age": 20}`,
+			status: http.StatusConflict, want: conflict,
+		},
+		{
+			name: "update", method: http.MethodPut, path: students + "/2",
+			body:   `{"name": "Bob Lee", "email": "Ann@example.com", "age": 20}`,
+			status: http.StatusConflict, want: conflict,
+		},
+		{
+			name: "patch", method: http.MethodPatch, path: students + "/2",
+			body:   `{"email": "aNN@example.com"}`,
+			status: http.StatusConflict, want: conflict,
+		},
+		{
+			name: "bulk, against each other", method: http.MethodPost, path: students + "/bulk",
+			body:   `[{"name": "Cid Lee", "email": "CID@example.com", "age": 20}, {"name": "Cid Two", "email": "cid@EXAMPLE.com", "age": 20}]`,
+			status: http.StatusMultiStatus,
+			want:   `{"succeeded":1,"failed":1,"results":[{"index":0,"status":"created","id":3},{"index":1,"status":"failed","code":"conflict","error":"student with this email already exists"}]}`,
+		},
+		{
+			name: "own address in another case", method: http.MethodPut, path: students + "/2",
+			body:   `{"name": "Bob Lee", "email": "BOB@Example.com", "age": 20}`,
+			status: http.StatusOK,
+		},
+	}
+
+	srv := apptest.Server(t, apptest.Config(t))
+	createAnn(t, srv)
+	if res := apptest.Do(t, srv, http.MethodPost, students, `{"name": "Bob Lee", "email": "bob@example.com", "age": 20}`); res.Status != http.StatusCreated {
+		t.Fatalf("create bob: status %d, body %s", res.Status, res.Body)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := apptest.Do(t, srv, tt.method, tt.path, tt.body)
+
+			if res.Status != tt.status {
+				t.Errorf("status %d, want %d (body %s)", res.Status, tt.status, res.Body)
+			}
+			if got := strings.TrimSpace(string(res.Body)); tt.want != "" && got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+
+	// stored lower-cased, and found by any spelling
+	res := apptest.Do(t, srv, http.MethodGet, students+"?email=BOB@example.COM", nil)
+	var page struct {
+		Data []types.Student `json:"data"`
+	}
+	res.JSON(t, &page)
+	if len(page.Data) != 1 || page.Data[0].Email != "bob@example.com" {
+		t.Errorf("?email=BOB@example.COM found %+v, want bob@example.com", page.Data)
+	}
+}
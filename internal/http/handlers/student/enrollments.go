@@ -0,0 +1,190 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - fmt      → formatting messages
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+   - strconv  → parse {course_id}
+*/
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// enrollmentRequest is the body of POST /api/students/{id}/enrollments.
+type enrollmentRequest struct {
+	CourseId int64 `json:"course_id" validate:"required,gte=1"`
+}
+
+/*
+Enroll()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "POST /api/students/{id}/enrollments": enrolls the
+	    student in a course, {"course_id":3}.
+	  → Capacity and "only once per course" are checked by
+	    storage, in the transaction of the enrollment.
+
+	RESPONSES:
+	  → 201 with the enrollment (course code and title included)
+	  → 409 when the student is already in the course, or the
+	    course is full
+	  → 404 when no live student has this ID, or no course has
+	    course_id
+	  → 400 when {id} or the body is malformed
+*/
+func Enroll(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which student, which course
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		var enrollment enrollmentRequest
+		if !decodeJSON(w, r, &enrollment) || !validateStruct(w, enrollment) {
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("enrolling a student",
+			slog.Int64("id", id),
+			slog.Int64("course_id", enrollment.CourseId),
+		)
+
+		// STEP 2: storage checks the student, the course and the seats
+		if err := storage.Enroll(r.Context(), id, enrollment.CourseId); err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		// STEP 3: send back the new enrollment
+		enrollments, err := storage.ListEnrollments(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		for _, current := range enrollments {
+			if current.CourseId == enrollment.CourseId {
+				current.StudentPublicId = publicID(r, id)
+				response.WriteJson(w, http.StatusCreated, current)
+				return
+			}
+		}
+
+		// Only when the enrollment was removed again in between
+		response.WriteJson(w, http.StatusNotFound, response.NotFound(
+			fmt.Sprintf("student with id %s is not enrolled in course %d", publicID(r, id), enrollment.CourseId),
+		))
+	}
+}
+
+/*
+Enrollments()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "GET /api/students/{id}/enrollments": the courses the
+	    student is enrolled in, ordered by course ID.
+
+	RESPONSES:
+	  → 200 with the list, [] when there are none
+	  → 404 when no live student has this ID
+	  → 400 when {id} is malformed
+*/
+func Enrollments(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		// An unknown student is a 404, not an empty list
+		if _, err := storage.GetStudentById(r.Context(), id); err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		enrollments, err := storage.ListEnrollments(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		for i := range enrollments {
+			enrollments[i].StudentPublicId = publicID(r, id)
+		}
+
+		response.WriteJson(w, http.StatusOK, enrollments)
+	}
+}
+
+/*
+Unenroll()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "DELETE /api/students/{id}/enrollments/{course_id}":
+	    frees the seat. Also how an enrolled student is made
+	    deletable when storage.on_student_delete is "block".
+
+	RESPONSES:
+	  → 204 (no body) when the enrollment was removed
+	  → 404 when the student is not enrolled in the course
+	  → 400 when {id} or {course_id} is malformed
+*/
+func Unenroll(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		courseID, err := strconv.ParseInt(r.PathValue("course_id"), 10, 64)
+		if err != nil || courseID < 1 {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(
+				fmt.Errorf("invalid course_id %q", r.PathValue("course_id")),
+			))
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("unenrolling a student",
+			slog.Int64("id", id),
+			slog.Int64("course_id", courseID),
+		)
+
+		removed, err := storage.Unenroll(r.Context(), id, courseID)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !removed {
+			response.WriteJson(w, http.StatusNotFound, response.NotFound(
+				fmt.Sprintf("student with id %s is not enrolled in course %d", publicID(r, id), courseID),
+			))
+			return
+		}
+
+		response.WriteNoContent(w)
+	}
+}
@@ -0,0 +1,46 @@
+package student_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+)
+
+// TestDeleteEnrolledStudent checks the 409 of deleting an enrolled student
+// (on_student_delete: block) names the ID the client sent, never the row
+// ID behind it, with both ID schemes.
+func TestDeleteEnrolledStudent(t *testing.T) {
+	for _, scheme := range []string{config.IDSchemeSerial, config.IDSchemeUUID} {
+		t.Run(scheme, func(t *testing.T) {
+			cfg := apptest.Config(t)
+			cfg.Storage.IDScheme = scheme
+			srv := apptest.Server(t, cfg)
+
+			ann := createAnn(t, srv)
+			if res := apptest.Do(t, srv, http.MethodPost, "/api/v1/courses",
+				map[string]any{"code": "CS101", "title": "Algorithms", "capacity": 10}); res.Status != http.StatusCreated {
+				t.Fatalf("create the course: status %d, body %s", res.Status, res.Body)
+			}
+			if res := apptest.Do(t, srv, http.MethodPost, students+"/"+string(ann.PublicId)+"/enrollments",
+				map[string]any{"course_id": 1}); res.Status != http.StatusCreated {
+				t.Fatalf("enroll: status %d, body %s", res.Status, res.Body)
+			}
+
+			res := apptest.Do(t, srv, http.MethodDelete, students+"/"+string(ann.PublicId), nil)
+			want := `{"status":"Error","code":"conflict","error":"student with id ` + string(ann.PublicId) + ` is still enrolled in courses"}`
+			if res.Status != http.StatusConflict || strings.TrimSpace(string(res.Body)) != want {
+				t.Errorf("delete: status %d, body %s; want 409 %s", res.Status, res.Body, want)
+			}
+
+			// the filtered delete has no {id} to name
+			res = apptest.Do(t, srv, http.MethodDelete, students+"?email=ann@example.com", nil)
+			want = `{"status":"Error","code":"conflict","error":"student is still enrolled in courses"}`
+			if res.Status != http.StatusConflict || strings.TrimSpace(string(res.Body)) != want {
+				t.Errorf("filtered delete: status %d, body %s; want 409 %s", res.Status, res.Body, want)
+			}
+		})
+	}
+}
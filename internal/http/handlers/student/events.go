@@ -0,0 +1,136 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - errors   → a missing Flush support is reported, not fatal
+   - fmt      → SSE frames
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+   - strconv  → parse Last-Event-ID
+   - strings  → SSE event names from event types
+   - time     → heartbeat interval, write deadline
+   - events   → the Hub mutations are published into
+   - logging  → request-scoped logger
+*/
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/events"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Events()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students/events".
+	  → Streams student changes as Server-Sent Events, so admin
+	    dashboards don't have to poll the list:
+
+	      id: 42
+	      event: updated
+	      data: {"id":7,"name":"Ann Lee",…}
+
+	  → event is created, updated, deleted or restored; data is the
+	    student as stored after the change (before it, for deleted).
+
+	RESUMING:
+	  → Browsers reconnect on their own and send the id of the last
+	    event they got as Last-Event-ID; the events after it that
+	    are still in the hub's history are sent first.
+	  → IDs restart when the server restarts.
+
+	LIFETIME:
+	  → A ": heartbeat" comment every heartbeat
+	    (api.event_heartbeat, 15s by default), so proxies don't
+	    close idle streams.
+	  → Ends when the client goes away, when the hub drops a
+	    subscriber that fell too far behind, and at shutdown
+	    (Hub.Close).
+*/
+func Events(hub *events.Hub, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := logging.FromContext(r.Context())
+
+		// STEP 1: Last-Event-ID (optional)
+		var lastID uint64
+		resume := false
+		if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				response.WriteJson(w, http.StatusBadRequest,
+					response.GeneralError(fmt.Errorf("invalid Last-Event-ID %q, must be an event id", raw)))
+				return
+			}
+			lastID, resume = id, true
+		}
+
+		// STEP 2: the stream outlives http_server.write_timeout
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logger.Warn("cannot clear the write deadline of the event stream", slog.String("error", err.Error()))
+		}
+
+		sub := hub.Subscribe(lastID, resume)
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+		w.WriteHeader(http.StatusOK)
+
+		// STEP 3: catch up, then follow
+		for _, event := range sub.Backlog {
+			writeEvent(w, event)
+		}
+		if err := rc.Flush(); err != nil {
+			logger.Error("event stream cannot be flushed", slog.String("error", err.Error()))
+			return
+		}
+
+		logger.Info("event stream opened", slog.Int("backlog", len(sub.Backlog)))
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				logger.Info("event stream closed by the client")
+				return
+
+			case event, ok := <-sub.C:
+				if !ok {
+					logger.Info("event stream closed by the server")
+					return
+				}
+				writeEvent(w, event)
+
+			case <-ticker.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			if err := rc.Flush(); err != nil {
+				logger.Info("event stream closed", slog.String("error", err.Error()))
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes one SSE frame. Data is compact JSON, so it never
+// contains the newlines that would need one data: line each.
+func writeEvent(w http.ResponseWriter, event events.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n",
+		event.ID, strings.TrimPrefix(event.Type, "student."), event.Data)
+}
@@ -0,0 +1,250 @@
+package student_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/events"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/handlers/student"
+)
+
+// frame is one SSE frame: an event, or a comment (heartbeat).
+type frame struct {
+	id, event, data, comment string
+}
+
+// stream is an open GET of the event stream.
+type stream struct {
+	res    *http.Response
+	lines  *bufio.Reader
+	cancel context.CancelFunc
+}
+
+// serveEvents serves student.Events(hub, heartbeat); every return of the
+// handler is sent on the returned channel.
+func serveEvents(t *testing.T, hub *events.Hub, heartbeat time.Duration) (*httptest.Server, <-chan struct{}) {
+	t.Helper()
+
+	returned := make(chan struct{}, 10)
+	handler := student.Events(hub, heartbeat)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() { returned <- struct{}{} }()
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, returned
+}
+
+// open GETs the stream, with a Last-Event-ID when lastID is not empty. It
+// returns once the handler has subscribed (the headers are flushed after).
+func open(t *testing.T, srv *httptest.Server, lastID string) *stream {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("GET the event stream: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		res.Body.Close()
+	})
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status %d, Content-Type %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	return &stream{res: res, lines: bufio.NewReader(res.Body), cancel: cancel}
+}
+
+// next reads the next frame.
+func (s *stream) next(t *testing.T) frame {
+	t.Helper()
+
+	var f frame
+	for {
+		line, err := s.lines.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return f
+		}
+
+		if comment, found := strings.CutPrefix(line, ": "); found {
+			f.comment = comment
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			f.id = value
+		case "event":
+			f.event = value
+		case "data":
+			f.data = value
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+}
+
+// waitReturned fails t unless the handler returns within a second.
+func waitReturned(t *testing.T, returned <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("the handler did not return")
+	}
+}
+
+// waitGoroutines fails t unless the goroutine count drops back to at most
+// want within a second.
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines, want at most %d", runtime.NumGoroutine(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestEvents checks the events are streamed in order, with increasing IDs,
+// the type without its "student." prefix and the payload as data.
+func TestEvents(t *testing.T) {
+	hub := events.NewHub()
+	srv, _ := serveEvents(t, hub, time.Hour)
+	s := open(t, srv, "")
+
+	hub.Publish(events.StudentCreated, map[string]any{"id": 1})
+	hub.Publish(events.StudentUpdated, map[string]any{"id": 1})
+	hub.Publish(events.StudentDeleted, map[string]any{"id": 2})
+
+	want := []frame{
+		{id: "1", event: "created", data: `{"id":1}`},
+		{id: "2", event: "updated", data: `{"id":1}`},
+		{id: "3", event: "deleted", data: `{"id":2}`},
+	}
+	for _, w := range want {
+		if got := s.next(t); got != w {
+			t.Errorf("frame %+v, want %+v", got, w)
+		}
+	}
+}
+
+// TestEventsResume checks a stream with Last-Event-ID first gets the
+// events after that ID, then the new ones; a malformed ID is a 400.
+func TestEventsResume(t *testing.T) {
+	hub := events.NewHub()
+	srv, _ := serveEvents(t, hub, time.Hour)
+	for range 3 {
+		hub.Publish(events.StudentCreated, map[string]any{"id": 1})
+	}
+
+	s := open(t, srv, "1")
+	hub.Publish(events.StudentRestored, map[string]any{"id": 1})
+
+	for _, id := range []string{"2", "3", "4"} {
+		if got := s.next(t); got.id != id {
+			t.Errorf("frame %+v, want id %s", got, id)
+		}
+	}
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), `invalid Last-Event-ID \"abc\"`) {
+		t.Errorf("Last-Event-ID abc: status %d, body %s; want a 400", res.StatusCode, body)
+	}
+}
+
+// TestEventsHeartbeat checks an idle stream gets a heartbeat comment every
+// interval, and events still come through between them.
+func TestEventsHeartbeat(t *testing.T) {
+	hub := events.NewHub()
+	srv, _ := serveEvents(t, hub, 20*time.Millisecond)
+	s := open(t, srv, "")
+
+	for range 2 {
+		if got := s.next(t); got.comment != "heartbeat" || got.id != "" {
+			t.Fatalf("frame %+v, want a heartbeat", got)
+		}
+	}
+
+	hub.Publish(events.StudentCreated, map[string]any{"id": 1})
+	for {
+		got := s.next(t)
+		if got.comment == "heartbeat" {
+			continue
+		}
+		if got.id != "1" || got.event != "created" {
+			t.Errorf("frame %+v, want event 1", got)
+		}
+		break
+	}
+}
+
+// TestEventsClientGone checks the handler returns, and so unsubscribes,
+// when the client cancels its request; nothing of the stream is left.
+func TestEventsClientGone(t *testing.T) {
+	hub := events.NewHub()
+	srv, returned := serveEvents(t, hub, time.Hour)
+	before := runtime.NumGoroutine()
+
+	s := open(t, srv, "")
+	s.cancel()
+	s.res.Body.Close()
+	waitReturned(t, returned)
+
+	srv.Client().CloseIdleConnections()
+	waitGoroutines(t, before)
+}
+
+// TestEventsHubClose checks Hub.Close (at shutdown) ends every open stream:
+// the clients see the end of the body and no handler goroutine is left.
+func TestEventsHubClose(t *testing.T) {
+	hub := events.NewHub()
+	srv, returned := serveEvents(t, hub, time.Hour)
+	before := runtime.NumGoroutine()
+
+	streams := []*stream{open(t, srv, ""), open(t, srv, ""), open(t, srv, "")}
+	hub.Close()
+
+	for i, s := range streams {
+		if _, err := s.lines.ReadString('\n'); err != io.EOF {
+			t.Errorf("stream %d: %v, want io.EOF", i, err)
+		}
+		waitReturned(t, returned)
+		s.res.Body.Close()
+	}
+
+	srv.Client().CloseIdleConnections()
+	waitGoroutines(t, before)
+}
@@ -0,0 +1,149 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - encoding/csv → quoting of commas, quotes and newlines
+   - fmt          → formatting messages
+   - log/slog     → structured logging (new standard logger)
+   - net/http     → for HTTP handler, status codes
+   - strconv      → numbers to CSV fields
+   - strings      → the tags in one field
+   - time         → date in the file name, RFC 3339 timestamps
+*/
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// csvHeader is the first row of every export, in field order.
+var csvHeader = []string{"id", "name", "email", "age", "date_of_birth", "phone", "tags", "status", "created_at", "updated_at"}
+
+/*
+Export()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students/export".
+	  → Streams every student matching the list filters (name,
+	    email, min_age, max_age, tag) as CSV with a header row;
+	    the tags go in one cell, "a;b".
+
+	STREAMING:
+	  → Rows go from storage.ForEachStudent straight into the
+	    csv.Writer, so memory use doesn't grow with the table.
+	  → Once the first row is out the status is already 200; a
+	    storage error after that can only be logged, and the
+	    client gets a truncated file.
+
+	QUERY PARAMETERS:
+	  - format → only "csv" (the default) for now
+*/
+func Export(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: format + filters
+		if format := r.URL.Query().Get("format"); format != "" && format != "csv" {
+			response.WriteJson(w, http.StatusBadRequest,
+				response.GeneralError(fmt.Errorf("unsupported format %q, only csv is available", format)))
+			return
+		}
+
+		filter, err := parseFilter(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("exporting students")
+
+		// STEP 2: headers that make browsers download "students-2026-01-31.csv"
+		filename := fmt.Sprintf("students-%s.csv", time.Now().UTC().Format(time.DateOnly))
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+		// STEP 3: header row, then one row per student
+		out := csv.NewWriter(w)
+		out.Write(csvHeader)
+
+		rows := 0
+		err = storage.ForEachStudent(r.Context(), filter, func(student types.Student) error {
+			rows++
+			return out.Write(csvRecord(student))
+		})
+
+		out.Flush()
+		if err == nil {
+			err = out.Error()
+		}
+
+		if err != nil {
+			logging.FromContext(r.Context()).Error("export aborted",
+				slog.Int("rows", rows),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("export finished", slog.Int("rows", rows))
+	}
+}
+
+/*
+csvRecord()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → One student as CSV fields, in csvHeader order.
+
+	FORMULA INJECTION:
+	  → Excel runs cells starting with = + - @ as formulas, so a
+	    name like "=HYPERLINK(…)" would execute on the admin's
+	    machine. Such text fields get a leading ' which Excel
+	    shows as plain text.
+*/
+func csvRecord(student types.Student) []string {
+	return []string{
+		string(student.PublicId),
+		csvText(student.Name),
+		csvText(student.Email),
+		strconv.Itoa(student.Age),
+		csvOptional(student.DateOfBirth),
+		csvText(csvOptional(student.Phone)),
+		strings.Join(student.Tags, csvTagSeparator),
+		student.Status,
+		student.CreatedAt.Format(time.RFC3339),
+		student.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// csvTagSeparator joins the tags of a student in their one cell; tags never
+// contain it (types.IsTag).
+const csvTagSeparator = ";"
+
+// csvOptional is the cell of an optional field, empty when it is not set.
+func csvOptional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func csvText(s string) string {
+	if s != "" && (s[0] == '=' || s[0] == '+' || s[0] == '-' || s[0] == '@') {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package student_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden with the current answers")
+
+// timestamp matches the JSON strings that change from run to run.
+var timestamp = regexp.MustCompile(`"\d{4}-\d\d-\d\dT[0-9:.]+(Z|[+-]\d\d:\d\d)"`)
+
+// TestGolden compares the bodies clients parse with testdata/<name>.golden,
+// key order included. Timestamps read "<timestamp>" there; the IDs are the
+// serial ones of a fresh store.
+//
+//	go test ./internal/http/handlers/student -run TestGolden -update
+func TestGolden(t *testing.T) {
+	srv := apptest.Server(t, apptest.Config(t))
+
+	// students 1 and 2; created_student is 3, so the list page (2 and 3)
+	// has a student before it
+	for _, name := range []string{"Bob", "Cid"} {
+		body := map[string]any{"name": name + " Lee", "email": strings.ToLower(name) + "@example.com", "age": 30}
+		if res := apptest.Do(t, srv, http.MethodPost, students, body); res.Status != http.StatusCreated {
+			t.Fatalf("seeding %s: status %d, body %s", name, res.Status, res.Body)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+	}{
+		{
+			name: "bad_request", method: http.MethodPost, path: students, body: "",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "validation_error", method: http.MethodPost, path: students,
+			body:   map[string]any{"name": "A", "email": "not-an-email", "age": 151, "tags": []string{"Not A Tag!"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "created_student", method: http.MethodPost, path: students,
+			body: map[string]any{
+				// no date_of_birth: the age derived from it changes every year
+				"name": "Ann Lee", "email": "Ann@Example.com", "age": 20,
+				"phone": "+44 20 7946 0958", "tags": []string{"honours", "Year-1"},
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name: "list_page", method: http.MethodGet, path: students + "?limit=2&offset=1",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := apptest.Do(t, srv, tt.method, tt.path, tt.body)
+			if res.Status != tt.status {
+				t.Fatalf("status %d, want %d (body %s)", res.Status, tt.status, res.Body)
+			}
+
+			var got bytes.Buffer
+			if err := json.Indent(&got, timestamp.ReplaceAll(res.Body, []byte(`"<timestamp>"`)), "", "  "); err != nil {
+				t.Fatalf("body is not JSON (%v): %s", err, res.Body)
+			}
+			got.WriteByte('\n')
+
+			golden(t, tt.name, got.Bytes())
+		})
+	}
+}
+
+// golden compares got with testdata/<name>.golden, or writes it there
+// under -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("body differs from %s (run with -update if the change is intended)\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,302 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes         → compare the magic bytes of an upload
+   - crypto/sha256 → the ETag of a photo is a hash of its bytes
+   - encoding/hex  → … written in hex
+   - errors        → detect *http.MaxBytesError, the photo errors
+   - fmt           → formatting messages
+   - io            → read the upload, at most MaxBytes of it
+   - log/slog      → structured logging (new standard logger)
+   - mime          → raw upload or multipart/form-data?
+   - net/http      → handlers, status codes
+   - strconv       → Content-Length of the photo
+   - time          → upload time, Last-Modified
+*/
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// photoField is the multipart/form-data field of a photo upload.
+const photoField = "photo"
+
+// multipartOverhead is what a multipart upload may send on top of the
+// photo: boundaries, part headers, small extra fields.
+const multipartOverhead = 64 << 10
+
+// photoSignatures are the magic bytes photos start with. The declared
+// Content-Type is never trusted: a file is a photo when its first bytes
+// say so.
+var photoSignatures = []struct {
+	contentType string
+	magic       []byte
+}{
+	{types.PhotoJPEG, []byte{0xFF, 0xD8, 0xFF}},
+	{types.PhotoPNG, []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}},
+}
+
+// photoInfo is the body of a successful PUT /api/students/{id}/photo.
+type photoInfo struct {
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	ETag        string    `json:"etag"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+/*
+PutPhoto()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "PUT /api/students/{id}/photo": uploads the profile
+	    photo of a student, replacing the one it had.
+	  → maxBytes is photos.max_bytes. The route is exempt from
+	    RequireJSON and http_server.max_body_bytes (see
+	    isPhotoUpload), this is its limit.
+
+	BODY:
+	  → Either the image itself (any Content-Type, e.g.
+	    image/png), or multipart/form-data with the image in
+	    the "photo" field (an HTML form upload).
+	  → JPEG or PNG, told apart by their magic bytes.
+
+	RESPONSES:
+	  → 201 {"content_type","size","etag","updated_at"} for a
+	    first photo, 200 for a replaced one; ETag header
+	  → 400 for {id}, an empty photo or a form without "photo"
+	  → 404 when no student has this ID
+	  → 413 when the photo is larger than maxBytes
+	  → 415 when the bytes are not a JPEG or PNG image
+*/
+func PutPhoto(storage storage.Storage, maxBytes int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("uploading a student photo", slog.Int64("id", id))
+
+		// STEP 1: the bytes, raw or from the form
+		data, err := readPhoto(w, r, maxBytes)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.Is(err, errPhotoTooLarge) || errors.As(err, &maxBytesErr) {
+				response.WriteJson(w, http.StatusRequestEntityTooLarge,
+					response.GeneralError(fmt.Errorf("photo must not be larger than %d bytes", maxBytes)))
+				return
+			}
+
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		// STEP 2: a photo is what its bytes say, whatever was declared
+		contentType := sniffPhoto(data)
+		if contentType == "" {
+			response.WriteJson(w, http.StatusUnsupportedMediaType,
+				response.GeneralError(errors.New("photo must be a JPEG or PNG image")))
+			return
+		}
+
+		// Last-Modified has a one second resolution: so has UpdatedAt,
+		// or If-Modified-Since would never match
+		sum := sha256.Sum256(data)
+		photo := types.Photo{
+			ContentType: contentType,
+			Data:        data,
+			ETag:        hex.EncodeToString(sum[:16]),
+			UpdatedAt:   time.Now().UTC().Truncate(time.Second),
+		}
+
+		// STEP 3: store it
+		created, err := storage.PutStudentPhoto(r.Context(), id, photo)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+
+		w.Header().Set("ETag", photoETag(photo))
+		response.WriteJson(w, status, photoInfo{
+			ContentType: photo.ContentType,
+			Size:        len(photo.Data),
+			ETag:        photo.ETag,
+			UpdatedAt:   photo.UpdatedAt,
+		})
+	}
+}
+
+/*
+Photo()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "GET /api/students/{id}/photo": the photo itself, with
+	    its Content-Type.
+
+	CACHING:
+	  → ETag (strong: it is a hash of the bytes) and
+	    Last-Modified, so If-None-Match / If-Modified-Since get
+	    a 304 without the bytes.
+	  → Cache-Control: no-cache: clients keep their copy but
+	    revalidate it, so a new upload shows at once.
+
+	RESPONSES:
+	  → 200 with the image, 304 when the client's copy is current
+	  → 404 when no student has this ID, or it has no photo
+	  → 400 when {id} is malformed
+*/
+func Photo(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		photo, err := storage.GetStudentPhoto(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		etag := photoETag(photo)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Last-Modified", photo.UpdatedAt.Format(http.TimeFormat))
+
+		if notModified(r, etag, photo.UpdatedAt) {
+			response.WriteNotModified(w, etag)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", photo.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(photo.Data)))
+		// the type was sniffed on upload; browsers must not guess another
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		w.Write(photo.Data)
+	}
+}
+
+// errPhotoTooLarge is returned by readPhoto for a photo over the limit.
+var errPhotoTooLarge = errors.New("photo too large")
+
+/*
+readPhoto()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads the uploaded bytes: the "photo" part of a
+	    multipart/form-data body, or the whole body otherwise.
+	  → At most maxBytes of photo (errPhotoTooLarge above); the
+	    body as a whole is capped too, with room for the
+	    multipart framing.
+*/
+func readPhoto(w http.ResponseWriter, r *http.Request, maxBytes int64) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+
+	var body io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		part, err := photoPart(r)
+		if err != nil {
+			return nil, err
+		}
+		defer part.Close()
+		body = part
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, errPhotoTooLarge
+	}
+	if len(data) == 0 {
+		return nil, errors.New("photo is empty")
+	}
+
+	return data, nil
+}
+
+// photoPart finds the "photo" field of a multipart/form-data body; other
+// fields are skipped.
+func photoPart(r *http.Request) (io.ReadCloser, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("malformed multipart body: %w", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("multipart body has no %q field", photoField)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed multipart body: %w", err)
+		}
+
+		if part.FormName() == photoField {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// sniffPhoto returns the type of a photo from its magic bytes, "" when it
+// is neither a JPEG nor a PNG.
+func sniffPhoto(data []byte) string {
+	for _, signature := range photoSignatures {
+		if bytes.HasPrefix(data, signature.magic) {
+			return signature.contentType
+		}
+	}
+
+	return ""
+}
+
+// photoETag is the (strong) ETag header of a photo.
+func photoETag(photo types.Photo) string {
+	return `"` + photo.ETag + `"`
+}
+
+// notModified reports whether the client's copy of the photo is current:
+// If-None-Match lists etag, or, without If-None-Match, If-Modified-Since
+// is not before updatedAt (RFC 9110 gives the ETag precedence).
+func notModified(r *http.Request, etag string, updatedAt time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !updatedAt.After(since)
+}
@@ -0,0 +1,174 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - errors   → the malformed If-Match error
+   - fmt      → ETag format, error messages
+   - net/http → headers, status codes
+   - strconv  → ID and version number inside the ETag
+   - strings  → trim quotes / the W/ prefix of an entity tag
+   - storage  → tell a UUID from garbage
+   - types    → Student.PublicId, Student.Version
+   - response → JSON bodies for 400 / 412 / 428
+*/
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+studentETag()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The ETag of a student is W/"<id>-<version>", e.g. W/"7-3",
+	    with the ID clients see (a UUID under storage.id_scheme
+	    uuid).
+	  → Every write increments the version, so id+version changes
+	    exactly when the student does.
+
+	WHY WEAK (W/)?
+	  → It identifies the student's data, not the exact bytes:
+	    the same version can be sent with other formatting or
+	    compressed by a proxy and still be "the same" for
+	    If-None-Match / If-Match.
+*/
+func studentETag(student types.Student) string {
+	return fmt.Sprintf(`W/"%s-%d"`, student.PublicId, student.Version)
+}
+
+/*
+etagMatches()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reports whether an If-None-Match / If-Match header value
+	    lists etag: "*" matches anything, otherwise any of the
+	    comma-separated tags may match.
+	  → Weak comparison: W/ is ignored on both sides.
+*/
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == want {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeStudent sends one student with its ETag, so the client can send it
+// back in If-Match on the next PUT/PATCH.
+func writeStudent(w http.ResponseWriter, status int, student types.Student) {
+	w.Header().Set("ETag", studentETag(student))
+	response.WriteJson(w, status, student)
+}
+
+/*
+expectedVersion()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Works out which version a PUT/PATCH may overwrite, from
+	    If-Match and/or the "version" field of the body
+	    (bodyVersion, 0 when absent).
+
+	RULES:
+	  - If-Match: W/"7-3"     → version 3 (with or without the W/)
+	  - If-Match: *           → any version
+	  - the ETag of another student → 412, it can't match
+	  - both If-Match and a body version → they must agree
+	  - neither               → any version, or 428 when require
+	                            (api.require_if_match) is set
+
+	RETURN VALUE:
+	  → the version (0 = unconditional) and true
+	  → false when a 400/428 response has already been written
+*/
+func expectedVersion(w http.ResponseWriter, r *http.Request, id int64, bodyVersion int, require bool) (int, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	if header == "" {
+		if bodyVersion == 0 && require {
+			response.WriteJson(w, http.StatusPreconditionRequired, response.PreconditionRequired(
+				"send If-Match with the student's ETag (or its version in the body) to update it",
+			))
+			return 0, false
+		}
+
+		return bodyVersion, true
+	}
+
+	if header == "*" {
+		return bodyVersion, true
+	}
+
+	tagID, version, err := parseETag(header)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return 0, false
+	}
+
+	if tagID != publicID(r, id) {
+		response.WriteJson(w, http.StatusPreconditionFailed, response.PreconditionFailed(
+			fmt.Sprintf("If-Match %s is not an ETag of student %s", header, publicID(r, id)),
+		))
+		return 0, false
+	}
+
+	if bodyVersion != 0 && bodyVersion != version {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(
+			fmt.Errorf("version %d in the body does not match If-Match %s", bodyVersion, header),
+		))
+		return 0, false
+	}
+
+	return version, true
+}
+
+// errBadETag is the 400 for an If-Match that studentETag can't have made.
+var errBadETag = errors.New(`If-Match must be a single ETag of this student, e.g. W/"7-3"`)
+
+// parseETag reads id and version back out of one entity tag made by
+// studentETag. The version follows the last dash: UUIDs have dashes too.
+func parseETag(tag string) (types.ID, int, error) {
+	raw := strings.TrimPrefix(tag, "W/")
+
+	unquoted, found := strings.CutPrefix(raw, `"`)
+	unquoted, closed := strings.CutSuffix(unquoted, `"`)
+	dash := strings.LastIndex(unquoted, "-")
+	if !found || !closed || dash < 0 {
+		return "", 0, errBadETag
+	}
+	rawID, rawVersion := unquoted[:dash], unquoted[dash+1:]
+
+	var id types.ID
+	if number, err := strconv.ParseInt(rawID, 10, 64); err == nil && number >= 1 {
+		id = types.SerialID(number)
+	} else if storage.IsUUID(rawID) {
+		id = types.ID(strings.ToLower(rawID))
+	} else {
+		return "", 0, errBadETag
+	}
+
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil || version < 1 {
+		return "", 0, errBadETag
+	}
+
+	return id, version, nil
+}
@@ -0,0 +1,51 @@
+package student_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+)
+
+// TestIfNoneMatch checks conditional GETs of a student at version 2: any
+// listed tag matching the current ETag, weakly compared, is a bodyless 304
+// repeating the ETag; anything else gets the student.
+func TestIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		status int
+	}{
+		{"match", []string{"If-None-Match", `W/"1-2"`}, http.StatusNotModified},
+		{"strong form of the tag", []string{"If-None-Match", `"1-2"`}, http.StatusNotModified},
+		{"in a list", []string{"If-None-Match", `W/"1-1", W/"1-2"`}, http.StatusNotModified},
+		{"any", []string{"If-None-Match", "*"}, http.StatusNotModified},
+		{"old version", []string{"If-None-Match", `W/"1-1"`}, http.StatusOK},
+		{"other student", []string{"If-None-Match", `W/"2-2"`}, http.StatusOK},
+		{"missing", nil, http.StatusOK},
+	}
+
+	srv := apptest.Server(t, apptest.Config(t))
+	createAnn(t, srv)
+	body := ann()
+	body["age"] = 21
+	if res := apptest.Do(t, srv, http.MethodPut, students+"/1", body); res.Status != http.StatusOK {
+		t.Fatalf("update: status %d, body %s", res.Status, res.Body)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := apptest.Do(t, srv, http.MethodGet, students+"/1", nil, tt.header...)
+
+			if res.Status != tt.status {
+				t.Errorf("status %d, want %d", res.Status, tt.status)
+			}
+			if got := res.Header.Get("ETag"); got != `W/"1-2"` {
+				t.Errorf("ETag %q, want %q", got, `W/"1-2"`)
+			}
+			if empty := len(res.Body) == 0; empty != (tt.status == http.StatusNotModified) {
+				t.Errorf("body %q on a %d", res.Body, res.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → the cache fetches with the request's context
+   - fmt      → Cache-Control value
+   - net/http → for HTTP handler, status codes
+   - sort     → buckets outside the standard range stay ordered
+   - sync     → the cache is shared by concurrent requests
+   - time     → cache expiry, the 30-day window
+*/
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+const (
+	// statsDays is the window of created_per_day, today included.
+	statsDays = 30
+
+	// statsMaxAge is the highest valid age (validate tag of Student.Age),
+	// the end of the last age bucket.
+	statsMaxAge = 150
+)
+
+/*
+Stats()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students/stats":
+	    total, students per status and per tag (most used
+	    first), average / min / max age,
+	    students per age bucket
+	    (1-10, 11-20, …) and students created per day over the
+	    last 30 days (UTC), oldest first.
+	  → Every status, bucket and day is listed, with count 0 when
+	    empty, so dashboards can plot the arrays as they come.
+
+	CACHING:
+	  → The result is kept for ttl (api.stats_cache_ttl), so
+	    dashboards refreshing every second cost one set of
+	    queries per ttl, not one per viewer; Cache-Control tells
+	    browsers the same. ttl 0 disables the cache.
+	  → Concurrent misses wait for the one refresh in progress
+	    instead of each running the queries.
+*/
+func Stats(storage storage.Storage, ttl time.Duration) http.HandlerFunc {
+	cache := &statsCache{ttl: ttl}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := cache.get(r.Context(), storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		if ttl > 0 {
+			w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(ttl.Seconds())))
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+
+		response.WriteJson(w, http.StatusOK, stats)
+	}
+}
+
+// statsCache holds the last StudentStats until expires.
+type statsCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	stats   types.StudentStats
+	expires time.Time
+}
+
+// get returns the cached stats, or fetches (and caches) fresh ones. The lock
+// is held during the fetch on purpose: see Stats.
+func (c *statsCache) get(ctx context.Context, storage storage.Storage) (types.StudentStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.expires) {
+		return c.stats, nil
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(statsDays - 1))
+
+	stats, err := storage.StudentStats(ctx, since)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	fillStats(&stats, since)
+
+	c.stats, c.expires = stats, now.Add(c.ttl)
+	return stats, nil
+}
+
+/*
+fillStats()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Storage only returns the statuses, buckets and days that
+	    have students; this adds the empty ones so the JSON shape
+	    never changes: every types.Statuses entry (in that order),
+	    statsMaxAge / AgeBucketWidth buckets and statsDays days
+	    starting at since. by_tag is [] rather than null when no
+	    student has a tag.
+	  → Buckets outside 1-150 (rows older than the age rules) are
+	    kept, in order, rather than silently dropped.
+*/
+func fillStats(stats *types.StudentStats, since time.Time) {
+	statuses := make(map[string]int, len(stats.ByStatus))
+	for _, status := range stats.ByStatus {
+		statuses[status.Status] = status.Count
+	}
+
+	stats.ByStatus = make([]types.StatusCount, len(types.Statuses))
+	for i, status := range types.Statuses {
+		stats.ByStatus[i] = types.StatusCount{Status: status, Count: statuses[status]}
+	}
+
+	// only the tags in use: there is no list of "every tag"
+	if stats.ByTag == nil {
+		stats.ByTag = []types.TagCount{}
+	}
+
+	buckets := make(map[int]types.AgeBucket, len(stats.AgeBuckets))
+	for _, bucket := range stats.AgeBuckets {
+		buckets[bucket.From] = bucket
+	}
+
+	for from := 1; from <= statsMaxAge; from += types.AgeBucketWidth {
+		if _, ok := buckets[from]; !ok {
+			buckets[from] = types.AgeBucket{From: from, To: from + types.AgeBucketWidth - 1}
+		}
+	}
+
+	stats.AgeBuckets = stats.AgeBuckets[:0]
+	for _, bucket := range buckets {
+		stats.AgeBuckets = append(stats.AgeBuckets, bucket)
+	}
+	sort.Slice(stats.AgeBuckets, func(i, j int) bool {
+		return stats.AgeBuckets[i].From < stats.AgeBuckets[j].From
+	})
+
+	counts := make(map[string]int, len(stats.CreatedPerDay))
+	for _, day := range stats.CreatedPerDay {
+		counts[day.Date] = day.Count
+	}
+
+	stats.CreatedPerDay = make([]types.DayCount, statsDays)
+	for i := range stats.CreatedPerDay {
+		date := since.AddDate(0, 0, i).Format(time.DateOnly)
+		stats.CreatedPerDay[i] = types.DayCount{Date: date, Count: counts[date]}
+	}
+}
@@ -0,0 +1,85 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+*/
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+)
+
+// statusChange is the body of POST /api/students/{id}/status.
+type statusChange struct {
+	Status string `json:"status" validate:"required,oneof=active suspended graduated"`
+}
+
+/*
+SetStatus()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "POST /api/students/{id}/status": moves the student to
+	    another status, {"status":"graduated"}.
+	  → The only way to change a status after the create, so
+	    every change follows types.CanChangeStatus:
+	      active    → suspended, graduated
+	      suspended → active, graduated
+	      graduated → nothing (terminal)
+
+	RESPONSES:
+	  → 200 with the updated student (and its new ETag)
+	  → 409 for a transition that is not allowed, including
+	    "changing" to the current status
+	  → 404 when no live student has this ID
+	  → 400 when {id} or the body is malformed
+*/
+func SetStatus(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which student, which status
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		var change statusChange
+		if !decodeJSON(w, r, &change) || !validateStruct(w, change) {
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("changing the status of a student",
+			slog.Int64("id", id),
+			slog.String("status", change.Status),
+		)
+
+		// STEP 2: storage checks the transition and applies it
+		changed, err := storage.SetStudentStatus(r.Context(), id, change.Status)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !changed {
+			writeNotFound(w, r, id)
+			return
+		}
+
+		// STEP 3: send back the student as it is now
+		student, err := storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		writeStudent(w, http.StatusOK, student)
+	}
+}
@@ -4,27 +4,48 @@ package student // package groups all student-related API handlers
    ---------------------------------------------------------
    IMPORTS
    ---------------------------------------------------------
+   - bytes         → decode the body once it was read and checked
+   - context       → storage calls cut short by the request deadline
+   - encoding/base64 → opaque pagination cursors
    - encoding/json → decode JSON request body into Go struct
    - errors        → used to check specific errors (like io.EOF)
    - fmt           → formatting messages
-   - io            → used for detecting empty request body (io.EOF)
+   - io            → read the request body, detect an empty one (io.EOF)
    - slog          → structured logging (new standard logger)
    - net/http      → for HTTP handler, status codes
+   - strconv       → parse numeric query parameters (limit, offset)
+   - strings       → detect the "unknown field" decode error
+   - time          → clear client-sent timestamps
+   - unicode/utf8  → count characters (not bytes) of a search query,
+                     reject a body that is not UTF-8
 
+   - logging       → request-scoped logger (carries request_id)
+   - storage       → Storage interface the handlers persist through
    - types         → your custom Student struct (from internal/types)
    - response      → custom helper for sending JSON responses
-   - validator/v10 → for struct validation (required fields etc.)
+   - validation    → shared validator instance (json field names)
+   - validator/v10 → ValidationErrors type for readable messages
 */
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
 	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
 	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/validation"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -37,114 +58,1100 @@ New()
 	  → This handler will process "create student" API requests.
 
 	WHY RETURN A FUNCTION?
-	  → Useful pattern to add dependencies later (DB, services…)
-	  → Example: func New(db *sql.DB) http.HandlerFunc
+	  → Useful pattern to inject dependencies (DB, services…)
+	  → The returned closure captures "storage" so every request
+	    can use it without globals.
 
 	RETURN VALUE:
 	  func(w http.ResponseWriter, r *http.Request)
 */
-func New() http.HandlerFunc {
+func New(storage storage.Storage, phoneCountry string) http.HandlerFunc {
 
 	// This anonymous function IS the real request handler
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		// Log API call (server console)
-		slog.Info("creating a student api")
+		logging.FromContext(r.Context()).Info("creating a student api")
 
 		/*
-		   STEP 1:
-		   Create a student variable that will store the JSON body.
-
-		   types.Student:
-		     - Your custom struct
-		     - It will receive values according to JSON keys sent by client
+		   STEP 1: DECODE + VALIDATE THE BODY
+		   --------------------------------------------------
+		   - decodeStudent writes the 400 response itself,
+		     so we only need to stop when ok == false.
 		*/
-		var student types.Student
+		student, ok := decodeStudent(w, r, phoneCountry)
+		if !ok {
+			return
+		}
 
 		/*
-		   STEP 2:
-		   Decode JSON request body into "student" struct.
-		   json.NewDecoder(r.Body) reads raw JSON from the HTTP request.
-
-		   Decode(&student):
-		     - Converts JSON → Go struct
-		     - Fills student.Name, student.Age, student.Email, etc.
-
-		   POSSIBLE ERRORS:
-		     - io.EOF → body is empty ({} or nothing)
-		     - invalid JSON format → {"name":123}
-		     - wrong types
+		   STEP 2: PERSIST THE STUDENT
+		   --------------------------------------------------
+		   - r.Context() is cancelled when the client disconnects,
+		     so the insert is abandoned instead of finishing for nobody.
+		   - Any storage failure is a server problem → 500
 		*/
-		err := json.NewDecoder(r.Body).Decode(&student)
+		id, err := storage.CreateStudent(r.Context(), student)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
 
 		/*
-		   STEP 3: Handle EMPTY BODY
+		   STEP 3: SUCCESS RESPONSE
 		   --------------------------------------------------
-		   - If the client sends empty request body
-		   - json.Decode() returns io.EOF error
-		   - errors.Is(err, io.EOF) checks exact error type
+		   - No JSON decode error
+		   - No validation error
+		   - So we return HTTP status 201 (Created)
+		   - Location header tells the client where the new
+		     resource lives: {the collection path}/{id}, so
+		     /api/v1/students/7 (or the legacy /api/students/7)
+		   - Body is the stored student read back, so it has the
+		     new ID and the timestamps storage assigned
 		*/
-		if errors.Is(err, io.EOF) {
+		student, err = storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
 
-			// Send nice JSON error
-			response.WriteJson(
-				w,
-				http.StatusBadRequest,
-				response.GeneralError(fmt.Errorf("empty body")),
-			)
-			return // STOP further execution
+		w.Header().Set("Location", fmt.Sprintf("%s/%s", r.URL.Path, student.PublicId))
+		writeStudent(w, http.StatusCreated, student)
+	}
+}
+
+/*
+GetById()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students/{id}".
+	  → This is the URL the create handler puts in Location.
+
+	RESPONSES:
+	  → 200 with the student; the ETag header identifies its
+	    version (for If-Match on a later PUT/PATCH)
+	  → 304 (no body) when If-None-Match lists that ETag: the
+	    client's copy is current, nothing to download
+	  → 404 when no student has this ID
+	  → 400 when {id} is malformed
+*/
+func GetById(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
 		}
 
-		/*
-		   STEP 4: Handle ANY OTHER JSON PARSING ERROR
-		   --------------------------------------------------
-		   Examples:
-		     - Missing commas
-		     - Wrong JSON syntax
-		     - Type mismatch
-		*/
+		logging.FromContext(r.Context()).Info("getting a student", slog.Int64("id", id))
+
+		student, err := storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		// Polling clients send back the ETag they have
+		etag := studentETag(student)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+			response.WriteNotModified(w, etag)
+			return
+		}
+
+		writeStudent(w, http.StatusOK, student)
+	}
+}
+
+/*
+PAGINATION LIMITS
+-------------------------------------------------------------
+  - defaultLimit → used when the client doesn't send ?limit=
+  - maxLimit     → hard cap so nobody can dump the whole table at once
+*/
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+/*
+GetList()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students".
+	  → Responds with one page of students in the list envelope:
+	      {"data":[...],"meta":{"total":…,"limit":…,"offset":…}}
+
+	QUERY PARAMETERS:
+	  - limit   → page size   (default 50, capped at 500)
+	  - offset  → rows to skip (default 0)
+	  - cursor  → meta.next_cursor of the previous page; switches to
+	              cursor pagination (cannot be combined with offset)
+	  - name    → case-insensitive substring of the name
+	  - email   → case-insensitive exact email
+	  - min_age → inclusive lower age bound
+	  - max_age → inclusive upper age bound
+	  - tag     → a tag the students must have; repeat it to
+	              require several (?tag=a&tag=b)
+	  - include_deleted → true also lists soft-deleted students
+	              (with deleted_at set); the route requires auth for it
+	  Filters combine with AND.
+
+	ERRORS:
+	  → 400 if limit/offset/min_age/max_age are non-numeric or out of range
+	  → 400 if a tag is malformed, or there are more than 10
+	  → 400 if include_deleted is not a boolean
+	  → 400 if cursor is malformed or sent together with offset
+	  → 500 if storage fails
+*/
+func GetList(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		logging.FromContext(r.Context()).Info("getting all students")
+
+		// STEP 1: read the filters
+		filter, err := parseFilter(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+
+		if raw := r.URL.Query().Get("include_deleted"); raw != "" {
+			filter.IncludeDeleted, err = strconv.ParseBool(raw)
+			if err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.BadRequest("include_deleted must be true or false"))
+				return
+			}
+		}
+
+		// STEP 2: paginate, fetch and respond
+		writeStudentPage(w, r, storage, filter)
+	}
+}
+
+/*
+Search()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "GET /api/students/search".
+	  → ?q= is split on spaces; every word must appear in the name
+	    or the email (case-insensitive), so "john gmail" matches a
+	    John with a gmail address.
+	  → Same envelope, pagination (limit/offset/cursor) and
+	    filters as GetList; no matches is an empty data array.
+
+	ERRORS:
+	  → 400 if q is shorter than 2 characters
+	  → 400 for the same parameter errors as GetList
+*/
+func Search(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		q := strings.TrimSpace(r.URL.Query().Get("q"))
+
+		logging.FromContext(r.Context()).Info("searching students", slog.String("q", q))
+
+		// STEP 1: a one-letter query would match nearly everything
+		if utf8.RuneCountInString(q) < minSearchLength {
+			response.WriteJson(w, http.StatusBadRequest,
+				response.BadRequest(fmt.Sprintf("q must be at least %d characters", minSearchLength)))
+			return
+		}
+
+		// STEP 2: the usual filters, plus the search terms
+		filter, err := parseFilter(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+			return
+		}
+		filter.Terms = strings.Fields(q)
+
+		// STEP 3: paginate, fetch and respond
+		writeStudentPage(w, r, storage, filter)
+	}
+}
+
+// minSearchLength is the shortest ?q= Search accepts.
+const minSearchLength = 2
+
+/*
+writeStudentPage()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The pagination plumbing shared by GetList and Search:
+	    reads limit/offset/cursor, fetches one page of students
+	    matching filter, counts the total and writes the envelope.
+*/
+func writeStudentPage(w http.ResponseWriter, r *http.Request, storage storage.Storage, filter types.StudentFilter) {
+
+	// STEP 1: read ?limit= / ?offset= / ?cursor= with defaults and validation
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+		return
+	}
+
+	afterID, cursorMode, err := parseCursor(r, storage)
+	if errors.Is(err, errInvalidCursor) || errors.Is(err, errCursorWithOffset) {
+		response.WriteJson(w, http.StatusBadRequest, response.BadRequest(err.Error()))
+		return
+	}
+	if err != nil {
+		writeStorageError(w, r, 0, err)
+		return
+	}
+
+	// STEP 2: fetch the page from storage
+	//   cursor mode asks for one row more than the page: if it comes
+	//   back there is a next page, and the cursor is the last ID shown
+	meta := response.Meta{Limit: limit, Offset: offset}
+
+	var students []types.Student
+	if cursorMode {
+		students, err = storage.ListStudentsAfter(r.Context(), filter, afterID, limit+1)
+		if err == nil && len(students) > limit {
+			students = students[:limit]
+			meta.NextCursor = encodeCursor(students[limit-1].PublicId)
+		}
+	} else {
+		students, err = storage.ListStudents(r.Context(), filter, limit, offset)
+	}
+	if err != nil {
+		writeStorageError(w, r, 0, err)
+		return
+	}
+
+	// STEP 3: total across all pages, with the same filters
+	meta.Total, err = storage.CountStudents(r.Context(), filter)
+	if err != nil {
+		writeStorageError(w, r, 0, err)
+		return
+	}
+
+	// STEP 4: a nil slice encodes as null → always send []
+	if students == nil {
+		students = []types.Student{}
+	}
+
+	response.WriteJsonWithMeta(w, http.StatusOK, students, meta)
+}
+
+/*
+Update()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "PUT /api/students/{id}".
+	  → Replaces every field of an existing student.
+
+	FLOW:
+	  - parse {id} from the path            → 400 if malformed
+	  - decode + validate the same payload
+	    the create handler accepts          → 400 on failure
+	  - the version being edited, from If-Match
+	    or "version" (see expectedVersion)  → 428 if required but missing
+	  - storage.UpdateStudent               → 404 if no row matched,
+	                                          412 if someone else updated it first
+	  - respond 200 with the updated record and its new ETag
+*/
+func Update(storage storage.Storage, requireIfMatch bool, phoneCountry string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which student? (path value from "PUT /api/students/{id}")
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("updating a student", slog.Int64("id", id))
+
+		// STEP 2: same decoding and validation rules as create
+		student, ok := decodeStudent(w, r, phoneCountry)
+		if !ok {
+			return
+		}
+
+		// STEP 3: which version the client edited (0 = don't check)
+		version, ok := expectedVersion(w, r, id, student.Version, requireIfMatch)
+		if !ok {
+			return
+		}
+
+		// STEP 4: replace the row; "updated" is false when the ID doesn't exist
+		updated, err := storage.UpdateStudent(r.Context(), id, student, version)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !updated {
+			writeNotFound(w, r, id)
+			return
+		}
+
+		// STEP 5: send back what is now stored (incl. the new updated_at)
+		student, err = storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		writeStudent(w, http.StatusOK, student)
+	}
+}
+
+/*
+Delete()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "DELETE /api/students/{id}".
+	  → This is a soft delete: the student disappears from every
+	    read but can be brought back with Restore until the purge
+	    removes it for good.
+
+	RESPONSES:
+	  → 204 (no body) when the student was deleted
+	  → 404 when no student has this ID (or it is already deleted)
+	  → 409 when the student is enrolled in courses and
+	    storage.on_student_delete is "block" (with "cascade" the
+	    enrollments are removed instead)
+	  → 400 when {id} is malformed
+*/
+func Delete(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
 		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("deleting a student", slog.Int64("id", id))
+
+		deleted, err := storage.DeleteStudent(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !deleted {
+			writeNotFound(w, r, id)
+			return
+		}
+
+		response.WriteNoContent(w)
+	}
+}
+
+/*
+Restore()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for
+	    "POST /api/students/{id}/restore": undoes a soft delete.
+
+	RESPONSES:
+	  → 200 with the restored student
+	  → 404 when no soft-deleted student has this ID (including
+	    live students: there is nothing to restore)
+	  → 409 when a live student has taken the email since
+	  → 400 when {id} is malformed
+*/
+func Restore(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("restoring a student", slog.Int64("id", id))
+
+		restored, err := storage.RestoreStudent(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !restored {
 			response.WriteJson(
 				w,
-				http.StatusBadRequest,
-				response.GeneralError(err),
+				http.StatusNotFound,
+				response.NotFound(fmt.Sprintf("no deleted student with id %s", publicID(r, id))),
 			)
 			return
 		}
 
-		/*
-		   STEP 5: STRUCT VALIDATION USING validator/v10
-		   --------------------------------------------------
-		   - Student struct likely contains tags like:
-		         Name  string `validate:"required"`
-		         Age   int    `validate:"required"`
-		   - validator.New().Struct(student)
-		         → checks all tags
-		         → returns error if validation fails
-		*/
-		if err := validator.New().Struct(student); err != nil {
+		student, err := storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		writeStudent(w, http.StatusOK, student)
+	}
+}
 
-			// Convert validation errors into readable JSON
-			validateErrs := err.(validator.ValidationErrors)
+/*
+Patch()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "PATCH /api/students/{id}".
+	  → Updates ONLY the fields present in the body.
+
+	WHY A SEPARATE STRUCT?
+	  → types.StudentPatch uses pointer fields, so a missing key
+	    stays nil and we never overwrite a column by accident.
+
+	RESPONSES:
+	  → 200 with the fully merged student and its new ETag
+	  → 400 for malformed IDs, bad JSON, invalid fields or {}
+	  → 404 when no student has this ID
+	  → 412 / 428: same version rules as Update
+*/
+func Patch(storage storage.Storage, requireIfMatch bool, phoneCountry string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
 
+		logging.FromContext(r.Context()).Info("patching a student", slog.Int64("id", id))
+
+		// STEP 1: decode into pointer fields
+		var patch types.StudentPatch
+		if !decodeJSON(w, r, &patch) {
+			return
+		}
+
+		// STEP 2: {} would be a no-op UPDATE → tell the client instead
+		if patch.IsEmpty() {
 			response.WriteJson(
 				w,
 				http.StatusBadRequest,
-				response.ValidationError(validateErrs),
+				response.GeneralError(fmt.Errorf("no fields to update")),
 			)
 			return
 		}
 
-		/*
-		   STEP 6: SUCCESS RESPONSE
-		   --------------------------------------------------
-		   - No JSON decode error
-		   - No validation error
-		   - So we return HTTP status 201 (Created)
-		   - Body is a simple JSON map: {"Success":"ok"}
-		*/
-		response.WriteJson(w, http.StatusCreated, map[string]string{
-			"Success": "ok",
-		})
+		// STEP 3: validate only what was sent (nil fields are skipped),
+		// in stored form like decodeStudent
+		patch.Normalize()
+		patch.NormalizePhone(phoneCountry)
+		if !validateStruct(w, patch) {
+			return
+		}
+
+		// STEP 4: which version the client edited (0 = don't check)
+		bodyVersion := 0
+		if patch.Version != nil {
+			bodyVersion = *patch.Version
+		}
+
+		version, ok := expectedVersion(w, r, id, bodyVersion, requireIfMatch)
+		if !ok {
+			return
+		}
+
+		// STEP 5: update the provided columns
+		updated, err := storage.PatchStudent(r.Context(), id, patch, version)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		if !updated {
+			writeNotFound(w, r, id)
+			return
+		}
+
+		// STEP 6: read back the merged record (old values + patched ones)
+		student, err := storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		writeStudent(w, http.StatusOK, student)
+	}
+}
+
+/*
+writeStorageError()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Translates a storage error into the right HTTP response.
+
+	WHY A PACKAGE-LEVEL FUNCTION?
+	  → Inside the handlers the "storage" parameter shadows the
+	    storage package, so the sentinel errors are only reachable here.
+
+	MAPPING:
+	  - storage.ErrInvalidID      → 400, the {id} is no ID at all
+	  - storage.ErrNotFound       → 404 (code "not_found")
+	  - storage.ErrCourseNotFound → 404, naming the course
+	  - storage.ErrPhotoNotFound  → 404, the student has no photo
+	  - storage.ErrDuplicateEmail → 409 (code "conflict")
+	  - storage.ErrStudentEnrolled → 409, naming the student of
+	    the {id} the client sent (storage only knows the row ID,
+	    which id_scheme: uuid keeps private)
+	  - storage.ErrInvalidTransition, ErrAlreadyEnrolled,
+	    ErrCourseFull, ErrTooManyTags → 409 with the error itself
+	    as message (it says what blocked)
+	  - storage.ErrVersionConflict → 412 (code "precondition_failed"),
+	    telling the client to fetch the student again
+	  - context.DeadlineExceeded  → 503 (code "timeout"), the same
+	    answer middleware.Timeout gives when it wins the race
+	  - anything else             → 500 (code "internal"); the real
+	    error is logged, the client only sees a generic message
+*/
+func writeStorageError(w http.ResponseWriter, r *http.Request, id int64, err error) {
+	if errors.Is(err, storage.ErrInvalidID) {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid id %q", r.PathValue("id"))))
+		return
+	}
+
+	if errors.Is(err, storage.ErrNotFound) {
+		writeNotFound(w, r, id)
+		return
+	}
+
+	if errors.Is(err, storage.ErrDuplicateEmail) {
+		response.WriteJson(w, http.StatusConflict, response.Conflict(storage.ErrDuplicateEmail.Error()))
+		return
+	}
+
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		response.WriteJson(w, http.StatusNotFound, response.NotFound(err.Error()))
+		return
+	}
+
+	if errors.Is(err, storage.ErrPhotoNotFound) {
+		response.WriteJson(w, http.StatusNotFound, response.NotFound(fmt.Sprintf("student with id %s has no photo", publicID(r, id))))
+		return
+	}
+
+	if errors.Is(err, storage.ErrStudentEnrolled) {
+		msg := err.Error()
+		if r.PathValue("id") != "" {
+			msg = fmt.Sprintf("student with id %s is still enrolled in courses", publicID(r, id))
+		}
+		response.WriteJson(w, http.StatusConflict, response.Conflict(msg))
+		return
+	}
+
+	if errors.Is(err, storage.ErrInvalidTransition) || errors.Is(err, storage.ErrAlreadyEnrolled) ||
+		errors.Is(err, storage.ErrCourseFull) || errors.Is(err, storage.ErrTooManyTags) {
+		response.WriteJson(w, http.StatusConflict, response.Conflict(err.Error()))
+		return
+	}
+
+	if errors.Is(err, storage.ErrVersionConflict) {
+		response.WriteJson(w, http.StatusPreconditionFailed, response.PreconditionFailed(
+			fmt.Sprintf("student with id %s was modified since you fetched it; GET it again and retry with the new ETag", publicID(r, id)),
+		))
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		logging.FromContext(r.Context()).Warn("storage call timed out", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusServiceUnavailable, response.Timeout("request timed out"))
+		return
+	}
+
+	logging.FromContext(r.Context()).Error("storage error", slog.String("error", err.Error()))
+
+	response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+}
+
+/*
+writeNotFound()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → One place for the 404 body of an unknown student ID,
+	    named as the client sent it (see publicID).
+*/
+func writeNotFound(w http.ResponseWriter, r *http.Request, id int64) {
+	response.WriteJson(
+		w,
+		http.StatusNotFound,
+		response.NotFound(fmt.Sprintf("student with id %s not found", publicID(r, id))),
+	)
+}
+
+/*
+parseID()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads the {id} wildcard registered in the route pattern
+	    (r.PathValue needs Go 1.22+) and turns it into the row ID
+	    every storage method takes (see storage.IDStore): a row
+	    ID is used as it is under storage.id_scheme serial, a
+	    UUID is looked up.
+
+	ERRORS (for writeStorageError):
+	  → storage.ErrInvalidID (400) when it is neither
+	  → storage.ErrNotFound (404) when no student has it, which
+	    is also the answer to a row ID under uuid
+*/
+func parseID(r *http.Request, ids storage.IDStore) (int64, error) {
+	return ids.ResolveStudentID(r.Context(), r.PathValue("id"))
+}
+
+// publicID is the ID clients know the student with row ID id by, for
+// messages and the handlers that answer with a student_id: the UUID of
+// the URL (lower-cased) when parseID resolved one, the row ID otherwise.
+// With no row ID (0, parseID failed) it is the {id} as it was sent.
+func publicID(r *http.Request, id int64) types.ID {
+	raw := r.PathValue("id")
+	switch {
+	case storage.IsUUID(raw):
+		return types.ID(strings.ToLower(raw))
+	case id == 0 && raw != "":
+		return types.ID(raw)
+	}
+
+	return types.SerialID(id)
+}
+
+/*
+parsePagination()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads limit and offset from the query string.
+
+	RULES:
+	  - missing limit  → defaultLimit
+	  - limit > max    → clamped to maxLimit
+	  - limit < 1      → error
+	  - missing offset → 0
+	  - offset < 0     → error
+	  - non-numeric    → error
+*/
+func parsePagination(r *http.Request) (int, int, error) {
+	limit := defaultLimit
+	offset := 0
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+		limit = min(n, maxLimit)
+	}
+
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
+
+/*
+parseCursor() / encodeCursor()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → A cursor is the last ID of the previous page, base64url
+	    encoded so clients treat it as opaque instead of doing
+	    arithmetic on it.
+	  → It is the public ID (a UUID under storage.id_scheme
+	    uuid), resolved back to a row ID like a path {id}, so
+	    cursors don't give row IDs away either.
+	  → parseCursor reports whether ?cursor= was sent at all; an
+	    empty value starts cursor mode from the first row.
+
+	RULES:
+	  - cursor together with offset → error
+	  - not base64 / not an ID of a student → error ("invalid cursor")
+	  - any other error is the lookup failing (writeStorageError)
+*/
+func parseCursor(r *http.Request, ids storage.IDStore) (int64, bool, error) {
+	query := r.URL.Query()
+	if !query.Has("cursor") {
+		return 0, false, nil
+	}
+
+	if query.Has("offset") {
+		return 0, false, errCursorWithOffset
 	}
+
+	// ?cursor= with no value asks for the first page in cursor mode
+	if query.Get("cursor") == "" {
+		return 0, true, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(query.Get("cursor"))
+	if err != nil {
+		return 0, false, errInvalidCursor
+	}
+
+	id, err := ids.ResolveStudentID(r.Context(), string(raw))
+	if errors.Is(err, storage.ErrInvalidID) || errors.Is(err, storage.ErrNotFound) {
+		return 0, false, errInvalidCursor
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+var (
+	errInvalidCursor    = errors.New("invalid cursor")
+	errCursorWithOffset = errors.New("cursor and offset cannot be combined")
+)
+
+func encodeCursor(id types.ID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+/*
+parseFilter()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads name, email, email_domain, status, min_age,
+	    max_age and tag from the query string into a
+	    StudentFilter.
+
+	RULES:
+	  - status must be one of types.Statuses
+	  - tag may be repeated (?tag=a&tag=b: students with both),
+	    at most types.MaxTags times; each one is normalized
+	    and must pass types.IsTag
+	  - email_domain is what follows the "@" ("example.com"; a
+	    leading "@" is accepted too)
+	  - ages must be non-negative integers
+	  - min_age > max_age → error
+*/
+func parseFilter(r *http.Request) (types.StudentFilter, error) {
+	query := r.URL.Query()
+
+	filter := types.StudentFilter{
+		Name:        query.Get("name"),
+		Email:       types.NormalizeEmail(query.Get("email")),
+		EmailDomain: strings.TrimPrefix(types.NormalizeEmail(query.Get("email_domain")), "@"),
+		Status:      query.Get("status"),
+	}
+
+	if filter.Status != "" && !types.IsStatus(filter.Status) {
+		return types.StudentFilter{}, fmt.Errorf("status must be one of %s", strings.Join(types.Statuses, ", "))
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  **int
+	}{
+		{"min_age", &filter.MinAge},
+		{"max_age", &filter.MaxAge},
+	} {
+		raw := query.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return types.StudentFilter{}, fmt.Errorf("%s must be a non-negative integer", bound.name)
+		}
+		*bound.dst = &n
+	}
+
+	if filter.MinAge != nil && filter.MaxAge != nil && *filter.MinAge > *filter.MaxAge {
+		return types.StudentFilter{}, fmt.Errorf("min_age must not be greater than max_age")
+	}
+
+	if tags := query["tag"]; len(tags) > 0 {
+		filter.Tags = types.NormalizeTags(tags)
+		if len(filter.Tags) > types.MaxTags {
+			return types.StudentFilter{}, fmt.Errorf("at most %d tag filters are allowed", types.MaxTags)
+		}
+		for _, tag := range filter.Tags {
+			if !types.IsTag(tag) {
+				return types.StudentFilter{}, errInvalidTag
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+/*
+decodeStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Shared by every handler that receives a full student body
+	    (create, update) so they all decode and validate the same way.
+	  → phoneCountry (config api.phone_country) is the country of
+	    phone numbers sent without "+".
+
+	RETURN VALUE:
+	  → the decoded student and true on success
+	  → false when a 400 response has already been written
+*/
+func decodeStudent(w http.ResponseWriter, r *http.Request, phoneCountry string) (types.Student, bool) {
+
+	/*
+	   STEP 1:
+	   Create a student variable that will store the JSON body.
+
+	   types.Student:
+	     - Your custom struct
+	     - It will receive values according to JSON keys sent by client
+	*/
+	var student types.Student
+
+	// STEP 2: decode the body (writes 400 on empty/malformed JSON)
+	if !decodeJSON(w, r, &student) {
+		return student, false
+	}
+
+	// The ID is assigned by storage / taken from the URL, never from the
+	// body; the timestamps are always set by storage
+	student.Id = 0
+	student.CreatedAt = time.Time{}
+	student.UpdatedAt = time.Time{}
+	student.DeletedAt = nil
+
+	// Stored form of the fields (trimmed name, lower-cased email), so
+	// validation and the uniqueness check see what will be stored;
+	// the phone in E.164
+	student.Normalize()
+	student.NormalizePhone(phoneCountry)
+
+	// STEP 3: check the validate:"..." tags (writes 400 on failure)
+	if !validateStruct(w, student) {
+		return student, false
+	}
+
+	return student, true
+}
+
+/*
+decodeJSON()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Decodes the JSON request body into dst (pointer to any struct).
+	  → Writes the 400 response itself when decoding fails.
+
+	RULES:
+	  → The body must be valid UTF-8: encoding/json would quietly
+	    turn every invalid byte into U+FFFD and store that.
+	  → The body is exactly one JSON value: {"name": ...}{"x": 1}
+	    is rejected instead of the second half being dropped.
+	  → Duplicate keys are not an error, the last one wins (as in
+	    encoding/json); nesting past its depth limit is a 400.
+
+	RETURN VALUE:
+	  → true on success, false when a response was already written
+*/
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+
+	/*
+	   STEP 1: Read the whole body
+	   --------------------------------------------------
+	   - It has to be checked for UTF-8 before decoding, and
+	     middleware.MaxBodyBytes already caps its size.
+	   - middleware.MaxBodyBytes wraps r.Body with
+	     http.MaxBytesReader, which fails with
+	     *http.MaxBytesError once the limit is crossed.
+	   - 413 tells the client the payload itself is the problem.
+	*/
+	body, err := io.ReadAll(r.Body)
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.WriteJson(
+			w,
+			http.StatusRequestEntityTooLarge,
+			response.BadRequest(fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit)),
+		)
+		return false
+	}
+
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.BadRequest("could not read request body"))
+		return false
+	}
+
+	/*
+	   STEP 2: Handle INVALID UTF-8
+	   --------------------------------------------------
+	   - JSON text is UTF-8 (RFC 8259). A name like
+	     "Ann\xffLee" would otherwise be stored as "Ann�Lee".
+	*/
+	if !utf8.Valid(body) {
+		response.WriteJson(
+			w,
+			http.StatusBadRequest,
+			response.BadRequest("request body must be valid UTF-8"),
+		)
+		return false
+	}
+
+	/*
+	   STEP 3:
+	   Decode JSON request body into "dst".
+	   json.NewDecoder reads the raw JSON of the request.
+
+	   Decode(dst):
+	     - Converts JSON → Go struct
+	     - Fills student.Name, student.Age, student.Email, etc.
+
+	   POSSIBLE ERRORS:
+	     - io.EOF → body is empty ({} or nothing)
+	     - invalid JSON format → {"name":123}
+	     - wrong types
+	*/
+	decoder := json.NewDecoder(bytes.NewReader(body))
+
+	// Reject keys that don't exist on dst ({"emial": ...}) instead of
+	// silently dropping them and failing validation with no hint why.
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(dst)
+
+	/*
+	   STEP 4: Handle EMPTY BODY
+	   --------------------------------------------------
+	   - If the client sends empty request body
+	   - json.Decode() returns io.EOF error
+	   - errors.Is(err, io.EOF) checks exact error type
+	*/
+	if errors.Is(err, io.EOF) {
+
+		// Send nice JSON error
+		response.WriteJson(
+			w,
+			http.StatusBadRequest,
+			response.BadRequest("empty body"),
+		)
+		return false // STOP further execution
+	}
+
+	/*
+	   STEP 5: Handle UNKNOWN FIELDS
+	   --------------------------------------------------
+	   - encoding/json has no typed error for this case, the
+	     message is: json: unknown field "emial"
+	   - We strip the "json: " prefix so the client sees
+	     exactly which key was rejected.
+	*/
+	if field, found := strings.CutPrefix(fmt.Sprint(err), "json: unknown field "); found {
+		response.WriteJson(
+			w,
+			http.StatusBadRequest,
+			response.BadRequest(fmt.Sprintf("unknown field %s", field)),
+		)
+		return false
+	}
+
+	/*
+	   STEP 6: Handle WRONG TYPES
+	   --------------------------------------------------
+	   - {"age": 20.5}, {"age": "20"}, {"age": 1e3} → a 400
+	     naming the field ("age must be an integer"), see
+	     response.TypeError; nothing is truncated.
+	   - {"age": 1e400} → "age is out of range".
+	*/
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		response.WriteJson(w, http.StatusBadRequest, response.TypeError(typeErr))
+		return false
+	}
+
+	/*
+	   STEP 7: Handle ANY OTHER JSON PARSING ERROR
+	   --------------------------------------------------
+	   Examples:
+	     - Missing commas
+	     - Wrong JSON syntax
+	     - Nested deeper than encoding/json allows
+	*/
+	if err != nil {
+		response.WriteJson(
+			w,
+			http.StatusBadRequest,
+			response.BadRequest(err.Error()),
+		)
+		return false
+	}
+
+	/*
+	   STEP 8: Handle TRAILING DATA
+	   --------------------------------------------------
+	   - Anything but whitespace after the value: a second
+	     object, a stray "}" or garbage.
+	*/
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		response.WriteJson(
+			w,
+			http.StatusBadRequest,
+			response.BadRequest("body must contain a single JSON value"),
+		)
+		return false
+	}
+
+	return true
+}
+
+/*
+validateStruct()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Runs validator/v10 on v and writes a 400 with readable
+	    messages when any validate:"..." tag fails.
+
+	RETURN VALUE:
+	  → true when v is valid, false when a response was already written
+*/
+func validateStruct(w http.ResponseWriter, v any) bool {
+
+	/*
+	   STRUCT VALIDATION USING validator/v10
+	   --------------------------------------------------
+	   - Student struct likely contains tags like:
+	         Name  string `validate:"required"`
+	         Age   int    `validate:"required"`
+	   - validation.Struct(v)
+	         → checks all tags with the shared validator
+	         → returns error if validation fails
+	*/
+	if err := validation.Struct(v); err != nil {
+
+		// Convert validation errors into readable JSON
+		validateErrs := err.(validator.ValidationErrors)
+
+		response.WriteJson(
+			w,
+			http.StatusBadRequest,
+			response.ValidationError(validateErrs),
+		)
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,74 @@
+package student_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+)
+
+// FuzzCreateStudent sends arbitrary bodies to POST /api/v1/students through
+// the full handler tree: whatever the body, the answer is a JSON 201, 400,
+// 413 or 422, never a 500 (Recover turns panics into one). Minimizing the
+// deeply nested inputs is slow, hence the short -fuzzminimizetime:
+//
+//	go test -run=^$ -fuzz=FuzzCreateStudent -fuzzminimizetime=5s ./internal/http/handlers/student
+func FuzzCreateStudent(f *testing.F) {
+	seeds := []string{
+		`{"name":"Ann Lee","email":"ann@example.com","age":20}`,
+		`{"name":"Ann Lee","email":"ann@example.com","date_of_birth":"2005-01-31","phone":"+44 20 7946 0958","tags":["x"]}`,
+		"{\"name\":\"Ann\xff\xfeLee\",\"email\":\"ann@example.com\",\"age\":20}",
+		`{"name":"Ann\ud800Lee","email":"ann@example.com","age":20}`,
+		`{"name":"Ann\u0000Lee","email":"ann@example.com","age":20}`,
+		`{"name":"Ann Lee","email":"ann@example.com","age":1e400}`,
+		`{"name":"Ann Lee","email":"ann@example.com","age":123456789012345678901234567890}`,
+		`{"name":"Ann Lee","email":"ann@example.com","age":-1e-400}`,
+		`{"name":"Ann Lee","email":"ann@example.com","age":20}{"x":1}`,
+		`{"name":"Ann Lee","email":"ann@example.com","age":20}}`,
+		`{"name":"Ann Lee","name":"Bob Lee","email":"ann@example.com","age":20}`,
+		// one level past the nesting encoding/json accepts
+		`{"tags":` + strings.Repeat("[", 10001) + strings.Repeat("]", 10001) + `}`,
+		strings.Repeat("[", 10001),
+		`{"name":{"first":"Ann"},"email":"ann@example.com","age":20}`,
+		`{"emial":"ann@example.com"}`,
+		`null`, `[]`, `"x"`, `0`, ``, ` `,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	quiet(f)
+	cfg := apptest.Config(f)
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		// a fresh store per body: the same valid body twice is a 409
+		handler := apptest.Handler(cfg)
+
+		req := httptest.NewRequest(http.MethodPost, students, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		switch rec.Code {
+		case http.StatusCreated, http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		default:
+			t.Fatalf("status %d for body %q: %s", rec.Code, body, rec.Body)
+		}
+		if !json.Valid(rec.Body.Bytes()) {
+			t.Fatalf("status %d with a body that is not JSON: %q", rec.Code, rec.Body)
+		}
+	})
+}
+
+// quiet drops the log lines of every request until the test ends: a fuzz
+// run makes millions.
+func quiet(tb testing.TB) {
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	tb.Cleanup(func() { slog.SetDefault(previous) })
+}
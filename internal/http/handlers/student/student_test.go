@@ -0,0 +1,215 @@
+package student_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+const students = "/api/v1/students"
+
+// ann is a valid create body; tests copy it and change a field.
+func ann() map[string]any {
+	return map[string]any{"name": "Ann Lee", "email": "ann@example.com", "age": 20}
+}
+
+// createAnn creates ann() and returns the stored student.
+func createAnn(t *testing.T, srv *httptest.Server) types.Student {
+	t.Helper()
+
+	res := apptest.Do(t, srv, http.MethodPost, students, ann())
+	if res.Status != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", res.Status, res.Body)
+	}
+
+	var created types.Student
+	res.JSON(t, &created)
+	return created
+}
+
+func TestStudentLifecycle(t *testing.T) {
+	srv := apptest.Server(t, apptest.Config(t))
+
+	// create
+	res := apptest.Do(t, srv, http.MethodPost, students, ann())
+	if res.Status != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", res.Status, res.Body)
+	}
+	var created types.Student
+	res.JSON(t, &created)
+	if created.PublicId != "1" || created.Name != "Ann Lee" || created.Email != "ann@example.com" || created.Age != 20 ||
+		created.Status != types.StatusActive || created.Version != 1 {
+		t.Fatalf("create: got %+v", created)
+	}
+	if got := res.Header.Get("Location"); got != students+"/1" {
+		t.Errorf("create: Location %q, want %q", got, students+"/1")
+	}
+	if got := res.Header.Get("ETag"); got != `W/"1-1"` {
+		t.Errorf("create: ETag %q, want %q", got, `W/"1-1"`)
+	}
+
+	// get
+	res = apptest.Do(t, srv, http.MethodGet, students+"/1", nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("get: status %d, body %s", res.Status, res.Body)
+	}
+	var got types.Student
+	res.JSON(t, &got)
+	if got.PublicId != created.PublicId || got.Email != created.Email || !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("get: got %+v, want %+v", got, created)
+	}
+
+	// list
+	res = apptest.Do(t, srv, http.MethodGet, students+"?limit=10", nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("list: status %d, body %s", res.Status, res.Body)
+	}
+	var page struct {
+		Data []types.Student `json:"data"`
+		Meta struct {
+			Total  int `json:"total"`
+			Limit  int `json:"limit"`
+			Offset int `json:"offset"`
+		} `json:"meta"`
+	}
+	res.JSON(t, &page)
+	if len(page.Data) != 1 || page.Data[0].PublicId != "1" || page.Meta.Total != 1 || page.Meta.Limit != 10 {
+		t.Errorf("list: got %+v", page)
+	}
+
+	// update (PUT replaces every field)
+	update := ann()
+	update["name"] = "Ann Marie Lee"
+	update["age"] = 21
+	res = apptest.Do(t, srv, http.MethodPut, students+"/1", update, "If-Match", `W/"1-1"`)
+	if res.Status != http.StatusOK {
+		t.Fatalf("update: status %d, body %s", res.Status, res.Body)
+	}
+	res.JSON(t, &got)
+	if got.Name != "Ann Marie Lee" || got.Age != 21 || got.Version != 2 {
+		t.Errorf("update: got %+v", got)
+	}
+
+	// patch (only the keys sent change)
+	res = apptest.Do(t, srv, http.MethodPatch, students+"/1", map[string]any{"age": 22})
+	if res.Status != http.StatusOK {
+		t.Fatalf("patch: status %d, body %s", res.Status, res.Body)
+	}
+	res.JSON(t, &got)
+	if got.Name != "Ann Marie Lee" || got.Age != 22 || got.Version != 3 {
+		t.Errorf("patch: got %+v", got)
+	}
+
+	// delete, then the student is gone
+	res = apptest.Do(t, srv, http.MethodDelete, students+"/1", nil)
+	if res.Status != http.StatusNoContent || len(res.Body) != 0 {
+		t.Fatalf("delete: status %d, body %s", res.Status, res.Body)
+	}
+	if res = apptest.Do(t, srv, http.MethodGet, students+"/1", nil); res.Status != http.StatusNotFound {
+		t.Errorf("get after delete: status %d, want 404", res.Status)
+	}
+
+	// restore brings it back, one version later
+	res = apptest.Do(t, srv, http.MethodPost, students+"/1/restore", nil)
+	if res.Status != http.StatusOK {
+		t.Fatalf("restore: status %d, body %s", res.Status, res.Body)
+	}
+	res.JSON(t, &got)
+	if got.PublicId != "1" || got.Age != 22 || got.Version != 4 || got.DeletedAt != nil {
+		t.Errorf("restore: got %+v", got)
+	}
+	if res = apptest.Do(t, srv, http.MethodGet, students+"/1", nil); res.Status != http.StatusOK {
+		t.Errorf("get after restore: status %d, want 200", res.Status)
+	}
+}
+
+func TestStudentErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		header []string
+		status int
+		want   string
+	}{
+		{
+			name: "empty body", method: http.MethodPost, path: students, body: "",
+			status: http.StatusBadRequest,
+			want:   `{"status":"Error","code":"bad_request","error":"empty body"}`,
+		},
+		{
+			name: "malformed JSON", method: http.MethodPost, path: students, body: `{"name": "Ann Lee",`,
+			status: http.StatusBadRequest,
+			want:   `{"status":"Error","code":"bad_request","error":"unexpected EOF"}`,
+		},
+		{
+			name: "validation failure", method: http.MethodPost, path: students,
+			body:   map[string]any{"name": "A", "email": "not-an-email", "age": 151},
+			status: http.StatusBadRequest,
+			want: `{"status":"Error","code":"validation_failed","error":"name must be at least 2 characters, email must be a valid email address, age must be at most 150",` +
+				`"errors":[{"field":"name","tag":"min","message":"name must be at least 2 characters"},` +
+				`{"field":"email","tag":"email","message":"email must be a valid email address"},` +
+				`{"field":"age","tag":"lte","message":"age must be at most 150"}]}`,
+		},
+		{
+			name: "duplicate email", method: http.MethodPost, path: students,
+			body:   map[string]any{"name": "Ann Other", "email": "ANN@example.com", "age": 30},
+			status: http.StatusConflict,
+			want:   `{"status":"Error","code":"conflict","error":"student with this email already exists"}`,
+		},
+		{
+			name: "unknown id", method: http.MethodGet, path: students + "/99",
+			status: http.StatusNotFound,
+			want:   `{"status":"Error","code":"not_found","error":"student with id 99 not found"}`,
+		},
+		{
+			name: "unknown id on update", method: http.MethodPut, path: students + "/99", body: ann(),
+			status: http.StatusNotFound,
+			want:   `{"status":"Error","code":"not_found","error":"student with id 99 not found"}`,
+		},
+		{
+			name: "method not allowed", method: http.MethodPost, path: students + "/1",
+			status: http.StatusMethodNotAllowed,
+			want:   `{"status":"Error","code":"method_not_allowed","error":"method POST not allowed on /api/v1/students/1"}`,
+		},
+		{
+			name: "stale If-Match", method: http.MethodPut, path: students + "/1", body: ann(),
+			header: []string{"If-Match", `W/"1-7"`},
+			status: http.StatusPreconditionFailed,
+			want:   `{"status":"Error","code":"precondition_failed","error":"student with id 1 was modified since you fetched it; GET it again and retry with the new ETag"}`,
+		},
+		{
+			name: "not JSON", method: http.MethodPost, path: students, body: "name=Ann",
+			header: []string{"Content-Type", "text/plain"},
+			status: http.StatusUnsupportedMediaType,
+			want:   `{"status":"Error","error":"Content-Type must be application/json, got \"text/plain\""}`,
+		},
+	}
+
+	srv := apptest.Server(t, apptest.Config(t))
+	createAnn(t, srv)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := apptest.Do(t, srv, tt.method, tt.path, tt.body, tt.header...)
+
+			if res.Status != tt.status {
+				t.Errorf("status %d, want %d", res.Status, tt.status)
+			}
+			if got := strings.TrimSpace(string(res.Body)); got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+
+	// 405 names what is allowed
+	res := apptest.Do(t, srv, http.MethodPost, students+"/1", nil)
+	if got := res.Header.Get("Allow"); !strings.Contains(got, "GET") || !strings.Contains(got, "PUT") {
+		t.Errorf("405: Allow %q, want GET and PUT in it", got)
+	}
+}
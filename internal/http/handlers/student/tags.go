@@ -0,0 +1,90 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → the signature of the storage method
+   - errors   → the 400 of a malformed {tag}
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+*/
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// errInvalidTag is the 400 of a tag that fails types.IsTag, in the path or
+// in ?tag=; the same rule as the tag validation tag.
+var errInvalidTag = errors.New("tag must be 1-32 lower-case letters, digits, - or _, starting with a letter or digit")
+
+/*
+AddTag() / RemoveTag()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Return the http.HandlerFuncs for
+	    "POST /api/students/{id}/tags/{tag}" and
+	    "DELETE /api/students/{id}/tags/{tag}": one tag at a
+	    time, without sending the whole student.
+	  → {tag} is normalized (lower-cased) first, and both are
+	    idempotent: tagging twice, or removing a tag the student
+	    hasn't, succeeds without a new version.
+
+	RESPONSES:
+	  → 200 with the student as it is now (and its ETag)
+	  → 409 when adding an 11th tag (types.MaxTags)
+	  → 404 when no live student has this ID
+	  → 400 when {id} or {tag} is malformed
+*/
+func AddTag(storage storage.Storage) http.HandlerFunc {
+	return changeTag(storage, "tagging a student", storage.AddStudentTag)
+}
+
+func RemoveTag(storage storage.Storage) http.HandlerFunc {
+	return changeTag(storage, "untagging a student", storage.RemoveStudentTag)
+}
+
+// changeTag is the handler behind AddTag and RemoveTag; change is the
+// storage method, msg what gets logged.
+func changeTag(storage storage.Storage, msg string, change func(ctx context.Context, id int64, tag string) (bool, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: which student, which tag
+		id, err := parseID(r, storage)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		tag := types.NormalizeTag(r.PathValue("tag"))
+		if !types.IsTag(tag) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errInvalidTag))
+			return
+		}
+
+		logging.FromContext(r.Context()).Info(msg, slog.Int64("id", id), slog.String("tag", tag))
+
+		// STEP 2: storage checks the limit and applies it
+		if _, err := change(r.Context(), id, tag); err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		// STEP 3: send back the student as it is now
+		student, err := storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		writeStudent(w, http.StatusOK, student)
+	}
+}
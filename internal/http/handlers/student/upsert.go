@@ -0,0 +1,100 @@
+package student
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - fmt      → the Location header
+   - log/slog → structured logging (new standard logger)
+   - net/http → for HTTP handler, status codes
+*/
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// Outcome of an upsert, in the "result" field of the body
+const (
+	upsertCreated = "created"
+	upsertUpdated = "updated"
+)
+
+// upsertResponse is the stored student plus what the upsert did to it.
+type upsertResponse struct {
+	Result string `json:"result"`
+	types.Student
+}
+
+/*
+Upsert()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns an http.HandlerFunc for "PUT /api/students"
+	    (the collection): create-or-update by email, for sync
+	    jobs that don't know the IDs.
+	  → Same body and validation as a create. When a student
+	    already has the (normalized) email, its name, age,
+	    date of birth, phone and tags are replaced (the status only changes
+	    through POST …/{id}/status); otherwise it is created.
+	  → Replaced like a PUT /students/{id}: a body without
+	    "tags" clears the tags of an existing student, so a
+	    sync that doesn't manage tags must send them back.
+
+	RESPONSES:
+	  → 201, Location, {"result":"created", …the student}
+	  → 200, {"result":"updated", …the student}
+	  → 400 on an invalid body
+
+	NOTES:
+	  → storage.UpsertStudent does the lookup and the write in
+	    one statement, so two syncs sending the same new email
+	    at once give one create and one update, never a 409.
+	  → No If-Match: the email is the key, the caller never saw
+	    a version.
+*/
+func Upsert(storage storage.Storage, phoneCountry string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// STEP 1: same decoding and validation rules as create
+		student, ok := decodeStudent(w, r, phoneCountry)
+		if !ok {
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("upserting a student")
+
+		// STEP 2: create or update, atomically
+		id, created, err := storage.UpsertStudent(r.Context(), student)
+		if err != nil {
+			writeStorageError(w, r, 0, err)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info("student upserted", slog.Int64("id", id), slog.Bool("created", created))
+
+		// STEP 3: send back what is now stored
+		student, err = storage.GetStudentById(r.Context(), id)
+		if err != nil {
+			writeStorageError(w, r, id, err)
+			return
+		}
+
+		body := upsertResponse{Result: upsertUpdated, Student: student}
+		status := http.StatusOK
+		if created {
+			body.Result = upsertCreated
+			status = http.StatusCreated
+			w.Header().Set("Location", fmt.Sprintf("%s/%s", r.URL.Path, student.PublicId))
+		}
+
+		w.Header().Set("ETag", studentETag(student))
+		response.WriteJson(w, status, body)
+	}
+}
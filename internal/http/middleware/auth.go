@@ -0,0 +1,254 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → store the token subject / API client / role on the request
+   - subtle   → constant-time API key comparison
+   - errors   → match jwt.ErrTokenExpired
+   - log/slog → add the subject to the request-scoped logger
+   - net/http → http.Handler, headers
+   - slices   → is the caller's role among the allowed ones
+   - strconv  → quote the role in the 403 message
+   - strings  → strip the "Bearer " prefix
+   - auth     → token claims and parser (shared with the login endpoint)
+   - config   → the "auth" config section
+   - jwt/v5   → detect expired tokens
+   - response → 401 / 403 JSON bodies
+   - logging  → request-scoped logger
+   - storage  → the actor recorded in the audit trail
+*/
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/auth"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// APIKeyHeader carries static keys for service-to-service calls.
+const APIKeyHeader = "X-API-Key"
+
+/*
+Auth()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Requires credentials on the wrapped handler. Two kinds
+	    are accepted (see config.Auth):
+
+	    1) X-API-Key: <key>
+	       → compared in constant time against every configured
+	         key; the key's LABEL (never the secret) becomes the
+	         request's client name, its role comes from
+	         auth.api_key_roles (none when not listed, which
+	         config validation refuses, so RequireRole would
+	         answer 403).
+
+	    2) Authorization: Bearer <jwt>
+	       → HS256, signed with jwt_secret, not expired ("exp"
+	         is mandatory); the "sub" claim becomes the subject,
+	         the "role" claim the role (none when absent).
+	       → Issued by POST /api/auth/login or by anyone holding
+	         the secret. Refresh tokens of the login endpoint are
+	         refused: they only buy new tokens.
+
+	ON SUCCESS:
+	  → Subject/Client and Role are stored in the context, added
+	    to the request-scoped logger and reported to Logging.
+	  → Auth only authenticates: RequireRole, inside it, decides
+	    what the role may do.
+	  → The caller also becomes the storage actor, recorded in the
+	    audit trail: the subject as is, "api_key:<label>" for a key.
+
+	ON FAILURE:
+	  → 401 with WWW-Authenticate: Bearer and a JSON body that
+	    says what was wrong.
+
+	USAGE (only selected routes):
+	  requireAuth := middleware.Auth(cfg.Auth)
+	  router.Handle("POST /api/students", requireAuth(student.New(storage)))
+*/
+func Auth(cfg config.Auth) func(http.Handler) http.Handler {
+	secret := []byte(cfg.JWTSecret)
+
+	parser := auth.NewParser()
+
+	keyFunc := func(*jwt.Token) (any, error) {
+		return secret, nil
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			// 1) API key
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				client, ok := matchAPIKey(cfg.APIKeys, key)
+				if !ok {
+					writeUnauthorized(w, "invalid api key")
+					return
+				}
+
+				role := cfg.APIKeyRoles[client]
+
+				ctx := context.WithValue(r.Context(), clientKey, client)
+				ctx = context.WithValue(ctx, roleKey, role)
+				ctx = storage.WithActor(ctx, "api_key:"+client)
+				ctx = logging.With(ctx, slog.String("client", client), slog.String("role", role))
+				if info := requestInfoFrom(ctx); info != nil {
+					info.set(func(f *requestFields) { f.client = client })
+				}
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			// 2) JWT bearer token
+			raw, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || raw == "" || len(secret) == 0 {
+				writeUnauthorized(w, "missing credentials")
+				return
+			}
+
+			var claims auth.Claims
+			if _, err := parser.ParseWithClaims(raw, &claims, keyFunc); err != nil {
+				if errors.Is(err, jwt.ErrTokenExpired) {
+					writeUnauthorized(w, "token expired")
+					return
+				}
+
+				writeUnauthorized(w, "invalid token")
+				return
+			}
+			if claims.Use == auth.UseRefresh {
+				writeUnauthorized(w, "refresh tokens can't be used as access tokens")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), subjectKey, claims.Subject)
+			ctx = context.WithValue(ctx, roleKey, claims.Role)
+			ctx = storage.WithActor(ctx, claims.Subject)
+			ctx = logging.With(ctx, slog.String("subject", claims.Subject), slog.String("role", claims.Role))
+			if info := requestInfoFrom(ctx); info != nil {
+				info.set(func(f *requestFields) { f.subject = claims.Subject })
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+/*
+RequireRole()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Lets the request through only when the role Auth found
+	    is one of roles:
+	      router.Handle("DELETE /api/students/{id}",
+	          requireAuth(middleware.RequireRole(student.Delete(storage), config.RoleAdmin)))
+	  → Otherwise 403 Forbidden, code "forbidden": the caller IS
+	    known (unlike a 401), it is just not allowed. No
+	    WWW-Authenticate: other credentials wouldn't be "retried"
+	    by a client, they'd have to be another caller.
+
+	NOTES:
+	  → Must sit inside Auth: without it there is no role and
+	    everything is refused. routes.go skips both when auth is
+	    disabled (dev).
+*/
+func RequireRole(next http.Handler, roles ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := Role(r.Context())
+		if !slices.Contains(roles, role) {
+			// the role itself is already on the request-scoped logger
+			logging.FromContext(r.Context()).Warn("role not allowed", slog.Any("allowed", roles))
+
+			msg := "role " + strconv.Quote(role) + " may not do this, it needs " + strings.Join(roles, " or ")
+			if role == "" {
+				msg = "credentials carry no role, this needs " + strings.Join(roles, " or ")
+			}
+			response.WriteJson(w, http.StatusForbidden, response.Forbidden(msg))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+/*
+matchAPIKey()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Finds the label of the key the client sent.
+
+	WHY CONSTANT TIME?
+	  → A plain == stops at the first differing byte, so response
+	    times would leak how much of a guess was right.
+	    subtle.ConstantTimeCompare always compares everything, and
+	    we keep looping after a match for the same reason.
+*/
+func matchAPIKey(keys map[string]string, key string) (string, bool) {
+	var client string
+	found := false
+
+	for label, secret := range keys {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(key)) == 1 {
+			client = label
+			found = true
+		}
+	}
+
+	return client, found
+}
+
+/*
+Subject()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the "sub" claim of the authenticated token,
+	    or "" on routes that are not behind Auth.
+*/
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey).(string)
+	return subject
+}
+
+// Role returns the role of the authenticated caller, or "" on routes that
+// are not behind Auth (and for tokens without a "role" claim).
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey).(string)
+	return role
+}
+
+/*
+Client()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the label of the API key used for this request,
+	    or "" when the caller did not use an API key.
+*/
+func Client(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey).(string)
+	return client
+}
+
+// writeUnauthorized sends the 401 shared by every auth failure.
+func writeUnauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	response.WriteJson(w, http.StatusUnauthorized, response.Unauthorized(msg))
+}
@@ -0,0 +1,43 @@
+package middleware // middleware package holds http.Handler wrappers shared by all routes
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net/http → http.Handler, http.MaxBytesReader
+*/
+import (
+	"net/http"
+)
+
+/*
+MaxBodyBytes()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Limits how many bytes a handler can read from r.Body on
+	    mutating requests (POST, PUT, PATCH, DELETE).
+
+	HOW IT WORKS:
+	  → http.MaxBytesReader returns *http.MaxBytesError once more
+	    than "limit" bytes are read. The JSON decoder surfaces that
+	    error and the handler turns it into a 413 JSON response.
+	  → The connection is also closed, so a client streaming a huge
+	    body can't keep sending data after the limit.
+
+	USAGE:
+	  handler := middleware.MaxBodyBytes(cfg.HTTPServer.MaxBodyBytes)(router)
+*/
+func MaxBodyBytes(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
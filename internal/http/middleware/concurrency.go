@@ -0,0 +1,140 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → stop waiting when the client goes away
+   - errors   → the fixed 503 error message
+   - math     → round Retry-After up to whole seconds
+   - net/http → http.Handler, headers
+   - strconv  → Retry-After header value
+   - time     → how long a request may wait for a slot
+   - config   → the "concurrency" config section
+   - metrics  → in-flight / queued gauges
+   - response → JSON body for 503
+*/
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+var errServerBusy = errors.New("server busy, retry later")
+
+/*
+slots STRUCT
+-------------------------------------------------------------
+  - A semaphore: a buffered channel holding one value per
+    request being served. class ("read" / "write") labels the
+    metrics.
+*/
+type slots struct {
+	class string
+	ch    chan struct{}
+}
+
+// newSlots returns nil (unlimited) for size 0.
+func newSlots(class string, size int) *slots {
+	if size == 0 {
+		return nil
+	}
+
+	return &slots{class: class, ch: make(chan struct{}, size)}
+}
+
+// acquire takes a slot, waiting up to wait for one; false when none freed up
+// in time or the client went away.
+func (s *slots) acquire(ctx context.Context, wait time.Duration) bool {
+	select {
+	case s.ch <- struct{}{}:
+		metrics.ConcurrencyAcquired(s.class)
+		return true
+	default:
+	}
+
+	metrics.ConcurrencyQueued(s.class, 1)
+	defer metrics.ConcurrencyQueued(s.class, -1)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case s.ch <- struct{}{}:
+		metrics.ConcurrencyAcquired(s.class)
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	metrics.ConcurrencyRejected(s.class)
+	return false
+}
+
+func (s *slots) release() {
+	<-s.ch
+	metrics.ConcurrencyReleased(s.class)
+}
+
+/*
+ConcurrencyLimit()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Caps the requests served at once (concurrency.*), so
+	    SQLite sees a handful of writers instead of hundreds
+	    fighting over the lock ("database is locked").
+	  → Two separate caps: max_writes for POST / PUT / PATCH /
+	    DELETE, max_reads (normally higher, or 0 = unlimited) for
+	    the rest.
+	  → A request over the cap waits for a slot (FIFO is not
+	    guaranteed) up to max_wait, then gets
+	      503 Service Unavailable
+	      Retry-After: <max_wait in seconds, at least 1>
+	      {"status":"Error","error":"server busy, retry later"}
+
+	EXEMPT:
+	  → exemptPaths (probes, metrics), like the rate limiter.
+	  → Event streams must not hold a read slot forever:
+	    routes.go skips them.
+*/
+func ConcurrencyLimit(cfg config.Concurrency) func(http.Handler) http.Handler {
+	writes := newSlots("write", cfg.MaxWrites)
+	reads := newSlots("read", cfg.MaxReads)
+	retryAfter := strconv.Itoa(max(int(math.Ceil(cfg.MaxWait.Seconds())), 1))
+
+	return func(next http.Handler) http.Handler {
+		if writes == nil && reads == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := reads
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				limit = writes
+			}
+
+			if limit == nil || exemptPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !limit.acquire(r.Context(), cfg.MaxWait) {
+				w.Header().Set("Retry-After", retryAfter)
+				response.WriteJson(w, http.StatusServiceUnavailable, response.Unavailable(errServerBusy.Error()))
+				return
+			}
+			defer limit.release()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+)
+
+// inFlight is a handler noting the most requests it served at once.
+type inFlight struct {
+	now, peak atomic.Int64
+	hold      time.Duration
+}
+
+func (f *inFlight) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := f.now.Add(1)
+	defer f.now.Add(-1)
+	for peak := f.peak.Load(); n > peak && !f.peak.CompareAndSwap(peak, n); peak = f.peak.Load() {
+	}
+
+	time.Sleep(f.hold)
+}
+
+// serveAll sends n requests to h at once and counts the statuses.
+func serveAll(h http.Handler, n int, method string) map[int]int {
+	var mu sync.Mutex
+	statuses := map[int]int{}
+
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, "/api/v1/students", nil))
+
+			mu.Lock()
+			statuses[rec.Code]++
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+func TestConcurrencyLimitCaps(t *testing.T) {
+	cfg := config.Concurrency{MaxWrites: 2, MaxReads: 5, MaxWait: 10 * time.Second}
+
+	for _, tt := range []struct {
+		method string
+		max    int64
+	}{
+		{http.MethodPost, 2},
+		{http.MethodGet, 5},
+	} {
+		t.Run(tt.method, func(t *testing.T) {
+			handler := &inFlight{hold: 5 * time.Millisecond}
+			h := middleware.ConcurrencyLimit(cfg)(handler)
+
+			// everyone waits for a slot instead of failing
+			statuses := serveAll(h, 40, tt.method)
+			if statuses[http.StatusOK] != 40 {
+				t.Errorf("statuses %v, want 40 × 200", statuses)
+			}
+			if peak := handler.peak.Load(); peak != tt.max {
+				t.Errorf("%d requests at once, want the cap of %d", peak, tt.max)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimitRejects(t *testing.T) {
+	cfg := config.Concurrency{MaxWrites: 1, MaxWait: 10 * time.Millisecond}
+	h := middleware.ConcurrencyLimit(cfg)(&inFlight{hold: 200 * time.Millisecond})
+
+	// one is served, the others give up after max_wait
+	var busy *httptest.ResponseRecorder
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/students", nil))
+			if rec.Code == http.StatusServiceUnavailable {
+				busy = rec
+			}
+		})
+	}
+	wg.Wait()
+
+	if busy == nil {
+		t.Fatal("no request was refused")
+	}
+	if got := busy.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After %q, want 1", got)
+	}
+	if want := `{"status":"Error","code":"unavailable","error":"server busy, retry later"}`; strings.TrimSpace(busy.Body.String()) != want {
+		t.Errorf("body %s, want %s", busy.Body, want)
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - errors   → error message when no type was sent
+   - fmt      → error message naming the rejected type
+   - mime     → parse "application/json; charset=utf-8"
+   - net/http → http.Handler
+   - response → JSON body of the 415
+*/
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+RequireJSON()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → POST, PUT and PATCH bodies must be declared as
+	    Content-Type: application/json, otherwise 415 Unsupported
+	    Media Type. Without it a form post or text/plain body that
+	    happens to parse as JSON would be accepted, and one that
+	    doesn't gets a confusing decode error.
+
+	RULES:
+	  → Parameters are allowed ("application/json; charset=utf-8"),
+	    the media type itself is compared case-insensitively.
+	  → GET, DELETE, … are exempt, and so are requests without a
+	    body (POST /api/students/{id}/restore): there is nothing to
+	    decode, so nothing to declare.
+
+	USAGE:
+	  handler := middleware.RequireJSON(router)
+*/
+func RequireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// ContentLength is -1 for chunked bodies, which do count
+		if r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+			err := fmt.Errorf("Content-Type must be application/json, got %q", contentType)
+			if contentType == "" {
+				err = errors.New("Content-Type must be application/json, none was sent")
+			}
+
+			response.WriteJson(w, http.StatusUnsupportedMediaType, response.GeneralError(err))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,61 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+)
+
+func TestRequireJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		status      int
+		want        string
+	}{
+		{"json", http.MethodPost, "application/json", "{}", http.StatusOK, ""},
+		{"with charset", http.MethodPost, "application/json; charset=utf-8", "{}", http.StatusOK, ""},
+		{"upper-case", http.MethodPut, "Application/JSON", "{}", http.StatusOK, ""},
+		{
+			"missing", http.MethodPost, "", "{}", http.StatusUnsupportedMediaType,
+			`{"status":"Error","error":"Content-Type must be application/json, none was sent"}`,
+		},
+		{
+			"xml", http.MethodPatch, "application/xml", "<student/>", http.StatusUnsupportedMediaType,
+			`{"status":"Error","error":"Content-Type must be application/json, got \"application/xml\""}`,
+		},
+		{
+			"unparsable", http.MethodPost, "application/json; charset", "{}", http.StatusUnsupportedMediaType,
+			`{"status":"Error","error":"Content-Type must be application/json, got \"application/json; charset\""}`,
+		},
+		{"no body", http.MethodPost, "", "", http.StatusOK, ""},
+		{"GET", http.MethodGet, "application/xml", "<student/>", http.StatusOK, ""},
+	}
+
+	h := middleware.RequireJSON(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/students", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - errors      → the fixed 403 error message
+   - net/http    → http.Handler, headers
+   - slices      → membership checks on the allowed lists
+   - strconv     → Max-Age header value
+   - strings     → join lists into header values
+   - sync/atomic → the rules are swapped on config reload
+   - config      → the "cors" config section
+   - response    → JSON body for rejected origins
+*/
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+var errOriginNotAllowed = errors.New("origin not allowed")
+
+/*
+CORSPolicy STRUCT
+-------------------------------------------------------------
+  - The "cors" section, prepared for every request (header
+    values joined once).
+  - SetConfig swaps it atomically, so a config reload changes
+    the allowed origins without a restart; requests in flight
+    finish with the rules they started with.
+*/
+type CORSPolicy struct {
+	rules atomic.Pointer[corsRules]
+}
+
+type corsRules struct {
+	cfg      config.CORS
+	allowAny bool
+	methods  string
+	headers  string
+	maxAge   string
+}
+
+// NewCORS returns a CORSPolicy enforcing cfg.
+func NewCORS(cfg config.CORS) *CORSPolicy {
+	p := &CORSPolicy{}
+	p.SetConfig(cfg)
+	return p
+}
+
+// SetConfig replaces the rules with cfg (already validated).
+func (p *CORSPolicy) SetConfig(cfg config.CORS) {
+	p.rules.Store(&corsRules{
+		cfg:      cfg,
+		allowAny: slices.Contains(cfg.AllowedOrigins, "*"),
+		methods:  strings.Join(cfg.AllowedMethods, ", "),
+		headers:  strings.Join(cfg.AllowedHeaders, ", "),
+		maxAge:   strconv.Itoa(cfg.MaxAge),
+	})
+}
+
+/*
+Middleware()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Lets browser apps on other origins call the API.
+
+	FLOW:
+	  - no allowed_origins configured → CORS disabled, pass through
+	  - no Origin header              → not a CORS request, pass through
+	  - Origin not allowed            → 403 JSON
+	  - preflight (OPTIONS + Access-Control-Request-Method)
+	                                  → 204 with Access-Control-Allow-* headers,
+	                                    the handler is never called
+	  - actual request                → Access-Control-Allow-Origin added,
+	                                    then the handler runs
+
+	Vary: Origin
+	  → The response differs per Origin, so caches must key on it.
+*/
+func (p *CORSPolicy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rules := p.rules.Load()
+		cfg := rules.cfg
+
+		origin := r.Header.Get("Origin")
+		if len(cfg.AllowedOrigins) == 0 || origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+
+		if !rules.allowAny && !slices.Contains(cfg.AllowedOrigins, origin) {
+			response.WriteJson(w, http.StatusForbidden,
+				response.GeneralError(errOriginNotAllowed))
+			return
+		}
+
+		// "*" is only valid without credentials (enforced at config load)
+		if rules.allowAny && !cfg.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if cfg.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Preflight: answer it here, so it gets the CORS headers; plain
+		// OPTIONS requests go on to the router, which sends Allow
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", rules.methods)
+			w.Header().Set("Access-Control-Allow-Headers", rules.headers)
+			w.Header().Set("Access-Control-Max-Age", rules.maxAge)
+
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
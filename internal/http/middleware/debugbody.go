@@ -0,0 +1,195 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes         → the copies of the bodies
+   - encoding/json → parse / re-encode bodies to redact them
+   - io            → re-buffer the request body for the handler
+   - log/slog      → the debug lines
+   - net/http      → http.Handler, http.ResponseWriter
+   - strings       → auth paths, case-insensitive field names
+   - config        → the "log" config section
+   - logging       → request-scoped logger
+*/
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+)
+
+// authPathPrefix is where the login / refresh endpoints live: their bodies
+// are passwords and tokens, never logged whatever the settings.
+const authPathPrefix = "/api/auth/"
+
+const redacted = "[REDACTED]"
+
+/*
+DebugBodies()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → For diagnosing client integrations: logs the JSON body
+	    of every request and response at DEBUG, as
+	      "request body"  bytes=… body={…}
+	      "response body" status=… bytes=… body={…}
+	  → Values of cfg.RedactFields keys are replaced with
+	    "[REDACTED]" at any depth, so passwords and tokens don't
+	    end up in the logs. The body is re-encoded for that:
+	    same values, but compact and with the keys sorted.
+
+	WHEN:
+	  → Never in production (env) or with debug_body_max_bytes
+	    0: the middleware isn't even part of the chain.
+	  → Otherwise per request, only while the logger is at
+	    debug, so a SIGHUP changing log.level turns it on / off.
+	  → Never for /api/auth/… (login, refresh).
+
+	NOTES:
+	  → Only bodies of at most debug_body_max_bytes are logged;
+	    bigger ones, and bodies that are not JSON (which could
+	    not be redacted), get a placeholder instead.
+	  → Request bytes is the Content-Length (-1 when chunked).
+	  → The handler still reads the whole request body: what
+	    was read for the log is put back in front of the rest.
+	  → Not for streams (SSE): routes.go skips them.
+*/
+func DebugBodies(cfg config.Log, env string) func(http.Handler) http.Handler {
+	limit := cfg.DebugBodyMaxBytes
+
+	fields := make(map[string]bool, len(cfg.RedactFields))
+	for _, field := range cfg.RedactFields {
+		fields[strings.ToLower(field)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if env == "production" || limit == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := logging.FromContext(r.Context())
+			if !logger.Enabled(r.Context(), slog.LevelDebug) || strings.HasPrefix(r.URL.Path, authPathPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				// One byte more than the cap tells "too big" from "exactly the cap".
+				head, _ := io.ReadAll(io.LimitReader(r.Body, limit+1))
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+
+				if len(head) > 0 {
+					logger.Debug("request body",
+						slog.Int64("bytes", r.ContentLength),
+						slog.String("body", scrubBody(head, int64(len(head)) <= limit, fields)),
+					)
+				}
+			}
+
+			dw := &debugWriter{ResponseWriter: w, status: http.StatusOK, limit: limit}
+			next.ServeHTTP(dw, r)
+
+			if dw.size > 0 {
+				logger.Debug("response body",
+					slog.Int("status", dw.status),
+					slog.Int64("bytes", dw.size),
+					slog.String("body", scrubBody(dw.body.Bytes(), dw.size <= limit, fields)),
+				)
+			}
+		})
+	}
+}
+
+// scrubBody returns body, redacted, for the log; a placeholder when it is
+// only the start of a bigger body (complete false) or not JSON.
+func scrubBody(body []byte, complete bool, fields map[string]bool) string {
+	if !complete {
+		return "[not logged: larger than log.debug_body_max_bytes]"
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return "[not logged: not a JSON document]"
+	}
+
+	scrubbed, err := json.Marshal(redact(value, fields))
+	if err != nil {
+		return "[not logged: not a JSON document]"
+	}
+
+	return string(scrubbed)
+}
+
+// redact replaces the values of the keys in fields, in every object of v.
+func redact(v any, fields map[string]bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if fields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redact(value, fields)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redact(value, fields)
+		}
+	}
+
+	return v
+}
+
+/*
+debugWriter STRUCT
+-------------------------------------------------------------
+  - Passes everything through to the client and keeps the
+    status, the total size and the first limit bytes of the
+    body for DebugBodies.
+*/
+type debugWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	size        int64
+	limit       int64
+	body        bytes.Buffer
+}
+
+func (dw *debugWriter) WriteHeader(status int) {
+	if !dw.wroteHeader {
+		dw.status = status
+		dw.wroteHeader = true
+	}
+	dw.ResponseWriter.WriteHeader(status)
+}
+
+func (dw *debugWriter) Write(b []byte) (int, error) {
+	dw.wroteHeader = true
+	if room := dw.limit - int64(dw.body.Len()); room > 0 {
+		dw.body.Write(b[:min(int64(len(b)), room)])
+	}
+
+	n, err := dw.ResponseWriter.Write(b)
+	dw.size += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController (and response.WriteJson, for the
+// negotiated format) reach the original writer.
+func (dw *debugWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
@@ -0,0 +1,255 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes         → buffer the body (hashing) and the response (recording)
+   - context       → WithoutCancel for the bookkeeping after the handler
+   - crypto/sha256 → fingerprint of the request
+   - encoding/hex  → fingerprint as text
+   - encoding/json → recorded response stored as JSON
+   - errors        → detect *http.MaxBytesError
+   - fmt           → error messages
+   - io            → read the request body
+   - log/slog      → storage failures are logged, not fatal
+   - net/http      → http.Handler, http.ResponseWriter
+   - time          → key expiry
+   - storage       → IdempotencyStore
+   - response      → JSON error bodies
+   - logging       → request-scoped logger
+*/
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// maxIdempotencyKeyLen bounds the Idempotency-Key header (the IETF draft
+// suggests UUIDs, which are 36 characters).
+const maxIdempotencyKeyLen = 255
+
+// replayedHeaders are the response headers recorded with the body; the rest
+// (Date, Content-Length, …) are produced again by net/http.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+/*
+recordedResponse STRUCT
+-------------------------------------------------------------
+  - What is stored (as JSON) for a completed key, enough to
+    send the same answer again.
+*/
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+/*
+Idempotency()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Makes a POST safe to retry: the first request with a given
+	    Idempotency-Key runs normally and its response is stored;
+	    a retry with the same key gets that response back (marked
+	    Idempotent-Replayed: true) instead of creating a duplicate.
+
+	RULES:
+	  → No header                 → the request runs as usual
+	  → Same key, same request    → recorded response replayed
+	  → Same key, other request   → 422 (method, path and body are
+	                                fingerprinted with SHA-256)
+	  → Same key, first request still running → 409 + Retry-After
+	  → Keys are scoped to the authenticated subject / API key
+	    client, so two callers can't see each other's responses.
+	  → 5xx responses (and panics) are not recorded: the key is
+	    released and the next retry runs the request again.
+	  → Records expire after ttl; App.Run purges them periodically.
+
+	USAGE:
+	  mux.Handle("POST /api/students",
+	      requireAuth(middleware.Idempotency(storage, ttl)(handler)))
+	  → Must sit inside Auth so the scope is known.
+*/
+func Idempotency(store storage.IdempotencyStore, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLen {
+				response.WriteJson(w, http.StatusBadRequest,
+					response.BadRequest(fmt.Sprintf("Idempotency-Key must not be longer than %d characters", maxIdempotencyKeyLen)))
+				return
+			}
+
+			// STEP 1: read the body once, hash it, and hand the handler
+			// an identical copy
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					response.WriteJson(w, http.StatusRequestEntityTooLarge,
+						response.BadRequest(fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit)))
+					return
+				}
+
+				response.WriteJson(w, http.StatusBadRequest, response.BadRequest(fmt.Sprintf("reading request body: %s", err)))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			logger := logging.FromContext(ctx)
+			scopedKey := idempotencyScope(ctx) + " " + key
+			hash := requestHash(r, body)
+
+			// STEP 2: claim the key, or find out who holds it
+			record, reserved, err := store.ReserveIdempotencyKey(ctx, scopedKey, hash, time.Now().Add(ttl))
+			if err != nil {
+				logger.Error("reserving idempotency key failed", slog.String("error", err.Error()))
+				response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+				return
+			}
+
+			if !reserved {
+				switch {
+				case record.RequestHash != hash:
+					response.WriteJson(w, http.StatusUnprocessableEntity,
+						response.IdempotencyConflict("Idempotency-Key was already used with a different request"))
+				case record.Response == nil:
+					w.Header().Set("Retry-After", "1")
+					response.WriteJson(w, http.StatusConflict,
+						response.Conflict("a request with this Idempotency-Key is still in progress"))
+				default:
+					replay(w, record.Response, logger)
+				}
+				return
+			}
+
+			// STEP 3: run the handler and record what it sends. The
+			// bookkeeping must happen even if the request context was
+			// cancelled meanwhile, hence WithoutCancel.
+			completed := false
+			defer func() {
+				if !completed {
+					if err := store.ReleaseIdempotencyKey(context.WithoutCancel(ctx), scopedKey); err != nil {
+						logger.Error("releasing idempotency key failed", slog.String("error", err.Error()))
+					}
+				}
+			}()
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+
+			recorded := recordedResponse{Status: rec.status, Header: http.Header{}, Body: rec.body.Bytes()}
+			for _, name := range replayedHeaders {
+				if values := w.Header().Values(name); len(values) > 0 {
+					recorded.Header[name] = values
+				}
+			}
+
+			data, err := json.Marshal(recorded)
+			if err != nil {
+				logger.Error("encoding idempotent response failed", slog.String("error", err.Error()))
+				return
+			}
+
+			if err := store.CompleteIdempotencyKey(context.WithoutCancel(ctx), scopedKey, data); err != nil {
+				logger.Error("storing idempotent response failed", slog.String("error", err.Error()))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// idempotencyScope names the caller a key belongs to (see Subject/Client).
+func idempotencyScope(ctx context.Context) string {
+	if subject := Subject(ctx); subject != "" {
+		return "subject:" + subject
+	}
+	if client := Client(ctx); client != "" {
+		return "client:" + client
+	}
+
+	return "anonymous"
+}
+
+// requestHash fingerprints what makes two requests "the same" for a key.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay sends a recorded response again.
+func replay(w http.ResponseWriter, data []byte, logger *slog.Logger) {
+	var recorded recordedResponse
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		logger.Error("decoding idempotent response failed", slog.String("error", err.Error()))
+		response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+		return
+	}
+
+	for name, values := range recorded.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(recorded.Status)
+	w.Write(recorded.Body)
+}
+
+/*
+recordingWriter STRUCT
+-------------------------------------------------------------
+  - Passes everything through to the client and keeps a copy
+    of the status and body for the idempotency record.
+*/
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the original writer.
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,141 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+)
+
+// creator counts the creates it does; each one answers a new ID.
+type creator struct {
+	creates atomic.Int64
+}
+
+func (c *creator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := c.creates.Add(1)
+	// long enough for the other requests to arrive while it runs
+	time.Sleep(20 * time.Millisecond)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/students/%d", id))
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, `{"id":%d}`, id)
+}
+
+// post sends one create with key to h.
+func post(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/students", bytes.NewBufferString(body))
+	req.Header.Set("Idempotency-Key", key)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// postAll sends n copies of the same create at once.
+func postAll(h http.Handler, n int, key, body string) []*httptest.ResponseRecorder {
+	recs := make([]*httptest.ResponseRecorder, n)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			<-start
+			recs[i] = post(h, key, body)
+		})
+	}
+	close(start)
+	wg.Wait()
+
+	return recs
+}
+
+func TestIdempotencyConcurrentRetries(t *testing.T) {
+	const n = 20
+	var c creator
+	h := middleware.Idempotency(memory.New(&config.Config{}), time.Hour)(&c)
+	body := `{"name":"Ann Lee","email":"ann@example.com","age":20}`
+
+	// while the first one runs, the others are told to come back
+	var created *httptest.ResponseRecorder
+	for _, rec := range postAll(h, n, "key-1", body) {
+		switch {
+		case rec.Code == http.StatusCreated && rec.Header().Get("Idempotent-Replayed") == "":
+			if created != nil {
+				t.Fatalf("two requests created: %s and %s", created.Body, rec.Body)
+			}
+			created = rec
+		case rec.Code == http.StatusConflict:
+			if rec.Header().Get("Retry-After") == "" {
+				t.Error("409 without Retry-After")
+			}
+		case rec.Code == http.StatusCreated:
+			// a replay, checked below with the later ones
+		default:
+			t.Errorf("status %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if created == nil {
+		t.Fatal("no request created the student")
+	}
+
+	// once it is done, every retry gets the same answer back
+	for _, rec := range postAll(h, n, "key-1", body) {
+		if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "true" ||
+			rec.Body.String() != created.Body.String() ||
+			rec.Header().Get("Location") != created.Header().Get("Location") {
+			t.Errorf("replay: status %d, Location %q, body %s; want 201, %q, %s",
+				rec.Code, rec.Header().Get("Location"), rec.Body, created.Header().Get("Location"), created.Body)
+		}
+	}
+
+	if got := c.creates.Load(); got != 1 {
+		t.Errorf("%d creates, want 1", got)
+	}
+	rec := post(h, "key-1", `{"name":"Bob Lee"}`)
+	want := `{"status":"Error","code":"idempotency_conflict","error":"Idempotency-Key was already used with a different request"}`
+	if rec.Code != http.StatusUnprocessableEntity || strings.TrimSpace(rec.Body.String()) != want {
+		t.Errorf("same key, other body: status %d, body %s; want 422 %s", rec.Code, rec.Body, want)
+	}
+
+	rec = post(h, strings.Repeat("k", 256), "{}")
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"code":"bad_request"`) {
+		t.Errorf("key too long: status %d, body %s; want a 400 bad_request", rec.Code, rec.Body)
+	}
+}
+
+func TestIdempotencyExpiredKeys(t *testing.T) {
+	const ttl = 200 * time.Millisecond
+	var c creator
+	store := memory.New(&config.Config{})
+	h := middleware.Idempotency(store, ttl)(&c)
+
+	post(h, "key-1", "{}")
+	post(h, "key-2", "{}")
+	if n, err := store.PurgeExpiredIdempotencyKeys(context.Background(), time.Now()); n != 0 || err != nil {
+		t.Errorf("purge before the ttl = %d, %v; want 0", n, err)
+	}
+
+	// past the ttl the records are purged, and the key runs the request again
+	time.Sleep(ttl)
+	if n, err := store.PurgeExpiredIdempotencyKeys(context.Background(), time.Now()); n != 2 || err != nil {
+		t.Errorf("purge after the ttl = %d, %v; want 2", n, err)
+	}
+	if rec := post(h, "key-1", "{}"); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("expired key: status %d, replayed %q; want a new create", rec.Code, rec.Header().Get("Idempotent-Replayed"))
+	}
+	if got := c.creates.Load(); got != 3 {
+		t.Errorf("%d creates, want 3", got)
+	}
+}
@@ -0,0 +1,227 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → share requestInfo with inner middleware
+   - log/slog → one structured log line per request
+   - net/http → http.Handler, http.ResponseWriter
+   - strings  → recognise event streams
+   - sync     → requestInfo may be filled by a timed-out handler
+   - time     → measure request latency
+   - logging  → request-scoped logger
+*/
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+)
+
+/*
+responseWriter STRUCT
+-------------------------------------------------------------
+  - Wraps the real http.ResponseWriter so we can remember
+    which status code and how many bytes the handler wrote.
+  - status starts at 200 because net/http sends 200 when a
+    handler calls Write() without calling WriteHeader().
+*/
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+// WriteHeader records the status (only the first call counts, like net/http).
+func (rw *responseWriter) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts the bytes of the response body.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+// (needed for Flush, SetWriteDeadline, …).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+/*
+requestInfo STRUCT
+-------------------------------------------------------------
+  - Middleware further down (Auth) learns who the caller is,
+    but it can only hand a NEW request to the next handler;
+    Logging never sees that request.
+  - So Logging puts a pointer in the context first and the
+    inner middleware fills it in. The same goes for the route
+    pattern (see RecordRoute).
+  - Guarded by mu: after a Timeout the handler may still be
+    running (and filling it in) while Logging reads it.
+*/
+type requestInfo struct {
+	mu     sync.Mutex
+	fields requestFields
+}
+
+type requestFields struct {
+	subject string
+	client  string
+	route   string
+}
+
+// set changes the fields under the lock.
+func (i *requestInfo) set(update func(*requestFields)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	update(&i.fields)
+}
+
+// get returns a copy of the fields.
+func (i *requestInfo) get() requestFields {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.fields
+}
+
+func requestInfoFrom(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey).(*requestInfo)
+	return info
+}
+
+/*
+RecordRoute()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Wraps the router (innermost in the chain) and saves the
+	    route pattern the ServeMux matched ("GET /api/v1/students/{id}")
+	    for Logging and Metrics.
+
+	WHY:
+	  → The ServeMux sets r.Pattern on the request IT receives.
+	    Timeout, Negotiate… hand a new request (r.WithContext) to
+	    the next handler, so the outer middleware never see it;
+	    they read it from requestInfo instead.
+	  → Deferred, so a panicking handler still has its route logged.
+*/
+func RecordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if info := requestInfoFrom(r.Context()); info != nil {
+				info.set(func(f *requestFields) { f.route = r.Pattern })
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+/*
+Logging()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Logs one structured line per request AFTER it finished:
+	      method, route pattern, path, remote addr,
+	      status, latency and response size.
+	  → Uses the request-scoped logger (logging.FromContext), so
+	    request_id, method and path are included when
+	    RequestIDMiddleware runs first.
+	  → Adds subject / client when Auth identified the caller.
+
+	SLOW REQUESTS:
+	  → A request that took longer than slowThreshold
+	    (log.slow_request_threshold) gets a second, WARN line
+	    "slow request", so p99 spikes can be traced to a route.
+	    0 disables it. Event streams (text/event-stream) are meant
+	    to stay open and are never reported.
+	  → The duration is taken as soon as the handler returns,
+	    before any logging, so it is the handler's time alone.
+
+	ACCESS LOG:
+	  → With access non-nil (log.access_log_path), the per-request
+	    line goes to it instead, with request_id, method and path
+	    spelled out; application logs (slow requests included)
+	    stay on the request-scoped logger.
+
+	ROUTE PATTERN:
+	  → Known only after the handler ran; recorded by
+	    RecordRoute. Unmatched requests have "".
+
+	USAGE:
+	  handler = middleware.Logging(cfg.Log.SlowRequestThreshold, nil)(handler)
+*/
+func Logging(slowThreshold time.Duration, access *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := newResponseWriter(w)
+
+			info := &requestInfo{}
+			r = r.WithContext(context.WithValue(r.Context(), requestInfoKey, info))
+
+			next.ServeHTTP(rw, r)
+			elapsed := time.Since(start)
+			fields := info.get()
+
+			logger := logging.FromContext(r.Context())
+
+			// method and path are already on the request-scoped logger
+			attrs := []any{
+				slog.String("route", fields.route),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.Int("status", rw.status),
+				slog.Duration("latency", elapsed),
+				slog.Int("size", rw.size),
+			}
+			if fields.subject != "" {
+				attrs = append(attrs, slog.String("subject", fields.subject))
+			}
+			if fields.client != "" {
+				attrs = append(attrs, slog.String("client", fields.client))
+			}
+
+			if access != nil {
+				attrs = append([]any{
+					slog.String("request_id", RequestID(r.Context())),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+				}, attrs...)
+				access.Info("http request", attrs...)
+			} else {
+				logger.Info("http request", attrs...)
+			}
+
+			streaming := strings.HasPrefix(rw.Header().Get("Content-Type"), "text/event-stream")
+			if slowThreshold > 0 && elapsed > slowThreshold && !streaming {
+				logger.Warn("slow request",
+					slog.String("route", fields.route),
+					slog.Int("status", rw.status),
+					slog.Duration("duration", elapsed),
+					slog.Duration("threshold", slowThreshold),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+)
+
+// TestSlowRequest checks the "slow request" warning: logged with the route,
+// status and both durations once a handler takes longer than the
+// threshold, and never for fast requests, a 0 threshold or event streams.
+func TestSlowRequest(t *testing.T) {
+	const route = "GET /api/v1/students"
+
+	tests := []struct {
+		name        string
+		threshold   time.Duration
+		sleep       time.Duration
+		contentType string
+		slow        bool
+	}{
+		{"slow handler", 20 * time.Millisecond, 60 * time.Millisecond, "application/json", true},
+		{"fast handler", time.Second, 0, "application/json", false},
+		{"disabled", 0, 60 * time.Millisecond, "application/json", false},
+		{"event stream", 20 * time.Millisecond, 60 * time.Millisecond, "text/event-stream", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle(route, middleware.RecordRoute(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.sleep)
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusAccepted)
+			})))
+			h := middleware.Logging(tt.threshold, nil)(mux)
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/students", nil)
+			req = req.WithContext(logging.WithLogger(req.Context(), logger))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			var requests int
+			var warnings []map[string]any
+			lines := bufio.NewScanner(&logs)
+			for lines.Scan() {
+				var line map[string]any
+				if err := json.Unmarshal(lines.Bytes(), &line); err != nil {
+					t.Fatalf("log line %s: %v", lines.Bytes(), err)
+				}
+				switch line["msg"] {
+				case "http request":
+					requests++
+				case "slow request":
+					warnings = append(warnings, line)
+				}
+			}
+			if requests != 1 {
+				t.Fatalf("%d request lines on the request's logger, want 1", requests)
+			}
+
+			if !tt.slow {
+				if len(warnings) != 0 {
+					t.Errorf("warned %v, want no slow request warning", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("%d slow request warnings, want 1 (logs %s)", len(warnings), logs.String())
+			}
+			w := warnings[0]
+			// the JSON handler writes durations in nanoseconds
+			duration, _ := w["duration"].(float64)
+			if w["level"] != "WARN" || w["route"] != route || w["status"] != float64(http.StatusAccepted) ||
+				w["threshold"] != float64(tt.threshold) || time.Duration(duration) < tt.sleep {
+				t.Errorf("warning %v", w)
+			}
+		})
+	}
+}
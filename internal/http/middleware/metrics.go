@@ -0,0 +1,46 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net/http → http.Handler
+   - time     → request latency
+   - metrics  → Prometheus collectors
+*/
+import (
+	"net/http"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+)
+
+/*
+Metrics()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Records request count, in-flight requests and latency
+	    for every request (see internal/metrics for the names).
+
+	ROUTE PATTERN:
+	  → Read from the requestInfo RecordRoute fills in, so Metrics
+	    must sit inside Logging (which creates it).
+*/
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := newResponseWriter(w)
+
+		metrics.RequestStarted()
+		defer func() {
+			route := ""
+			if info := requestInfoFrom(r.Context()); info != nil {
+				route = info.get().route
+			}
+			metrics.RequestFinished(route, r.Method, rw.status, time.Since(start))
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
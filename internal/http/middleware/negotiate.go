@@ -0,0 +1,68 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net/http → http.Handler
+   - strconv  → parse ?pretty=
+   - response → Format, PrefersProblem
+*/
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Negotiate()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Decides once per request how response.WriteJson renders
+	    bodies and attaches that to the ResponseWriter:
+	      - Accept prefers application/problem+json → errors are
+	        sent as RFC 7807 problems (response.PrefersProblem)
+	      - otherwise                               → the usual
+	        {"status":"Error",...} body
+	      - ?pretty (or ?pretty=1 / true) → indented JSON; applies
+	        to WriteJson bodies only, exports and /metrics keep
+	        their format
+	  → Vary: Accept, because error bodies now depend on it.
+
+	USAGE:
+	  handler := middleware.Negotiate(router)
+	  → Anything that writes errors (Recover, CORS, Auth, the
+	    handlers) must run inside it.
+*/
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		format := response.Format{
+			Problem:  response.PrefersProblem(r.Header.Get("Accept")),
+			Instance: r.URL.Path,
+			Pretty:   wantsPretty(r),
+		}
+
+		next.ServeHTTP(response.WithFormat(w, format), r)
+	})
+}
+
+// wantsPretty reports whether the query asks for indented JSON: a bare
+// ?pretty counts, ?pretty=0 / false don't.
+func wantsPretty(r *http.Request) bool {
+	query := r.URL.Query()
+	if !query.Has("pretty") {
+		return false
+	}
+
+	raw := query.Get("pretty")
+	if raw == "" {
+		return true
+	}
+
+	pretty, err := strconv.ParseBool(raw)
+	return err == nil && pretty
+}
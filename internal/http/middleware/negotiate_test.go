@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+// TestNegotiate checks the error format follows Accept: problem+json only
+// when asked for by name, the usual body for wildcards, unknown types or
+// no Accept at all, never a 406.
+func TestNegotiate(t *testing.T) {
+	const (
+		plain   = `{"status":"Error","code":"not_found","error":"student with id 7 not found"}`
+		problem = `{"type":"about:blank","title":"Not Found","status":404,"detail":"student with id 7 not found","instance":"/api/v1/students/7","code":"not_found"}`
+	)
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		want        string
+	}{
+		{"no Accept", "", "application/json", plain},
+		{"any", "*/*", "application/json", plain},
+		{"problem+json", "application/problem+json", response.ProblemContentType, problem},
+		{"problem+json preferred", "application/json;q=0.5, application/problem+json", response.ProblemContentType, problem},
+		{"unknown types", "text/html, application/xml", "application/json", plain},
+	}
+
+	h := middleware.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusNotFound, response.NotFound("student with id 7 not found"))
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/students/7", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status %d, want 404", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary %q, want Accept", got)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
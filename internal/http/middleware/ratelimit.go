@@ -0,0 +1,287 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context     → LimitStore calls may go over the network (Redis)
+   - errors      → the fixed 429 error message
+   - log/slog    → a failing LimitStore is logged (and the request let through)
+   - math        → round Retry-After up to whole seconds
+   - net         → split "ip:port" from r.RemoteAddr
+   - net/http    → http.Handler, headers
+   - strconv     → Retry-After header value
+   - strings     → parse X-Forwarded-For
+   - sync        → one mutex protects the bucket map
+   - sync/atomic → trust_proxy, changed by a config reload
+   - time        → token refill and idle bucket cleanup
+   - config      → the "rate_limit" config section
+   - response    → JSON body for 429
+   - logging     → request-scoped logger
+*/
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// exemptPaths are never rate limited: load balancer probes and the
+// Prometheus scraper hit them on a schedule from the same address and must
+// not be answered with 429.
+var exemptPaths = map[string]bool{
+	"/health":  true,
+	"/ready":   true,
+	"/metrics": true,
+}
+
+/*
+bucket STRUCT
+-------------------------------------------------------------
+  - tokens → how many requests the client may still make now
+  - last   → when tokens was last refilled (also "last seen")
+*/
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+/*
+LimitStore INTERFACE
+-------------------------------------------------------------
+  - Holds the token buckets. MemoryLimitStore (the default)
+    keeps them in this process; with several replicas each one
+    then limits on its own, so redisstore provides a shared one.
+  - Take removes one token from key's bucket. It returns true
+    when the request may proceed, false plus the wait until the
+    next token when the bucket is empty, and an error when the
+    store is unavailable.
+  - SetLimits changes rate and burst for every bucket from now
+    on (config reload); tokens already in a bucket are kept, up
+    to the new burst.
+*/
+type LimitStore interface {
+	Take(ctx context.Context, key string) (bool, time.Duration, error)
+	SetLimits(rate float64, burst int)
+}
+
+/*
+RateLimiter STRUCT
+-------------------------------------------------------------
+  - One token bucket per client IP, kept in store.
+*/
+type RateLimiter struct {
+	store      LimitStore
+	trustProxy atomic.Bool
+}
+
+/*
+NewRateLimiter()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Builds a limiter from the rate_limit config section.
+	  → store nil → a MemoryLimitStore with the configured rate
+	    and burst.
+*/
+func NewRateLimiter(cfg config.RateLimit, store LimitStore) *RateLimiter {
+	if store == nil {
+		store = NewMemoryLimitStore(cfg.RequestsPerSecond, cfg.Burst)
+	}
+
+	l := &RateLimiter{store: store}
+	l.trustProxy.Store(cfg.TrustProxy)
+	return l
+}
+
+// SetConfig applies a reloaded rate_limit section: rate, burst and
+// trust_proxy. Enabled is only read at startup.
+func (l *RateLimiter) SetConfig(cfg config.RateLimit) {
+	l.store.SetLimits(cfg.RequestsPerSecond, cfg.Burst)
+	l.trustProxy.Store(cfg.TrustProxy)
+}
+
+/*
+MemoryLimitStore STRUCT
+-------------------------------------------------------------
+  - The in-process LimitStore.
+  - Buckets idle longer than idleTTL are full again anyway, so
+    they are deleted; otherwise every IP ever seen would stay
+    in memory forever.
+*/
+type MemoryLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	rate      float64 // tokens added per second
+	burst     float64 // bucket capacity
+	idleTTL   time.Duration
+	lastSweep time.Time
+
+	now func() time.Time // replaceable clock
+}
+
+/*
+NewMemoryLimitStore()
+-------------------------------------------------------------
+
+	idleTTL:
+	  → the time an empty bucket needs to refill completely
+	    (burst / rate), but at least one minute.
+*/
+func NewMemoryLimitStore(rate float64, burst int) *MemoryLimitStore {
+	l := &MemoryLimitStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+	l.setLimits(rate, burst)
+
+	return l
+}
+
+// SetLimits implements LimitStore.
+func (l *MemoryLimitStore) SetLimits(rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.setLimits(rate, burst)
+}
+
+// setLimits sets rate, burst and the idleTTL that follows from them.
+// Caller holds l.mu (or owns l).
+func (l *MemoryLimitStore) setLimits(rate float64, burst int) {
+	refill := time.Duration(float64(burst) / rate * float64(time.Second))
+
+	l.rate = rate
+	l.burst = float64(burst)
+	l.idleTTL = max(refill, time.Minute)
+}
+
+// Take implements LimitStore; it never fails.
+func (l *MemoryLimitStore) Take(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	// Refill for the time that passed since the last request
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / l.rate
+		return false, time.Duration(wait * float64(time.Second)), nil
+	}
+
+	b.tokens--
+	return true, 0, nil
+}
+
+// sweep deletes idle buckets, at most once per idleTTL. Caller holds l.mu.
+func (l *MemoryLimitStore) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idleTTL {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
+
+/*
+Middleware()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Rejects clients that ran out of tokens with
+	      429 Too Many Requests
+	      Retry-After: <seconds>
+	      {"status":"Error","error":"rate limit exceeded"}
+	  → Paths in exemptPaths (health probes) always pass.
+	  → A failing store lets the request through (logged): an
+	    outage of Redis must not take the API down with it.
+*/
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exemptPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, retryAfter, err := l.store.Take(r.Context(), l.clientIP(r))
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("rate limit store unavailable, request let through", slog.String("error", err.Error()))
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+
+			response.WriteJson(w, http.StatusTooManyRequests, response.GeneralError(errRateLimited))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP is the key for the client's bucket, see ClientIP.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	return ClientIP(r, l.trustProxy.Load())
+}
+
+/*
+ClientIP()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The address of the client, for per-client limits (the
+	    rate limiter's buckets, the login failure counter).
+
+	X-Forwarded-For:
+	  → Anyone can send this header, so it is used ONLY when
+	    trustProxy is set. We take the LAST entry: that is the
+	    address our own proxy appended, the earlier ones are
+	    whatever the client claimed.
+*/
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			parts := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+)
+
+// frozenStore is a MemoryLimitStore whose clock only moves when the test
+// says so; it is not safe to move it while Take runs.
+func frozenStore(rate float64, burst int) (*MemoryLimitStore, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLimitStore(rate, burst)
+	l.now = func() time.Time { return now }
+
+	return l, &now
+}
+
+// TestMemoryLimitStoreHammer takes tokens from many goroutines at once, on
+// a few keys: with the clock stopped each key lets exactly burst through.
+// Run with -race, it also checks the locking.
+func TestMemoryLimitStoreHammer(t *testing.T) {
+	const (
+		burst      = 50
+		keys       = 8
+		goroutines = 64
+		takes      = 40 // per goroutine and key, 2560 per key in total
+	)
+	l, _ := frozenStore(1, burst)
+
+	var allowed [keys]atomic.Int64
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range takes {
+				for k := range keys {
+					ok, wait, err := l.Take(context.Background(), fmt.Sprintf("10.0.0.%d", k))
+					if err != nil {
+						t.Errorf("Take: %v", err)
+						return
+					}
+					if ok {
+						allowed[k].Add(1)
+					} else if wait <= 0 {
+						t.Errorf("refused with a wait of %s", wait)
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	for k := range keys {
+		if got := allowed[k].Load(); got != burst {
+			t.Errorf("key %d: %d requests allowed, want %d", k, got, burst)
+		}
+	}
+}
+
+func TestMemoryLimitStoreRefill(t *testing.T) {
+	l, now := frozenStore(2, 2)
+	ctx := context.Background()
+
+	for i := range 2 {
+		if ok, _, _ := l.Take(ctx, "a"); !ok {
+			t.Fatalf("take %d refused within the burst", i+1)
+		}
+	}
+	if ok, wait, _ := l.Take(ctx, "a"); ok || wait != 500*time.Millisecond {
+		t.Errorf("empty bucket = %v, wait %s; want refused, 500ms", ok, wait)
+	}
+
+	// 2 tokens per second: one back after half a second
+	*now = now.Add(500 * time.Millisecond)
+	if ok, _, _ := l.Take(ctx, "a"); !ok {
+		t.Error("refused after the refill")
+	}
+}
+
+// TestMemoryLimitStoreSweep checks idle buckets are dropped, so the map
+// doesn't keep every IP ever seen.
+func TestMemoryLimitStoreSweep(t *testing.T) {
+	l, now := frozenStore(10, 20) // refills in 2s, so idleTTL is the 1 minute floor
+	ctx := context.Background()
+
+	l.Take(ctx, "idle-1")
+	l.Take(ctx, "idle-2")
+
+	*now = now.Add(30 * time.Second)
+	l.Take(ctx, "active")
+	if got := len(l.buckets); got != 3 {
+		t.Fatalf("%d buckets before the ttl, want 3", got)
+	}
+
+	// a minute after the first ones: they go, "active" (30s idle) stays
+	*now = now.Add(30 * time.Second)
+	l.Take(ctx, "new")
+	if _, ok := l.buckets["active"]; !ok || len(l.buckets) != 2 {
+		t.Errorf("buckets after the sweep: %v, want active and new", keysOf(l.buckets))
+	}
+
+	// a minute later the others have been idle long enough too
+	*now = now.Add(time.Minute)
+	l.Take(ctx, "last")
+	if len(l.buckets) != 1 {
+		t.Errorf("buckets after the second sweep: %v, want last", keysOf(l.buckets))
+	}
+}
+
+func keysOf(buckets map[string]*bucket) []string {
+	keys := make([]string, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	l := NewRateLimiter(config.RateLimit{RequestsPerSecond: 0.5, Burst: 1}, nil)
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	get := func(path, remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/api/v1/students", "10.0.0.1:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status %d", rec.Code)
+	}
+	rec := get("/api/v1/students", "10.0.0.1:1001")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("second request: status %d, Retry-After %q; want 429, 2", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := get("/api/v1/students", "10.0.0.2:1000"); rec.Code != http.StatusOK {
+		t.Errorf("other client: status %d, want its own bucket", rec.Code)
+	}
+	for path := range exemptPaths {
+		if rec := get(path, "10.0.0.1:1002"); rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d, want exempt", path, rec.Code)
+		}
+	}
+}
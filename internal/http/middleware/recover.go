@@ -0,0 +1,64 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - log/slog      → log the panic value and stack trace
+   - net/http      → http.Handler, http.ErrAbortHandler
+   - runtime/debug → debug.Stack() returns the goroutine's stack
+   - response      → JSON error body for the client
+   - logging       → request-scoped logger
+*/
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Recover()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Catches panics from any handler below it and answers
+	    with a JSON 500 instead of letting net/http drop the
+	    connection with no body.
+
+	WHAT THE CLIENT SEES:
+	  → {"status":"Error","code":"internal","error":"internal server error"}
+	  → The panic message is NOT sent (it may leak internals);
+	    it goes to the logs together with the stack trace.
+
+	http.ErrAbortHandler:
+	  → Handlers panic with this value ON PURPOSE to abort a
+	    response; net/http handles it silently, so we re-panic.
+*/
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			logging.FromContext(r.Context()).Error("panic recovered",
+				slog.Any("panic", rec),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			response.WriteJson(w, http.StatusInternalServerError, response.Internal("internal server error"))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+)
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name  string
+		panic func()
+	}{
+		{"string", func() { panic("secret table students_v2 is missing") }},
+		{"error", func() { panic(errors.New("secret table students_v2 is missing")) }},
+		{"runtime error", func() {
+			var counts map[string]int
+			counts["x"]++
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.panic()
+			}))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/students", nil))
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status %d, want 500", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+				t.Errorf("Content-Type %q, want JSON", got)
+			}
+			want := `{"status":"Error","code":"internal","error":"internal server error"}`
+			if got := strings.TrimSpace(rec.Body.String()); got != want {
+				t.Errorf("body %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+// TestRecoverAbortHandler checks http.ErrAbortHandler goes on up to
+// net/http, which drops the connection without logging.
+func TestRecoverAbortHandler(t *testing.T) {
+	h := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	rec := httptest.NewRecorder()
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler re-panicked", p)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("body %s, want none", rec.Body)
+		}
+	}()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/students", nil))
+}
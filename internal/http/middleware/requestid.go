@@ -0,0 +1,117 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context     → store the request ID and logger on the request
+   - crypto/rand → random bytes for generated IDs
+   - encoding/hex→ turn the random bytes into a printable ID
+   - log/slog    → attributes of the request-scoped logger
+   - net/http    → http.Handler, headers
+   - logging     → where the request-scoped logger is stored
+*/
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+)
+
+// RequestIDHeader is read from incoming requests and echoed on every response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength stops clients from stuffing huge values into our logs.
+const maxRequestIDLength = 128
+
+/*
+CONTEXT KEYS
+-------------------------------------------------------------
+  - An unexported type means no other package can create a
+    colliding key, even if it also uses the value 0 or 1.
+*/
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	subjectKey
+	clientKey
+	roleKey
+	requestInfoKey
+)
+
+/*
+RequestIDMiddleware()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Gives every request a correlation ID:
+	      - reuse X-Request-ID when the client (or a proxy) sent
+	        a sane one
+	      - otherwise generate 16 random bytes as hex
+	  → Echoes it back in the X-Request-ID response header.
+	  → Stores it in the context (see RequestID) together with a
+	    logger that already has request_id, method and path
+	    attached (see logging.FromContext).
+
+	ORDER:
+	  → Must wrap Logging/Recover so their log lines carry the ID.
+*/
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		ctx = logging.WithLogger(ctx, slog.Default().With(
+			slog.String("request_id", id),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+/*
+RequestID()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the request ID stored by RequestIDMiddleware,
+	    or "" when the context has none (e.g. background jobs).
+*/
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// validRequestID accepts short IDs made of printable ASCII only.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID returns 32 hex characters from crypto/rand.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,224 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context       → context.WithTimeout puts a deadline on the request
+   - errors        → deadline passed vs client gone
+   - fmt           → keep the handler's stack in a re-raised panic
+   - log/slog      → timed-out requests, panics after a timeout
+   - net/http      → http.Handler, http.ErrHandlerTimeout
+   - runtime/debug → stack of a panicking handler
+   - sync          → the handler and the timeout race for the response
+   - time          → the configured duration
+   - logging       → request-scoped logger
+   - response      → JSON body of the 503
+*/
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/logging"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Timeout()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Gives every request context a deadline of d.
+	  → Handlers pass r.Context() to every storage call, and the
+	    SQL backends use QueryContext/ExecContext, so a hung query
+	    is cancelled once the deadline passes (the same happens
+	    when the client disconnects: net/http cancels the context).
+	  → A handler that hasn't started its response by then is not
+	    waited for: the client gets
+	      503 {"status":"Error","code":"timeout","error":"request timed out after 10s"}
+	    and whatever the handler writes later is discarded
+	    (http.ErrHandlerTimeout), see timeoutWriter.
+
+	NOTES:
+	  → d <= 0 disables the deadline (http_server.request_timeout: 0).
+	  → The handler runs in its own goroutine; a panic in it is
+	    raised again here, so Recover still answers 500.
+	  → A response already started when the deadline passes is
+	    left to the handler to finish: the status is sent, it can
+	    no longer become a 503.
+	  → Not for streams (SSE), which are meant to outlive any
+	    deadline: routes.go skips them.
+
+	USAGE:
+	  handler := middleware.Timeout(cfg.HTTPServer.RequestTimeout)(router)
+*/
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, header: w.Header().Clone()}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						tw.handlerPanicked(r, p, panicked)
+						return
+					}
+					close(done)
+				}()
+
+				next.ServeHTTP(tw, r.WithContext(ctx))
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				return
+			case <-ctx.Done():
+			}
+
+			tw.mu.Lock()
+			if tw.wroteHeader {
+				tw.mu.Unlock()
+
+				select {
+				case p := <-panicked:
+					panic(p)
+				case <-done:
+				}
+				return
+			}
+			tw.timedOut = true
+			tw.mu.Unlock()
+
+			// Cancelled instead: the client went away, nobody to answer.
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				logging.FromContext(r.Context()).Warn("request timed out", slog.Duration("timeout", d))
+
+				response.WriteJson(w, http.StatusServiceUnavailable,
+					response.Timeout(fmt.Sprintf("request timed out after %s", d)))
+			}
+		})
+	}
+}
+
+/*
+timeoutWriter STRUCT
+-------------------------------------------------------------
+  - What the handler writes to under Timeout. Whoever sends the
+    status first owns the response: the handler (WriteHeader /
+    Write / Flush) or Timeout (the 503, flagged by timedOut).
+  - The handler gets its own copy of the headers, copied to the
+    real writer with the status, so a late handler can't touch
+    the headers of the 503 either.
+*/
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.writeHeader(status)
+}
+
+// Write fails with http.ErrHandlerTimeout once the 503 was sent.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+
+	return tw.w.Write(b)
+}
+
+// FlushError is what http.ResponseController.Flush calls; flushing the real
+// writer must not happen while Timeout writes the 503.
+func (tw *timeoutWriter) FlushError() error {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+
+	return http.NewResponseController(tw.w).Flush()
+}
+
+// Unwrap lets http.ResponseController (and response.WriteJson, for the
+// negotiated format) reach the original writer.
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+// writeHeader sends the handler's headers and status. tw.mu must be held.
+func (tw *timeoutWriter) writeHeader(status int) {
+	dst := tw.w.Header()
+	clear(dst)
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+
+	tw.w.WriteHeader(status)
+	tw.wroteHeader = true
+}
+
+// handlerPanicked hands a panic of the handler goroutine to Timeout, with
+// the handler's stack (the re-raised panic only has Timeout's). After the
+// 503, Timeout has returned: the panic is only logged.
+func (tw *timeoutWriter) handlerPanicked(r *http.Request, p any, panicked chan<- any) {
+	tw.mu.Lock()
+	late := tw.timedOut
+	tw.mu.Unlock()
+
+	if late {
+		if p != http.ErrAbortHandler {
+			logging.FromContext(r.Context()).Error("panic after the request timed out",
+				slog.Any("panic", p),
+				slog.String("stack", string(debug.Stack())),
+			)
+		}
+		return
+	}
+
+	if p != http.ErrAbortHandler {
+		p = fmt.Sprintf("%v\n\n%s", p, debug.Stack())
+	}
+	panicked <- p
+}
@@ -0,0 +1,52 @@
+package middleware
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net/http → http.Handler
+   - strings  → map a deprecated path to its successor
+*/
+import (
+	"net/http"
+	"strings"
+)
+
+// APIVersion tags every response of the wrapped routes with
+// X-API-Version: version, so clients (and logs of proxies) can tell which
+// contract answered.
+func APIVersion(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-API-Version", version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/*
+Deprecated()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Marks routes kept only as an alias: responses carry
+	    "Deprecation: true" and a Link to the same resource under
+	    its successor prefix:
+	      GET /api/students/7
+	        → Link: </api/v1/students/7>; rel="successor-version"
+
+	USAGE:
+	  mux.Group("/api", middleware.Deprecated("/api", "/api/v1"))
+*/
+func Deprecated(prefix, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+				w.Header().Set("Link", "<"+successor+rest+`>; rel="successor-version"`)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
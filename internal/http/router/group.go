@@ -0,0 +1,62 @@
+package router
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net/http → http.Handler
+   - strings  → split "METHOD /path" patterns
+*/
+import (
+	"net/http"
+	"strings"
+)
+
+/*
+Group STRUCT
+-------------------------------------------------------------
+  - Registers routes under a common path prefix, with the same
+    middleware wrapped around each of them:
+      v1 := mux.Group("/api/v1", setVersion)
+      v1.Handle("GET /students/{id}", h)
+        → "GET /api/v1/students/{id}", served by setVersion(h)
+  - Route code only names paths relative to its group, so the
+    same routes can be mounted twice (an alias) and a v2 group
+    can get different handlers without touching v1.
+*/
+type Group struct {
+	router *Router
+	prefix string
+	wrap   []func(http.Handler) http.Handler
+}
+
+// Group returns a Group registering on rt under prefix ("/api/v1", no
+// trailing slash). wrap is applied to every handler, first one outermost.
+func (rt *Router) Group(prefix string, wrap ...func(http.Handler) http.Handler) *Group {
+	return &Group{router: rt, prefix: prefix, wrap: wrap}
+}
+
+// Handle registers handler for pattern ("METHOD /path" or "/path") below
+// the group's prefix.
+func (g *Group) Handle(pattern string, handler http.Handler) {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		method, path = "", pattern
+	}
+
+	for i := len(g.wrap) - 1; i >= 0; i-- {
+		handler = g.wrap[i](handler)
+	}
+
+	pattern = g.prefix + path
+	if method != "" {
+		pattern = method + " " + pattern
+	}
+
+	g.router.Handle(pattern, handler)
+}
+
+// HandleFunc is Handle for a plain function.
+func (g *Group) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	g.Handle(pattern, http.HandlerFunc(handler))
+}
@@ -0,0 +1,136 @@
+package router // router package wraps http.ServeMux so unmatched requests get JSON errors
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - fmt      → error messages
+   - net/http → ServeMux, http.Handler
+   - slices   → keep the Allow list sorted
+   - strings  → split / join the Allow list
+   - response → JSON error bodies
+*/
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+/*
+Router STRUCT
+-------------------------------------------------------------
+  - A plain http.ServeMux (same Go 1.22+ patterns, same
+    matching), except for requests no pattern matches:
+      - ServeMux answers those in text/plain
+      - Router answers them in JSON like every other endpoint,
+        and answers OPTIONS for every registered path
+*/
+type Router struct {
+	mux *http.ServeMux
+}
+
+// New returns an empty Router.
+func New() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+// Handle registers handler for pattern, see http.ServeMux.Handle.
+func (rt *Router) Handle(pattern string, handler http.Handler) {
+	rt.mux.Handle(pattern, handler)
+}
+
+// HandleFunc registers handler for pattern, see http.ServeMux.HandleFunc.
+func (rt *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	rt.mux.HandleFunc(pattern, handler)
+}
+
+/*
+ServeHTTP()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Matched requests (and ServeMux's own redirects to the
+	    canonical path) are served by the mux unchanged.
+	  → Unmatched requests:
+	      - OPTIONS on a registered path       → 204 + Allow
+	      - the path exists with other methods → 405 + Allow
+	      - nothing registered for the path    → 404
+	  → Allow always includes OPTIONS itself.
+	  → With CORS enabled, preflights never get here: the CORS
+	    middleware answers them first. Plain OPTIONS (no
+	    Access-Control-Request-Method) do, and only learn which
+	    methods the path supports.
+
+	HOW IT WORKS:
+	  → ServeMux.Handler reports an empty pattern when it would
+	    fall back to its own 404/405 handler. That fallback is run
+	    against a probe writer: its status tells 404 from 405, and
+	    its Allow header lists the methods registered for the path
+	    (GET implies HEAD), so nothing needs tracking here.
+*/
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fallback, pattern := rt.mux.Handler(r)
+	if pattern != "" {
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+
+	probe := &probeWriter{header: http.Header{}}
+	fallback.ServeHTTP(probe, r)
+
+	if probe.status == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", withOptions(probe.header.Get("Allow")))
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		response.WriteJson(w, http.StatusMethodNotAllowed,
+			response.MethodNotAllowed(fmt.Sprintf("method %s not allowed on %s", r.Method, r.URL.Path)))
+		return
+	}
+
+	response.WriteJson(w, http.StatusNotFound, response.NotFound(fmt.Sprintf("no route for %s", r.URL.Path)))
+}
+
+// withOptions adds OPTIONS to a ServeMux Allow list ("GET, HEAD, POST"),
+// keeping it sorted the way ServeMux sorts it.
+func withOptions(allow string) string {
+	methods := strings.Split(allow, ", ")
+	if !slices.Contains(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+		slices.Sort(methods)
+	}
+
+	return strings.Join(methods, ", ")
+}
+
+/*
+probeWriter STRUCT
+-------------------------------------------------------------
+  - Collects what ServeMux's fallback handler would have sent
+    and discards the body.
+*/
+type probeWriter struct {
+	header http.Header
+	status int
+}
+
+func (p *probeWriter) Header() http.Header { return p.header }
+
+func (p *probeWriter) WriteHeader(status int) {
+	if p.status == 0 {
+		p.status = status
+	}
+}
+
+func (p *probeWriter) Write(b []byte) (int, error) {
+	if p.status == 0 {
+		p.status = http.StatusOK
+	}
+	return len(b), nil
+}
@@ -0,0 +1,147 @@
+package logging
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - errors   → writes before Open / after Close
+   - fmt      → open / rotate errors with the path
+   - log/slog → a failed rotation is reported, writing goes on
+   - os       → the log file itself
+   - sync     → shared by every request
+   - time     → timestamp suffix of rotated files
+*/
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// rotatedSuffix is appended (after a dot) to the name of a rotated file:
+// access.log → access.log.2024-05-01T10-30-00.000
+const rotatedSuffix = "2006-01-02T15-04-05.000"
+
+/*
+File STRUCT
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → An io.Writer appending to a log file (log.access_log_path)
+	    that can be rotated while the server runs.
+
+	ROTATION:
+	  → By logrotate: it renames the file and sends SIGHUP; Open
+	    is called again and starts a new file at path.
+	  → By size: once a write would grow the file past maxSize,
+	    the file is renamed with a timestamp suffix first and a new
+	    one is started. maxSize 0 disables it. Old files are never
+	    deleted here.
+
+	LIFECYCLE:
+	  → NewFile → Open (fails on a bad path) → Write… → Close.
+*/
+type File struct {
+	path    string
+	maxSize int64
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewFile returns a File for path; nothing is opened until Open.
+func NewFile(path string, maxSize int64) *File {
+	return &File{path: path, maxSize: maxSize}
+}
+
+// Path returns the path the file is written to.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Open opens (creating it if needed, appending otherwise) the file at path,
+// closing the previously open one. Fails after Close.
+func (f *File) Open() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	return f.open()
+}
+
+// Write appends p, rotating the file first when it would exceed maxSize.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, errors.New("log file is not open")
+	}
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			// keep writing to the current file rather than losing lines
+			slog.Error("cannot rotate log file", slog.String("path", f.path), slog.String("error", err.Error()))
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close flushes the file to disk and closes it; Write and Open fail
+// afterwards. Safe to call more than once.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+
+	file := f.file
+	f.file = nil
+
+	syncErr := file.Sync()
+	return errors.Join(syncErr, file.Close())
+}
+
+// open (re)opens path and records its current size. f.mu must be held.
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if f.file != nil {
+		f.file.Close()
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and starts a new one. f.mu must be
+// held.
+func (f *File) rotate() error {
+	rotated := f.path + "." + time.Now().UTC().Format(rotatedSuffix)
+	if err := os.Rename(f.path, rotated); err != nil {
+		return fmt.Errorf("rename to %s: %w", rotated, err)
+	}
+
+	return f.open()
+}
@@ -0,0 +1,48 @@
+package logging // logging package carries the request-scoped logger in the context
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → the logger travels with the request context
+   - log/slog → structured logging (new standard logger)
+*/
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey is unexported, so no other package can collide with it.
+type loggerKey struct{}
+
+/*
+FromContext()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the request-scoped logger, so handlers can write
+	      logging.FromContext(r.Context()).Info("creating a student")
+	    and get request_id, method and path (installed by
+	    middleware.RequestIDMiddleware), plus subject / client
+	    once Auth identified the caller, on every line.
+	  → Falls back to slog.Default() when ctx carries no logger
+	    (background jobs, library code), so it never returns nil.
+*/
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// With returns a copy of ctx whose logger has args added to it (same as
+// slog.Logger.With).
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,191 @@
+package metrics // metrics package owns the Prometheus collectors and the /metrics handler
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - database/sql  → connection pool statistics (sql.DBStats)
+   - net/http      → http.Handler for the /metrics endpoint
+   - strconv       → status class label ("2xx", "4xx", …)
+   - time          → request latency
+   - prometheus    → counters, gauges, histograms and the registry
+   - collectors    → Go runtime + process + connection pool metrics
+   - promhttp      → exposition format handler
+*/
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+/*
+REGISTRY
+-------------------------------------------------------------
+  - Our own registry instead of prometheus.DefaultRegisterer,
+    so /metrics shows exactly what this service registers
+    (plus the Go runtime / process collectors added below).
+*/
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests handled, by route pattern, method and status class.",
+	}, []string{"route", "method", "status"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency, by route pattern, method and status class.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	storageQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_queries_total",
+		Help: "Storage calls, by operation.",
+	}, []string{"operation"})
+
+	storageErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_errors_total",
+		Help: "Storage calls that failed (not found is not a failure), by operation.",
+	}, []string{"operation"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "student_cache_lookups_total",
+		Help: "Lookups in the student cache (cache.enabled), by result (hit or miss).",
+	}, []string{"result"})
+
+	concurrencyInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_concurrency_in_flight",
+		Help: "Requests holding a concurrency slot (concurrency.*), by class (read or write).",
+	}, []string{"class"})
+
+	concurrencyQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_concurrency_queued",
+		Help: "Requests waiting for a concurrency slot, by class (read or write).",
+	}, []string{"class"})
+
+	concurrencyRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_concurrency_rejected_total",
+		Help: "Requests answered 503 after waiting concurrency.max_wait for a slot, by class.",
+	}, []string{"class"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpInFlight,
+		httpDuration,
+		storageQueries,
+		storageErrors,
+		cacheLookups,
+		concurrencyInFlight,
+		concurrencyQueued,
+		concurrencyRejected,
+	)
+}
+
+/*
+RegisterDBStats()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Exposes the sql.DBStats of a connection pool, read at every
+	    scrape, labelled db_name=<name> (the storage driver):
+	      go_sql_max_open_connections   storage.max_open_conns
+	      go_sql_open_connections       in use + idle
+	      go_sql_in_use_connections
+	      go_sql_idle_connections
+	      go_sql_wait_count_total       waits for a free connection
+	      go_sql_wait_duration_seconds_total
+	      go_sql_max_idle_closed_total, …_max_lifetime_closed_total
+	  → A growing wait count with in use == max open is pool
+	    exhaustion: raise max_open_conns or find the slow query.
+
+	NOTES:
+	  → Called once at startup by main, for the SQL backends only.
+*/
+func RegisterDBStats(db *sql.DB, name string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves every registered metric in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+/*
+RequestStarted() / RequestFinished()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Called by the HTTP metrics middleware around every request.
+
+	LABELS:
+	  → route is the ServeMux pattern ("GET /api/students/{id}"),
+	    never the raw path, so /api/students/1, /2, /3… share one
+	    series. Requests that matched no route are "unmatched".
+	  → status is the class ("2xx"), which keeps cardinality low.
+*/
+func RequestStarted() {
+	httpInFlight.Inc()
+}
+
+// RequestFinished undoes RequestStarted and records the finished request.
+func RequestFinished(route, method string, status int, elapsed time.Duration) {
+	httpInFlight.Dec()
+
+	if route == "" {
+		route = "unmatched"
+	}
+	class := strconv.Itoa(status/100) + "xx"
+
+	httpRequests.WithLabelValues(route, method, class).Inc()
+	httpDuration.WithLabelValues(route, method, class).Observe(elapsed.Seconds())
+}
+
+// CacheLookup counts one lookup in the student cache.
+func CacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	cacheLookups.WithLabelValues(result).Inc()
+}
+
+/*
+ConcurrencyQueued() / ConcurrencyAcquired() / ConcurrencyReleased()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Called by the concurrency limiter: delta +1 / -1 as a
+	    request starts / stops waiting for a slot of class, then
+	    Acquired (or Rejected) and, once served, Released.
+*/
+func ConcurrencyQueued(class string, delta float64) {
+	concurrencyQueued.WithLabelValues(class).Add(delta)
+}
+
+func ConcurrencyAcquired(class string) {
+	concurrencyInFlight.WithLabelValues(class).Inc()
+}
+
+func ConcurrencyReleased(class string) {
+	concurrencyInFlight.WithLabelValues(class).Dec()
+}
+
+func ConcurrencyRejected(class string) {
+	concurrencyRejected.WithLabelValues(class).Inc()
+}
@@ -0,0 +1,118 @@
+package metrics_test
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/app"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/metrics"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+)
+
+// scrape is one read of /metrics: the type of every family, and the value
+// of every series by its name and labels as written
+// (`http_requests_total{method="GET",...}`).
+type scrape struct {
+	types  map[string]string
+	series map[string]float64
+}
+
+func scrapeMetrics(t *testing.T, srv *httptest.Server) scrape {
+	t.Helper()
+
+	res := apptest.Do(t, srv, http.MethodGet, "/metrics", nil)
+	if res.Status != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("/metrics: status %d, Content-Type %q", res.Status, res.Header.Get("Content-Type"))
+	}
+
+	s := scrape{types: map[string]string{}, series: map[string]float64{}}
+	lines := bufio.NewScanner(bytes.NewReader(res.Body))
+	for lines.Scan() {
+		line := lines.Text()
+		if family, found := strings.CutPrefix(line, "# TYPE "); found {
+			name, kind, _ := strings.Cut(family, " ")
+			s.types[name] = kind
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sep := strings.LastIndexByte(line, ' ')
+		value, err := strconv.ParseFloat(line[sep+1:], 64)
+		if err != nil {
+			t.Fatalf("/metrics line %q: %v", line, err)
+		}
+		s.series[line[:sep]] = value
+	}
+
+	return s
+}
+
+// TestScrape checks the families the dashboards and alerts query are
+// exposed with their type, and that requests move the series they should.
+// The registry is the package's, so series are compared to a first scrape.
+func TestScrape(t *testing.T) {
+	cfg := apptest.Config(t)
+	cfg.Cache.Enabled = true
+	srv := httptest.NewServer(app.New(cfg, metrics.InstrumentStorage(memory.New(cfg))).Handler())
+	t.Cleanup(srv.Close)
+
+	before := scrapeMetrics(t, srv)
+
+	ann := map[string]any{"name": "Ann Lee", "email": "ann@example.com", "age": 20}
+	apptest.Do(t, srv, http.MethodPost, "/api/v1/students", ann) // 201
+	apptest.Do(t, srv, http.MethodPost, "/api/v1/students", ann) // 409, a storage error
+	apptest.Do(t, srv, http.MethodGet, "/api/v1/students/1", nil)
+	apptest.Do(t, srv, http.MethodGet, "/api/v1/students/1", nil)
+	apptest.Do(t, srv, http.MethodGet, "/no/such/route", nil)
+
+	after := scrapeMetrics(t, srv)
+
+	families := map[string]string{
+		"http_requests_total":           "counter",
+		"http_requests_in_flight":       "gauge",
+		"http_request_duration_seconds": "histogram",
+		"storage_queries_total":         "counter",
+		"storage_errors_total":          "counter",
+		"student_cache_lookups_total":   "counter",
+		"go_goroutines":                 "gauge",
+		"process_start_time_seconds":    "gauge",
+	}
+	for name, kind := range families {
+		if got := after.types[name]; got != kind {
+			t.Errorf("family %s has type %q, want %q", name, got, kind)
+		}
+	}
+
+	tests := []struct {
+		series string
+		delta  float64 // at least; 0 means only "exposed"
+	}{
+		{`http_requests_total{method="POST",route="POST /api/v1/students",status="2xx"}`, 1},
+		{`http_requests_total{method="POST",route="POST /api/v1/students",status="4xx"}`, 1},
+		{`http_requests_total{method="GET",route="GET /api/v1/students/{id}",status="2xx"}`, 2},
+		{`http_requests_total{method="GET",route="unmatched",status="4xx"}`, 1},
+		{`http_request_duration_seconds_count{method="GET",route="GET /api/v1/students/{id}",status="2xx"}`, 2},
+		{`http_request_duration_seconds_bucket{method="GET",route="GET /api/v1/students/{id}",status="2xx",le="+Inf"}`, 2},
+		{`storage_queries_total{operation="create_student"}`, 2},
+		{`storage_errors_total{operation="create_student"}`, 1},
+		{`student_cache_lookups_total{result="hit"}`, 1},
+		{`http_requests_in_flight`, 0},
+	}
+	for _, tt := range tests {
+		value, exposed := after.series[tt.series]
+		if !exposed {
+			t.Errorf("%s is not exposed", tt.series)
+			continue
+		}
+		if delta := value - before.series[tt.series]; delta < tt.delta {
+			t.Errorf("%s went up by %g, want at least %g", tt.series, delta, tt.delta)
+		}
+	}
+}
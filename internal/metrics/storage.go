@@ -0,0 +1,288 @@
+package metrics
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → passed through to the wrapped storage
+   - errors  → ErrNotFound / ErrCourseNotFound / ErrPhotoNotFound are
+               answers, not failures
+   - storage → the interface we decorate
+   - time    → purge cut-offs, idempotency key expiry
+   - types   → Student / StudentPatch / Course / Photo
+*/
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+instrumentedStorage STRUCT
+-------------------------------------------------------------
+  - Decorator: implements storage.Storage by calling the real
+    backend and counting every call (and every failure).
+  - Works the same for any backend, so SQLite, Postgres or an
+    in-memory store all get metrics for free.
+*/
+type instrumentedStorage struct {
+	next storage.Storage
+}
+
+// InstrumentStorage wraps s so every call is counted in
+// storage_queries_total and storage_errors_total.
+func InstrumentStorage(s storage.Storage) storage.Storage {
+	return &instrumentedStorage{next: s}
+}
+
+// observe counts one call of operation and, when it failed, one error.
+func observe(operation string, err error) {
+	storageQueries.WithLabelValues(operation).Inc()
+
+	if err != nil && !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, storage.ErrCourseNotFound) &&
+		!errors.Is(err, storage.ErrPhotoNotFound) && !errors.Is(err, storage.ErrInvalidID) {
+		storageErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (s *instrumentedStorage) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+	id, err := s.next.CreateStudent(ctx, student)
+	observe("create_student", err)
+	return id, err
+}
+
+func (s *instrumentedStorage) CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]storage.BulkResult, error) {
+	results, err := s.next.CreateStudents(ctx, students, atomic)
+	observe("create_students", err)
+	return results, err
+}
+
+func (s *instrumentedStorage) UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error) {
+	id, created, err := s.next.UpsertStudent(ctx, student)
+	observe("upsert_student", err)
+	return id, created, err
+}
+
+func (s *instrumentedStorage) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	student, err := s.next.GetStudentById(ctx, id)
+	observe("get_student", err)
+	return student, err
+}
+
+func (s *instrumentedStorage) ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error) {
+	students, err := s.next.ListStudents(ctx, filter, limit, offset)
+	observe("list_students", err)
+	return students, err
+}
+
+func (s *instrumentedStorage) ListStudentsAfter(ctx context.Context, filter types.StudentFilter, afterID int64, limit int) ([]types.Student, error) {
+	students, err := s.next.ListStudentsAfter(ctx, filter, afterID, limit)
+	observe("list_students_after", err)
+	return students, err
+}
+
+func (s *instrumentedStorage) ForEachStudent(ctx context.Context, filter types.StudentFilter, fn func(types.Student) error) error {
+	err := s.next.ForEachStudent(ctx, filter, fn)
+	observe("for_each_student", err)
+	return err
+}
+
+func (s *instrumentedStorage) CountStudents(ctx context.Context, filter types.StudentFilter) (int, error) {
+	total, err := s.next.CountStudents(ctx, filter)
+	observe("count_students", err)
+	return total, err
+}
+
+func (s *instrumentedStorage) FindStudentsByEmailOrName(ctx context.Context, emails, names []string) ([]types.Student, error) {
+	students, err := s.next.FindStudentsByEmailOrName(ctx, emails, names)
+	observe("find_students", err)
+	return students, err
+}
+
+func (s *instrumentedStorage) StudentStats(ctx context.Context, since time.Time) (types.StudentStats, error) {
+	stats, err := s.next.StudentStats(ctx, since)
+	observe("student_stats", err)
+	return stats, err
+}
+
+func (s *instrumentedStorage) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+	updated, err := s.next.UpdateStudent(ctx, id, student, version)
+	observe("update_student", err)
+	return updated, err
+}
+
+func (s *instrumentedStorage) PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error) {
+	updated, err := s.next.PatchStudent(ctx, id, patch, version)
+	observe("patch_student", err)
+	return updated, err
+}
+
+func (s *instrumentedStorage) SetStudentStatus(ctx context.Context, id int64, status string) (bool, error) {
+	changed, err := s.next.SetStudentStatus(ctx, id, status)
+	observe("set_student_status", err)
+	return changed, err
+}
+
+func (s *instrumentedStorage) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+	deleted, err := s.next.DeleteStudent(ctx, id)
+	observe("delete_student", err)
+	return deleted, err
+}
+
+func (s *instrumentedStorage) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	deleted, err := s.next.DeleteStudents(ctx, filter)
+	observe("delete_students", err)
+	return deleted, err
+}
+
+func (s *instrumentedStorage) RestoreStudent(ctx context.Context, id int64) (bool, error) {
+	restored, err := s.next.RestoreStudent(ctx, id)
+	observe("restore_student", err)
+	return restored, err
+}
+
+func (s *instrumentedStorage) PurgeDeletedStudents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	purged, err := s.next.PurgeDeletedStudents(ctx, before, limit)
+	observe("purge_deleted_students", err)
+	return purged, err
+}
+
+func (s *instrumentedStorage) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, expiresAt time.Time) (storage.IdempotencyRecord, bool, error) {
+	record, reserved, err := s.next.ReserveIdempotencyKey(ctx, key, requestHash, expiresAt)
+	observe("reserve_idempotency_key", err)
+	return record, reserved, err
+}
+
+func (s *instrumentedStorage) CompleteIdempotencyKey(ctx context.Context, key string, response []byte) error {
+	err := s.next.CompleteIdempotencyKey(ctx, key, response)
+	observe("complete_idempotency_key", err)
+	return err
+}
+
+func (s *instrumentedStorage) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	err := s.next.ReleaseIdempotencyKey(ctx, key)
+	observe("release_idempotency_key", err)
+	return err
+}
+
+func (s *instrumentedStorage) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	purged, err := s.next.PurgeExpiredIdempotencyKeys(ctx, now)
+	observe("purge_expired_idempotency_keys", err)
+	return purged, err
+}
+
+func (s *instrumentedStorage) ListAuditEvents(ctx context.Context, studentID int64, limit, offset int) ([]types.AuditEvent, error) {
+	events, err := s.next.ListAuditEvents(ctx, studentID, limit, offset)
+	observe("list_audit_events", err)
+	return events, err
+}
+
+func (s *instrumentedStorage) CountAuditEvents(ctx context.Context, studentID int64) (int, error) {
+	count, err := s.next.CountAuditEvents(ctx, studentID)
+	observe("count_audit_events", err)
+	return count, err
+}
+
+func (s *instrumentedStorage) CreateCourse(ctx context.Context, course types.Course) (int64, error) {
+	id, err := s.next.CreateCourse(ctx, course)
+	observe("create_course", err)
+	return id, err
+}
+
+func (s *instrumentedStorage) GetCourseById(ctx context.Context, id int64) (types.Course, error) {
+	course, err := s.next.GetCourseById(ctx, id)
+	observe("get_course", err)
+	return course, err
+}
+
+func (s *instrumentedStorage) ListCourses(ctx context.Context, limit, offset int) ([]types.Course, error) {
+	courses, err := s.next.ListCourses(ctx, limit, offset)
+	observe("list_courses", err)
+	return courses, err
+}
+
+func (s *instrumentedStorage) CountCourses(ctx context.Context) (int, error) {
+	count, err := s.next.CountCourses(ctx)
+	observe("count_courses", err)
+	return count, err
+}
+
+func (s *instrumentedStorage) UpdateCourse(ctx context.Context, id int64, course types.Course) (bool, error) {
+	updated, err := s.next.UpdateCourse(ctx, id, course)
+	observe("update_course", err)
+	return updated, err
+}
+
+func (s *instrumentedStorage) DeleteCourse(ctx context.Context, id int64) (bool, error) {
+	deleted, err := s.next.DeleteCourse(ctx, id)
+	observe("delete_course", err)
+	return deleted, err
+}
+
+func (s *instrumentedStorage) Enroll(ctx context.Context, studentID, courseID int64) error {
+	err := s.next.Enroll(ctx, studentID, courseID)
+	observe("enroll", err)
+	return err
+}
+
+func (s *instrumentedStorage) Unenroll(ctx context.Context, studentID, courseID int64) (bool, error) {
+	removed, err := s.next.Unenroll(ctx, studentID, courseID)
+	observe("unenroll", err)
+	return removed, err
+}
+
+func (s *instrumentedStorage) ListEnrollments(ctx context.Context, studentID int64) ([]types.Enrollment, error) {
+	enrollments, err := s.next.ListEnrollments(ctx, studentID)
+	observe("list_enrollments", err)
+	return enrollments, err
+}
+
+func (s *instrumentedStorage) ListCourseStudents(ctx context.Context, courseID int64, sort types.RosterSort, limit, offset int) ([]types.CourseStudent, error) {
+	roster, err := s.next.ListCourseStudents(ctx, courseID, sort, limit, offset)
+	observe("list_course_students", err)
+	return roster, err
+}
+
+func (s *instrumentedStorage) PutStudentPhoto(ctx context.Context, id int64, photo types.Photo) (bool, error) {
+	created, err := s.next.PutStudentPhoto(ctx, id, photo)
+	observe("put_student_photo", err)
+	return created, err
+}
+
+func (s *instrumentedStorage) GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error) {
+	photo, err := s.next.GetStudentPhoto(ctx, id)
+	observe("get_student_photo", err)
+	return photo, err
+}
+
+func (s *instrumentedStorage) AddStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	added, err := s.next.AddStudentTag(ctx, id, tag)
+	observe("add_student_tag", err)
+	return added, err
+}
+
+func (s *instrumentedStorage) RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	removed, err := s.next.RemoveStudentTag(ctx, id, tag)
+	observe("remove_student_tag", err)
+	return removed, err
+}
+
+func (s *instrumentedStorage) ResolveStudentID(ctx context.Context, id string) (int64, error) {
+	rowID, err := s.next.ResolveStudentID(ctx, id)
+	observe("resolve_student_id", err)
+	return rowID, err
+}
+
+func (s *instrumentedStorage) Ping(ctx context.Context) error {
+	err := s.next.Ping(ctx)
+	observe("ping", err)
+	return err
+}
+
+// Close is not a query: it is not counted.
+func (s *instrumentedStorage) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,259 @@
+package photos // photos package keeps the profile photos of the students as files (photos.store: disk)
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context       → passed through to the wrapped storage
+   - errors        → a missing file is "no photo", not a failure
+   - fmt           → file names, wrapped errors
+   - io/fs         → fs.ErrNotExist
+   - log/slog      → files that can't be removed are logged
+   - os            → the files themselves
+   - path/filepath → paths inside the photo directory
+   - slices        → order the files of a student by upload time
+   - strings       → read the ETag back from a file name
+   - sync          → one upload at a time touches the directory
+   - time          → mtimes
+   - storage       → the interface we decorate
+   - types         → Photo / Student
+*/
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// extensions maps the accepted photo types to the extension of their files,
+// which is how a file's type is known again when it is served.
+var extensions = map[string]string{
+	types.PhotoJPEG: ".jpg",
+	types.PhotoPNG:  ".png",
+}
+
+/*
+DiskStorage()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Wraps a storage.Storage so the photos are files in dir
+	    instead of rows of the database (photos.store: disk).
+	    Everything else goes straight to next.
+	  → Creates dir if needed, so a bad path stops the startup.
+
+	FILES:
+	  → One per student, "<id>-<etag>.jpg" (or .png): the name
+	    carries what the database row would, and the mtime is
+	    when it was uploaded.
+	  → An upload writes a temporary file and renames it, so a
+	    reader never sees half a photo, then removes the photo
+	    it replaces.
+	  → DeleteStudent / DeleteStudents remove the files of the
+	    students they deleted, once the delete is committed. A
+	    file that can't be removed is logged: the student is gone
+	    either way, and the file is never served again.
+
+	NOTES:
+	  → The student is checked with GetStudentById before an
+	    upload; a delete landing in between leaves an orphan
+	    file, never a photo on a deleted student.
+*/
+func DiskStorage(next storage.Storage, dir string) (storage.Storage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("photo directory (photos.dir): %w", err)
+	}
+
+	return &diskStorage{Storage: next, dir: dir}, nil
+}
+
+type diskStorage struct {
+	storage.Storage
+	dir string
+
+	// mu makes replacing a photo (rename + cleanup) one step, so two
+	// uploads for a student can't remove each other's file.
+	mu sync.Mutex
+}
+
+func (s *diskStorage) PutStudentPhoto(ctx context.Context, id int64, photo types.Photo) (bool, error) {
+	if _, err := s.Storage.GetStudentById(ctx, id); err != nil {
+		return false, err
+	}
+
+	ext, ok := extensions[photo.ContentType]
+	if !ok {
+		return false, fmt.Errorf("unsupported photo type %q", photo.ContentType)
+	}
+
+	// STEP 1: the bytes, under a name no reader looks for
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(photo.Data); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Chtimes(tmp.Name(), photo.UpdatedAt, photo.UpdatedAt); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// STEP 2: in place, then the photo it replaces goes
+	previous, err := s.files(id)
+	if err != nil {
+		return false, err
+	}
+
+	name := filepath.Join(s.dir, fmt.Sprintf("%d-%s%s", id, photo.ETag, ext))
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return false, err
+	}
+
+	for _, old := range previous {
+		if old != name {
+			s.remove(old)
+		}
+	}
+
+	return len(previous) == 0, nil
+}
+
+func (s *diskStorage) GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error) {
+	if _, err := s.Storage.GetStudentById(ctx, id); err != nil {
+		return types.Photo{}, err
+	}
+
+	s.mu.Lock()
+	files, err := s.files(id)
+	s.mu.Unlock()
+	if err != nil {
+		return types.Photo{}, err
+	}
+	if len(files) == 0 {
+		return types.Photo{}, storage.ErrPhotoNotFound
+	}
+
+	// the newest one, should a crash have left two
+	name := files[len(files)-1]
+	photo, err := readPhoto(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		// replaced or deleted since files listed it
+		return types.Photo{}, storage.ErrPhotoNotFound
+	}
+
+	return photo, err
+}
+
+func (s *diskStorage) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+	deleted, err := s.Storage.DeleteStudent(ctx, id)
+	if deleted && err == nil {
+		s.removeAll(id)
+	}
+	return deleted, err
+}
+
+func (s *diskStorage) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	deleted, err := s.Storage.DeleteStudents(ctx, filter)
+	if err == nil {
+		for _, student := range deleted {
+			s.removeAll(student.Id)
+		}
+	}
+	return deleted, err
+}
+
+// files returns the photo files of student id, oldest first. Callers must
+// hold mu.
+func (s *diskStorage) files(id int64) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dir, fmt.Sprintf("%d-*", id)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Glob sorts by name; the upload time is the mtime
+	slices.SortFunc(matches, func(a, b string) int {
+		return modTime(a).Compare(modTime(b))
+	})
+
+	return matches, nil
+}
+
+// modTime is the mtime of a file, zero when it is gone.
+func modTime(name string) time.Time {
+	info, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// removeAll removes every photo file of student id.
+func (s *diskStorage) removeAll(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.files(id)
+	if err != nil {
+		slog.Error("cannot list the photo files of a deleted student", slog.Int64("id", id), slog.String("error", err.Error()))
+		return
+	}
+	for _, name := range files {
+		s.remove(name)
+	}
+}
+
+// remove removes one photo file, logging a failure.
+func (s *diskStorage) remove(name string) {
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Error("cannot remove photo file", slog.String("path", name), slog.String("error", err.Error()))
+	}
+}
+
+// readPhoto reads a photo file back: the type from the extension, the ETag
+// from the name, UpdatedAt from the mtime.
+func readPhoto(name string) (types.Photo, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return types.Photo{}, err
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return types.Photo{}, err
+	}
+
+	base := filepath.Base(name)
+	ext := filepath.Ext(base)
+	_, etag, _ := strings.Cut(strings.TrimSuffix(base, ext), "-")
+
+	photo := types.Photo{Data: data, ETag: etag, UpdatedAt: info.ModTime().UTC()}
+	for contentType, e := range extensions {
+		if e == ext {
+			photo.ContentType = contentType
+		}
+	}
+	if photo.ContentType == "" {
+		return types.Photo{}, fmt.Errorf("photo file %s: unknown extension", name)
+	}
+
+	return photo, nil
+}
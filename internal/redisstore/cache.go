@@ -0,0 +1,123 @@
+package redisstore
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context       → every command is bound to the request
+   - encoding/json → students are stored as JSON
+   - errors        → redis.Nil is "no such key"
+   - strconv       → IDs in key names
+   - time          → entry and generation expiry
+   - types         → the cached Student values
+   - go-redis      → the Redis client
+*/
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// generationTTL is how long the invalidation counter of an ID outlives its
+// last invalidation. A read slower than that could cache a stale row, so it
+// is far above any request timeout.
+const generationTTL = time.Hour
+
+// addScript stores the student (ARGV[2]) for ARGV[3] ms, unless KEYS[2],
+// the invalidation counter of its ID, moved past ARGV[1] since Snapshot.
+// Check and write happen atomically inside Redis.
+var addScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+/*
+Cache STRUCT
+-------------------------------------------------------------
+  - cache.Store in Redis, shared by every replica:
+      <prefix>student:<id>     → the student as JSON, expires after ttl
+      <prefix>student-gen:<id> → how many times <id> was invalidated
+  - The counter is per ID (the in-process LRU uses one for all),
+    so a write only blocks caching of the student it changed.
+*/
+type Cache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewCache returns a Cache keeping students for ttl under keys starting with
+// prefix.
+func NewCache(client *redis.Client, prefix string, ttl time.Duration) *Cache {
+	return &Cache{client: client, prefix: prefix, ttl: ttl}
+}
+
+func (c *Cache) key(id int64) string {
+	return c.prefix + "student:" + strconv.FormatInt(id, 10)
+}
+
+func (c *Cache) generationKey(id int64) string {
+	return c.prefix + "student-gen:" + strconv.FormatInt(id, 10)
+}
+
+// Get implements cache.Store.
+func (c *Cache) Get(ctx context.Context, id int64) (types.Student, bool, error) {
+	data, err := c.client.Get(ctx, c.key(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return types.Student{}, false, nil
+	}
+	if err != nil {
+		return types.Student{}, false, err
+	}
+
+	var student types.Student
+	if err := json.Unmarshal(data, &student); err != nil {
+		return types.Student{}, false, err
+	}
+	// The row ID is not part of the JSON (only PublicId is), the key has it
+	student.Id = id
+
+	return student, true, nil
+}
+
+// Snapshot implements cache.Store.
+func (c *Cache) Snapshot(ctx context.Context, id int64) (uint64, error) {
+	generation, err := c.client.Get(ctx, c.generationKey(id)).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return generation, err
+}
+
+// Add implements cache.Store.
+func (c *Cache) Add(ctx context.Context, student types.Student, generation uint64) error {
+	data, err := json.Marshal(student)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{c.key(student.Id), c.generationKey(student.Id)}
+	return addScript.Run(ctx, c.client, keys, strconv.FormatUint(generation, 10), data, c.ttl.Milliseconds()).Err()
+}
+
+// Invalidate implements cache.Store: bump the counter, drop the entry, in
+// one MULTI.
+func (c *Cache) Invalidate(ctx context.Context, id int64) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, c.generationKey(id))
+		pipe.Expire(ctx, c.generationKey(id), generationTTL)
+		pipe.Del(ctx, c.key(id))
+		return nil
+	})
+
+	return err
+}
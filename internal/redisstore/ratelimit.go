@@ -0,0 +1,120 @@
+package redisstore
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context  → every command is bound to the request
+   - fmt      → malformed script replies
+   - sync     → limits may change on config reload
+   - time     → Retry-After, bucket expiry
+   - config   → the "rate_limit" config section
+   - go-redis → the Redis client
+*/
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+takeScript
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The token bucket of MemoryLimitStore.Take, run inside
+	    Redis so concurrent requests on different replicas can't
+	    both take the last token.
+	  → KEYS[1]: hash {tokens, last (ms)}; ARGV: rate per second,
+	    burst, key expiry in ms.
+	  → Time comes from the Redis server (TIME), so replicas with
+	    skewed clocks still agree.
+	  → Returns {1, 0} when allowed, {0, ms until next token}
+	    otherwise (Lua numbers are truncated to integers in
+	    replies, hence milliseconds).
+*/
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(bucket[1]) or burst
+local last = tonumber(bucket[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - last) / 1000 * rate)
+
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {allowed, wait}
+`)
+
+/*
+LimitStore STRUCT
+-------------------------------------------------------------
+  - middleware.LimitStore in Redis: one hash per client at
+    <prefix>ratelimit:<ip>, shared by every replica.
+  - Keys expire once the bucket would be full again (like the
+    idle sweep of MemoryLimitStore), so Redis doesn't keep
+    every IP ever seen.
+*/
+type LimitStore struct {
+	client *redis.Client
+	prefix string
+
+	// mu guards the limits, which a config reload may change
+	mu     sync.RWMutex
+	rate   float64
+	burst  int
+	expiry time.Duration
+}
+
+// NewLimitStore returns a LimitStore with the rate and burst of cfg.
+func NewLimitStore(client *redis.Client, prefix string, cfg config.RateLimit) *LimitStore {
+	l := &LimitStore{client: client, prefix: prefix}
+	l.SetLimits(cfg.RequestsPerSecond, cfg.Burst)
+
+	return l
+}
+
+// SetLimits implements middleware.LimitStore. Every replica must be reloaded
+// alike: the limits travel with each Take, not with the bucket.
+func (l *LimitStore) SetLimits(rate float64, burst int) {
+	refill := time.Duration(float64(burst) / rate * float64(time.Second))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rate, l.burst, l.expiry = rate, burst, max(refill, time.Second)
+}
+
+// Take implements middleware.LimitStore.
+func (l *LimitStore) Take(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.mu.RLock()
+	rate, burst, expiry := l.rate, l.burst, l.expiry
+	l.mu.RUnlock()
+
+	reply, err := takeScript.Run(ctx, l.client, []string{l.prefix + "ratelimit:" + key},
+		rate, burst, expiry.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(reply) != 2 {
+		return false, 0, fmt.Errorf("redisstore: unexpected rate limit reply %v", reply)
+	}
+
+	return reply[0] == 1, time.Duration(reply[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,36 @@
+package redisstore // redisstore package keeps the student cache and the rate limit buckets in Redis, shared by every replica
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - config   → the "redis" config section
+   - go-redis → the Redis client
+*/
+import (
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+NewClient()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The client shared by Cache and LimitStore.
+	  → It connects lazily: NewClient never fails, and an
+	    unreachable server only shows up as command errors,
+	    which both callers survive.
+	  → redis.timeout applies to dialing, reads and writes, so a
+	    stuck server delays a request by that much at most.
+*/
+func NewClient(cfg config.Redis) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	})
+}
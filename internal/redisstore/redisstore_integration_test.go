@@ -0,0 +1,179 @@
+//go:build integration
+
+package redisstore_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/cache"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/http/middleware"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/redisstore"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// These tests talk to a real server and only build with the integration tag:
+//
+//	REDIS_ADDR=localhost:6379 go test -tags integration ./internal/redisstore
+//
+// Without REDIS_ADDR they are skipped. Every test writes under a key prefix
+// of its own and deletes its keys at the end.
+const addrEnv = "REDIS_ADDR"
+
+var (
+	_ cache.Store           = (*redisstore.Cache)(nil)
+	_ middleware.LimitStore = (*redisstore.LimitStore)(nil)
+)
+
+var prefixes atomic.Int64
+
+// connect returns a client for $REDIS_ADDR and a key prefix for t; t is
+// skipped when REDIS_ADDR is unset.
+func connect(t *testing.T) (*redis.Client, string) {
+	t.Helper()
+
+	addr := os.Getenv(addrEnv)
+	if addr == "" {
+		t.Skipf("%s is not set", addrEnv)
+	}
+
+	client := redisstore.NewClient(config.Redis{Addr: addr, Timeout: time.Second})
+	t.Cleanup(func() { client.Close() })
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("connecting to %s=%s: %v", addrEnv, addr, err)
+	}
+
+	prefix := fmt.Sprintf("students-api-test:%d:%d:", os.Getpid(), prefixes.Add(1))
+	t.Cleanup(func() {
+		ctx := context.Background()
+		keys, _ := client.Keys(ctx, prefix+"*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+	})
+
+	return client, prefix
+}
+
+func TestCache(t *testing.T) {
+	client, prefix := connect(t)
+	ctx := context.Background()
+	c := redisstore.NewCache(client, prefix, time.Minute)
+	ann := types.Student{Id: 7, PublicId: "7", Name: "Ann Lee", Email: "ann@example.com", Age: 20, Version: 1}
+
+	if _, found, err := c.Get(ctx, 7); found || err != nil {
+		t.Fatalf("Get before Add: found %t, %v", found, err)
+	}
+
+	generation, err := c.Snapshot(ctx, 7)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := c.Add(ctx, ann, generation); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	got, found, err := c.Get(ctx, 7)
+	if !found || err != nil || got.Id != 7 || got.PublicId != "7" || got.Email != "ann@example.com" {
+		t.Fatalf("Get after Add = %+v, %t, %v", got, found, err)
+	}
+
+	// a write between Snapshot and Add keeps the read it raced out
+	stale, err := c.Snapshot(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Invalidate(ctx, 7); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if _, found, _ := c.Get(ctx, 7); found {
+		t.Error("Get after Invalidate found the student")
+	}
+	if err := c.Add(ctx, ann, stale); err != nil {
+		t.Fatalf("Add with a stale generation: %v", err)
+	}
+	if _, found, _ := c.Get(ctx, 7); found {
+		t.Error("Add with a stale generation cached the student")
+	}
+
+	// another ID is not blocked by that write
+	bob := types.Student{Id: 8, PublicId: "8", Name: "Bob Lee", Email: "bob@example.com", Age: 30, Version: 1}
+	if err := c.Add(ctx, bob, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := c.Get(ctx, 8); !found {
+		t.Error("student 8 was not cached")
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client, prefix := connect(t)
+	ctx := context.Background()
+	c := redisstore.NewCache(client, prefix, 100*time.Millisecond)
+
+	if err := c.Add(ctx, types.Student{Id: 1, PublicId: "1", Name: "Ann Lee"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, found, err := c.Get(ctx, 1); found || err != nil {
+		t.Errorf("Get after the ttl: found %t, %v", found, err)
+	}
+}
+
+func TestLimitStore(t *testing.T) {
+	client, prefix := connect(t)
+	ctx := context.Background()
+	l := redisstore.NewLimitStore(client, prefix, config.RateLimit{RequestsPerSecond: 1, Burst: 3})
+
+	for i := range 3 {
+		if allowed, _, err := l.Take(ctx, "10.0.0.1"); !allowed || err != nil {
+			t.Fatalf("take %d of the burst: %t, %v", i+1, allowed, err)
+		}
+	}
+	allowed, wait, err := l.Take(ctx, "10.0.0.1")
+	if allowed || err != nil || wait <= 0 || wait > time.Second {
+		t.Errorf("take past the burst: allowed %t, wait %s, %v; want a wait of up to 1s", allowed, wait, err)
+	}
+
+	// buckets are per client
+	if allowed, _, err := l.Take(ctx, "10.0.0.2"); !allowed || err != nil {
+		t.Errorf("another client: %t, %v", allowed, err)
+	}
+}
+
+// TestLimitStoreReplicas checks two stores on one server, like two
+// replicas, share the buckets: concurrent takes get exactly the burst.
+func TestLimitStoreReplicas(t *testing.T) {
+	client, prefix := connect(t)
+	ctx := context.Background()
+	limits := config.RateLimit{RequestsPerSecond: 0.001, Burst: 5}
+	replicas := []*redisstore.LimitStore{
+		redisstore.NewLimitStore(client, prefix, limits),
+		redisstore.NewLimitStore(client, prefix, limits),
+	}
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := range 40 {
+		wg.Go(func() {
+			ok, _, err := replicas[i%2].Take(ctx, "10.0.0.1")
+			if err != nil {
+				t.Errorf("take: %v", err)
+			}
+			if ok {
+				allowed.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != 5 {
+		t.Errorf("%d takes allowed, want the burst of 5", got)
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context       → the actor travels in the request context
+   - encoding/json → before / after snapshots and details are stored as JSON
+   - types         → AuditEvent / Student
+*/
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+AuditStore INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads the audit trail. Writing is not part of it: every
+	    backend records the AuditEvent of a create, update, patch,
+	    delete or restore itself, inside the transaction (or
+	    lock) of the change, so the two can't drift apart.
+
+	METHODS:
+	  - ListAuditEvents  → one page of the events of a student,
+	                       oldest first (ordered by event ID)
+	  - CountAuditEvents → how many events a student has
+
+	  → Summaries of bulk operations are filed under
+	    types.AuditStudentNone, so the same two methods list them.
+
+	NOTES:
+	  → Events outlive the student: they are still there after
+	    PurgeDeletedStudents removed the row.
+*/
+type AuditStore interface {
+	ListAuditEvents(ctx context.Context, studentID int64, limit, offset int) ([]types.AuditEvent, error)
+	CountAuditEvents(ctx context.Context, studentID int64) (int, error)
+}
+
+// actorKey is the context key of the actor; unexported so only WithActor
+// can set it.
+type actorKey struct{}
+
+// AnonymousActor is recorded for changes made without credentials (auth
+// disabled in dev).
+const AnonymousActor = "anonymous"
+
+// WithActor returns a copy of ctx recording who makes the changes done with
+// it; the auth middleware sets it for every authenticated request.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or AnonymousActor.
+func ActorFrom(ctx context.Context) string {
+	if actor, _ := ctx.Value(actorKey{}).(string); actor != "" {
+		return actor
+	}
+
+	return AnonymousActor
+}
+
+// AuditSnapshot encodes a before / after student for the SQL backends'
+// audit_events columns; nil stays nil, which the drivers store as NULL.
+func AuditSnapshot(student *types.Student) (*string, error) {
+	if student == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(student)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+// ParseAuditSnapshot decodes what AuditSnapshot stored.
+func ParseAuditSnapshot(snapshot *string) (*types.Student, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	var student types.Student
+	if err := json.Unmarshal([]byte(*snapshot), &student); err != nil {
+		return nil, err
+	}
+
+	return &student, nil
+}
+
+// AuditDetailsJSON encodes the details of a summary event for the SQL
+// backends' details column; nil stays nil (NULL).
+func AuditDetailsJSON(details *types.AuditDetails) (*string, error) {
+	if details == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// ParseAuditDetails decodes what AuditDetailsJSON stored.
+func ParseAuditDetails(encoded *string) (*types.AuditDetails, error) {
+	if encoded == nil {
+		return nil, nil
+	}
+
+	var details types.AuditDetails
+	if err := json.Unmarshal([]byte(*encoded), &details); err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → every storage call receives the request context
+   - errors  → the course sentinel errors
+   - types   → Course / Enrollment
+*/
+import (
+	"context"
+	"errors"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+COURSE SENTINEL ERRORS
+-------------------------------------------------------------
+  - ErrCourseNotFound: no course has the ID.
+  - ErrDuplicateCourseCode: another course has the code
+    (unique whatever the case), on create and on update.
+  - ErrAlreadyEnrolled: the student is in the course already.
+  - ErrCourseFull: the course has as many students as its
+    capacity.
+  - ErrCapacityBelowEnrolled: an update would give a course
+    less capacity than it has students.
+*/
+var (
+	ErrCourseNotFound        = errors.New("course not found")
+	ErrDuplicateCourseCode   = errors.New("course with this code already exists")
+	ErrAlreadyEnrolled       = errors.New("student is already enrolled in this course")
+	ErrCourseFull            = errors.New("course is full")
+	ErrCapacityBelowEnrolled = errors.New("capacity is below the number of enrolled students")
+)
+
+/*
+CourseStore INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Courses and which students are enrolled in them.
+
+	METHODS:
+	  - CreateCourse   → inserts a course, returns the generated ID
+	  - GetCourseById  → returns one course (with Enrolled) or
+	                     ErrCourseNotFound
+	  - ListCourses    → one page of the courses, ordered by ID
+	  - CountCourses   → how many courses there are
+	  - UpdateCourse   → replaces code, title and capacity, reports
+	                     whether the ID existed
+	  - DeleteCourse   → removes a course and its enrollments,
+	                     reports whether the ID existed
+	  - Enroll         → enrolls a live student in a course
+	  - Unenroll       → removes an enrollment, reports whether it
+	                     existed
+	  - ListEnrollments → the courses a student is enrolled in,
+	                     ordered by course ID
+	  - ListCourseStudents → one page of the live students enrolled
+	                     in a course (with when), in the given
+	                     order; one JOIN, not a lookup per student.
+	                     The total is the course's Enrolled.
+
+	CAPACITY AND UNIQUENESS:
+	  → Enforced by storage: Enroll fails with ErrAlreadyEnrolled
+	    or ErrCourseFull, UpdateCourse with
+	    ErrCapacityBelowEnrolled, in the same transaction (or
+	    lock) as the write, so two concurrent enrollments can't
+	    both take the last seat.
+	  → Enroll returns ErrNotFound for a missing (or soft-deleted)
+	    student and ErrCourseNotFound for a missing course.
+*/
+type CourseStore interface {
+	CreateCourse(ctx context.Context, course types.Course) (int64, error)
+	GetCourseById(ctx context.Context, id int64) (types.Course, error)
+	ListCourses(ctx context.Context, limit, offset int) ([]types.Course, error)
+	CountCourses(ctx context.Context) (int, error)
+	UpdateCourse(ctx context.Context, id int64, course types.Course) (bool, error)
+	DeleteCourse(ctx context.Context, id int64) (bool, error)
+	Enroll(ctx context.Context, studentID, courseID int64) error
+	Unenroll(ctx context.Context, studentID, courseID int64) (bool, error)
+	ListEnrollments(ctx context.Context, studentID int64) ([]types.Enrollment, error)
+	ListCourseStudents(ctx context.Context, courseID int64, sort types.RosterSort, limit, offset int) ([]types.CourseStudent, error)
+}
@@ -0,0 +1,126 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context     → every storage call receives the request context
+   - crypto/rand → the random bits of a UUID
+   - encoding/hex → UUID text form
+   - errors      → the ID sentinel error
+   - strconv     → row IDs in a path
+   - strings     → UUIDs compare lower-cased
+   - time        → the timestamp of a version 7 UUID
+   - types       → types.ID
+*/
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// ErrInvalidID is returned by ResolveStudentID for an ID that is neither a
+// row ID nor a UUID.
+var ErrInvalidID = errors.New("invalid student id")
+
+/*
+IDStore INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Turns the ID a client sent (GET /api/students/{id}) into
+	    the row ID every other method takes.
+
+	RULES (storage.id_scheme):
+	  → serial: row IDs ("7") are used as they are, without a
+	    query; they are the public IDs.
+	  → uuid: new students get a version 7 UUID (NewUUID) as
+	    their public ID, and the ones created before get one
+	    when the backend opens. Row IDs are not accepted, so
+	    they can't be enumerated: ErrNotFound, like a UUID no
+	    student has.
+	  → A UUID is looked up in both modes, whether or not the
+	    student is deleted, so a deployment that goes back to
+	    serial keeps the links it gave out.
+	  → ErrInvalidID for anything else ("abc", "-1").
+*/
+type IDStore interface {
+	ResolveStudentID(ctx context.Context, id string) (int64, error)
+}
+
+// ParseStudentID does the part of ResolveStudentID that needs no query:
+// the row ID when id is one (0 for a UUID, lower-cased into uuid), and the
+// errors of the rules above. uuidIDs is storage.id_scheme uuid.
+func ParseStudentID(id string, uuidIDs bool) (rowID int64, uuid string, err error) {
+	if IsUUID(id) {
+		return 0, strings.ToLower(id), nil
+	}
+
+	rowID, err = strconv.ParseInt(id, 10, 64)
+	if err != nil || rowID < 1 {
+		return 0, "", ErrInvalidID
+	}
+	if uuidIDs {
+		return 0, "", ErrNotFound
+	}
+
+	return rowID, "", nil
+}
+
+// PublicID is the ID clients see of the student with row ID id: its UUID
+// when it has one ("" otherwise, e.g. a NULL column).
+func PublicID(id int64, uuid string) types.ID {
+	if uuid != "" {
+		return types.ID(uuid)
+	}
+
+	return types.SerialID(id)
+}
+
+// NewUUID returns a version 7 UUID (RFC 9562) for a student created at t:
+// 48 bits of Unix milliseconds, then random bits, so UUIDs sort roughly by
+// creation like row IDs do.
+func NewUUID(t time.Time) string {
+	var b [16]byte
+	// crypto/rand.Read never fails (it crashes the program instead)
+	rand.Read(b[6:])
+
+	ms := uint64(t.UnixMilli())
+	for i := range 6 {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	text := hex.EncodeToString(b[:])
+	return text[0:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+}
+
+// IsUUID reports whether s is a UUID in its text form
+// (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, any case), whatever its version.
+func IsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
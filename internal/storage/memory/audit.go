@@ -0,0 +1,90 @@
+package memory
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.AuditStore signatures; the actor
+   - time    → created_at of an event
+   - storage → actor of the request
+   - types   → AuditEvent / Student
+*/
+import (
+	"context"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// recordAudit appends the event of a change of the student with this ID:
+// before as given (nil for a create), after as it is now. Called with mu
+// held for writing, by the change itself, so both happen under one lock.
+func (m *Memory) recordAudit(ctx context.Context, id int64, action string, before *types.Student) {
+	after := m.students[id]
+
+	m.nextAuditID++
+	m.audit = append(m.audit, types.AuditEvent{
+		Id:        m.nextAuditID,
+		StudentId: id,
+		Action:    action,
+		Actor:     storage.ActorFrom(ctx),
+		CreatedAt: time.Now().UTC(),
+		Before:    before,
+		After:     &after,
+	})
+}
+
+// recordSummary appends the event of an operation on many students, filed
+// under types.AuditStudentNone. Called with mu held for writing.
+func (m *Memory) recordSummary(ctx context.Context, action string, details types.AuditDetails) {
+	m.nextAuditID++
+	m.audit = append(m.audit, types.AuditEvent{
+		Id:        m.nextAuditID,
+		StudentId: types.AuditStudentNone,
+		Action:    action,
+		Actor:     storage.ActorFrom(ctx),
+		CreatedAt: time.Now().UTC(),
+		Details:   &details,
+	})
+}
+
+// ListAuditEvents returns one page of the events of the student with this
+// ID, oldest first.
+func (m *Memory) ListAuditEvents(ctx context.Context, studentID int64, limit, offset int) ([]types.AuditEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var events []types.AuditEvent
+	for _, event := range m.audit {
+		if event.StudentId != studentID {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(events) == limit {
+			break
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// CountAuditEvents returns how many events the student with this ID has.
+func (m *Memory) CountAuditEvents(ctx context.Context, studentID int64) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, event := range m.audit {
+		if event.StudentId == studentID {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,324 @@
+package memory
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.Storage signatures
+   - fmt     → name the enrolled student in the error
+   - sort    → lists are ordered by ID like the SQL backends
+   - strings → case-insensitive roster order by name
+   - time    → created_at / updated_at
+   - storage → sentinel errors shared by all backends
+   - types   → Course / Enrollment
+*/
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// enrollmentKey identifies an enrollment, like the primary key of the SQL
+// enrollments table.
+type enrollmentKey struct {
+	studentID int64
+	courseID  int64
+}
+
+// enrolled counts the students of a course. Callers must hold mu.
+func (m *Memory) enrolled(courseID int64) int {
+	count := 0
+	for key := range m.enrollments {
+		if key.courseID == courseID {
+			count++
+		}
+	}
+
+	return count
+}
+
+// codeTaken reports whether another course (not exceptID) has code, which is
+// stored normalized. Callers must hold mu.
+func (m *Memory) codeTaken(code string, exceptID int64) bool {
+	for id, course := range m.courses {
+		if id != exceptID && course.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateCourse stores one course and returns its generated ID;
+// storage.ErrDuplicateCourseCode when the code is taken.
+func (m *Memory) CreateCourse(ctx context.Context, course types.Course) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.codeTaken(course.Code, 0) {
+		return 0, storage.ErrDuplicateCourseCode
+	}
+
+	now := time.Now().UTC()
+	m.nextCourseID++
+	course.Id = m.nextCourseID
+	course.Enrolled = 0
+	course.CreatedAt = now
+	course.UpdatedAt = now
+	m.courses[course.Id] = course
+
+	return course.Id, nil
+}
+
+// GetCourseById returns the course with this ID, or storage.ErrCourseNotFound.
+func (m *Memory) GetCourseById(ctx context.Context, id int64) (types.Course, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	course, ok := m.courses[id]
+	if !ok {
+		return types.Course{}, storage.ErrCourseNotFound
+	}
+	course.Enrolled = m.enrolled(id)
+
+	return course, nil
+}
+
+// ListCourses returns one page of the courses, ordered by ID.
+func (m *Memory) ListCourses(ctx context.Context, limit, offset int) ([]types.Course, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	courses := make([]types.Course, 0, len(m.courses))
+	for _, course := range m.courses {
+		course.Enrolled = m.enrolled(course.Id)
+		courses = append(courses, course)
+	}
+	sort.Slice(courses, func(i, j int) bool {
+		return courses[i].Id < courses[j].Id
+	})
+
+	if offset >= len(courses) {
+		return []types.Course{}, nil
+	}
+
+	return courses[offset:min(offset+limit, len(courses))], nil
+}
+
+// CountCourses returns how many courses there are.
+func (m *Memory) CountCourses(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.courses), nil
+}
+
+// UpdateCourse replaces the code, title and capacity of a course, with the
+// same checks as the SQL backends. Reports false when no course has this ID.
+func (m *Memory) UpdateCourse(ctx context.Context, id int64, course types.Course) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.courses[id]
+	if !ok {
+		return false, nil
+	}
+
+	if enrolled := m.enrolled(id); course.Capacity < enrolled {
+		return false, fmt.Errorf("%w: %d enrolled", storage.ErrCapacityBelowEnrolled, enrolled)
+	}
+	if m.codeTaken(course.Code, id) {
+		return false, storage.ErrDuplicateCourseCode
+	}
+
+	current.Code = course.Code
+	current.Title = course.Title
+	current.Capacity = course.Capacity
+	current.UpdatedAt = time.Now().UTC()
+	m.courses[id] = current
+
+	return true, nil
+}
+
+// DeleteCourse removes a course and its enrollments. Reports false when no
+// course has this ID.
+func (m *Memory) DeleteCourse(ctx context.Context, id int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.courses[id]; !ok {
+		return false, nil
+	}
+
+	for key := range m.enrollments {
+		if key.courseID == id {
+			delete(m.enrollments, key)
+		}
+	}
+	delete(m.courses, id)
+
+	return true, nil
+}
+
+// Enroll enrolls the live student studentID in courseID; the lock makes the
+// checks and the write one step. Same errors as the SQL backends.
+func (m *Memory) Enroll(ctx context.Context, studentID, courseID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if student, ok := m.students[studentID]; !ok || student.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+
+	course, ok := m.courses[courseID]
+	if !ok {
+		return storage.ErrCourseNotFound
+	}
+
+	key := enrollmentKey{studentID: studentID, courseID: courseID}
+	if _, ok := m.enrollments[key]; ok {
+		return storage.ErrAlreadyEnrolled
+	}
+	if m.enrolled(courseID) >= course.Capacity {
+		return storage.ErrCourseFull
+	}
+
+	m.enrollments[key] = time.Now().UTC()
+
+	return nil
+}
+
+// Unenroll removes the enrollment of studentID in courseID and reports
+// whether there was one.
+func (m *Memory) Unenroll(ctx context.Context, studentID, courseID int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := enrollmentKey{studentID: studentID, courseID: courseID}
+	if _, ok := m.enrollments[key]; !ok {
+		return false, nil
+	}
+	delete(m.enrollments, key)
+
+	return true, nil
+}
+
+// ListEnrollments returns the courses studentID is enrolled in, ordered by
+// course ID.
+func (m *Memory) ListEnrollments(ctx context.Context, studentID int64) ([]types.Enrollment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	enrollments := make([]types.Enrollment, 0)
+	for key, enrolledAt := range m.enrollments {
+		if key.studentID != studentID {
+			continue
+		}
+
+		course := m.courses[key.courseID]
+		enrollments = append(enrollments, types.Enrollment{
+			StudentId:  studentID,
+			CourseId:   course.Id,
+			Code:       course.Code,
+			Title:      course.Title,
+			EnrolledAt: enrolledAt,
+		})
+	}
+	sort.Slice(enrollments, func(i, j int) bool {
+		return enrollments[i].CourseId < enrollments[j].CourseId
+	})
+
+	return enrollments, nil
+}
+
+// ListCourseStudents returns one page of the roster of a course, in the
+// order of the SQL backends (ties broken by student ID).
+func (m *Memory) ListCourseStudents(ctx context.Context, courseID int64, order types.RosterSort, limit, offset int) ([]types.CourseStudent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	roster := make([]types.CourseStudent, 0)
+	for key, enrolledAt := range m.enrollments {
+		student, ok := m.students[key.studentID]
+		if key.courseID != courseID || !ok || student.DeletedAt != nil {
+			continue
+		}
+		student.DeriveAge(time.Now())
+		roster = append(roster, types.CourseStudent{Student: student, EnrolledAt: enrolledAt})
+	}
+
+	sort.Slice(roster, func(i, j int) bool {
+		a, b := roster[i], roster[j]
+		if order.Desc {
+			a, b = b, a
+		}
+
+		var cmp int
+		switch order.Field {
+		case types.RosterSortID:
+			if a.Id != b.Id {
+				return a.Id < b.Id
+			}
+		case types.RosterSortName:
+			cmp = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case types.RosterSortEmail:
+			cmp = strings.Compare(a.Email, b.Email)
+		case types.RosterSortEnrolledAt:
+			cmp = a.EnrolledAt.Compare(b.EnrolledAt)
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+
+		return roster[i].Id < roster[j].Id
+	})
+
+	if offset >= len(roster) {
+		return []types.CourseStudent{}, nil
+	}
+
+	return roster[offset:min(offset+limit, len(roster))], nil
+}
+
+// releaseEnrollments applies storage.on_student_delete to a student about
+// to be deleted: block fails with storage.ErrStudentEnrolled if
+// it has enrollments, cascade removes them. Callers must hold mu for
+// writing.
+func (m *Memory) releaseEnrollments(studentID int64) error {
+	for key := range m.enrollments {
+		if key.studentID != studentID {
+			continue
+		}
+		if !m.cascadeEnrollments {
+			return storage.ErrStudentEnrolled
+		}
+		delete(m.enrollments, key)
+	}
+
+	return nil
+}
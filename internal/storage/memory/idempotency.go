@@ -0,0 +1,98 @@
+package memory
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.IdempotencyStore signatures
+   - time    → expiry of a key
+   - storage → IdempotencyRecord
+*/
+import (
+	"context"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+)
+
+// idempotencyEntry is one stored key: the record plus when it expires.
+type idempotencyEntry struct {
+	record    storage.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// ReserveIdempotencyKey claims key unless a live entry already holds it, in
+// which case that entry's record is returned. Expired entries are replaced,
+// like the SQL backends do.
+func (m *Memory) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, expiresAt time.Time) (storage.IdempotencyRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return storage.IdempotencyRecord{}, false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.idempotencyKeys[key]; ok && entry.expiresAt.After(time.Now()) {
+		return entry.record, false, nil
+	}
+
+	m.idempotencyKeys[key] = idempotencyEntry{
+		record:    storage.IdempotencyRecord{RequestHash: requestHash},
+		expiresAt: expiresAt,
+	}
+
+	return storage.IdempotencyRecord{}, true, nil
+}
+
+// CompleteIdempotencyKey stores the recorded response of a reserved key.
+func (m *Memory) CompleteIdempotencyKey(ctx context.Context, key string, response []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.idempotencyKeys[key]; ok {
+		entry.record.Response = response
+		m.idempotencyKeys[key] = entry
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey drops a reservation that never got a response.
+func (m *Memory) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.idempotencyKeys[key]; ok && entry.record.Response == nil {
+		delete(m.idempotencyKeys, key)
+	}
+
+	return nil
+}
+
+// PurgeExpiredIdempotencyKeys removes every entry expired at now.
+func (m *Memory) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for key, entry := range m.idempotencyKeys {
+		if !entry.expiresAt.After(now) {
+			delete(m.idempotencyKeys, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.Storage signatures
+   - storage → ID parsing, sentinel errors
+   - types   → types.ID
+*/
+import (
+	"context"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// ResolveStudentID implements storage.IDStore with the rules of the SQL
+// backends. Nothing outlives a restart here, so there are no students
+// from an earlier scheme: a UUID is looked up with a scan, like emails.
+func (m *Memory) ResolveStudentID(ctx context.Context, id string) (int64, error) {
+	rowID, uuid, err := storage.ParseStudentID(id, m.uuidIDs)
+	if err != nil || uuid == "" {
+		return rowID, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for id, student := range m.students {
+		if student.PublicId == types.ID(uuid) {
+			return id, nil
+		}
+	}
+
+	return 0, storage.ErrNotFound
+}
@@ -0,0 +1,777 @@
+package memory // memory package implements storage.Storage with a plain Go map (nothing is persisted)
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.Storage signatures; every call
+               checks it, like a SQL query would, so a cancelled
+               request reads nothing and changes nothing
+   - errors  → the "no fields" error of PatchStudent
+   - fmt     → wrap status transition errors
+   - slices  → tag filters
+   - sort    → lists are ordered by ID like the SQL backends
+   - strings → case-insensitive name / email matching
+   - sync    → one RWMutex guards the map (handlers run concurrently)
+   - time    → created_at / updated_at
+   - config  → storage.on_student_delete
+   - storage → sentinel errors shared by all backends
+   - types   → Student struct returned to the handlers
+*/
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+Memory STRUCT
+-------------------------------------------------------------
+  - students is keyed by ID; nextID is the last ID handed out,
+    so IDs keep increasing even after deletes (like SQLite).
+  - idempotencyKeys holds the Idempotency-Key records.
+  - audit is the audit trail, in event ID order (nextAuditID
+    is the last one handed out); it is never trimmed.
+  - courses is keyed by ID (nextCourseID like nextID), and
+    enrollments holds when each student / course pair was
+    enrolled; cascadeEnrollments is storage.on_student_delete
+    "cascade" (see releaseEnrollments).
+  - photos holds the profile photo of the students that have
+    one.
+  - uuidIDs is storage.id_scheme "uuid": new students get a UUID
+    as their PublicId (see ids.go).
+  - mu protects all of them: reads take RLock, writes take Lock.
+  - Used for storage.driver: memory (demo mode) — every restart
+    starts from an empty list.
+*/
+type Memory struct {
+	mu       sync.RWMutex
+	nextID   int64
+	students map[int64]types.Student
+
+	idempotencyKeys map[string]idempotencyEntry
+
+	nextAuditID int64
+	audit       []types.AuditEvent
+
+	nextCourseID       int64
+	courses            map[int64]types.Course
+	enrollments        map[enrollmentKey]time.Time
+	cascadeEnrollments bool
+
+	photos map[int64]types.Photo
+
+	uuidIDs bool
+}
+
+// New returns an empty in-memory store.
+func New(cfg *config.Config) *Memory {
+	return &Memory{
+		students:           make(map[int64]types.Student),
+		idempotencyKeys:    make(map[string]idempotencyEntry),
+		courses:            make(map[int64]types.Course),
+		enrollments:        make(map[enrollmentKey]time.Time),
+		cascadeEnrollments: cfg.Storage.OnStudentDelete == config.OnDeleteCascade,
+		photos:             make(map[int64]types.Photo),
+		uuidIDs:            cfg.Storage.IDScheme == config.IDSchemeUUID,
+	}
+}
+
+// emailTaken reports whether another live student (not exceptID) already
+// uses email. Same rule as the SQL unique index: a case-insensitive match,
+// and soft-deleted students don't count.
+// Callers must hold mu.
+func (m *Memory) emailTaken(email string, exceptID int64) bool {
+	for id, student := range m.students {
+		if id != exceptID && student.DeletedAt == nil && strings.EqualFold(student.Email, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insert stores student under a new ID. Callers must hold mu for writing.
+func (m *Memory) insert(student types.Student, now time.Time) (int64, error) {
+	if m.emailTaken(student.Email, 0) {
+		return 0, storage.ErrDuplicateEmail
+	}
+
+	// the SQL backends read missing tags back as []
+	if student.Tags == nil {
+		student.Tags = []string{}
+	}
+
+	m.nextID++
+	student.Id = m.nextID
+	student.PublicId = types.SerialID(student.Id)
+	if m.uuidIDs {
+		student.PublicId = types.ID(storage.NewUUID(now))
+	}
+	student.Version = 1
+	student.CreatedAt = now
+	student.UpdatedAt = now
+	m.students[student.Id] = student
+
+	return student.Id, nil
+}
+
+// CreateStudent stores one student and returns its generated ID.
+func (m *Memory) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, err := m.insert(student, time.Now().UTC())
+	if err == nil {
+		m.recordAudit(ctx, id, types.AuditCreate, nil)
+	}
+
+	return id, err
+}
+
+// UpsertStudent updates the fields (not the status) of the live student
+// with this email, or creates it; the lock makes the lookup and the write one step.
+func (m *Memory) UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	for id, current := range m.students {
+		if current.DeletedAt != nil || !strings.EqualFold(current.Email, student.Email) {
+			continue
+		}
+
+		before := current
+		current.Name = student.Name
+		current.Age = student.Age
+		current.DateOfBirth = student.DateOfBirth
+		current.Phone = student.Phone
+		current.Tags = student.Tags
+		current.Version++
+		current.UpdatedAt = now
+		m.students[id] = current
+		m.recordAudit(ctx, id, types.AuditUpdate, &before)
+
+		return id, false, nil
+	}
+
+	id, err := m.insert(student, now)
+	if err != nil {
+		return 0, false, err
+	}
+	m.recordAudit(ctx, id, types.AuditCreate, nil)
+
+	return id, true, nil
+}
+
+/*
+CreateStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Same contract as the SQL backends. The whole batch runs
+	    under one lock, which plays the role of the transaction.
+	  → atomic=true: on the first failure the rows created so far
+	    are removed again and every other item is reported as
+	    storage.ErrBatchAborted.
+	  → The "create" audit events are recorded once the batch is
+	    through, so an aborted one leaves none behind.
+*/
+func (m *Memory) CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]storage.BulkResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	results := make([]storage.BulkResult, len(students))
+
+	for i, student := range students {
+		id, err := m.insert(student, now)
+		if err == nil {
+			results[i].Id = id
+			results[i].PublicId = m.students[id].PublicId
+			continue
+		}
+
+		results[i].Err = err
+
+		if atomic {
+			for j := range results {
+				if j < i {
+					delete(m.students, results[j].Id)
+				}
+				if j != i {
+					results[j] = storage.BulkResult{Err: storage.ErrBatchAborted}
+				}
+			}
+			return results, nil
+		}
+	}
+
+	for _, result := range results {
+		if result.Err == nil {
+			m.recordAudit(ctx, result.Id, types.AuditCreate, nil)
+		}
+	}
+
+	return results, nil
+}
+
+// GetStudentById returns the student with this ID, or storage.ErrNotFound
+// when there is none or it is soft-deleted.
+func (m *Memory) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return types.Student{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	student, ok := m.students[id]
+	if !ok || student.DeletedAt != nil {
+		return types.Student{}, storage.ErrNotFound
+	}
+	student.DeriveAge(time.Now())
+
+	return student, nil
+}
+
+/*
+matching()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns a copy of every student accepted by filter,
+	    ordered by ID. The SQL backends do this with WHERE and
+	    ORDER BY; here it is a scan of the map.
+	  → The copy is what lets callers use the result after the
+	    lock is released. Its ages are those of today (see
+	    types.Student.DeriveAge), which the filter also uses.
+*/
+func (m *Memory) matching(filter types.StudentFilter) []types.Student {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	students := make([]types.Student, 0)
+	for _, student := range m.students {
+		student.DeriveAge(now)
+		if matches(filter, student) {
+			students = append(students, student)
+		}
+	}
+
+	sort.Slice(students, func(i, j int) bool {
+		return students[i].Id < students[j].Id
+	})
+
+	return students
+}
+
+// matches applies the StudentFilter rules (see types.StudentFilter).
+func matches(filter types.StudentFilter, student types.Student) bool {
+	if !filter.IncludeDeleted && student.DeletedAt != nil {
+		return false
+	}
+	if filter.Name != "" && !containsFold(student.Name, filter.Name) {
+		return false
+	}
+	if filter.Email != "" && !strings.EqualFold(student.Email, filter.Email) {
+		return false
+	}
+	if filter.EmailDomain != "" && !strings.HasSuffix(strings.ToLower(student.Email), "@"+strings.ToLower(filter.EmailDomain)) {
+		return false
+	}
+	if filter.Status != "" && student.Status != filter.Status {
+		return false
+	}
+	if filter.MinAge != nil && student.Age < *filter.MinAge {
+		return false
+	}
+	if filter.MaxAge != nil && student.Age > *filter.MaxAge {
+		return false
+	}
+	for _, term := range filter.Terms {
+		if !containsFold(student.Name, term) && !containsFold(student.Email, term) {
+			return false
+		}
+	}
+	for _, tag := range filter.Tags {
+		if !slices.Contains(student.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsFold is a case-insensitive strings.Contains.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ListStudents returns one page of the students matching filter, ordered by ID.
+func (m *Memory) ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	students := m.matching(filter)
+
+	return page(students, offset, limit), nil
+}
+
+// ListStudentsAfter is cursor pagination: the matching students with an ID
+// greater than afterID, ordered by ID.
+func (m *Memory) ListStudentsAfter(ctx context.Context, filter types.StudentFilter, afterID int64, limit int) ([]types.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	students := m.matching(filter)
+
+	// students is sorted, so the first ID > afterID starts the page
+	start := sort.Search(len(students), func(i int) bool {
+		return students[i].Id > afterID
+	})
+
+	return page(students, start, limit), nil
+}
+
+// page returns at most limit students starting at offset (never nil).
+func page(students []types.Student, offset, limit int) []types.Student {
+	if offset >= len(students) {
+		return []types.Student{}
+	}
+
+	students = students[offset:]
+	if limit < len(students) {
+		students = students[:limit]
+	}
+
+	return students
+}
+
+// ForEachStudent calls fn for every student matching filter, ordered by ID.
+// fn runs without the lock held, so a slow export doesn't block writers.
+func (m *Memory) ForEachStudent(ctx context.Context, filter types.StudentFilter, fn func(types.Student) error) error {
+	for _, student := range m.matching(filter) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(student); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CountStudents counts every student matching filter, ignoring paging.
+func (m *Memory) CountStudents(ctx context.Context, filter types.StudentFilter) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(m.matching(filter)), nil
+}
+
+// FindStudentsByEmailOrName returns the live students whose email is one of
+// emails or whose name is one of names, both compared case-insensitively,
+// ordered by ID.
+func (m *Memory) FindStudentsByEmailOrName(ctx context.Context, emails, names []string) ([]types.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(emails)+len(names))
+	for _, email := range emails {
+		wanted["email:"+strings.ToLower(email)] = true
+	}
+	for _, name := range names {
+		wanted["name:"+strings.ToLower(name)] = true
+	}
+
+	students := make([]types.Student, 0)
+	for _, student := range m.matching(types.StudentFilter{}) {
+		if wanted["email:"+strings.ToLower(student.Email)] || wanted["name:"+strings.ToLower(student.Name)] {
+			students = append(students, student)
+		}
+	}
+
+	return students, nil
+}
+
+// StudentStats computes the aggregates in one pass over the live students;
+// buckets and days come out sorted like the SQL backends' ORDER BY.
+func (m *Memory) StudentStats(ctx context.Context, since time.Time) (types.StudentStats, error) {
+	if err := ctx.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	var stats types.StudentStats
+	statuses := map[string]int{}
+	tags := map[string]int{}
+	buckets := map[int]int{}
+	days := map[string]int{}
+	sum := 0
+
+	for _, student := range m.matching(types.StudentFilter{}) {
+		if stats.Total == 0 || student.Age < stats.Age.Min {
+			stats.Age.Min = student.Age
+		}
+		if student.Age > stats.Age.Max {
+			stats.Age.Max = student.Age
+		}
+		stats.Total++
+		sum += student.Age
+		statuses[student.Status]++
+		for _, tag := range student.Tags {
+			tags[tag]++
+		}
+
+		buckets[(student.Age-1)/types.AgeBucketWidth*types.AgeBucketWidth+1]++
+		if !student.CreatedAt.Before(since) {
+			days[student.CreatedAt.UTC().Format(time.DateOnly)]++
+		}
+	}
+
+	if stats.Total > 0 {
+		stats.Age.Average = float64(sum) / float64(stats.Total)
+	}
+
+	for status, count := range statuses {
+		stats.ByStatus = append(stats.ByStatus, types.StatusCount{Status: status, Count: count})
+	}
+	sort.Slice(stats.ByStatus, func(i, j int) bool {
+		return stats.ByStatus[i].Status < stats.ByStatus[j].Status
+	})
+
+	for tag, count := range tags {
+		stats.ByTag = append(stats.ByTag, types.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(stats.ByTag, func(i, j int) bool {
+		a, b := stats.ByTag[i], stats.ByTag[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Tag < b.Tag
+	})
+
+	for from, count := range buckets {
+		stats.AgeBuckets = append(stats.AgeBuckets, types.AgeBucket{From: from, To: from + types.AgeBucketWidth - 1, Count: count})
+	}
+	sort.Slice(stats.AgeBuckets, func(i, j int) bool {
+		return stats.AgeBuckets[i].From < stats.AgeBuckets[j].From
+	})
+
+	for date, count := range days {
+		stats.CreatedPerDay = append(stats.CreatedPerDay, types.DayCount{Date: date, Count: count})
+	}
+	sort.Slice(stats.CreatedPerDay, func(i, j int) bool {
+		return stats.CreatedPerDay[i].Date < stats.CreatedPerDay[j].Date
+	})
+
+	return stats, nil
+}
+
+// UpdateStudent replaces name, email, age, date of birth, phone and tags,
+// refreshes updated_at and increments version. version > 0 makes it conditional.
+// Reports false when no live student has this ID, storage.ErrVersionConflict
+// when it is no longer at version. Every change here and below records its
+// audit event.
+func (m *Memory) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.students[id]
+	if !ok || current.DeletedAt != nil {
+		return false, nil
+	}
+
+	if version > 0 && current.Version != version {
+		return false, storage.ErrVersionConflict
+	}
+
+	if m.emailTaken(student.Email, id) {
+		return false, storage.ErrDuplicateEmail
+	}
+
+	before := current
+	current.Name = student.Name
+	current.Email = student.Email
+	current.Age = student.Age
+	current.DateOfBirth = student.DateOfBirth
+	current.Phone = student.Phone
+	current.Tags = student.Tags
+	current.Version++
+	current.UpdatedAt = time.Now().UTC()
+	m.students[id] = current
+	m.recordAudit(ctx, id, types.AuditUpdate, &before)
+
+	return true, nil
+}
+
+// PatchStudent updates only the fields whose patch value is non-nil, plus
+// updated_at and version, with the same rules as UpdateStudent.
+func (m *Memory) PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error) {
+	// Nothing to change; the handler rejects {} before we get here
+	if patch.IsEmpty() {
+		return false, errors.New("no fields to update")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.students[id]
+	if !ok || current.DeletedAt != nil {
+		return false, nil
+	}
+
+	if version > 0 && current.Version != version {
+		return false, storage.ErrVersionConflict
+	}
+
+	if patch.Email != nil && m.emailTaken(*patch.Email, id) {
+		return false, storage.ErrDuplicateEmail
+	}
+
+	before := current
+	if patch.Name != nil {
+		current.Name = *patch.Name
+	}
+	if patch.Email != nil {
+		current.Email = *patch.Email
+	}
+	// an age alone clears the date of birth, like in the SQL backends
+	if patch.Age != nil {
+		current.Age = *patch.Age
+		current.DateOfBirth = patch.DateOfBirth
+	}
+	if patch.Phone != nil {
+		current.Phone = patch.Phone
+	}
+	if patch.Tags != nil {
+		current.Tags = *patch.Tags
+	}
+	current.Version++
+	current.UpdatedAt = time.Now().UTC()
+	m.students[id] = current
+	m.recordAudit(ctx, id, types.AuditUpdate, &before)
+
+	return true, nil
+}
+
+// SetStudentStatus moves the student with this ID to status if
+// types.CanChangeStatus allows it, incrementing version. Reports false when
+// no live student has this ID; storage.ErrInvalidTransition (wrapped) for a
+// transition not allowed.
+func (m *Memory) SetStudentStatus(ctx context.Context, id int64, status string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.students[id]
+	if !ok || current.DeletedAt != nil {
+		return false, nil
+	}
+
+	if !types.CanChangeStatus(current.Status, status) {
+		return false, fmt.Errorf("%w: %s → %s", storage.ErrInvalidTransition, current.Status, status)
+	}
+
+	before := current
+	current.Status = status
+	current.Version++
+	current.UpdatedAt = time.Now().UTC()
+	m.students[id] = current
+	m.recordAudit(ctx, id, types.AuditUpdate, &before)
+
+	return true, nil
+}
+
+// DeleteStudent soft-deletes the student with this ID (sets DeletedAt),
+// after its enrollments block it or are removed (see releaseEnrollments);
+// its photo is removed. Reports false when no live student has this ID.
+func (m *Memory) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.students[id]
+	if !ok || current.DeletedAt != nil {
+		return false, nil
+	}
+
+	if err := m.releaseEnrollments(id); err != nil {
+		return false, err
+	}
+
+	before := current
+	now := time.Now().UTC()
+	current.DeletedAt = &now
+	m.students[id] = current
+	delete(m.photos, id)
+	m.recordAudit(ctx, id, types.AuditDelete, &before)
+
+	return true, nil
+}
+
+// DeleteStudents soft-deletes every live student matching filter, under one
+// lock, with the same audit events as the SQL backends. An empty filter is
+// storage.ErrEmptyFilter; in block mode, one enrolled student fails it all
+// before anything changes.
+func (m *Memory) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if filter.IsEmpty() {
+		return nil, storage.ErrEmptyFilter
+	}
+	filter.IncludeDeleted = false
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Ages of today, as in matching: CountStudents (dry_run) must count the
+	// same students.
+	now := time.Now()
+	deleted := make([]types.Student, 0)
+	for _, student := range m.students {
+		student.DeriveAge(now)
+		if matches(filter, student) {
+			deleted = append(deleted, student)
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool {
+		return deleted[i].Id < deleted[j].Id
+	})
+
+	// All the enrollments first: in block mode one of them fails the whole
+	// operation, and nothing after this can fail.
+	for _, student := range deleted {
+		if err := m.releaseEnrollments(student.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	deletedAt := now.UTC()
+	for _, before := range deleted {
+		current := before
+		current.DeletedAt = &deletedAt
+		m.students[current.Id] = current
+		delete(m.photos, current.Id)
+		m.recordAudit(ctx, current.Id, types.AuditDelete, &before)
+	}
+	m.recordSummary(ctx, types.AuditBulkDelete, types.AuditDetails{Filter: filter, Count: len(deleted)})
+
+	return deleted, nil
+}
+
+// RestoreStudent clears DeletedAt again and increments version. Reports false when no
+// soft-deleted student has this ID; storage.ErrDuplicateEmail when a live
+// student took the email in the meantime.
+func (m *Memory) RestoreStudent(ctx context.Context, id int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.students[id]
+	if !ok || current.DeletedAt == nil {
+		return false, nil
+	}
+
+	if m.emailTaken(current.Email, id) {
+		return false, storage.ErrDuplicateEmail
+	}
+
+	before := current
+	current.DeletedAt = nil
+	current.Version++
+	current.UpdatedAt = time.Now().UTC()
+	m.students[id] = current
+	m.recordAudit(ctx, id, types.AuditRestore, &before)
+
+	return true, nil
+}
+
+// PurgeDeletedStudents permanently removes up to limit students
+// soft-deleted before the cut-off, longest deleted first, and returns how
+// many there were.
+func (m *Memory) PurgeDeletedStudents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []types.Student
+	for _, student := range m.students {
+		if student.DeletedAt != nil && student.DeletedAt.Before(before) {
+			expired = append(expired, student)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		if !expired[i].DeletedAt.Equal(*expired[j].DeletedAt) {
+			return expired[i].DeletedAt.Before(*expired[j].DeletedAt)
+		}
+		return expired[i].Id < expired[j].Id
+	})
+
+	expired = expired[:min(limit, len(expired))]
+	for _, student := range expired {
+		delete(m.students, student.Id)
+	}
+
+	return int64(len(expired)), nil
+}
+
+// Ping always succeeds: there is no connection that could be down.
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close has nothing to release: the students go with the process.
+func (m *Memory) Close() error {
+	return nil
+}
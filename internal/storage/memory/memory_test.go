@@ -0,0 +1,44 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/memory"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/storagetest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.TestStorage(t, func(t *testing.T, cfg *config.Config) storage.Storage {
+		return memory.New(cfg)
+	})
+}
+
+// TestCancelledContext checks the calls give up on a cancelled context the
+// way the SQL drivers do: ctx.Err(), and nothing stored.
+func TestCancelledContext(t *testing.T) {
+	s := memory.New(apptest.Config(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	student := types.Student{Name: "Ann Lee", Email: "ann@example.com", Age: 20, Status: types.StatusActive}
+	if _, err := s.CreateStudent(ctx, student); !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateStudent: %v, want context.Canceled", err)
+	}
+	if _, err := s.ListStudents(ctx, types.StudentFilter{}, 10, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("ListStudents: %v, want context.Canceled", err)
+	}
+	if _, err := s.CountStudents(ctx, types.StudentFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("CountStudents: %v, want context.Canceled", err)
+	}
+
+	if n, err := s.CountStudents(context.Background(), types.StudentFilter{IncludeDeleted: true}); n != 0 || err != nil {
+		t.Errorf("CountStudents after the cancelled create = %d, %v; want 0", n, err)
+	}
+}
@@ -0,0 +1,57 @@
+package memory
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.Storage signatures
+   - storage → sentinel errors shared by all backends
+   - types   → Photo
+*/
+import (
+	"context"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// PutStudentPhoto stores the photo of the live student id, replacing the one
+// it had, and reports whether it had none; storage.ErrNotFound for a
+// missing or deleted student.
+func (m *Memory) PutStudentPhoto(ctx context.Context, id int64, photo types.Photo) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if student, ok := m.students[id]; !ok || student.DeletedAt != nil {
+		return false, storage.ErrNotFound
+	}
+
+	_, existed := m.photos[id]
+	// the handler's buffer is ours from now on, a copy is not needed
+	m.photos[id] = photo
+
+	return !existed, nil
+}
+
+// GetStudentPhoto returns the photo of the live student id;
+// storage.ErrNotFound for a missing or deleted student,
+// storage.ErrPhotoNotFound when it has no photo.
+func (m *Memory) GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if student, ok := m.students[id]; !ok || student.DeletedAt != nil {
+		return types.Photo{}, storage.ErrNotFound
+	}
+
+	photo, ok := m.photos[id]
+	if !ok {
+		return types.Photo{}, storage.ErrPhotoNotFound
+	}
+
+	return photo, nil
+}
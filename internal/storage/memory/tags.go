@@ -0,0 +1,73 @@
+package memory
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → part of the storage.Storage signatures
+   - fmt     → name the limit in ErrTooManyTags
+   - time    → updated_at
+   - storage → sentinel errors shared by all backends
+   - types   → the tag helpers
+*/
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// AddStudentTag tags the live student id, unless it already has
+// types.MaxTags tags: storage.ErrTooManyTags (wrapped).
+func (m *Memory) AddStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	return m.changeTags(ctx, id, func(tags []string) ([]string, bool, error) {
+		tagged, added := types.WithTag(tags, tag)
+		if added && len(tagged) > types.MaxTags {
+			return nil, false, fmt.Errorf("%w: at most %d", storage.ErrTooManyTags, types.MaxTags)
+		}
+		return tagged, added, nil
+	})
+}
+
+// RemoveStudentTag untags the live student id.
+func (m *Memory) RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	return m.changeTags(ctx, id, func(tags []string) ([]string, bool, error) {
+		untagged, removed := types.WithoutTag(tags, tag)
+		return untagged, removed, nil
+	})
+}
+
+// changeTags applies change to the tags of the live student id under the
+// lock, with the same rules as the SQL backends: a change bumps version and
+// is audited, no change writes nothing. Reports whether the tags changed,
+// storage.ErrNotFound for a missing or deleted student.
+func (m *Memory) changeTags(ctx context.Context, id int64, change func([]string) ([]string, bool, error)) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.students[id]
+	if !ok || current.DeletedAt != nil {
+		return false, storage.ErrNotFound
+	}
+
+	// WithTag / WithoutTag copy, so before keeps the old tags
+	tags, changed, err := change(current.Tags)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	before := current
+	current.Tags = tags
+	current.Version++
+	current.UpdatedAt = time.Now().UTC()
+	m.students[id] = current
+	m.recordAudit(ctx, id, types.AuditUpdate, &before)
+
+	return true, nil
+}
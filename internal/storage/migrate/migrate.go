@@ -0,0 +1,184 @@
+package migrate // migrate package applies numbered SQL schema migrations and records the schema version
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → migrations run with the caller's context
+   - database/sql → the backend's connection pool
+   - fmt          → readable errors ("migration 2 add_index: …")
+   - io/fs        → migrations come from an embed.FS of the backend
+   - log/slog     → every applied migration is logged
+   - path         → file names inside the fs.FS ("0001_create.sql")
+   - sort         → apply in version order
+   - strconv      → version number prefix of the file name
+   - strings      → split "0001_name.sql"
+*/
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Migration STRUCT
+-------------------------------------------------------------
+  - One file "<version>_<name>.sql", e.g. 0002_add_index.sql.
+  - Versions start at 1 and never change once released: a
+    schema change is always a NEW file.
+*/
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+/*
+Load()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Reads every *.sql file at the root of fsys and returns
+	    them sorted by version.
+
+	ERRORS:
+	  → a file name without a numeric prefix
+	  → two files with the same version
+*/
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	seen := make(map[int]string)
+
+	for _, file := range files {
+		prefix, name, _ := strings.Cut(strings.TrimSuffix(path.Base(file), ".sql"), "_")
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s: name must start with a version number >= 1", file)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", file, version, other)
+		}
+		seen[version] = file
+
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(body)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+/*
+Up()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Brings the database to the newest migration in fsys.
+	  → The applied versions live in schema_migrations; each
+	    pending migration runs in its own transaction together
+	    with the row that records it, so a failure leaves the
+	    database at the previous version.
+
+	DOWNGRADES:
+	  → If the database is at a version this binary does not
+	    know (it was migrated by a newer release), Up refuses to
+	    start instead of running against a schema it can't read.
+
+	The SQL used here is the same in SQLite and Postgres, so every
+	backend shares this function and only brings its own files.
+*/
+func Up(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	current, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+
+	if current > latest {
+		return fmt.Errorf("database schema is at version %d but this binary only knows up to %d: refusing to downgrade, run a newer release", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+		}
+
+		slog.Info("applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+
+	return nil
+}
+
+// Version returns the highest applied migration, 0 for a new database.
+// schema_migrations must exist.
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// apply runs one migration and records it, atomically.
+func apply(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return err
+	}
+
+	// The version is an int we parsed ourselves, so it is formatted into
+	// the statement; this avoids the "?" vs "$1" placeholder difference.
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ("+strconv.Itoa(m.Version)+")"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
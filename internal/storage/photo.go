@@ -0,0 +1,52 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → every storage call receives the request context
+   - errors  → the photo sentinel error
+   - types   → Photo
+*/
+import (
+	"context"
+	"errors"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// ErrPhotoNotFound is returned by GetStudentPhoto when the student exists
+// but has no photo.
+var ErrPhotoNotFound = errors.New("student has no photo")
+
+/*
+PhotoStore INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The profile photos of the students, one per student.
+
+	METHODS:
+	  - PutStudentPhoto → stores the photo of a live student,
+	                      replacing the one it had; reports whether
+	                      it had none (created)
+	  - GetStudentPhoto → returns the photo of a live student,
+	                      ErrPhotoNotFound when it has none
+
+	RULES:
+	  → Both return ErrNotFound for a missing (or soft-deleted)
+	    student.
+	  → Deleting a student (DeleteStudent, DeleteStudents)
+	    removes its photo in the same transaction: restoring it
+	    brings the student back without one.
+
+	WHERE:
+	  → Every backend keeps the photos next to the students (a
+	    blob column for the SQL ones). With photos.store "disk",
+	    photos.DiskStorage takes these methods over and keeps
+	    them as files instead.
+*/
+type PhotoStore interface {
+	PutStudentPhoto(ctx context.Context, id int64, photo types.Photo) (bool, error)
+	GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error)
+}
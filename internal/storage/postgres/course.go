@@ -0,0 +1,368 @@
+package postgres
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → transactions of the enrollment checks
+   - errors       → sql.ErrNoRows, driver errors
+   - fmt          → name the enrolled student in the error
+   - strings      → qualify the student columns of the roster JOIN
+   - time         → created_at / updated_at
+   - storage      → sentinel errors shared by all backends
+   - types        → Course / Enrollment
+   - pgconn       → SQLSTATE of a failed statement
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// courseColumns is the column list of the course SELECTs (FROM courses c),
+// in the order scanCourse expects; enrolled is counted, not stored.
+const courseColumns = "c.id, c.code, c.title, c.capacity, " +
+	"(SELECT COUNT(*) FROM enrollments e WHERE e.course_id = c.id), c.created_at, c.updated_at"
+
+// scanCourse reads one row selected with courseColumns.
+func scanCourse(row rowScanner) (types.Course, error) {
+	var course types.Course
+
+	err := row.Scan(
+		&course.Id, &course.Code, &course.Title, &course.Capacity, &course.Enrolled,
+		&course.CreatedAt, &course.UpdatedAt,
+	)
+
+	return course, err
+}
+
+// CreateCourse inserts one course and returns its ID;
+// storage.ErrDuplicateCourseCode when the code is taken.
+func (p *Postgres) CreateCourse(ctx context.Context, course types.Course) (int64, error) {
+	now := time.Now().UTC()
+
+	var id int64
+	err := p.Db.QueryRowContext(ctx,
+		"INSERT INTO courses (code, title, capacity, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		course.Code, course.Title, course.Capacity, now, now,
+	).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, storage.ErrDuplicateCourseCode
+	}
+
+	return id, err
+}
+
+// GetCourseById returns the course with this ID, or storage.ErrCourseNotFound.
+func (p *Postgres) GetCourseById(ctx context.Context, id int64) (types.Course, error) {
+	course, err := scanCourse(p.Db.QueryRowContext(ctx,
+		"SELECT "+courseColumns+" FROM courses c WHERE c.id = $1", id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Course{}, storage.ErrCourseNotFound
+	}
+
+	return course, err
+}
+
+// ListCourses returns one page of the courses, ordered by ID.
+func (p *Postgres) ListCourses(ctx context.Context, limit, offset int) ([]types.Course, error) {
+	rows, err := p.Db.QueryContext(ctx,
+		"SELECT "+courseColumns+" FROM courses c ORDER BY c.id LIMIT $1 OFFSET $2",
+		limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := make([]types.Course, 0)
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+
+	return courses, rows.Err()
+}
+
+// CountCourses returns how many courses there are.
+func (p *Postgres) CountCourses(ctx context.Context) (int, error) {
+	var count int
+	err := p.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM courses").Scan(&count)
+
+	return count, err
+}
+
+// UpdateCourse replaces the code, title and capacity of a course. The course
+// row is locked (FOR UPDATE) before its students are counted, so no
+// Enroll can take a seat in between; a capacity below that count is
+// storage.ErrCapacityBelowEnrolled. Reports false when no course has this ID.
+func (p *Postgres) UpdateCourse(ctx context.Context, id int64, course types.Course) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM courses WHERE id = $1 FOR UPDATE", id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var enrolled int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM enrollments WHERE course_id = $1", id).Scan(&enrolled)
+	if err != nil {
+		return false, err
+	}
+	if course.Capacity < enrolled {
+		return false, fmt.Errorf("%w: %d enrolled", storage.ErrCapacityBelowEnrolled, enrolled)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE courses SET code = $1, title = $2, capacity = $3, updated_at = $4 WHERE id = $5",
+		course.Code, course.Title, course.Capacity, time.Now().UTC(), id,
+	)
+	if isUniqueViolation(err) {
+		return false, storage.ErrDuplicateCourseCode
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// DeleteCourse removes a course and its enrollments in one transaction.
+// Reports false when no course has this ID.
+func (p *Postgres) DeleteCourse(ctx context.Context, id int64) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM enrollments WHERE course_id = $1", id); err != nil {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM courses WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+/*
+Enroll()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Same steps as the SQLite backend, with row locks in
+	    place of its single writer:
+	      - the student row FOR SHARE, so a concurrent delete
+	        (which locks it FOR UPDATE) waits for the enrollment
+	        and then sees it
+	      - the course row FOR UPDATE, so enrollments of the same
+	        course run one after the other and the count after
+	        the INSERT is exact
+*/
+func (p *Postgres) Enroll(ctx context.Context, studentID, courseID int64) error {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"SELECT id FROM students WHERE id = $1 AND deleted_at IS NULL FOR SHARE", studentID,
+	).Scan(&studentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var capacity int
+	err = tx.QueryRowContext(ctx, "SELECT capacity FROM courses WHERE id = $1 FOR UPDATE", courseID).Scan(&capacity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrCourseNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO enrollments (student_id, course_id, created_at) VALUES ($1, $2, $3)",
+		studentID, courseID, time.Now().UTC(),
+	)
+	if isUniqueViolation(err) {
+		return storage.ErrAlreadyEnrolled
+	}
+	if err != nil {
+		return err
+	}
+
+	var enrolled int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM enrollments WHERE course_id = $1", courseID).Scan(&enrolled)
+	if err != nil {
+		return err
+	}
+	if enrolled > capacity {
+		return storage.ErrCourseFull
+	}
+
+	return tx.Commit()
+}
+
+// Unenroll removes the enrollment of studentID in courseID and reports
+// whether there was one.
+func (p *Postgres) Unenroll(ctx context.Context, studentID, courseID int64) (bool, error) {
+	result, err := p.Db.ExecContext(ctx,
+		"DELETE FROM enrollments WHERE student_id = $1 AND course_id = $2", studentID, courseID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ListEnrollments returns the courses studentID is enrolled in, ordered by
+// course ID; empty for a student without any (or no student at all).
+func (p *Postgres) ListEnrollments(ctx context.Context, studentID int64) ([]types.Enrollment, error) {
+	rows, err := p.Db.QueryContext(ctx,
+		"SELECT e.student_id, c.id, c.code, c.title, e.created_at FROM enrollments e "+
+			"JOIN courses c ON c.id = e.course_id WHERE e.student_id = $1 ORDER BY c.id",
+		studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enrollments := make([]types.Enrollment, 0)
+	for rows.Next() {
+		var enrollment types.Enrollment
+		err := rows.Scan(&enrollment.StudentId, &enrollment.CourseId, &enrollment.Code, &enrollment.Title, &enrollment.EnrolledAt)
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, rows.Err()
+}
+
+// ListCourseStudents returns one page of the roster of a course in one JOIN,
+// ordered like the SQLite backend (see rosterOrder).
+func (p *Postgres) ListCourseStudents(ctx context.Context, courseID int64, sort types.RosterSort, limit, offset int) ([]types.CourseStudent, error) {
+	order, ok := rosterOrder[sort.Field]
+	if !ok {
+		order = rosterOrder[types.RosterSortID]
+	}
+	if sort.Desc {
+		order += " DESC"
+	}
+
+	rows, err := p.Db.QueryContext(ctx,
+		"SELECT "+rosterColumns+" FROM enrollments e JOIN students s ON s.id = e.student_id "+
+			"WHERE e.course_id = $1 AND s.deleted_at IS NULL ORDER BY "+order+", s.id LIMIT $2 OFFSET $3",
+		courseID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roster := make([]types.CourseStudent, 0)
+	for rows.Next() {
+		var entry types.CourseStudent
+		var uuid sql.NullString
+		var dateOfBirth, deletedAt sql.NullTime
+		var tags []byte
+
+		err := rows.Scan(
+			&entry.Id, &uuid, &entry.Name, &entry.Email, &entry.Age, &dateOfBirth, &entry.Phone, &tags, &entry.Status, &entry.Version,
+			&entry.CreatedAt, &entry.UpdatedAt, &deletedAt, &entry.EnrolledAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.PublicId = storage.PublicID(entry.Id, uuid.String)
+		setDateOfBirth(&entry.Student, dateOfBirth)
+		if entry.Tags, err = storage.ParseTags(tags); err != nil {
+			return nil, err
+		}
+		roster = append(roster, entry)
+	}
+
+	return roster, rows.Err()
+}
+
+// rosterColumns is studentColumns of the students table "s", plus the
+// enrollment's created_at: both tables have a created_at.
+var rosterColumns = "s." + strings.ReplaceAll(studentColumns, ", ", ", s.") + ", e.created_at"
+
+// rosterOrder maps each types.RosterSort field to its ORDER BY expression.
+var rosterOrder = map[string]string{
+	types.RosterSortID:         "s.id",
+	types.RosterSortName:       "LOWER(s.name)",
+	types.RosterSortEmail:      "s.email",
+	types.RosterSortEnrolledAt: "e.created_at",
+}
+
+// releaseEnrollments applies storage.on_student_delete inside the
+// transaction of a student delete, like the SQLite backend: block fails with
+// storage.ErrStudentEnrolled if the student has enrollments,
+// cascade removes them.
+func (p *Postgres) releaseEnrollments(ctx context.Context, tx *sql.Tx, studentID int64) error {
+	if p.cascadeEnrollments {
+		_, err := tx.ExecContext(ctx, "DELETE FROM enrollments WHERE student_id = $1", studentID)
+		return err
+	}
+
+	var enrolled bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM enrollments WHERE student_id = $1)", studentID,
+	).Scan(&enrolled)
+	if err != nil {
+		return err
+	}
+	if enrolled {
+		return storage.ErrStudentEnrolled
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether err is SQLSTATE 23505. The course
+// statements have two unique indexes of their own (code, the enrollment
+// key), so they map it themselves rather than through mapError.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,88 @@
+package postgres
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → sql.NullString for the uuid column
+   - errors       → sql.ErrNoRows
+   - time         → the creation time a UUID is made from
+   - storage      → ID parsing, UUIDs, sentinel errors
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+)
+
+// ResolveStudentID implements storage.IDStore like the SQLite backend.
+func (p *Postgres) ResolveStudentID(ctx context.Context, id string) (int64, error) {
+	rowID, uuid, err := storage.ParseStudentID(id, p.uuidIDs)
+	if err != nil || uuid == "" {
+		return rowID, err
+	}
+
+	err = p.Db.QueryRowContext(ctx, "SELECT id FROM students WHERE uuid = $1", uuid).Scan(&rowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrNotFound
+	}
+
+	return rowID, err
+}
+
+// newUUID is the uuid column of a student created at t, NULL under serial.
+func (p *Postgres) newUUID(t time.Time) sql.NullString {
+	if !p.uuidIDs {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: storage.NewUUID(t), Valid: true}
+}
+
+// assignUUIDs gives a UUID to the students without one under
+// storage.id_scheme uuid (see the SQLite backend). The rows are read
+// before any is updated: a transaction holds a single connection.
+func (p *Postgres) assignUUIDs(ctx context.Context) error {
+	if !p.uuidIDs {
+		return nil
+	}
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, created_at FROM students WHERE uuid IS NULL FOR UPDATE")
+	if err != nil {
+		return err
+	}
+
+	created := make(map[int64]time.Time)
+	for rows.Next() {
+		var id int64
+		var createdAt time.Time
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			rows.Close()
+			return err
+		}
+		created[id] = createdAt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, createdAt := range created {
+		if _, err := tx.ExecContext(ctx, "UPDATE students SET uuid = $1 WHERE id = $2", storage.NewUUID(createdAt), id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
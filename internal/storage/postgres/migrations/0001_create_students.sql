@@ -0,0 +1,11 @@
+-- Initial schema: the students table and one student per email.
+CREATE TABLE IF NOT EXISTS students (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT,
+	email TEXT,
+	age INTEGER,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_email ON students (email);
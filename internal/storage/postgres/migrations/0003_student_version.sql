@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every update increments version, and an update
+-- that names a stale version changes nothing.
+ALTER TABLE students ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,26 @@
+-- Courses and the students enrolled in them. Codes are stored upper-cased
+-- (types.Course.Normalize), so the plain unique index is case-insensitive.
+-- The seats are counted in the enrollment transaction, under a lock of the
+-- course row: capacity is not a constraint SQL can express.
+CREATE TABLE courses (
+	id BIGSERIAL PRIMARY KEY,
+	code TEXT NOT NULL,
+	title TEXT NOT NULL,
+	capacity INTEGER NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_courses_code ON courses (code);
+
+-- One row per student and course; the primary key is what makes a second
+-- enrollment fail. Rows are removed explicitly (storage.on_student_delete,
+-- DeleteCourse) like on SQLite, so both backends behave the same.
+CREATE TABLE enrollments (
+	student_id BIGINT NOT NULL REFERENCES students (id),
+	course_id BIGINT NOT NULL REFERENCES courses (id),
+	created_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (student_id, course_id)
+);
+
+CREATE INDEX idx_enrollments_course_id ON enrollments (course_id);
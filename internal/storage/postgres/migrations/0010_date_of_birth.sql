@@ -0,0 +1,5 @@
+-- Optional date of birth; the API derives the age from it when it is set,
+-- and the age filters turn into ranges of this column.
+ALTER TABLE students ADD COLUMN date_of_birth DATE;
+
+CREATE INDEX idx_students_date_of_birth ON students (date_of_birth);
@@ -0,0 +1,10 @@
+-- Profile photos, at most one per student, when photos.store is
+-- "database". Rows are removed with the student (soft delete included) by
+-- the delete statements, like on SQLite.
+CREATE TABLE student_photos (
+	student_id BIGINT PRIMARY KEY REFERENCES students (id),
+	content_type TEXT NOT NULL,
+	data BYTEA NOT NULL,
+	etag TEXT NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,6 @@
+-- Tags of a student, a JSON array of normalized tags ('["2025-intake",
+-- "scholarship"]'), sorted and without duplicates: the API normalizes them
+-- before they get here. The GIN index serves the tags @> '[...]' filter.
+ALTER TABLE students ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX idx_students_tags ON students USING GIN (tags);
@@ -0,0 +1,6 @@
+-- Public ID of a student under storage.id_scheme uuid, NULL under serial
+-- (see the SQLite migration). Generated by the storage layer, not by the
+-- database, so every backend makes the same version 7 UUIDs.
+ALTER TABLE students ADD COLUMN uuid UUID;
+
+CREATE UNIQUE INDEX idx_students_uuid ON students (uuid);
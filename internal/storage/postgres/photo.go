@@ -0,0 +1,98 @@
+package postgres
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → the transaction of a photo upload
+   - errors       → sql.ErrNoRows
+   - storage      → sentinel errors shared by all backends
+   - types        → Photo
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// PutStudentPhoto stores the photo of the live student id, replacing the one
+// it had, and reports whether it had none (see the SQLite backend). FOR
+// SHARE holds off a concurrent delete of the student until the commit.
+func (p *Postgres) PutStudentPhoto(ctx context.Context, id int64, photo types.Photo) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"SELECT id FROM students WHERE id = $1 AND deleted_at IS NULL FOR SHARE", id,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, storage.ErrNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var existed bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM student_photos WHERE student_id = $1)", id,
+	).Scan(&existed)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO student_photos (student_id, content_type, data, etag, updated_at) VALUES ($1, $2, $3, $4, $5) "+
+			"ON CONFLICT (student_id) DO UPDATE SET "+
+			"content_type = EXCLUDED.content_type, data = EXCLUDED.data, etag = EXCLUDED.etag, updated_at = EXCLUDED.updated_at",
+		id, photo.ContentType, photo.Data, photo.ETag, photo.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return !existed, tx.Commit()
+}
+
+// GetStudentPhoto returns the photo of the live student id, telling a
+// missing student from one without a photo like the SQLite backend.
+func (p *Postgres) GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error) {
+	var contentType, etag sql.NullString
+	var updatedAt sql.NullTime
+	var photo types.Photo
+
+	err := p.Db.QueryRowContext(ctx,
+		"SELECT ph.content_type, ph.data, ph.etag, ph.updated_at FROM students s "+
+			"LEFT JOIN student_photos ph ON ph.student_id = s.id WHERE s.id = $1 AND s.deleted_at IS NULL",
+		id,
+	).Scan(&contentType, &photo.Data, &etag, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Photo{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return types.Photo{}, err
+	}
+	if !contentType.Valid {
+		return types.Photo{}, storage.ErrPhotoNotFound
+	}
+
+	photo.ContentType = contentType.String
+	photo.ETag = etag.String
+	photo.UpdatedAt = updatedAt.Time
+
+	return photo, nil
+}
+
+// deletePhoto removes the photo of a student being deleted, inside its
+// transaction.
+func deletePhoto(ctx context.Context, tx *sql.Tx, studentID int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM student_photos WHERE student_id = $1", studentID)
+	return err
+}
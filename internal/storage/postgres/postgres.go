@@ -0,0 +1,997 @@
+package postgres // postgres package implements storage.Storage on top of a PostgreSQL database
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query (QueryContext/ExecContext)
+   - database/sql → Go's generic SQL API (connection pool, queries, rows)
+   - embed        → the migrations/*.sql files are compiled into the binary
+   - errors       → map sql.ErrNoRows / unique violations to storage errors
+   - fmt          → wrap connection and status transition errors
+   - io/fs        → hand the embedded migrations to migrate.Up
+   - strconv      → "$1", "$2", … placeholders
+   - strings      → join SET / WHERE clauses
+   - time         → created_at / updated_at
+   - config       → we need the DSN to know which database to use
+   - storage      → sentinel errors shared by all backends
+   - migrate      → applies the numbered schema migrations
+   - types        → Student struct returned to the handlers
+   - pgconn       → PgError carries the SQLSTATE code of a failure
+   - pgx/stdlib   → registers the "pgx" driver with database/sql
+*/
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/migrate"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// studentColumns is the column list every SELECT uses, in the order
+// scanStudent expects. The uuid is read as text, like SQLite's.
+const studentColumns = "id, uuid::text, name, email, age, date_of_birth, phone, tags, status, version, created_at, updated_at, deleted_at"
+
+// ageExpr is today's age (in UTC) in SQL, like the SQLite backend's: from
+// date_of_birth when there is one, the stored age otherwise.
+const ageExpr = "CASE WHEN date_of_birth IS NULL THEN age ELSE " +
+	"date_part('year', age((now() AT TIME ZONE 'UTC')::date, date_of_birth))::int END"
+
+// uniqueViolation is the SQLSTATE Postgres reports when a UNIQUE index fires.
+const uniqueViolation = "23505"
+
+// emailIndex is the unique index on LOWER(email) of the live students, as
+// in the SQLite backend.
+const emailIndex = "idx_students_email"
+
+// SQLSTATEs of a transaction that lost to a concurrent one (see IsTransient).
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+/*
+Postgres STRUCT
+-------------------------------------------------------------
+  - Holds the *sql.DB connection pool.
+  - cascadeEnrollments: storage.on_student_delete is
+    "cascade" (see releaseEnrollments).
+  - uuidIDs: storage.id_scheme is "uuid" (see ids.go).
+  - Implements every method of storage.Storage, with the same
+    errors as the SQLite backend so handlers can't tell them apart.
+*/
+type Postgres struct {
+	Db *sql.DB
+
+	cascadeEnrollments bool
+	uuidIDs            bool
+}
+
+/*
+New()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Connects to the database at cfg.Storage.DSN, with the pool
+	    sized by storage.max_open_conns / max_idle_conns /
+	    conn_max_lifetime.
+	  → Applies the pending schema migrations (migrations/*.sql).
+
+	RETURN VALUE:
+	  → *Postgres ready to be used as storage.Storage
+	  → error if the database can't be reached or a migration
+	    fails
+*/
+func New(cfg *config.Config) (*Postgres, error) {
+
+	// sql.Open does NOT connect yet, it only prepares the pool
+	db, err := sql.Open("pgx", cfg.Storage.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.Storage.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Storage.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Storage.ConnMaxLifetime)
+
+	// Unlike a SQLite file, the server can be down: fail at startup
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+
+	// Create or upgrade the schema (migrations/*.sql)
+	if err := migrate.Up(context.Background(), db, migrationFiles()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	p := &Postgres{
+		Db:                 db,
+		cascadeEnrollments: cfg.Storage.OnStudentDelete == config.OnDeleteCascade,
+		uuidIDs:            cfg.Storage.IDScheme == config.IDSchemeUUID,
+	}
+
+	// Students created under serial get their UUID now
+	if err := p.assignUUIDs(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// migrations holds the numbered schema files applied by migrate.Up.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationFiles returns the migrations directory as the root of an fs.FS.
+func migrationFiles() fs.FS {
+	files, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		// Only fails for an invalid path, which is a constant here
+		panic(err)
+	}
+
+	return files
+}
+
+/*
+params TYPE
+-------------------------------------------------------------
+  - Postgres numbers its placeholders ($1, $2, …), so queries
+    built from optional parts need a running count.
+  - add appends a value and returns its placeholder.
+*/
+type params []any
+
+func (p *params) add(v any) string {
+	*p = append(*p, v)
+	return "$" + strconv.Itoa(len(*p))
+}
+
+// rowScanner is what *sql.Row and *sql.Rows have in common.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanStudent reads one row selected with studentColumns; the age of a
+// student with a date of birth is the one of today.
+func scanStudent(row rowScanner) (types.Student, error) {
+	var student types.Student
+	var uuid sql.NullString
+	var dateOfBirth, deletedAt sql.NullTime
+	var tags []byte
+
+	err := row.Scan(
+		&student.Id, &uuid, &student.Name, &student.Email, &student.Age, &dateOfBirth, &student.Phone, &tags, &student.Status, &student.Version,
+		&student.CreatedAt, &student.UpdatedAt, &deletedAt,
+	)
+	if err != nil {
+		return student, err
+	}
+
+	student.PublicId = storage.PublicID(student.Id, uuid.String)
+	setDateOfBirth(&student, dateOfBirth)
+	if deletedAt.Valid {
+		student.DeletedAt = &deletedAt.Time
+	}
+
+	student.Tags, err = storage.ParseTags(tags)
+	return student, err
+}
+
+// setDateOfBirth puts a scanned date_of_birth into student and derives the
+// age from it.
+func setDateOfBirth(student *types.Student, dateOfBirth sql.NullTime) {
+	if !dateOfBirth.Valid {
+		return
+	}
+
+	date := dateOfBirth.Time.Format(time.DateOnly)
+	student.DateOfBirth = &date
+	student.DeriveAge(time.Now())
+}
+
+const insertStudent = "INSERT INTO students (uuid, name, email, age, date_of_birth, phone, tags, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+
+// CreateStudent inserts one student and its "create" audit event in one
+// transaction; Postgres has no LastInsertId, so the new ID comes back
+// through RETURNING.
+func (p *Postgres) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+	now := time.Now().UTC()
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, insertStudent,
+		p.newUUID(now), student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags), student.Status, now, now,
+	).Scan(&id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	if err := recordAudit(ctx, tx, id, types.AuditCreate, nil); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+/*
+UpsertStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Create-or-update by email in one INSERT … ON CONFLICT on
+	    the unique index of the live students' LOWER(email), so
+	    concurrent syncs of one email can't fail with a
+	    duplicate-key error: one inserts, the others update the
+	    name, age, date of birth, phone and tags.
+	  → version 1 in RETURNING means the row was inserted.
+
+	NOTES:
+	  → The "before" of the audit event is read (FOR UPDATE)
+	    just ahead of the upsert; a student inserted by another
+	    transaction in between is updated with no "before".
+*/
+func (p *Postgres) UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error) {
+	now := time.Now().UTC()
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var before *types.Student
+	current, err := scanStudent(tx.QueryRowContext(ctx,
+		"SELECT "+studentColumns+" FROM students WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL FOR UPDATE", student.Email,
+	))
+	switch {
+	case err == nil:
+		before = &current
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, false, err
+	}
+
+	var id int64
+	var version int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO students (uuid, name, email, age, date_of_birth, phone, tags, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) "+
+			"ON CONFLICT (LOWER(email)) WHERE deleted_at IS NULL DO UPDATE SET "+
+			"name = EXCLUDED.name, age = EXCLUDED.age, date_of_birth = EXCLUDED.date_of_birth, phone = EXCLUDED.phone, tags = EXCLUDED.tags, "+
+			"updated_at = EXCLUDED.updated_at, version = students.version + 1 "+
+			"RETURNING id, version",
+		p.newUUID(now), student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags), student.Status, now, now,
+	).Scan(&id, &version)
+	if err != nil {
+		return 0, false, mapError(err)
+	}
+
+	created := version == 1
+	action := types.AuditUpdate
+	if created {
+		action, before = types.AuditCreate, nil
+	}
+
+	if err := recordAudit(ctx, tx, id, action, before); err != nil {
+		return 0, false, err
+	}
+
+	return id, created, tx.Commit()
+}
+
+/*
+CreateStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Same contract as the SQLite backend: one transaction,
+	    per-item results, atomic=true rolls back everything, a
+	    "create" audit event per created row.
+
+	WHY SAVEPOINTS?
+	  → In Postgres one failing statement aborts the whole
+	    transaction. For atomic=false every insert runs inside
+	    its own savepoint, so a duplicate email only undoes that
+	    one row.
+*/
+func (p *Postgres) CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]storage.BulkResult, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	results := make([]storage.BulkResult, len(students))
+
+	for i, student := range students {
+		if !atomic {
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT bulk_item"); err != nil {
+				return nil, err
+			}
+		}
+
+		uuid := p.newUUID(now)
+		err := tx.QueryRowContext(ctx, insertStudent,
+			uuid, student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags), student.Status, now, now,
+		).Scan(&results[i].Id)
+		if err == nil {
+			results[i].PublicId = storage.PublicID(results[i].Id, uuid.String)
+			if err := recordAudit(ctx, tx, results[i].Id, types.AuditCreate, nil); err != nil {
+				return nil, err
+			}
+			if !atomic {
+				if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT bulk_item"); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		results[i].Err = mapError(err)
+
+		if atomic {
+			for j := range results {
+				if j != i {
+					results[j] = storage.BulkResult{Err: storage.ErrBatchAborted}
+				}
+			}
+			return results, nil
+		}
+
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_item"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetStudentById returns the student with this ID, or storage.ErrNotFound
+// when there is none or it is soft-deleted.
+func (p *Postgres) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	student, err := scanStudent(p.Db.QueryRowContext(ctx,
+		"SELECT "+studentColumns+" FROM students WHERE id = $1 AND deleted_at IS NULL",
+		id,
+	))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Student{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return types.Student{}, err
+	}
+
+	return student, nil
+}
+
+// ListStudents returns one page of the students matching filter, ordered by ID.
+func (p *Postgres) ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error) {
+	var args params
+	where := filterClause(filter, &args)
+
+	query := "SELECT " + studentColumns + " FROM students" + where +
+		" ORDER BY id LIMIT " + args.add(limit) + " OFFSET " + args.add(offset)
+
+	return p.queryStudents(ctx, query, args...)
+}
+
+// ListStudentsAfter is cursor pagination: the matching students with an ID
+// greater than afterID, ordered by ID.
+func (p *Postgres) ListStudentsAfter(ctx context.Context, filter types.StudentFilter, afterID int64, limit int) ([]types.Student, error) {
+	var args params
+	where := filterClause(filter, &args)
+
+	if where == "" {
+		where = " WHERE id > " + args.add(afterID)
+	} else {
+		where += " AND id > " + args.add(afterID)
+	}
+
+	query := "SELECT " + studentColumns + " FROM students" + where +
+		" ORDER BY id LIMIT " + args.add(limit)
+
+	return p.queryStudents(ctx, query, args...)
+}
+
+// FindStudentsByEmailOrName returns the live students whose email is one of
+// emails or whose name is one of names (both case-insensitive), ordered by
+// ID, in one query with two IN lists; the caller caps their sizes.
+func (p *Postgres) FindStudentsByEmailOrName(ctx context.Context, emails, names []string) ([]types.Student, error) {
+	var args params
+	var conds []string
+
+	if len(emails) > 0 {
+		conds = append(conds, "LOWER(email) IN ("+inList(&args, emails)+")")
+	}
+	if len(names) > 0 {
+		conds = append(conds, "LOWER(name) IN ("+inList(&args, names)+")")
+	}
+
+	if len(conds) == 0 {
+		return make([]types.Student, 0), nil
+	}
+
+	query := "SELECT " + studentColumns + " FROM students WHERE deleted_at IS NULL AND (" +
+		strings.Join(conds, " OR ") + ") ORDER BY id"
+
+	return p.queryStudents(ctx, query, args...)
+}
+
+// inList adds the lower-cased values to args and returns their "$1, $2, …".
+func inList(args *params, values []string) string {
+	refs := make([]string, len(values))
+	for i, value := range values {
+		refs[i] = args.add(strings.ToLower(value))
+	}
+
+	return strings.Join(refs, ", ")
+}
+
+// ForEachStudent streams every student matching filter into fn, one row at
+// a time; the first error from fn stops the scan and is returned.
+func (p *Postgres) ForEachStudent(ctx context.Context, filter types.StudentFilter, fn func(types.Student) error) error {
+	var args params
+	where := filterClause(filter, &args)
+
+	rows, err := p.Db.QueryContext(ctx,
+		"SELECT "+studentColumns+" FROM students"+where+" ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(student); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// CountStudents counts every student matching filter, ignoring paging.
+func (p *Postgres) CountStudents(ctx context.Context, filter types.StudentFilter) (int, error) {
+	var args params
+	where := filterClause(filter, &args)
+
+	var total int
+	err := p.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM students"+where, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+/*
+StudentStats()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Aggregates of the live students, in five queries that
+	    return a handful of rows whatever the size of the table:
+	      1. COUNT / AVG / MIN / MAX over everyone
+	      2. GROUP BY status
+	      3. GROUP BY tag (jsonb_array_elements_text unnests them)
+	      4. GROUP BY age bucket
+	      5. GROUP BY creation day, from since on
+
+	NOTES:
+	  → Ages are ageExpr, today's age for the students with a
+	    date of birth.
+	  → Days are cut in UTC, whatever the session time zone.
+*/
+func (p *Postgres) StudentStats(ctx context.Context, since time.Time) (types.StudentStats, error) {
+	var stats types.StudentStats
+
+	err := p.Db.QueryRowContext(ctx,
+		"SELECT COUNT(*), COALESCE(AVG("+ageExpr+"), 0)::float8, COALESCE(MIN("+ageExpr+"), 0), COALESCE(MAX("+ageExpr+"), 0) "+
+			"FROM students WHERE deleted_at IS NULL",
+	).Scan(&stats.Total, &stats.Age.Average, &stats.Age.Min, &stats.Age.Max)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+
+	statuses, err := p.Db.QueryContext(ctx,
+		"SELECT status, COUNT(*) FROM students WHERE deleted_at IS NULL GROUP BY status ORDER BY status",
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer statuses.Close()
+
+	for statuses.Next() {
+		var status types.StatusCount
+		if err := statuses.Scan(&status.Status, &status.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		stats.ByStatus = append(stats.ByStatus, status)
+	}
+	if err := statuses.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	tags, err := p.Db.QueryContext(ctx,
+		"SELECT tag, COUNT(*) AS count FROM students, jsonb_array_elements_text(students.tags) AS tag "+
+			"WHERE deleted_at IS NULL GROUP BY tag ORDER BY count DESC, tag",
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer tags.Close()
+
+	for tags.Next() {
+		var tag types.TagCount
+		if err := tags.Scan(&tag.Tag, &tag.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		stats.ByTag = append(stats.ByTag, tag)
+	}
+	if err := tags.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	rows, err := p.Db.QueryContext(ctx,
+		"SELECT ("+ageExpr+" - 1) / $1 * $1 + 1 AS bucket, COUNT(*) FROM students WHERE deleted_at IS NULL GROUP BY bucket ORDER BY bucket",
+		types.AgeBucketWidth,
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket types.AgeBucket
+		if err := rows.Scan(&bucket.From, &bucket.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		bucket.To = bucket.From + types.AgeBucketWidth - 1
+		stats.AgeBuckets = append(stats.AgeBuckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	days, err := p.Db.QueryContext(ctx,
+		"SELECT to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*) FROM students WHERE deleted_at IS NULL AND created_at >= $1 GROUP BY day ORDER BY day",
+		since.UTC(),
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer days.Close()
+
+	for days.Next() {
+		var day types.DayCount
+		if err := days.Scan(&day.Date, &day.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		stats.CreatedPerDay = append(stats.CreatedPerDay, day)
+	}
+
+	return stats, days.Err()
+}
+
+/*
+filterClause()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Turns a StudentFilter into " WHERE a AND b …", adding the
+	    values to args ("" when nothing is set).
+	  → Same semantics as the SQLite backend; ILIKE gives the
+	    case-insensitive match SQLite's LIKE has by default.
+	  → min_age / max_age become date ranges for the students
+	    with a date of birth, like in the SQLite backend.
+	  → The tags are one containment test (tags @> '["a","b"]'),
+	    which the GIN index serves.
+	  → Soft-deleted rows are excluded unless IncludeDeleted.
+*/
+func filterClause(filter types.StudentFilter, args *params) string {
+	var conds []string
+
+	if !filter.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+
+	if filter.Name != "" {
+		conds = append(conds, "name ILIKE "+args.add(likePattern(filter.Name))+` ESCAPE '\'`)
+	}
+	if filter.Email != "" {
+		conds = append(conds, "LOWER(email) = LOWER("+args.add(filter.Email)+")")
+	}
+	if filter.EmailDomain != "" {
+		domain := "%@" + likeEscaper.Replace(strings.ToLower(filter.EmailDomain))
+		conds = append(conds, "LOWER(email) LIKE "+args.add(domain)+` ESCAPE '\'`)
+	}
+	if filter.Status != "" {
+		conds = append(conds, "status = "+args.add(filter.Status))
+	}
+
+	now := time.Now()
+	if filter.MinAge != nil {
+		bornBy := types.BornBy(*filter.MinAge, now).Format(time.DateOnly)
+		conds = append(conds, "((date_of_birth IS NULL AND age >= "+args.add(*filter.MinAge)+") OR date_of_birth <= "+args.add(bornBy)+")")
+	}
+	if filter.MaxAge != nil {
+		bornAfter := types.BornBy(*filter.MaxAge+1, now).Format(time.DateOnly)
+		conds = append(conds, "((date_of_birth IS NULL AND age <= "+args.add(*filter.MaxAge)+") OR date_of_birth > "+args.add(bornAfter)+")")
+	}
+	for _, term := range filter.Terms {
+		pattern := args.add(likePattern(term))
+		conds = append(conds, "(name ILIKE "+pattern+` ESCAPE '\' OR email ILIKE `+pattern+` ESCAPE '\')`)
+	}
+	if len(filter.Tags) > 0 {
+		conds = append(conds, "tags @> "+args.add(storage.TagsJSON(filter.Tags))+"::jsonb")
+	}
+
+	if len(conds) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// likeEscaper escapes LIKE wildcards (and the escape character itself).
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// likePattern wraps s for a substring match with its wildcards escaped.
+func likePattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// queryStudents runs a SELECT of studentColumns and scans every row.
+// Always returns a non-nil slice so an empty page is encoded as [].
+func (p *Postgres) queryStudents(ctx context.Context, query string, args ...any) ([]types.Student, error) {
+	rows, err := p.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := make([]types.Student, 0)
+
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		students = append(students, student)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
+
+// UpdateStudent replaces name, email, age, date of birth, phone and tags,
+// refreshes updated_at and increments version. version > 0 makes it conditional.
+// Reports false when no live student has this ID, storage.ErrVersionConflict
+// when it is no longer at version. Recorded as an "update" audit event.
+func (p *Postgres) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+	updated, err := p.audited(ctx, id, types.AuditUpdate, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			"UPDATE students SET name = $1, email = $2, age = $3, date_of_birth = $4, phone = $5, tags = $6, updated_at = $7, version = version + 1 "+
+				"WHERE id = $8 AND deleted_at IS NULL AND ($9 = 0 OR version = $9)",
+			student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags),
+			time.Now().UTC(), id, version,
+		)
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return p.versionedUpdate(ctx, updated, id, version)
+}
+
+// versionedUpdate follows up on conditional updates: when a versioned update
+// matched no row it tells a missing student (false) apart from one that
+// moved on to another version (storage.ErrVersionConflict).
+func (p *Postgres) versionedUpdate(ctx context.Context, updated bool, id int64, version int) (bool, error) {
+	if updated || version == 0 {
+		return updated, nil
+	}
+
+	var exists bool
+	err := p.Db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM students WHERE id = $1 AND deleted_at IS NULL)",
+		id,
+	).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, storage.ErrVersionConflict
+	}
+
+	return false, nil
+}
+
+// PatchStudent updates only the columns whose patch field is non-nil, plus
+// updated_at and version, with the same rules (and audit event) as
+// UpdateStudent.
+func (p *Postgres) PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error) {
+	var sets []string
+	var args params
+
+	if patch.Name != nil {
+		sets = append(sets, "name = "+args.add(*patch.Name))
+	}
+	if patch.Email != nil {
+		sets = append(sets, "email = "+args.add(*patch.Email))
+	}
+	// an age alone clears the date of birth, like in the SQLite backend
+	if patch.Age != nil {
+		sets = append(sets, "age = "+args.add(*patch.Age), "date_of_birth = "+args.add(patch.DateOfBirth))
+	}
+	if patch.Phone != nil {
+		sets = append(sets, "phone = "+args.add(*patch.Phone))
+	}
+	if patch.Tags != nil {
+		sets = append(sets, "tags = "+args.add(storage.TagsJSON(*patch.Tags)))
+	}
+
+	// Nothing to change; the handler rejects {} before we get here
+	if len(sets) == 0 {
+		return false, errors.New("no fields to update")
+	}
+
+	sets = append(sets, "updated_at = "+args.add(time.Now().UTC()), "version = version + 1")
+
+	where := " WHERE id = " + args.add(id) + " AND deleted_at IS NULL"
+	v := args.add(version)
+	where += " AND (" + v + " = 0 OR version = " + v + ")"
+
+	updated, err := p.audited(ctx, id, types.AuditUpdate, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			"UPDATE students SET "+strings.Join(sets, ", ")+where,
+			args...,
+		)
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return p.versionedUpdate(ctx, updated, id, version)
+}
+
+// SetStudentStatus moves the live student with this ID to status if
+// types.CanChangeStatus allows it, incrementing version; recorded as an
+// "update" audit event. auditSnapshot's FOR UPDATE holds the row from the
+// check to the commit. Reports false when no live student has this ID;
+// storage.ErrInvalidTransition (wrapped) for a transition not allowed.
+func (p *Postgres) SetStudentStatus(ctx context.Context, id int64, status string) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, id)
+	if err != nil || before == nil || before.DeletedAt != nil {
+		return false, err
+	}
+
+	if !types.CanChangeStatus(before.Status, status) {
+		return false, fmt.Errorf("%w: %s → %s", storage.ErrInvalidTransition, before.Status, status)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE students SET status = $1, updated_at = $2, version = version + 1 WHERE id = $3",
+		status, time.Now().UTC(), id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, tx, id, types.AuditUpdate, before); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// DeleteStudent soft-deletes the student with this ID (sets deleted_at),
+// recorded as a "delete" audit event, after its enrollments block it or are
+// removed (see releaseEnrollments); its photo is removed. Reports false
+// when no live student has this ID.
+func (p *Postgres) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+	return p.audited(ctx, id, types.AuditDelete, func(tx *sql.Tx) (sql.Result, error) {
+		if err := p.releaseEnrollments(ctx, tx, id); err != nil {
+			return nil, err
+		}
+		if err := deletePhoto(ctx, tx, id); err != nil {
+			return nil, err
+		}
+		return tx.ExecContext(ctx,
+			"UPDATE students SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL",
+			time.Now().UTC(), id,
+		)
+	})
+}
+
+// DeleteStudents soft-deletes every live student matching filter in one
+// transaction, each with its "delete" audit event, plus one "bulk_delete"
+// summary (see the SQLite backend). FOR UPDATE locks the selected rows, so
+// the students returned are exactly those deleted. An empty filter is
+// storage.ErrEmptyFilter; in block mode, one enrolled student fails it all.
+func (p *Postgres) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	if filter.IsEmpty() {
+		return nil, storage.ErrEmptyFilter
+	}
+	filter.IncludeDeleted = false
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	var args params
+	where := filterClause(filter, &args)
+	rows, err := tx.QueryContext(ctx, "SELECT "+studentColumns+" FROM students"+where+" ORDER BY id FOR UPDATE", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]types.Student, 0)
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deleted = append(deleted, student)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for i := range deleted {
+		if err := p.releaseEnrollments(ctx, tx, deleted[i].Id); err != nil {
+			return nil, err
+		}
+		if err := deletePhoto(ctx, tx, deleted[i].Id); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE students SET deleted_at = $1 WHERE id = $2", now, deleted[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordAudit(ctx, tx, deleted[i].Id, types.AuditDelete, &deleted[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	details := types.AuditDetails{Filter: filter, Count: len(deleted)}
+	if err := recordSummary(ctx, tx, types.AuditBulkDelete, details); err != nil {
+		return nil, err
+	}
+
+	return deleted, tx.Commit()
+}
+
+// RestoreStudent clears deleted_at again and increments version, recorded as
+// a "restore" audit event. Reports false when no soft-deleted student has
+// this ID; storage.ErrDuplicateEmail when a live student took the email in
+// the meantime.
+func (p *Postgres) RestoreStudent(ctx context.Context, id int64) (bool, error) {
+	restored, err := p.audited(ctx, id, types.AuditRestore, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			"UPDATE students SET deleted_at = NULL, updated_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NOT NULL",
+			time.Now().UTC(), id,
+		)
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return restored, nil
+}
+
+// PurgeDeletedStudents permanently removes up to limit students
+// soft-deleted before the cut-off, longest deleted first, and returns how
+// many there were (see the SQLite backend).
+func (p *Postgres) PurgeDeletedStudents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result, err := p.Db.ExecContext(ctx,
+		"DELETE FROM students WHERE id IN ("+
+			"SELECT id FROM students WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY deleted_at, id LIMIT $2)",
+		before.UTC(), limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Ping verifies the database server is reachable (readiness probe).
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.Db.PingContext(ctx)
+}
+
+// Close closes the connection pool, at shutdown, so the server ends the
+// sessions now instead of when it notices they are gone.
+func (p *Postgres) Close() error {
+	return p.Db.Close()
+}
+
+/*
+IsTransient()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The classification storage.Retry uses for this backend:
+	      - serialization failures and deadlocks: Postgres rolled
+	        the transaction back, it may succeed when run again
+	      - failures pgconn marks safe to retry: nothing reached
+	        the server (e.g. a pooled connection found closed)
+*/
+func IsTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+	}
+
+	return pgconn.SafeToRetry(err)
+}
+
+/*
+mapError()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Turns driver errors the handlers care about into the
+	    shared storage sentinels; everything else is returned as is.
+	  → Only used on students statements. Only a 23505 on
+	    emailIndex means ErrDuplicateEmail: one on
+	    idx_students_uuid is returned as is, a 500 rather than a
+	    bogus 409.
+*/
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == emailIndex {
+		return storage.ErrDuplicateEmail
+	}
+
+	return err
+}
@@ -0,0 +1,81 @@
+package postgres
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → passed into every query
+   - fmt     → name the limit in ErrTooManyTags
+   - time    → updated_at
+   - storage → sentinel errors shared by all backends, the tags JSON
+   - types   → the tag helpers
+*/
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// AddStudentTag tags the live student id, unless it already has
+// types.MaxTags tags: storage.ErrTooManyTags (wrapped).
+func (p *Postgres) AddStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	return p.changeTags(ctx, id, func(tags []string) ([]string, bool, error) {
+		tagged, added := types.WithTag(tags, tag)
+		if added && len(tagged) > types.MaxTags {
+			return nil, false, fmt.Errorf("%w: at most %d", storage.ErrTooManyTags, types.MaxTags)
+		}
+		return tagged, added, nil
+	})
+}
+
+// RemoveStudentTag untags the live student id.
+func (p *Postgres) RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	return p.changeTags(ctx, id, func(tags []string) ([]string, bool, error) {
+		untagged, removed := types.WithoutTag(tags, tag)
+		return untagged, removed, nil
+	})
+}
+
+// changeTags is the read-modify-write of the tags of the live student id
+// (see the SQLite backend); auditSnapshot's FOR UPDATE holds the row from
+// the read to the commit, so concurrent changes can't lose one another.
+// Reports whether the tags changed, storage.ErrNotFound for a missing or
+// deleted student.
+func (p *Postgres) changeTags(ctx context.Context, id int64, change func([]string) ([]string, bool, error)) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, id)
+	if err != nil {
+		return false, err
+	}
+	if before == nil || before.DeletedAt != nil {
+		return false, storage.ErrNotFound
+	}
+
+	tags, changed, err := change(before.Tags)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE students SET tags = $1, updated_at = $2, version = version + 1 WHERE id = $3",
+		storage.TagsJSON(tags), time.Now().UTC(), id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, tx, id, types.AuditUpdate, before); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
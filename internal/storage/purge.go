@@ -0,0 +1,50 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → cancelled between batches (shutdown, request deadline)
+   - time    → the cut-off
+*/
+import (
+	"context"
+	"time"
+)
+
+/*
+PurgeInBatches()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Permanently removes every student soft-deleted before
+	    "before", batchSize at a time: each PurgeDeletedStudents
+	    call is its own short statement, so readers and the other
+	    writers get the table in between.
+	  → Shared by the purge job and POST /api/admin/purge.
+
+	RETURN VALUE:
+	  → how many students were removed, also when it stopped
+	    early: on a failing batch (with its error) or when ctx
+	    was cancelled between two batches (ctx.Err())
+*/
+func PurgeInBatches(ctx context.Context, s Storage, before time.Time, batchSize int) (int64, error) {
+	var total int64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		purged, err := s.PurgeDeletedStudents(ctx, before, batchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+
+		// A short batch was the last one
+		if purged < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → stop retrying when the request is cancelled
+   - math/rand/v2 → jitter of the backoff delay
+   - time         → backoff delays
+   - config       → the storage.retry config section
+   - types        → Student struct / filters passed through
+*/
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+Retry()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Wraps a Storage so that a call failing with a transient
+	    error (SQLITE_BUSY, a Postgres serialization failure…) is
+	    tried again instead of reaching the client as a 500.
+	  → transient is the classification of the backend's driver
+	    (sqlite.IsTransient, postgres.IsTransient). A nil one (the
+	    memory backend has nothing transient) returns next as is.
+
+	RETRIED:
+	  → Reads: GetStudentById, ListStudents, ListStudentsAfter,
+	    CountStudents, FindStudentsByEmailOrName, StudentStats,
+	    List/CountAuditEvents, GetCourseById, List/CountCourses,
+	    ListEnrollments, ListCourseStudents, GetStudentPhoto,
+	    ResolveStudentID, Ping.
+	  → Writes that can't apply twice: CreateStudent (the unique
+	    email turns a second insert into ErrDuplicateEmail),
+	    CreateCourse and Enroll (likewise with the course code and
+	    the enrollment key) and ReserveIdempotencyKey (the key is
+	    the primary key).
+	  → Everything else goes straight to next: an update that did
+	    commit before the error would fail its version check on
+	    the retry, a delete would report "not found", and
+	    ForEachStudent has already handed rows to its callback.
+
+	BACKOFF:
+	  → Up to cfg.Attempts calls in total; before each retry it
+	    waits base_delay * 2^(retry-1), capped at max_delay, with
+	    "full jitter" (a random delay between 0 and that), so
+	    clients blocked on the same lock don't retry in lockstep.
+	  → Gives up at once when ctx is done, with the error of the
+	    last attempt.
+*/
+func Retry(next Storage, cfg config.StorageRetry, transient func(error) bool) Storage {
+	if transient == nil || cfg.Attempts <= 1 {
+		return next
+	}
+
+	return &retryingStorage{Storage: next, cfg: cfg, transient: transient}
+}
+
+type retryingStorage struct {
+	Storage
+	cfg       config.StorageRetry
+	transient func(error) bool
+}
+
+// retry calls op until it succeeds, fails with a non-transient error, runs
+// out of attempts or ctx is done.
+func retry[T any](ctx context.Context, s *retryingStorage, op func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := op()
+		if err == nil || attempt >= s.cfg.Attempts || !s.transient(err) || ctx.Err() != nil {
+			return result, err
+		}
+
+		timer := time.NewTimer(s.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		}
+	}
+}
+
+// backoff returns the jittered delay before retry number attempt (from 1).
+func (s *retryingStorage) backoff(attempt int) time.Duration {
+	ceiling := s.cfg.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		ceiling = min(s.cfg.BaseDelay<<shift, s.cfg.MaxDelay)
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling + 1)
+}
+
+func (s *retryingStorage) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+	return retry(ctx, s, func() (int64, error) { return s.Storage.CreateStudent(ctx, student) })
+}
+
+func (s *retryingStorage) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	return retry(ctx, s, func() (types.Student, error) { return s.Storage.GetStudentById(ctx, id) })
+}
+
+func (s *retryingStorage) ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error) {
+	return retry(ctx, s, func() ([]types.Student, error) { return s.Storage.ListStudents(ctx, filter, limit, offset) })
+}
+
+func (s *retryingStorage) ListStudentsAfter(ctx context.Context, filter types.StudentFilter, afterID int64, limit int) ([]types.Student, error) {
+	return retry(ctx, s, func() ([]types.Student, error) {
+		return s.Storage.ListStudentsAfter(ctx, filter, afterID, limit)
+	})
+}
+
+func (s *retryingStorage) CountStudents(ctx context.Context, filter types.StudentFilter) (int, error) {
+	return retry(ctx, s, func() (int, error) { return s.Storage.CountStudents(ctx, filter) })
+}
+
+func (s *retryingStorage) FindStudentsByEmailOrName(ctx context.Context, emails, names []string) ([]types.Student, error) {
+	return retry(ctx, s, func() ([]types.Student, error) {
+		return s.Storage.FindStudentsByEmailOrName(ctx, emails, names)
+	})
+}
+
+func (s *retryingStorage) StudentStats(ctx context.Context, since time.Time) (types.StudentStats, error) {
+	return retry(ctx, s, func() (types.StudentStats, error) { return s.Storage.StudentStats(ctx, since) })
+}
+
+func (s *retryingStorage) ListAuditEvents(ctx context.Context, studentID int64, limit, offset int) ([]types.AuditEvent, error) {
+	return retry(ctx, s, func() ([]types.AuditEvent, error) {
+		return s.Storage.ListAuditEvents(ctx, studentID, limit, offset)
+	})
+}
+
+func (s *retryingStorage) CountAuditEvents(ctx context.Context, studentID int64) (int, error) {
+	return retry(ctx, s, func() (int, error) { return s.Storage.CountAuditEvents(ctx, studentID) })
+}
+
+func (s *retryingStorage) CreateCourse(ctx context.Context, course types.Course) (int64, error) {
+	return retry(ctx, s, func() (int64, error) { return s.Storage.CreateCourse(ctx, course) })
+}
+
+func (s *retryingStorage) GetCourseById(ctx context.Context, id int64) (types.Course, error) {
+	return retry(ctx, s, func() (types.Course, error) { return s.Storage.GetCourseById(ctx, id) })
+}
+
+func (s *retryingStorage) ListCourses(ctx context.Context, limit, offset int) ([]types.Course, error) {
+	return retry(ctx, s, func() ([]types.Course, error) { return s.Storage.ListCourses(ctx, limit, offset) })
+}
+
+func (s *retryingStorage) CountCourses(ctx context.Context) (int, error) {
+	return retry(ctx, s, func() (int, error) { return s.Storage.CountCourses(ctx) })
+}
+
+func (s *retryingStorage) Enroll(ctx context.Context, studentID, courseID int64) error {
+	_, err := retry(ctx, s, func() (struct{}, error) { return struct{}{}, s.Storage.Enroll(ctx, studentID, courseID) })
+	return err
+}
+
+func (s *retryingStorage) ListEnrollments(ctx context.Context, studentID int64) ([]types.Enrollment, error) {
+	return retry(ctx, s, func() ([]types.Enrollment, error) { return s.Storage.ListEnrollments(ctx, studentID) })
+}
+
+func (s *retryingStorage) ListCourseStudents(ctx context.Context, courseID int64, sort types.RosterSort, limit, offset int) ([]types.CourseStudent, error) {
+	return retry(ctx, s, func() ([]types.CourseStudent, error) {
+		return s.Storage.ListCourseStudents(ctx, courseID, sort, limit, offset)
+	})
+}
+
+func (s *retryingStorage) GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error) {
+	return retry(ctx, s, func() (types.Photo, error) { return s.Storage.GetStudentPhoto(ctx, id) })
+}
+
+func (s *retryingStorage) ResolveStudentID(ctx context.Context, id string) (int64, error) {
+	return retry(ctx, s, func() (int64, error) { return s.Storage.ResolveStudentID(ctx, id) })
+}
+
+func (s *retryingStorage) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, expiresAt time.Time) (IdempotencyRecord, bool, error) {
+	type reservation struct {
+		record   IdempotencyRecord
+		reserved bool
+	}
+
+	r, err := retry(ctx, s, func() (reservation, error) {
+		record, reserved, err := s.Storage.ReserveIdempotencyKey(ctx, key, requestHash, expiresAt)
+		return reservation{record, reserved}, err
+	})
+	return r.record, r.reserved, err
+}
+
+func (s *retryingStorage) Ping(ctx context.Context) error {
+	_, err := retry(ctx, s, func() (struct{}, error) { return struct{}{}, s.Storage.Ping(ctx) })
+	return err
+}
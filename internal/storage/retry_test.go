@@ -0,0 +1,122 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+var errBusy = errors.New("database is locked")
+
+func transient(err error) bool { return errors.Is(err, errBusy) }
+
+// flaky is a storage whose calls fail with err until they have been made
+// failures times, counting them. Only the methods under test are there.
+type flaky struct {
+	storage.Storage
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flaky) call() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flaky) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+	if err := f.call(); err != nil {
+		return 0, err
+	}
+	return 7, nil
+}
+
+func (f *flaky) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+	if err := f.call(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func TestRetry(t *testing.T) {
+	cfg := config.StorageRetry{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	tests := []struct {
+		name     string
+		failures int
+		err      error
+		update   bool // call UpdateStudent, which is never retried
+		calls    int
+		wantErr  error
+	}{
+		{"first try", 0, errBusy, false, 1, nil},
+		{"fails twice", 2, errBusy, false, 3, nil},
+		{"fails every attempt", 5, errBusy, false, 3, errBusy},
+		{"not transient", 5, storage.ErrDuplicateEmail, false, 1, storage.ErrDuplicateEmail},
+		{"write that may have applied", 2, errBusy, true, 1, errBusy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &flaky{failures: tt.failures, err: tt.err}
+			s := storage.Retry(fake, cfg, transient)
+
+			var err error
+			if tt.update {
+				_, err = s.UpdateStudent(context.Background(), 7, types.Student{}, 0)
+			} else {
+				var id int64
+				id, err = s.CreateStudent(context.Background(), types.Student{})
+				if err == nil && id != 7 {
+					t.Errorf("id %d, want 7", id)
+				}
+			}
+
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+			if fake.calls != tt.calls {
+				t.Errorf("%d calls, want %d", fake.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	cfg := config.StorageRetry{Attempts: 10, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	fake := &flaky{failures: 10, err: errBusy}
+	s := storage.Retry(fake, cfg, transient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// the hour of backoff is cut short, with the error of the last attempt
+	start := time.Now()
+	if _, err := s.CreateStudent(ctx, types.Student{}); !errors.Is(err, errBusy) {
+		t.Errorf("error %v, want the transient one", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("took %s after the context ended", took)
+	}
+	if fake.calls > 2 {
+		t.Errorf("%d calls, want at most 2", fake.calls)
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	fake := &flaky{}
+	if s := storage.Retry(fake, config.StorageRetry{Attempts: 1}, transient); s != storage.Storage(fake) {
+		t.Error("attempts 1: Retry wrapped the storage")
+	}
+	if s := storage.Retry(fake, config.StorageRetry{Attempts: 3}, nil); s != storage.Storage(fake) {
+		t.Error("no classification: Retry wrapped the storage")
+	}
+}
@@ -0,0 +1,178 @@
+package sqlite
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → the transaction the audit row is written in
+   - errors       → sql.ErrNoRows (no "before" for a create)
+   - time         → created_at of an event
+   - storage      → actor of the request, JSON snapshots
+   - types        → AuditEvent / Student
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+audited()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Runs change (one UPDATE of the student with this ID) in a
+	    transaction that also records its audit_events row, with
+	    the student as it was before and after.
+	  → Nothing is recorded (and the transaction is rolled back)
+	    when change matched no row.
+
+	RETURN VALUE:
+	  → true if change updated the row and everything committed
+	  → change's error as is, for the caller to map
+*/
+func (s *Sqlite) audited(ctx context.Context, id int64, action string, change func(tx *sql.Tx) (sql.Result, error)) (bool, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, id)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := change(tx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, tx, id, action, before); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// auditSnapshot reads the student with this ID inside tx, soft-deleted or
+// not; nil when there is none.
+func auditSnapshot(ctx context.Context, tx *sql.Tx, id int64) (*types.Student, error) {
+	student, err := scanStudent(tx.QueryRowContext(ctx,
+		"SELECT "+studentColumns+" FROM students WHERE id = ?", id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &student, nil
+}
+
+// recordAudit writes the event of a change of the student with this ID:
+// before as given, after read back inside tx.
+func recordAudit(ctx context.Context, tx *sql.Tx, id int64, action string, before *types.Student) error {
+	after, err := auditSnapshot(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	beforeJSON, err := storage.AuditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := storage.AuditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO audit_events (student_id, action, actor, before, after, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		id, action, storage.ActorFrom(ctx), beforeJSON, afterJSON, time.Now().UTC(),
+	)
+	return err
+}
+
+// recordSummary writes the event of an operation on many students, filed
+// under types.AuditStudentNone with its details.
+func recordSummary(ctx context.Context, tx *sql.Tx, action string, details types.AuditDetails) error {
+	detailsJSON, err := storage.AuditDetailsJSON(&details)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO audit_events (student_id, action, actor, details, created_at) VALUES (?, ?, ?, ?, ?)",
+		types.AuditStudentNone, action, storage.ActorFrom(ctx), detailsJSON, time.Now().UTC(),
+	)
+	return err
+}
+
+/*
+ListAuditEvents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns one page of the audit trail of a student, oldest
+	    first. A student without events (or no student at all)
+	    gives an empty slice, not an error.
+*/
+func (s *Sqlite) ListAuditEvents(ctx context.Context, studentID int64, limit, offset int) ([]types.AuditEvent, error) {
+	rows, err := s.Db.QueryContext(ctx,
+		"SELECT id, student_id, action, actor, before, after, details, created_at FROM audit_events "+
+			"WHERE student_id = ? ORDER BY id LIMIT ? OFFSET ?",
+		studentID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []types.AuditEvent
+	for rows.Next() {
+		var event types.AuditEvent
+		var before, after, details *string
+
+		err := rows.Scan(&event.Id, &event.StudentId, &event.Action, &event.Actor, &before, &after, &details, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if event.Before, err = storage.ParseAuditSnapshot(before); err != nil {
+			return nil, err
+		}
+		if event.After, err = storage.ParseAuditSnapshot(after); err != nil {
+			return nil, err
+		}
+		if event.Details, err = storage.ParseAuditDetails(details); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// CountAuditEvents returns how many events the student with this ID has.
+func (s *Sqlite) CountAuditEvents(ctx context.Context, studentID int64) (int, error) {
+	var count int
+	err := s.Db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM audit_events WHERE student_id = ?", studentID,
+	).Scan(&count)
+
+	return count, err
+}
@@ -0,0 +1,435 @@
+package sqlite
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → transactions of the enrollment checks
+   - errors       → sql.ErrNoRows, driver errors
+   - fmt          → name the enrolled student in the error
+   - strings      → qualify the student columns of the roster JOIN
+   - time         → created_at / updated_at
+   - storage      → sentinel errors shared by all backends
+   - types        → Course / Enrollment
+   - go-sqlite3   → which constraint failed
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/mattn/go-sqlite3"
+)
+
+// courseColumns is the column list of the course SELECTs (FROM courses c),
+// in the order scanCourse expects; enrolled is counted, not stored.
+const courseColumns = "c.id, c.code, c.title, c.capacity, " +
+	"(SELECT COUNT(*) FROM enrollments e WHERE e.course_id = c.id), c.created_at, c.updated_at"
+
+// scanCourse reads one row selected with courseColumns.
+func scanCourse(row rowScanner) (types.Course, error) {
+	var course types.Course
+
+	err := row.Scan(
+		&course.Id, &course.Code, &course.Title, &course.Capacity, &course.Enrolled,
+		&course.CreatedAt, &course.UpdatedAt,
+	)
+
+	return course, err
+}
+
+/*
+CreateCourse()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Inserts one course, created_at and updated_at set to now.
+
+	RETURN VALUE:
+	  → the auto-incremented ID generated by SQLite
+	  → storage.ErrDuplicateCourseCode if the code is taken
+*/
+func (s *Sqlite) CreateCourse(ctx context.Context, course types.Course) (int64, error) {
+	now := time.Now().UTC()
+
+	result, err := s.Db.ExecContext(ctx,
+		"INSERT INTO courses (code, title, capacity, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		course.Code, course.Title, course.Capacity, now, now,
+	)
+	if isUniqueViolation(err) {
+		return 0, storage.ErrDuplicateCourseCode
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetCourseById returns the course with this ID, or storage.ErrCourseNotFound.
+func (s *Sqlite) GetCourseById(ctx context.Context, id int64) (types.Course, error) {
+	course, err := scanCourse(s.Db.QueryRowContext(ctx,
+		"SELECT "+courseColumns+" FROM courses c WHERE c.id = ?", id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Course{}, storage.ErrCourseNotFound
+	}
+
+	return course, err
+}
+
+// ListCourses returns one page of the courses, ordered by ID.
+func (s *Sqlite) ListCourses(ctx context.Context, limit, offset int) ([]types.Course, error) {
+	rows, err := s.Db.QueryContext(ctx,
+		"SELECT "+courseColumns+" FROM courses c ORDER BY c.id LIMIT ? OFFSET ?",
+		limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := make([]types.Course, 0)
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+
+	return courses, rows.Err()
+}
+
+// CountCourses returns how many courses there are.
+func (s *Sqlite) CountCourses(ctx context.Context) (int, error) {
+	var count int
+	err := s.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM courses").Scan(&count)
+
+	return count, err
+}
+
+/*
+UpdateCourse()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Replaces the code, title and capacity of a course,
+	    refreshing updated_at.
+	  → The seats taken are counted in the same transaction
+	    (_txlock=immediate holds the write lock), so no
+	    enrollment can slip in between.
+
+	RETURN VALUE:
+	  → true  if the course was updated
+	  → false if no course has this ID
+	  → storage.ErrCapacityBelowEnrolled if it has more students
+	    than the new capacity
+	  → storage.ErrDuplicateCourseCode if another course has the code
+*/
+func (s *Sqlite) UpdateCourse(ctx context.Context, id int64, course types.Course) (bool, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	var enrolled int
+	err = tx.QueryRowContext(ctx,
+		"SELECT (SELECT COUNT(*) FROM enrollments WHERE course_id = c.id) FROM courses c WHERE c.id = ?", id,
+	).Scan(&enrolled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if course.Capacity < enrolled {
+		return false, fmt.Errorf("%w: %d enrolled", storage.ErrCapacityBelowEnrolled, enrolled)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE courses SET code = ?, title = ?, capacity = ?, updated_at = ? WHERE id = ?",
+		course.Code, course.Title, course.Capacity, time.Now().UTC(), id,
+	)
+	if isUniqueViolation(err) {
+		return false, storage.ErrDuplicateCourseCode
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+/*
+DeleteCourse()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Removes a course for good, with its enrollments, in one
+	    transaction.
+
+	RETURN VALUE:
+	  → true  if the course was deleted
+	  → false if no course has this ID
+*/
+func (s *Sqlite) DeleteCourse(ctx context.Context, id int64) (bool, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM enrollments WHERE course_id = ?", id); err != nil {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM courses WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+/*
+Enroll()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Enrolls the live student studentID in courseID, in one
+	    transaction:
+	      1. the student must exist and not be deleted
+	      2. the course must exist
+	      3. INSERT: the primary key rejects a second enrollment
+	      4. more students than seats now → rolled back, full
+	  → _txlock=immediate serializes the writers, so the count of
+	    step 4 can't miss a concurrent enrollment.
+
+	RETURN VALUE:
+	  → storage.ErrNotFound / ErrCourseNotFound for step 1 / 2
+	  → storage.ErrAlreadyEnrolled, storage.ErrCourseFull
+*/
+func (s *Sqlite) Enroll(ctx context.Context, studentID, courseID int64) error {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	var live bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM students WHERE id = ? AND deleted_at IS NULL)", studentID,
+	).Scan(&live)
+	if err != nil {
+		return err
+	}
+	if !live {
+		return storage.ErrNotFound
+	}
+
+	var capacity int
+	err = tx.QueryRowContext(ctx, "SELECT capacity FROM courses WHERE id = ?", courseID).Scan(&capacity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrCourseNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO enrollments (student_id, course_id, created_at) VALUES (?, ?, ?)",
+		studentID, courseID, time.Now().UTC(),
+	)
+	if isUniqueViolation(err) {
+		return storage.ErrAlreadyEnrolled
+	}
+	if err != nil {
+		return err
+	}
+
+	var enrolled int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM enrollments WHERE course_id = ?", courseID).Scan(&enrolled)
+	if err != nil {
+		return err
+	}
+	if enrolled > capacity {
+		return storage.ErrCourseFull
+	}
+
+	return tx.Commit()
+}
+
+// Unenroll removes the enrollment of studentID in courseID and reports
+// whether there was one.
+func (s *Sqlite) Unenroll(ctx context.Context, studentID, courseID int64) (bool, error) {
+	result, err := s.Db.ExecContext(ctx,
+		"DELETE FROM enrollments WHERE student_id = ? AND course_id = ?", studentID, courseID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ListEnrollments returns the courses studentID is enrolled in, ordered by
+// course ID; empty for a student without any (or no student at all).
+func (s *Sqlite) ListEnrollments(ctx context.Context, studentID int64) ([]types.Enrollment, error) {
+	rows, err := s.Db.QueryContext(ctx,
+		"SELECT e.student_id, c.id, c.code, c.title, e.created_at FROM enrollments e "+
+			"JOIN courses c ON c.id = e.course_id WHERE e.student_id = ? ORDER BY c.id",
+		studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enrollments := make([]types.Enrollment, 0)
+	for rows.Next() {
+		var enrollment types.Enrollment
+		err := rows.Scan(&enrollment.StudentId, &enrollment.CourseId, &enrollment.Code, &enrollment.Title, &enrollment.EnrolledAt)
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, rows.Err()
+}
+
+/*
+ListCourseStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → One page of the roster of a course: the live students
+	    enrolled in it and when, in ONE query joining enrollments
+	    to students.
+	  → sort picks the ORDER BY from rosterOrder (never from the
+	    client's text), ties broken by student ID so pages don't
+	    overlap.
+
+	RETURN VALUE:
+	  → the entries, empty past the last page or for a course
+	    without students (or no course at all: callers check the
+	    course first)
+*/
+func (s *Sqlite) ListCourseStudents(ctx context.Context, courseID int64, sort types.RosterSort, limit, offset int) ([]types.CourseStudent, error) {
+	order, ok := rosterOrder[sort.Field]
+	if !ok {
+		order = rosterOrder[types.RosterSortID]
+	}
+	if sort.Desc {
+		order += " DESC"
+	}
+
+	rows, err := s.Db.QueryContext(ctx,
+		"SELECT "+rosterColumns+" FROM enrollments e JOIN students s ON s.id = e.student_id "+
+			"WHERE e.course_id = ? AND s.deleted_at IS NULL ORDER BY "+order+", s.id LIMIT ? OFFSET ?",
+		courseID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roster := make([]types.CourseStudent, 0)
+	for rows.Next() {
+		var entry types.CourseStudent
+		var uuid sql.NullString
+		var dateOfBirth, deletedAt sql.NullTime
+		var tags []byte
+
+		err := rows.Scan(
+			&entry.Id, &uuid, &entry.Name, &entry.Email, &entry.Age, &dateOfBirth, &entry.Phone, &tags, &entry.Status, &entry.Version,
+			&entry.CreatedAt, &entry.UpdatedAt, &deletedAt, &entry.EnrolledAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.PublicId = storage.PublicID(entry.Id, uuid.String)
+		setDateOfBirth(&entry.Student, dateOfBirth)
+		if entry.Tags, err = storage.ParseTags(tags); err != nil {
+			return nil, err
+		}
+		roster = append(roster, entry)
+	}
+
+	return roster, rows.Err()
+}
+
+// rosterColumns is studentColumns of the students table "s", plus the
+// enrollment's created_at: both tables have a created_at.
+var rosterColumns = "s." + strings.ReplaceAll(studentColumns, ", ", ", s.") + ", e.created_at"
+
+// rosterOrder maps each types.RosterSort field to its ORDER BY expression.
+var rosterOrder = map[string]string{
+	types.RosterSortID:         "s.id",
+	types.RosterSortName:       "LOWER(s.name)",
+	types.RosterSortEmail:      "s.email",
+	types.RosterSortEnrolledAt: "e.created_at",
+}
+
+/*
+releaseEnrollments()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → What a student delete does to the student's enrollments,
+	    inside its transaction (storage.on_student_delete):
+	      - block   → storage.ErrStudentEnrolled if there is any,
+	                  so nothing changes. It carries no ID: the
+	                  row ID is internal (id_scheme: uuid), the
+	                  handler names the student it was asked for
+	      - cascade → they are removed
+*/
+func (s *Sqlite) releaseEnrollments(ctx context.Context, tx *sql.Tx, studentID int64) error {
+	if s.cascadeEnrollments {
+		_, err := tx.ExecContext(ctx, "DELETE FROM enrollments WHERE student_id = ?", studentID)
+		return err
+	}
+
+	var enrolled bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM enrollments WHERE student_id = ?)", studentID,
+	).Scan(&enrolled)
+	if err != nil {
+		return err
+	}
+	if enrolled {
+		return storage.ErrStudentEnrolled
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether err is a UNIQUE or PRIMARY KEY
+// constraint failure. The course statements have two such constraints of
+// their own, so they map it themselves rather than through mapError.
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → passed into every query
+   - time    → created_at / expires_at of a key
+   - storage → IdempotencyRecord
+*/
+import (
+	"context"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+)
+
+/*
+ReserveIdempotencyKey()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Claims key for a new request, in ONE statement so two
+	    concurrent requests with the same key can't both win:
+	      - no row yet       → INSERT, reserved
+	      - row has expired  → ON CONFLICT … DO UPDATE replaces it,
+	                           reserved
+	      - row still live   → the WHERE skips the update (0 rows),
+	                           and the live record is returned
+
+	RETURN VALUE:
+	  → (zero record, true) when the key is now ours
+	  → (existing record, false) otherwise
+*/
+func (s *Sqlite) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, expiresAt time.Time) (storage.IdempotencyRecord, bool, error) {
+	now := time.Now().UTC()
+
+	result, err := s.Db.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (key, request_hash, response, created_at, expires_at)
+		VALUES (?, ?, NULL, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = excluded.request_hash,
+			response = NULL,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at
+		WHERE idempotency_keys.expires_at <= excluded.created_at`,
+		key, requestHash, now, expiresAt.UTC(),
+	)
+	if err != nil {
+		return storage.IdempotencyRecord{}, false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return storage.IdempotencyRecord{}, false, err
+	}
+	if affected > 0 {
+		return storage.IdempotencyRecord{}, true, nil
+	}
+
+	var record storage.IdempotencyRecord
+	err = s.Db.QueryRowContext(ctx,
+		"SELECT request_hash, response FROM idempotency_keys WHERE key = ?",
+		key,
+	).Scan(&record.RequestHash, &record.Response)
+	if err != nil {
+		return storage.IdempotencyRecord{}, false, err
+	}
+
+	return record, false, nil
+}
+
+// CompleteIdempotencyKey stores the recorded response of a reserved key.
+func (s *Sqlite) CompleteIdempotencyKey(ctx context.Context, key string, response []byte) error {
+	_, err := s.Db.ExecContext(ctx, "UPDATE idempotency_keys SET response = ? WHERE key = ?", response, key)
+	return err
+}
+
+// ReleaseIdempotencyKey drops a reservation that never got a response, so a
+// retry with the same key runs the request again.
+func (s *Sqlite) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	_, err := s.Db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = ? AND response IS NULL", key)
+	return err
+}
+
+// PurgeExpiredIdempotencyKeys removes every key expired at now and returns
+// how many there were.
+func (s *Sqlite) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	result, err := s.Db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= ?", now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
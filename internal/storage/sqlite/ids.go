@@ -0,0 +1,100 @@
+package sqlite
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → sql.NullString for the uuid column
+   - errors       → sql.ErrNoRows
+   - time         → the creation time a UUID is made from
+   - storage      → ID parsing, UUIDs, sentinel errors
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+)
+
+// ResolveStudentID implements storage.IDStore: a UUID is looked up in the
+// uuid column (deleted students too), a row ID is only parsed.
+func (s *Sqlite) ResolveStudentID(ctx context.Context, id string) (int64, error) {
+	rowID, uuid, err := storage.ParseStudentID(id, s.uuidIDs)
+	if err != nil || uuid == "" {
+		return rowID, err
+	}
+
+	err = s.Db.QueryRowContext(ctx, "SELECT id FROM students WHERE uuid = ?", uuid).Scan(&rowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrNotFound
+	}
+
+	return rowID, err
+}
+
+// newUUID is the uuid column of a student created at t: a new UUID under
+// storage.id_scheme uuid, NULL under serial.
+func (s *Sqlite) newUUID(t time.Time) sql.NullString {
+	if !s.uuidIDs {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: storage.NewUUID(t), Valid: true}
+}
+
+/*
+assignUUIDs()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Under storage.id_scheme uuid, gives a UUID to every
+	    student that has none yet (created under serial), made
+	    from its created_at, so switching a deployment to uuid
+	    needs no other step. Runs when the backend opens, in one
+	    transaction; a no-op once every student has one.
+	  → Their row IDs stop working in URLs from then on.
+*/
+func (s *Sqlite) assignUUIDs(ctx context.Context) error {
+	if !s.uuidIDs {
+		return nil
+	}
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, created_at FROM students WHERE uuid IS NULL")
+	if err != nil {
+		return err
+	}
+
+	// Read them all first: the tx has only one connection
+	created := make(map[int64]time.Time)
+	for rows.Next() {
+		var id int64
+		var createdAt time.Time
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			rows.Close()
+			return err
+		}
+		created[id] = createdAt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, createdAt := range created {
+		if _, err := tx.ExecContext(ctx, "UPDATE students SET uuid = ? WHERE id = ?", storage.NewUUID(createdAt), id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,15 @@
+-- Initial schema: the students table and one student per email.
+-- IF NOT EXISTS because databases created before migrations existed
+-- already have both (see adoptLegacySchema).
+CREATE TABLE IF NOT EXISTS students (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT,
+	email TEXT,
+	age INTEGER,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Fails on an existing database that already holds duplicate emails;
+-- those have to be cleaned up by hand first.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_email ON students (email);
@@ -0,0 +1,10 @@
+-- Soft delete: DELETE sets deleted_at instead of removing the row.
+ALTER TABLE students ADD COLUMN deleted_at DATETIME;
+
+-- Only live students hold their email, so a deleted student's address
+-- can be reused.
+DROP INDEX IF EXISTS idx_students_email;
+CREATE UNIQUE INDEX idx_students_email ON students (email) WHERE deleted_at IS NULL;
+
+-- Lets the purge find old deleted rows without a full scan.
+CREATE INDEX idx_students_deleted_at ON students (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Idempotency-Key support: one row per key, holding the hash of the first
+-- request and, once it finished, its recorded response.
+CREATE TABLE idempotency_keys (
+	key TEXT PRIMARY KEY,
+	request_hash TEXT NOT NULL,
+	response BLOB,
+	created_at DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,8 @@
+-- Emails are stored lower-cased (types.NormalizeEmail); bring older rows in
+-- line. If two live students only differ by case this fails on the index
+-- below and the migration is rolled back: resolve the duplicates by hand.
+UPDATE students SET email = LOWER(TRIM(email));
+
+-- Uniqueness ignores case even for rows written around the API.
+DROP INDEX IF EXISTS idx_students_email;
+CREATE UNIQUE INDEX idx_students_email ON students (LOWER(email)) WHERE deleted_at IS NULL;
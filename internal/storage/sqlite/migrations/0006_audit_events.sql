@@ -0,0 +1,13 @@
+-- Audit trail: one row per change of a student, written in the same
+-- transaction as the change. No foreign key: the history outlives a purge.
+CREATE TABLE audit_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	student_id INTEGER NOT NULL,
+	action TEXT NOT NULL,
+	actor TEXT NOT NULL,
+	before TEXT,
+	after TEXT,
+	created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_audit_events_student_id ON audit_events (student_id, id);
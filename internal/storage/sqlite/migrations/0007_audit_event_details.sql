@@ -0,0 +1,3 @@
+-- Summary events (a bulk delete) describe many students at once: the filter
+-- and the count go in details, as JSON. NULL for the events of one student.
+ALTER TABLE audit_events ADD COLUMN details TEXT;
@@ -0,0 +1,5 @@
+-- Lifecycle status (types.Status*). Existing rows get the default, active;
+-- the API checks the values and the transitions between them.
+ALTER TABLE students ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+
+CREATE INDEX idx_students_status ON students (status);
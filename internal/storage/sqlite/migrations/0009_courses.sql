@@ -0,0 +1,27 @@
+-- Courses and the students enrolled in them. Codes are stored upper-cased
+-- (types.Course.Normalize), so the plain unique index is case-insensitive.
+-- The seats are counted in the enrollment transaction: capacity is not a
+-- constraint SQL can express.
+CREATE TABLE courses (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	code TEXT NOT NULL,
+	title TEXT NOT NULL,
+	capacity INTEGER NOT NULL,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_courses_code ON courses (code);
+
+-- One row per student and course; the primary key is what makes a second
+-- enrollment fail. Rows are removed explicitly (storage.on_student_delete,
+-- DeleteCourse) rather than by ON DELETE CASCADE, which depends on the
+-- foreign_keys pragma.
+CREATE TABLE enrollments (
+	student_id INTEGER NOT NULL REFERENCES students (id),
+	course_id INTEGER NOT NULL REFERENCES courses (id),
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (student_id, course_id)
+);
+
+CREATE INDEX idx_enrollments_course_id ON enrollments (course_id);
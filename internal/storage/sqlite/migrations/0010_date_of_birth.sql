@@ -0,0 +1,6 @@
+-- Optional date of birth (YYYY-MM-DD); the API derives the age from it when
+-- it is set, and the age filters turn into ranges of this column. DATE makes
+-- the driver hand it back as a time.
+ALTER TABLE students ADD COLUMN date_of_birth DATE;
+
+CREATE INDEX idx_students_date_of_birth ON students (date_of_birth);
@@ -0,0 +1,3 @@
+-- Optional phone number, stored in E.164 ("+14155550123"): the API
+-- normalizes it before it gets here.
+ALTER TABLE students ADD COLUMN phone TEXT;
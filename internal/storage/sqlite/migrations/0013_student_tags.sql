@@ -0,0 +1,4 @@
+-- Tags of a student, a JSON array of normalized tags ('["2025-intake",
+-- "scholarship"]'), sorted and without duplicates: the API normalizes them
+-- before they get here. Filters and stats read them with json_each.
+ALTER TABLE students ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,8 @@
+-- Public ID of a student under storage.id_scheme uuid, lower-case text
+-- ("0190b4c2-6f1e-7c3a-9d2b-5e8f1a2b3c4d"). NULL under serial, where the
+-- row ID is the public one; the storage layer generates them, and fills in
+-- the missing ones when it opens in uuid mode. A UNIQUE index allows any
+-- number of NULLs.
+ALTER TABLE students ADD COLUMN uuid TEXT;
+
+CREATE UNIQUE INDEX idx_students_uuid ON students (uuid);
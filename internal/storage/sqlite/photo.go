@@ -0,0 +1,106 @@
+package sqlite
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query
+   - database/sql → the transaction of a photo upload
+   - errors       → sql.ErrNoRows
+   - storage      → sentinel errors shared by all backends
+   - types        → Photo
+*/
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+PutStudentPhoto()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Stores the photo of the live student id in
+	    student_photos, replacing the one it had, in one
+	    transaction: a concurrent delete of the student can't
+	    leave an orphan photo behind.
+
+	RETURN VALUE:
+	  → true when the student had no photo yet
+	  → storage.ErrNotFound for a missing or deleted student
+*/
+func (s *Sqlite) PutStudentPhoto(ctx context.Context, id int64, photo types.Photo) (bool, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	var live, existed bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM students WHERE id = ? AND deleted_at IS NULL), "+
+			"EXISTS (SELECT 1 FROM student_photos WHERE student_id = ?)",
+		id, id,
+	).Scan(&live, &existed)
+	if err != nil {
+		return false, err
+	}
+	if !live {
+		return false, storage.ErrNotFound
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO student_photos (student_id, content_type, data, etag, updated_at) VALUES (?, ?, ?, ?, ?) "+
+			"ON CONFLICT (student_id) DO UPDATE SET "+
+			"content_type = excluded.content_type, data = excluded.data, etag = excluded.etag, updated_at = excluded.updated_at",
+		id, photo.ContentType, photo.Data, photo.ETag, photo.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return !existed, tx.Commit()
+}
+
+// GetStudentPhoto returns the photo of the live student id: one LEFT JOIN
+// tells a missing student (storage.ErrNotFound) from one without a photo
+// (storage.ErrPhotoNotFound).
+func (s *Sqlite) GetStudentPhoto(ctx context.Context, id int64) (types.Photo, error) {
+	var contentType, etag sql.NullString
+	var updatedAt sql.NullTime
+	var photo types.Photo
+
+	err := s.Db.QueryRowContext(ctx,
+		"SELECT p.content_type, p.data, p.etag, p.updated_at FROM students s "+
+			"LEFT JOIN student_photos p ON p.student_id = s.id WHERE s.id = ? AND s.deleted_at IS NULL",
+		id,
+	).Scan(&contentType, &photo.Data, &etag, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Photo{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return types.Photo{}, err
+	}
+	if !contentType.Valid {
+		return types.Photo{}, storage.ErrPhotoNotFound
+	}
+
+	photo.ContentType = contentType.String
+	photo.ETag = etag.String
+	photo.UpdatedAt = updatedAt.Time
+
+	return photo, nil
+}
+
+// deletePhoto removes the photo of a student being deleted, inside its
+// transaction. Explicit like releaseEnrollments, rather than ON DELETE
+// CASCADE: a soft delete removes no row.
+func deletePhoto(ctx context.Context, tx *sql.Tx, studentID int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM student_photos WHERE student_id = ?", studentID)
+	return err
+}
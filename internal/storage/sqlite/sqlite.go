@@ -0,0 +1,1287 @@
+package sqlite // sqlite package implements storage.Storage on top of a SQLite file
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context      → passed into every query (QueryContext/ExecContext)
+   - database/sql → Go's generic SQL API (connection pool, queries, rows)
+   - embed        → the migrations/*.sql files are compiled into the binary
+   - errors       → map sql.ErrNoRows to storage.ErrNotFound
+   - fmt          → wrap schema setup and status transition errors
+   - io/fs        → hand the embedded migrations to migrate.Up
+   - net/url      → connection parameters (pragmas) in the DSN
+   - strconv      → busy timeout in milliseconds
+   - strings      → join the SET clauses of a partial update
+   - time         → created_at / updated_at
+   - config       → we need StoragePath to know where the .db file lives
+   - storage      → sentinel errors shared by all backends
+   - migrate      → applies the numbered schema migrations
+   - types        → Student struct returned to the handlers
+   - go-sqlite3   → registers the "sqlite3" driver with database/sql;
+                    its Error type tells us which constraint failed
+*/
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/migrate"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/mattn/go-sqlite3"
+)
+
+/*
+Sqlite STRUCT
+-------------------------------------------------------------
+  - Holds the *sql.DB connection pool.
+  - cascadeEnrollments: storage.on_student_delete is
+    "cascade" (see releaseEnrollments).
+  - uuidIDs: storage.id_scheme is "uuid" (see ids.go).
+  - Implements every method of storage.Storage.
+*/
+type Sqlite struct {
+	Db *sql.DB
+
+	cascadeEnrollments bool
+	uuidIDs            bool
+}
+
+// emailIndex is the unique index on LOWER(email) of the live students; SQLite
+// names it in the message of its violation ("UNIQUE constraint failed:
+// index 'idx_students_email'").
+const emailIndex = "idx_students_email"
+
+// studentColumns is the column list every SELECT uses, in the order
+// scanStudent expects.
+const studentColumns = "id, uuid, name, email, age, date_of_birth, phone, tags, status, version, created_at, updated_at, deleted_at"
+
+// ageExpr is the age of today in SQL: derived from date_of_birth like
+// types.AgeOn ('now' is UTC), the stored age for students without one.
+const ageExpr = "CASE WHEN date_of_birth IS NULL THEN age ELSE " +
+	"CAST(strftime('%Y', 'now') AS INTEGER) - CAST(strftime('%Y', date_of_birth) AS INTEGER) - " +
+	"(strftime('%m-%d', 'now') < strftime('%m-%d', date_of_birth)) END"
+
+// rowScanner is what *sql.Row and *sql.Rows have in common.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanStudent reads one row selected with studentColumns. The age of a
+// student with a date of birth is the one of today, not the stored one.
+func scanStudent(row rowScanner) (types.Student, error) {
+	var student types.Student
+	var uuid sql.NullString
+	var dateOfBirth, deletedAt sql.NullTime
+	var tags []byte
+
+	err := row.Scan(
+		&student.Id, &uuid, &student.Name, &student.Email, &student.Age, &dateOfBirth, &student.Phone, &tags, &student.Status, &student.Version,
+		&student.CreatedAt, &student.UpdatedAt, &deletedAt,
+	)
+	if err != nil {
+		return student, err
+	}
+
+	student.PublicId = storage.PublicID(student.Id, uuid.String)
+	setDateOfBirth(&student, dateOfBirth)
+	if deletedAt.Valid {
+		student.DeletedAt = &deletedAt.Time
+	}
+
+	student.Tags, err = storage.ParseTags(tags)
+	return student, err
+}
+
+// setDateOfBirth puts a scanned date_of_birth (the DATE column type makes
+// the driver parse it) into student, and derives the age from it.
+func setDateOfBirth(student *types.Student, dateOfBirth sql.NullTime) {
+	if !dateOfBirth.Valid {
+		return
+	}
+
+	date := dateOfBirth.Time.Format(time.DateOnly)
+	student.DateOfBirth = &date
+	student.DeriveAge(time.Now())
+}
+
+/*
+New()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Opens (or creates) the SQLite file at cfg.StoragePath.
+	  → Applies the pending schema migrations (migrations/*.sql).
+
+	CONCURRENCY:
+	  → Every connection of the pool gets the storage.sqlite
+	    pragmas (see dsn): with WAL, reads run in parallel with
+	    the one writer SQLite allows, and busy_timeout makes a
+	    second writer wait for the lock instead of failing.
+	  → Transactions start with BEGIN IMMEDIATE (_txlock), taking
+	    the write lock up front: a DEFERRED one that upgrades
+	    later can fail with SQLITE_BUSY without ever waiting.
+	  → The pool is not limited to one connection, so reads are
+	    not queued behind writes; concurrency.max_writes caps the
+	    writers when busy_timeout isn't enough. storage.max_open_conns
+	    / max_idle_conns / conn_max_lifetime size it.
+
+	RETURN VALUE:
+	  → *Sqlite ready to be used as storage.Storage
+	  → error if the file can't be opened or a migration fails
+*/
+func New(cfg *config.Config) (*Sqlite, error) {
+
+	// sql.Open does NOT connect yet, it only prepares the pool
+	db, err := sql.Open("sqlite3", dsn(cfg.StoragePath, cfg.Storage.SQLite))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.Storage.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Storage.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Storage.ConnMaxLifetime)
+
+	if err := adoptLegacySchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Create or upgrade the schema (migrations/*.sql)
+	if err := migrate.Up(context.Background(), db, migrationFiles()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &Sqlite{
+		Db:                 db,
+		cascadeEnrollments: cfg.Storage.OnStudentDelete == config.OnDeleteCascade,
+		uuidIDs:            cfg.Storage.IDScheme == config.IDSchemeUUID,
+	}
+
+	// Students created under serial get their UUID now
+	if err := s.assignUUIDs(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// dsn appends the pragmas to path, as go-sqlite3 connection parameters: the
+// driver runs them on every new connection, which a PRAGMA statement on the
+// pool (one connection) would not.
+func dsn(path string, cfg config.SQLite) string {
+	params := url.Values{}
+	params.Set("_journal_mode", strings.ToUpper(cfg.JournalMode))
+	params.Set("_synchronous", strings.ToUpper(cfg.Synchronous))
+	params.Set("_foreign_keys", strconv.FormatBool(cfg.ForeignKeys))
+	params.Set("_busy_timeout", strconv.FormatInt(cfg.BusyTimeout.Milliseconds(), 10))
+	params.Set("_txlock", "immediate")
+
+	return path + "?" + params.Encode()
+}
+
+// migrations holds the numbered schema files applied by migrate.Up.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationFiles returns the migrations directory as the root of an fs.FS.
+func migrationFiles() fs.FS {
+	files, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		// Only fails for an invalid path, which is a constant here
+		panic(err)
+	}
+
+	return files
+}
+
+/*
+adoptLegacySchema()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Databases created before migrations existed have a
+	    students table but no schema_migrations. Some of them
+	    are also missing the timestamp columns.
+	  → This adds those columns, so the IF NOT EXISTS statements
+	    of migration 1 match the table and it is simply recorded
+	    as applied.
+	  → No-op for new databases and for databases that already
+	    track their version.
+*/
+func adoptLegacySchema(db *sql.DB) error {
+	var legacy bool
+	err := db.QueryRow(`SELECT
+		EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'students') AND
+		NOT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')`,
+	).Scan(&legacy)
+	if err != nil || !legacy {
+		return err
+	}
+
+	for _, column := range []string{"created_at", "updated_at"} {
+		if err := addTimestampColumn(db, column); err != nil {
+			return fmt.Errorf("add %s column: %w", column, err)
+		}
+	}
+
+	return nil
+}
+
+/*
+addTimestampColumn()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Adds a DATETIME column to an existing students table and
+	    fills it for the rows already there.
+	  → No-op when the column exists.
+
+	WHY NOT A DEFAULT?
+	  → SQLite's ALTER TABLE ADD COLUMN only accepts constant
+	    defaults, so CURRENT_TIMESTAMP has to be an UPDATE.
+*/
+func addTimestampColumn(db *sql.DB, column string) error {
+	var exists int
+	err := db.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('students') WHERE name = ?",
+		column,
+	).Scan(&exists)
+	if err != nil || exists > 0 {
+		return err
+	}
+
+	// column comes from our own code, never from a client
+	if _, err := db.Exec("ALTER TABLE students ADD COLUMN " + column + " DATETIME"); err != nil {
+		return err
+	}
+
+	_, err = db.Exec("UPDATE students SET " + column + " = CURRENT_TIMESTAMP")
+	return err
+}
+
+/*
+CreateStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Inserts one student row.
+	  → created_at and updated_at are both set to now (UTC).
+	  → The "create" audit event is written in the same
+	    transaction.
+
+	RETURN VALUE:
+	  → the auto-incremented ID generated by SQLite
+*/
+func (s *Sqlite) CreateStudent(ctx context.Context, student types.Student) (int64, error) {
+
+	now := time.Now().UTC()
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// "?" placeholders → values are sent separately, never concatenated (no SQL injection)
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO students (uuid, name, email, age, date_of_birth, phone, tags, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		s.newUUID(now), student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags), student.Status, now, now,
+	)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := recordAudit(ctx, tx, id, types.AuditCreate, nil); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+/*
+UpsertStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Create-or-update by email, the natural key: one
+	    INSERT … ON CONFLICT on the unique index of the live
+	    students' LOWER(email). A new email inserts the student;
+	    a known one updates its name, age, date of birth, phone
+	    and tags (email, status, created_at and ID stay), bumping
+	    version.
+	  → The conflict is resolved by SQLite itself, so concurrent
+	    syncs of the same email can't fail with a duplicate-key
+	    error: one inserts, the others update.
+	  → Which one happened is told by the version RETURNING
+	    gives back: 1 only for a fresh row. The "create" or
+	    "update" audit event is written in the same transaction
+	    (writers are serialized by _txlock=immediate, so the
+	    "before" read first is still current).
+
+	RETURN VALUE:
+	  → the student's ID, and true when it was created
+*/
+func (s *Sqlite) UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var before *types.Student
+	current, err := scanStudent(tx.QueryRowContext(ctx,
+		"SELECT "+studentColumns+" FROM students WHERE LOWER(email) = LOWER(?) AND deleted_at IS NULL", student.Email,
+	))
+	switch {
+	case err == nil:
+		before = &current
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, false, err
+	}
+
+	var id int64
+	var version int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO students (uuid, name, email, age, date_of_birth, phone, tags, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+			"ON CONFLICT (LOWER(email)) WHERE deleted_at IS NULL DO UPDATE SET "+
+			"name = excluded.name, age = excluded.age, date_of_birth = excluded.date_of_birth, phone = excluded.phone, tags = excluded.tags, "+
+			"updated_at = excluded.updated_at, version = version + 1 "+
+			"RETURNING id, version",
+		s.newUUID(now), student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags), student.Status, now, now,
+	).Scan(&id, &version)
+	if err != nil {
+		return 0, false, mapError(err)
+	}
+
+	created := version == 1
+	action := types.AuditUpdate
+	if created {
+		action, before = types.AuditCreate, nil
+	}
+
+	if err := recordAudit(ctx, tx, id, action, before); err != nil {
+		return 0, false, err
+	}
+
+	return id, created, tx.Commit()
+}
+
+/*
+CreateStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Inserts many students inside ONE transaction, reusing a
+	    prepared statement (much faster than N separate inserts).
+
+	PER-ITEM FAILURES:
+	  → atomic=false: a failing INSERT (e.g. duplicate email)
+	    only undoes that statement; SQLite keeps the transaction
+	    open, so the other rows are still committed.
+	  → atomic=true: the first failure rolls everything back and
+	    every other item is reported as storage.ErrBatchAborted.
+	  → Every created row gets its "create" audit event in the
+	    same transaction; failing to write one fails the batch.
+
+	RETURN VALUE:
+	  → one BulkResult per input student, in order
+	  → error only when the transaction (or the audit) fails
+*/
+func (s *Sqlite) CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]storage.BulkResult, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO students (uuid, name, email, age, date_of_birth, phone, tags, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	results := make([]storage.BulkResult, len(students))
+
+	for i, student := range students {
+		uuid := s.newUUID(now)
+		result, err := stmt.ExecContext(ctx, uuid, student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags), student.Status, now, now)
+		if err == nil {
+			results[i].Id, err = result.LastInsertId()
+		}
+		if err == nil {
+			results[i].PublicId = storage.PublicID(results[i].Id, uuid.String)
+			if err := recordAudit(ctx, tx, results[i].Id, types.AuditCreate, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		results[i].Err = mapError(err)
+
+		if atomic {
+			for j := range results {
+				if j != i {
+					results[j] = storage.BulkResult{Err: storage.ErrBatchAborted}
+				}
+			}
+			return results, nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+/*
+GetStudentById()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns the student with this ID.
+
+	ERRORS:
+	  → storage.ErrNotFound when no row matches or the student
+	    is soft-deleted
+*/
+func (s *Sqlite) GetStudentById(ctx context.Context, id int64) (types.Student, error) {
+	student, err := scanStudent(s.Db.QueryRowContext(ctx,
+		"SELECT "+studentColumns+" FROM students WHERE id = ? AND deleted_at IS NULL",
+		id,
+	))
+
+	// QueryRow reports "no row" only when we Scan
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Student{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return types.Student{}, err
+	}
+
+	return student, nil
+}
+
+/*
+ListStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns one page of the students matching filter,
+	    ordered by ID.
+
+	PARAMETERS:
+	  - filter → WHERE conditions, see filterClause
+	  - limit  → maximum number of rows to return
+	  - offset → number of rows to skip
+*/
+func (s *Sqlite) ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error) {
+	where, args := filterClause(filter)
+	args = append(args, limit, offset)
+
+	return s.queryStudents(ctx,
+		"SELECT "+studentColumns+" FROM students"+where+" ORDER BY id LIMIT ? OFFSET ?",
+		args...,
+	)
+}
+
+/*
+ListStudentsAfter()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Cursor pagination: the students matching filter whose
+	    ID is greater than afterID, ordered by ID.
+	  → "WHERE id > ?" uses the primary key index, so every page
+	    costs the same; OFFSET has to walk all skipped rows and
+	    shifts when rows are inserted mid-scan.
+*/
+func (s *Sqlite) ListStudentsAfter(ctx context.Context, filter types.StudentFilter, afterID int64, limit int) ([]types.Student, error) {
+	where, args := filterClause(filter)
+
+	if where == "" {
+		where = " WHERE id > ?"
+	} else {
+		where += " AND id > ?"
+	}
+	args = append(args, afterID, limit)
+
+	return s.queryStudents(ctx,
+		"SELECT "+studentColumns+" FROM students"+where+" ORDER BY id LIMIT ?",
+		args...,
+	)
+}
+
+/*
+FindStudentsByEmailOrName()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The live students whose email is one of emails or whose
+	    name is one of names (both case-insensitive), ordered by
+	    ID: the candidates of a duplicate check.
+	  → One query with two IN lists, whatever the number of
+	    entries checked; LOWER(email) is what the unique index
+	    is built on. The caller caps the list sizes (SQLite
+	    allows 32766 parameters).
+
+	NOTES:
+	  → SQLite's LOWER only folds ASCII: "ÉMILE" doesn't match
+	    "émile" here (it does on Postgres and in memory).
+*/
+func (s *Sqlite) FindStudentsByEmailOrName(ctx context.Context, emails, names []string) ([]types.Student, error) {
+	var conds []string
+	var args []any
+
+	if len(emails) > 0 {
+		conds = append(conds, "LOWER(email) IN ("+placeholders(len(emails))+")")
+		for _, email := range emails {
+			args = append(args, strings.ToLower(email))
+		}
+	}
+	if len(names) > 0 {
+		conds = append(conds, "LOWER(name) IN ("+placeholders(len(names))+")")
+		for _, name := range names {
+			args = append(args, strings.ToLower(name))
+		}
+	}
+
+	if len(conds) == 0 {
+		return make([]types.Student, 0), nil
+	}
+
+	return s.queryStudents(ctx,
+		"SELECT "+studentColumns+" FROM students WHERE deleted_at IS NULL AND ("+strings.Join(conds, " OR ")+") ORDER BY id",
+		args...,
+	)
+}
+
+// placeholders returns "?, ?, …" for n parameters.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+/*
+ForEachStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Streams every student matching filter (ordered by ID)
+	    into fn, one row at a time, so an export of the whole
+	    table never holds more than one student in memory.
+	  → The first error from fn stops the scan and is returned.
+*/
+func (s *Sqlite) ForEachStudent(ctx context.Context, filter types.StudentFilter, fn func(types.Student) error) error {
+	where, args := filterClause(filter)
+
+	rows, err := s.Db.QueryContext(ctx,
+		"SELECT "+studentColumns+" FROM students"+where+" ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(student); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// queryStudents runs a SELECT of studentColumns and scans every row.
+// Always returns a non-nil slice so an empty page is encoded as [].
+func (s *Sqlite) queryStudents(ctx context.Context, query string, args ...any) ([]types.Student, error) {
+	rows, err := s.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := make([]types.Student, 0)
+
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		students = append(students, student)
+	}
+
+	// rows.Err reports any error hit while iterating (e.g. connection dropped)
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
+
+/*
+CountStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Counts every student matching filter, ignoring paging.
+	  → Uses the same filterClause as ListStudents, so the total
+	    always agrees with the rows the list can return.
+*/
+func (s *Sqlite) CountStudents(ctx context.Context, filter types.StudentFilter) (int, error) {
+	where, args := filterClause(filter)
+
+	var total int
+	err := s.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM students"+where, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+/*
+StudentStats()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Aggregates of the live students, in five queries that
+	    return a handful of rows whatever the size of the table:
+	      1. COUNT / AVG / MIN / MAX over everyone
+	      2. GROUP BY status
+	      3. GROUP BY tag (json_each unnests the arrays)
+	      4. GROUP BY age bucket
+	      5. GROUP BY creation day, from since on
+
+	NOTES:
+	  → Ages are ageExpr, today's age for the students with a
+	    date of birth.
+	  → created_at is stored as "YYYY-MM-DD HH:MM:SS…" in UTC, so
+	    its first 10 characters are the day.
+*/
+func (s *Sqlite) StudentStats(ctx context.Context, since time.Time) (types.StudentStats, error) {
+	var stats types.StudentStats
+
+	err := s.Db.QueryRowContext(ctx,
+		"SELECT COUNT(*), COALESCE(AVG("+ageExpr+"), 0), COALESCE(MIN("+ageExpr+"), 0), COALESCE(MAX("+ageExpr+"), 0) "+
+			"FROM students WHERE deleted_at IS NULL",
+	).Scan(&stats.Total, &stats.Age.Average, &stats.Age.Min, &stats.Age.Max)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+
+	statuses, err := s.Db.QueryContext(ctx,
+		"SELECT status, COUNT(*) FROM students WHERE deleted_at IS NULL GROUP BY status ORDER BY status",
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer statuses.Close()
+
+	for statuses.Next() {
+		var status types.StatusCount
+		if err := statuses.Scan(&status.Status, &status.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		stats.ByStatus = append(stats.ByStatus, status)
+	}
+	if err := statuses.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	tags, err := s.Db.QueryContext(ctx,
+		"SELECT tag.value, COUNT(*) AS count FROM students, json_each(students.tags) AS tag "+
+			"WHERE deleted_at IS NULL GROUP BY tag.value ORDER BY count DESC, tag.value",
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer tags.Close()
+
+	for tags.Next() {
+		var tag types.TagCount
+		if err := tags.Scan(&tag.Tag, &tag.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		stats.ByTag = append(stats.ByTag, tag)
+	}
+	if err := tags.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	rows, err := s.Db.QueryContext(ctx,
+		"SELECT ("+ageExpr+" - 1) / ? * ? + 1 AS bucket, COUNT(*) FROM students WHERE deleted_at IS NULL GROUP BY bucket ORDER BY bucket",
+		types.AgeBucketWidth, types.AgeBucketWidth,
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket types.AgeBucket
+		if err := rows.Scan(&bucket.From, &bucket.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		bucket.To = bucket.From + types.AgeBucketWidth - 1
+		stats.AgeBuckets = append(stats.AgeBuckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return types.StudentStats{}, err
+	}
+
+	days, err := s.Db.QueryContext(ctx,
+		"SELECT substr(created_at, 1, 10) AS day, COUNT(*) FROM students WHERE deleted_at IS NULL AND created_at >= ? GROUP BY day ORDER BY day",
+		since.UTC(),
+	)
+	if err != nil {
+		return types.StudentStats{}, err
+	}
+	defer days.Close()
+
+	for days.Next() {
+		var day types.DayCount
+		if err := days.Scan(&day.Date, &day.Count); err != nil {
+			return types.StudentStats{}, err
+		}
+		stats.CreatedPerDay = append(stats.CreatedPerDay, day)
+	}
+
+	return stats, days.Err()
+}
+
+/*
+filterClause()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Turns a StudentFilter into " WHERE a AND b …" plus the
+	    matching "?" arguments ("" and nil when nothing is set).
+
+	HOW THE CLAUSE IS BUILT:
+	  → Like PatchStudent: the SQL text comes from this code, the
+	    user's values only ever travel as arguments.
+	  → name and search terms use LIKE, which is case-insensitive
+	    for ASCII in SQLite; % and _ typed by the user are escaped
+	    so they match literally.
+	  → Each search term is its own (name OR email) condition, so
+	    "john gmail" finds John whose email is at gmail.
+	  → min_age / max_age compare the stored age of students
+	    without a date of birth, and turn into a date range for
+	    the others (types.BornBy): age >= 18 is "born on or
+	    before today 18 years ago", which also uses the index.
+	  → Each tag is its own EXISTS over json_each(tags), so a
+	    student must have all of them.
+	  → Soft-deleted rows are excluded unless IncludeDeleted.
+*/
+func filterClause(filter types.StudentFilter) (string, []any) {
+	var conds []string
+	var args []any
+
+	if !filter.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+
+	if filter.Name != "" {
+		conds = append(conds, `name LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+likeEscaper.Replace(filter.Name)+"%")
+	}
+	if filter.Email != "" {
+		conds = append(conds, "LOWER(email) = LOWER(?)")
+		args = append(args, filter.Email)
+	}
+	if filter.EmailDomain != "" {
+		conds = append(conds, `LOWER(email) LIKE ? ESCAPE '\'`)
+		args = append(args, "%@"+likeEscaper.Replace(strings.ToLower(filter.EmailDomain)))
+	}
+	if filter.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	now := time.Now()
+	if filter.MinAge != nil {
+		conds = append(conds, "((date_of_birth IS NULL AND age >= ?) OR date_of_birth <= ?)")
+		args = append(args, *filter.MinAge, types.BornBy(*filter.MinAge, now).Format(time.DateOnly))
+	}
+	if filter.MaxAge != nil {
+		conds = append(conds, "((date_of_birth IS NULL AND age <= ?) OR date_of_birth > ?)")
+		args = append(args, *filter.MaxAge, types.BornBy(*filter.MaxAge+1, now).Format(time.DateOnly))
+	}
+	for _, term := range filter.Terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		conds = append(conds, `(name LIKE ? ESCAPE '\' OR email LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	for _, tag := range filter.Tags {
+		conds = append(conds, "EXISTS (SELECT 1 FROM json_each(students.tags) WHERE value = ?)")
+		args = append(args, tag)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// likeEscaper escapes LIKE wildcards (and the escape character itself).
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+/*
+UpdateStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Replaces name, email, age, date of birth, phone and
+	    tags of the student with this ID, refreshes updated_at and
+	    increments version; created_at is never touched.
+	  → version > 0 makes it conditional ("AND version = ?"), so
+	    two clients editing the same version can't both win.
+	  → Recorded as an "update" audit event (see audited).
+
+	RETURN VALUE:
+	  → true  if a row was updated
+	  → false if no live student has this ID (handler turns it into 404)
+	  → storage.ErrVersionConflict if it is no longer at version
+*/
+func (s *Sqlite) UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error) {
+
+	// audited tells us whether the WHERE clause matched anything
+	updated, err := s.audited(ctx, id, types.AuditUpdate, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			"UPDATE students SET name = ?, email = ?, age = ?, date_of_birth = ?, phone = ?, tags = ?, updated_at = ?, version = version + 1 "+
+				"WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)",
+			student.Name, student.Email, student.Age, student.DateOfBirth, student.Phone, storage.TagsJSON(student.Tags),
+			time.Now().UTC(), id, version, version,
+		)
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	if !updated && version > 0 {
+		return false, s.versionMiss(ctx, id)
+	}
+
+	return updated, nil
+}
+
+/*
+versionMiss()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → A conditional update matched no row: either the student
+	    is gone (nil → the caller reports false / 404) or it has
+	    moved on to another version (storage.ErrVersionConflict).
+*/
+func (s *Sqlite) versionMiss(ctx context.Context, id int64) error {
+	var exists bool
+	err := s.Db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM students WHERE id = ? AND deleted_at IS NULL)",
+		id,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return storage.ErrVersionConflict
+	}
+
+	return nil
+}
+
+/*
+PatchStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Updates only the columns whose patch field is non-nil,
+	    plus updated_at and version (same rules as UpdateStudent).
+
+	HOW THE QUERY IS BUILT:
+	  → Column names come from this code (never from the client),
+	    values are always "?" placeholders → still injection safe.
+	  → {name, age} becomes:
+	      UPDATE students SET name = ?, age = ?, updated_at = ?, version = version + 1
+	      WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)
+*/
+func (s *Sqlite) PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error) {
+	var sets []string
+	var args []any
+
+	if patch.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *patch.Name)
+	}
+	if patch.Email != nil {
+		sets = append(sets, "email = ?")
+		args = append(args, *patch.Email)
+	}
+	// a date of birth comes with its age (StudentPatch.Normalize);
+	// an age alone clears the date of birth
+	if patch.Age != nil {
+		sets = append(sets, "age = ?", "date_of_birth = ?")
+		args = append(args, *patch.Age, patch.DateOfBirth)
+	}
+	if patch.Phone != nil {
+		sets = append(sets, "phone = ?")
+		args = append(args, *patch.Phone)
+	}
+	if patch.Tags != nil {
+		sets = append(sets, "tags = ?")
+		args = append(args, storage.TagsJSON(*patch.Tags))
+	}
+
+	// Nothing to change; the handler rejects {} before we get here
+	if len(sets) == 0 {
+		return false, errors.New("no fields to update")
+	}
+
+	sets = append(sets, "updated_at = ?", "version = version + 1")
+	args = append(args, time.Now().UTC(), id, version, version)
+
+	updated, err := s.audited(ctx, id, types.AuditUpdate, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			"UPDATE students SET "+strings.Join(sets, ", ")+
+				" WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)",
+			args...,
+		)
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	if !updated && version > 0 {
+		return false, s.versionMiss(ctx, id)
+	}
+
+	return updated, nil
+}
+
+/*
+SetStudentStatus()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Moves the live student with this ID to status, refreshing
+	    updated_at and incrementing version, if its current
+	    status may change to it (types.CanChangeStatus).
+	  → The check and the update run in one transaction, whose
+	    write lock (_txlock=immediate) keeps another change from
+	    slipping in between. Recorded as an "update" audit event.
+
+	RETURN VALUE:
+	  → true  if the status was changed
+	  → false if no live student has this ID
+	  → storage.ErrInvalidTransition (wrapped, naming both
+	    statuses) if the transition is not allowed
+*/
+func (s *Sqlite) SetStudentStatus(ctx context.Context, id int64, status string) (bool, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, id)
+	if err != nil || before == nil || before.DeletedAt != nil {
+		return false, err
+	}
+
+	if !types.CanChangeStatus(before.Status, status) {
+		return false, fmt.Errorf("%w: %s → %s", storage.ErrInvalidTransition, before.Status, status)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE students SET status = ?, updated_at = ?, version = version + 1 WHERE id = ?",
+		status, time.Now().UTC(), id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, tx, id, types.AuditUpdate, before); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+/*
+DeleteStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Soft-deletes the student with this ID: the row stays, with
+	    deleted_at set, until PurgeDeletedStudents removes it.
+	  → Recorded as a "delete" audit event.
+	  → Its enrollments block the delete or go with it, see
+	    releaseEnrollments; its photo goes with it.
+
+	RETURN VALUE:
+	  → true  if a live student was deleted
+	  → false if no student has this ID or it is already deleted
+	  → storage.ErrStudentEnrolled in block mode
+*/
+func (s *Sqlite) DeleteStudent(ctx context.Context, id int64) (bool, error) {
+
+	return s.audited(ctx, id, types.AuditDelete, func(tx *sql.Tx) (sql.Result, error) {
+		if err := s.releaseEnrollments(ctx, tx, id); err != nil {
+			return nil, err
+		}
+		if err := deletePhoto(ctx, tx, id); err != nil {
+			return nil, err
+		}
+		return tx.ExecContext(ctx,
+			"UPDATE students SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL",
+			time.Now().UTC(), id,
+		)
+	})
+}
+
+/*
+DeleteStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Soft-deletes every live student matching filter, in one
+	    transaction: all of them or none.
+	  → Each one gets its "delete" audit event, and the whole
+	    operation one "bulk_delete" summary (the filter and the
+	    count) under types.AuditStudentNone.
+	  → In block mode, one enrolled student fails the whole
+	    operation (see releaseEnrollments). Their photos are
+	    removed.
+
+	RETURN VALUE:
+	  → the students deleted, as they were just before (ordered
+	    by ID); empty when nothing matched
+	  → an error for an empty filter: deleting everything is
+	    never what a caller meant
+
+	NOTES:
+	  → _txlock=immediate takes the write lock when the
+	    transaction begins, so the rows selected are exactly the
+	    rows deleted.
+*/
+func (s *Sqlite) DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error) {
+	if filter.IsEmpty() {
+		return nil, storage.ErrEmptyFilter
+	}
+	filter.IncludeDeleted = false
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	// STEP 1: the matching students, as they are before the delete
+	where, args := filterClause(filter)
+	rows, err := tx.QueryContext(ctx, "SELECT "+studentColumns+" FROM students"+where+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]types.Student, 0)
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deleted = append(deleted, student)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// STEP 2: delete and audit them one by one
+	now := time.Now().UTC()
+	for i := range deleted {
+		if err := s.releaseEnrollments(ctx, tx, deleted[i].Id); err != nil {
+			return nil, err
+		}
+		if err := deletePhoto(ctx, tx, deleted[i].Id); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE students SET deleted_at = ? WHERE id = ?", now, deleted[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordAudit(ctx, tx, deleted[i].Id, types.AuditDelete, &deleted[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// STEP 3: the summary of the operation
+	details := types.AuditDetails{Filter: filter, Count: len(deleted)}
+	if err := recordSummary(ctx, tx, types.AuditBulkDelete, details); err != nil {
+		return nil, err
+	}
+
+	return deleted, tx.Commit()
+}
+
+/*
+RestoreStudent()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Undoes DeleteStudent: clears deleted_at, refreshes
+	    updated_at and increments version.
+	  → Recorded as a "restore" audit event.
+
+	RETURN VALUE:
+	  → true  if a soft-deleted student was restored
+	  → false if no student has this ID or it is not deleted
+	  → storage.ErrDuplicateEmail if a live student took the
+	    email in the meantime
+*/
+func (s *Sqlite) RestoreStudent(ctx context.Context, id int64) (bool, error) {
+
+	restored, err := s.audited(ctx, id, types.AuditRestore, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			"UPDATE students SET deleted_at = NULL, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL",
+			time.Now().UTC(), id,
+		)
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return restored, nil
+}
+
+/*
+PurgeDeletedStudents()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Permanently removes the students soft-deleted before
+	    "before" (now minus the retention period), at most
+	    limit of them, longest deleted first: one batch of
+	    PurgeInBatches, short enough not to hold the write lock
+	    for long.
+	  → Their photos and enrollments went with the soft delete;
+	    their audit events stay.
+
+	RETURN VALUE:
+	  → number of rows removed; fewer than limit means none is
+	    left
+*/
+func (s *Sqlite) PurgeDeletedStudents(ctx context.Context, before time.Time, limit int) (int64, error) {
+
+	result, err := s.Db.ExecContext(ctx,
+		"DELETE FROM students WHERE id IN ("+
+			"SELECT id FROM students WHERE deleted_at IS NOT NULL AND deleted_at < ? ORDER BY deleted_at, id LIMIT ?)",
+		before.UTC(), limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+/*
+Ping()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Verifies a connection to the database file can be used.
+	  → Called by /ready; ctx carries the probe's short timeout.
+*/
+func (s *Sqlite) Ping(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
+/*
+Close()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Closes the connection pool, at shutdown. When the last
+	    connection goes, SQLite checkpoints the WAL into the
+	    database file, so no -wal file is left behind.
+*/
+func (s *Sqlite) Close() error {
+	return s.Db.Close()
+}
+
+/*
+IsTransient()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The classification storage.Retry uses for this backend:
+	    SQLITE_BUSY / SQLITE_LOCKED, i.e. another connection held
+	    the lock past busy_timeout. The statement did not run, so
+	    trying it again later is safe.
+*/
+func IsTransient(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+/*
+mapError()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Turns driver errors the handlers care about into the
+	    shared storage sentinels; everything else is returned as is.
+	  → Only used on students statements. Only a UNIQUE violation
+	    of emailIndex means ErrDuplicateEmail: the one of
+	    idx_students_uuid (a uuid collision, a backfill conflict)
+	    is returned as is, a 500 rather than a bogus 409.
+*/
+func mapError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique &&
+		strings.Contains(sqliteErr.Error(), "'"+emailIndex+"'") {
+		return storage.ErrDuplicateEmail
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/sqlite"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage/storagetest"
+	"github.com/mattn/go-sqlite3"
+)
+
+// open opens a new database file under t's temporary directory.
+func open(t *testing.T, cfg *config.Config) *sqlite.Sqlite {
+	t.Helper()
+
+	cfg.Storage.Driver = config.DriverSQLite
+	cfg.StoragePath = filepath.Join(t.TempDir(), "students.db")
+
+	s, err := sqlite.New(cfg)
+	if err != nil {
+		t.Fatalf("opening %s: %v", cfg.StoragePath, err)
+	}
+
+	return s
+}
+
+func TestStorage(t *testing.T) {
+	storagetest.TestStorage(t, func(t *testing.T, cfg *config.Config) storage.Storage {
+		return open(t, cfg)
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"busy", sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		{"locked", sqlite3.Error{Code: sqlite3.ErrLocked}, true},
+		{"wrapped busy", fmt.Errorf("insert student: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), true},
+		{"unique violation", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}, false},
+		{"read only", sqlite3.Error{Code: sqlite3.ErrReadonly}, false},
+		{"storage sentinel", storage.ErrDuplicateEmail, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"plain error", errors.New("database is locked"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sqlite.IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → passed into every query
+   - fmt     → name the limit in ErrTooManyTags
+   - time    → updated_at
+   - storage → sentinel errors shared by all backends, the tags JSON
+   - types   → the tag helpers
+*/
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// AddStudentTag tags the live student id (see changeTags), unless it already
+// has types.MaxTags tags: storage.ErrTooManyTags (wrapped).
+func (s *Sqlite) AddStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	return s.changeTags(ctx, id, func(tags []string) ([]string, bool, error) {
+		tagged, added := types.WithTag(tags, tag)
+		if added && len(tagged) > types.MaxTags {
+			return nil, false, fmt.Errorf("%w: at most %d", storage.ErrTooManyTags, types.MaxTags)
+		}
+		return tagged, added, nil
+	})
+}
+
+// RemoveStudentTag untags the live student id (see changeTags).
+func (s *Sqlite) RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error) {
+	return s.changeTags(ctx, id, func(tags []string) ([]string, bool, error) {
+		untagged, removed := types.WithoutTag(tags, tag)
+		return untagged, removed, nil
+	})
+}
+
+/*
+changeTags()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Read-modify-write of the tags of the live student id, in
+	    one transaction like SetStudentStatus: change gets the
+	    current tags and returns the new ones, and whether they
+	    differ.
+	  → A change refreshes updated_at, increments version and is
+	    recorded as an "update" audit event; no change writes
+	    nothing.
+
+	RETURN VALUE:
+	  → whether the tags changed
+	  → storage.ErrNotFound for a missing or deleted student
+*/
+func (s *Sqlite) changeTags(ctx context.Context, id int64, change func([]string) ([]string, bool, error)) (bool, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, id)
+	if err != nil {
+		return false, err
+	}
+	if before == nil || before.DeletedAt != nil {
+		return false, storage.ErrNotFound
+	}
+
+	tags, changed, err := change(before.Tags)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE students SET tags = ?, updated_at = ?, version = version + 1 WHERE id = ?",
+		storage.TagsJSON(tags), time.Now().UTC(), id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, tx, id, types.AuditUpdate, before); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,231 @@
+package storage // storage package defines the contract every database backend must satisfy
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context → every storage call receives the request context so slow
+               queries can be cancelled when the client goes away.
+   - time    → purge cut-offs, idempotency key expiry
+   - types   → your custom Student struct (from internal/types)
+*/
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+/*
+SENTINEL ERRORS
+-------------------------------------------------------------
+  - Every backend returns these exact values so handlers can
+    use errors.Is() without knowing which database is behind.
+  - ErrDuplicateEmail is what a backend returns when its unique
+    email constraint fires (SQLite "UNIQUE constraint failed",
+    Postgres code 23505), on create and on update alike.
+  - ErrBatchAborted marks the items of an atomic CreateStudents
+    batch that were not created because another item failed.
+  - ErrVersionConflict is returned by UpdateStudent/PatchStudent
+    when the student exists but is no longer at the expected
+    version (someone else updated it first).
+  - ErrEmptyFilter is returned by DeleteStudents for a filter
+    that would match every student.
+  - ErrInvalidTransition is returned (wrapped, with the two
+    statuses) by SetStudentStatus when the student's current
+    status can't change to the requested one.
+  - ErrStudentEnrolled is returned by DeleteStudent /
+    DeleteStudents when a student still has enrollments and
+    storage.on_student_delete is "block".
+  - The course errors are listed with CourseStore.
+*/
+var (
+	ErrNotFound          = errors.New("student not found")
+	ErrDuplicateEmail    = errors.New("student with this email already exists")
+	ErrBatchAborted      = errors.New("not created because another item in the atomic batch failed")
+	ErrVersionConflict   = errors.New("student was modified by another request")
+	ErrEmptyFilter       = errors.New("at least one filter is required")
+	ErrInvalidTransition = errors.New("illegal status transition")
+	ErrStudentEnrolled   = errors.New("student is still enrolled in courses")
+)
+
+/*
+BulkResult STRUCT
+-------------------------------------------------------------
+  - Outcome of one item of CreateStudents, same position as
+    the input slice.
+  - Id (and PublicId, what clients see, see IDStore) is set
+    when the row was created, Err when it was not.
+*/
+type BulkResult struct {
+	Id       int64
+	PublicId types.ID
+	Err      error
+}
+
+/*
+IdempotencyRecord STRUCT
+-------------------------------------------------------------
+  - What is stored for one Idempotency-Key.
+  - RequestHash identifies the request the key was first used
+    with; Response is the recorded answer, opaque to storage,
+    and nil while that first request is still running.
+*/
+type IdempotencyRecord struct {
+	RequestHash string
+	Response    []byte
+}
+
+/*
+IdempotencyStore INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The part of storage the Idempotency middleware needs.
+
+	METHODS:
+	  - ReserveIdempotencyKey → claims key for a new request (true), or
+	                            returns the live record already holding
+	                            it (false). Expired records are replaced.
+	  - CompleteIdempotencyKey → stores the response of the reserved key
+	  - ReleaseIdempotencyKey  → drops a reservation that never got a
+	                            response, so the key can be retried
+	  - PurgeExpiredIdempotencyKeys → removes records expired at "now",
+	                            returns how many
+*/
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, key, requestHash string, expiresAt time.Time) (IdempotencyRecord, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, key string, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+/*
+Storage INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Describes WHAT the handlers need from a database,
+	    not HOW a particular database does it.
+	  → Handlers depend on this interface, so we can swap
+	    SQLite for Postgres (or an in-memory fake) without
+	    touching any handler code.
+
+	METHODS:
+	  - CreateStudent  → inserts a student, returns the generated ID
+	  - CreateStudents → inserts many students in one transaction; with
+	                     atomic=true one failure rolls back all of them
+	  - UpsertStudent  → creates the student, or updates the fields (not
+	                     the status) of the live one with its email,
+	                     atomically; reports the ID and whether it was
+	                     created
+	  - GetStudentById → returns one student or ErrNotFound
+	  - ListStudents   → returns one page of the students matching filter, ordered by ID
+	  - ListStudentsAfter → like ListStudents, but starts after an ID
+	                      instead of skipping rows (cursor pagination)
+	  - ForEachStudent → calls fn for every student matching filter, one row
+	                     at a time (streaming export); stops at fn's first error
+	  - CountStudents  → counts all students matching filter (same rules as ListStudents)
+	  - FindStudentsByEmailOrName → the live students having one of the
+	                     emails, or one of the names (case-insensitive),
+	                     in one query (duplicate checks)
+	  - StudentStats   → aggregates of the live students (totals, statuses,
+	                     tags, ages, age buckets, creations per day since
+	                     the given time),
+	                     computed by the database, not by loading rows
+	  - UpdateStudent  → replaces a student, reports whether the ID existed
+	  - PatchStudent   → updates only the non-nil fields, reports whether the ID existed
+	  - SetStudentStatus → moves a student to another status if
+	                     types.CanChangeStatus allows it, reports
+	                     whether a live student had this ID
+	  - DeleteStudent  → soft-deletes a student (sets deleted_at), reports
+	                     whether a live student had this ID; see
+	                     ENROLLMENTS
+	  - DeleteStudents → soft-deletes every live student matching a
+	                     non-empty filter in one transaction, returns
+	                     them as they were before
+	  - RestoreStudent → clears deleted_at again, reports whether a
+	                     soft-deleted student had this ID
+	  - PurgeDeletedStudents → permanently removes up to limit students
+	                     soft-deleted before the cut-off, the longest
+	                     deleted first, returns how many (see
+	                     PurgeInBatches)
+	  - Ping           → checks the database is reachable (readiness probe)
+	  - Close          → releases the connections; called once, at
+	                     shutdown after the last request (see app.Run)
+
+	VERSIONS:
+	  → Every write (update, patch, status, restore) increments version.
+	  → UpdateStudent/PatchStudent only apply when the student is
+	    still at "version"; 0 means "whatever the current version".
+
+	IDEMPOTENCY:
+	  → Every backend is also an IdempotencyStore, so retried
+	    POSTs are recorded in the same database as the students.
+
+	AUDIT:
+	  → Every backend is also an AuditStore: each change of a
+	    student (create, update, patch, delete, restore) records
+	    an AuditEvent with the actor of ctx (see WithActor).
+	  → DeleteStudents also records one summary event of the
+	    whole operation (types.AuditBulkDelete).
+
+	ENROLLMENTS:
+	  → Every backend is also a CourseStore. Deleting students
+	    (DeleteStudent, DeleteStudents) follows
+	    storage.on_student_delete: "block" fails with
+	    ErrStudentEnrolled, changing nothing, while one of them
+	    has enrollments; "cascade" removes their enrollments in
+	    the same transaction.
+
+	PHOTOS:
+	  → Every backend is also a PhotoStore; deleting students
+	    removes their photos.
+
+	IDS:
+	  → Every backend is also an IDStore: the methods take row
+	    IDs (types.Student.Id), ResolveStudentID turns the ID
+	    of a URL into one. Students carry both
+	    (types.Student.PublicId), following storage.id_scheme.
+
+	TAGS:
+	  → Every backend is also a TagStore. The tags are part of
+	    the student (types.Student.Tags): read with it, written
+	    by create, update, upsert and patch, and matched by
+	    filter.Tags (every one of them).
+
+	SOFT DELETE:
+	  → A soft-deleted student behaves as missing everywhere
+	    (Get/Update/Patch/Delete, lists unless filter.IncludeDeleted)
+	    and does not hold its email: a new student may take it, in
+	    which case RestoreStudent returns ErrDuplicateEmail.
+*/
+type Storage interface {
+	CreateStudent(ctx context.Context, student types.Student) (int64, error)
+	CreateStudents(ctx context.Context, students []types.Student, atomic bool) ([]BulkResult, error)
+	UpsertStudent(ctx context.Context, student types.Student) (int64, bool, error)
+	GetStudentById(ctx context.Context, id int64) (types.Student, error)
+	ListStudents(ctx context.Context, filter types.StudentFilter, limit, offset int) ([]types.Student, error)
+	ListStudentsAfter(ctx context.Context, filter types.StudentFilter, afterID int64, limit int) ([]types.Student, error)
+	ForEachStudent(ctx context.Context, filter types.StudentFilter, fn func(types.Student) error) error
+	CountStudents(ctx context.Context, filter types.StudentFilter) (int, error)
+	FindStudentsByEmailOrName(ctx context.Context, emails, names []string) ([]types.Student, error)
+	StudentStats(ctx context.Context, since time.Time) (types.StudentStats, error)
+	UpdateStudent(ctx context.Context, id int64, student types.Student, version int) (bool, error)
+	PatchStudent(ctx context.Context, id int64, patch types.StudentPatch, version int) (bool, error)
+	SetStudentStatus(ctx context.Context, id int64, status string) (bool, error)
+	DeleteStudent(ctx context.Context, id int64) (bool, error)
+	DeleteStudents(ctx context.Context, filter types.StudentFilter) ([]types.Student, error)
+	RestoreStudent(ctx context.Context, id int64) (bool, error)
+	PurgeDeletedStudents(ctx context.Context, before time.Time, limit int) (int64, error)
+	Ping(ctx context.Context) error
+	Close() error
+
+	IdempotencyStore
+	AuditStore
+	CourseStore
+	PhotoStore
+	TagStore
+	IDStore
+}
@@ -0,0 +1,449 @@
+package storagetest // storagetest package checks that a backend keeps every rule of storage.Storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context, errors → calls and the sentinel errors they return
+   - fmt             → distinct emails for the concurrent creates
+   - sync            → the concurrent creates
+   - testing         → the suite reports on the caller's t
+   - time            → idempotency key expiry
+
+   - apptest → the defaults config.Load would give
+   - config  → the id_scheme each run uses
+   - storage → the contract under test
+   - types   → the students it stores
+*/
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/storage"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+)
+
+// concurrentCreates is how many goroutines testConcurrency starts at once.
+const concurrentCreates = 32
+
+/*
+TestStorage()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → The same checks for every backend, so memory, SQLite and
+	    Postgres can't drift apart: each backend's test calls it
+	    with a function opening a fresh, empty store for cfg.
+	  → Runs everything twice, under storage.id_scheme serial
+	    and uuid. The suite closes the stores it opens.
+
+	USAGE:
+	  func TestStorage(t *testing.T) {
+	      storagetest.TestStorage(t, func(t *testing.T, cfg *config.Config) storage.Storage {
+	          return memory.New(cfg)
+	      })
+	  }
+*/
+func TestStorage(t *testing.T, open func(t *testing.T, cfg *config.Config) storage.Storage) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage, uuidIDs bool)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"ResolveStudentID", testResolveStudentID},
+		{"DuplicateEmail", testDuplicateEmail},
+		{"Missing", testMissing},
+		{"ListAndCount", testListAndCount},
+		{"UpdateAndPatch", testUpdateAndPatch},
+		{"SoftDelete", testSoftDelete},
+		{"Tags", testTags},
+		{"Idempotency", testIdempotency},
+		{"Concurrency", testConcurrency},
+	}
+
+	for _, scheme := range []string{config.IDSchemeSerial, config.IDSchemeUUID} {
+		t.Run(scheme, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					cfg := apptest.Config(t)
+					cfg.Storage.IDScheme = scheme
+
+					s := open(t, cfg)
+					t.Cleanup(func() { s.Close() })
+
+					tt.fn(t, s, scheme == config.IDSchemeUUID)
+				})
+			}
+		})
+	}
+}
+
+// student is a valid student with the given name and email.
+func student(name, email string) types.Student {
+	return types.Student{Name: name, Email: email, Age: 20, Status: types.StatusActive}
+}
+
+// create stores student(name, email) and returns its row ID.
+func create(t *testing.T, s storage.Storage, name, email string) int64 {
+	t.Helper()
+
+	id, err := s.CreateStudent(context.Background(), student(name, email))
+	if err != nil {
+		t.Fatalf("CreateStudent(%s): %v", email, err)
+	}
+
+	return id
+}
+
+// get loads the student with row ID id.
+func get(t *testing.T, s storage.Storage, id int64) types.Student {
+	t.Helper()
+
+	got, err := s.GetStudentById(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetStudentById(%d): %v", id, err)
+	}
+
+	return got
+}
+
+func testCreateAndGet(t *testing.T, s storage.Storage, uuidIDs bool) {
+	id := create(t, s, "Ann Lee", "ann@example.com")
+
+	got := get(t, s, id)
+	if got.Id != id || got.Name != "Ann Lee" || got.Email != "ann@example.com" || got.Age != 20 ||
+		got.Status != types.StatusActive || got.Version != 1 || got.DeletedAt != nil {
+		t.Errorf("got %+v", got)
+	}
+	// the SQL backends read missing tags back as []
+	if got.Tags == nil || len(got.Tags) != 0 {
+		t.Errorf("tags %#v, want []", got.Tags)
+	}
+	if got.CreatedAt.IsZero() || !got.UpdatedAt.Equal(got.CreatedAt) {
+		t.Errorf("created_at %v, updated_at %v", got.CreatedAt, got.UpdatedAt)
+	}
+
+	if uuidIDs {
+		if !storage.IsUUID(string(got.PublicId)) {
+			t.Errorf("public id %q, want a UUID", got.PublicId)
+		}
+	} else if got.PublicId != types.SerialID(id) {
+		t.Errorf("public id %q, want %q", got.PublicId, types.SerialID(id))
+	}
+}
+
+func testResolveStudentID(t *testing.T, s storage.Storage, uuidIDs bool) {
+	ctx := context.Background()
+	id := create(t, s, "Ann Lee", "ann@example.com")
+	public := get(t, s, id).PublicId
+
+	if got, err := s.ResolveStudentID(ctx, string(public)); err != nil || got != id {
+		t.Errorf("ResolveStudentID(%q) = %d, %v; want %d", public, got, err, id)
+	}
+	if _, err := s.ResolveStudentID(ctx, "abc"); !errors.Is(err, storage.ErrInvalidID) {
+		t.Errorf("ResolveStudentID(abc): %v, want ErrInvalidID", err)
+	}
+	if _, err := s.ResolveStudentID(ctx, storage.NewUUID(time.Now())); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("ResolveStudentID(unknown UUID): %v, want ErrNotFound", err)
+	}
+
+	// uuid deployments don't take row IDs, so they can't be enumerated
+	_, err := s.ResolveStudentID(ctx, "1")
+	if uuidIDs && !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("ResolveStudentID(1) under uuid: %v, want ErrNotFound", err)
+	}
+	if !uuidIDs && err != nil {
+		t.Errorf("ResolveStudentID(1) under serial: %v", err)
+	}
+}
+
+func testDuplicateEmail(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	ann := create(t, s, "Ann Lee", "ann@example.com")
+	bob := create(t, s, "Bob Lee", "bob@example.com")
+
+	// the email is unique whatever its case
+	if _, err := s.CreateStudent(ctx, student("Ann Other", "ANN@Example.com")); !errors.Is(err, storage.ErrDuplicateEmail) {
+		t.Errorf("create with a taken email: %v, want ErrDuplicateEmail", err)
+	}
+	if _, err := s.UpdateStudent(ctx, bob, student("Bob Lee", "Ann@example.com"), 0); !errors.Is(err, storage.ErrDuplicateEmail) {
+		t.Errorf("update to a taken email: %v, want ErrDuplicateEmail", err)
+	}
+	email := "ann@EXAMPLE.com"
+	if _, err := s.PatchStudent(ctx, bob, types.StudentPatch{Email: &email}, 0); !errors.Is(err, storage.ErrDuplicateEmail) {
+		t.Errorf("patch to a taken email: %v, want ErrDuplicateEmail", err)
+	}
+
+	// a soft-deleted student gives its email up, and can't get it back
+	if ok, err := s.DeleteStudent(ctx, ann); !ok || err != nil {
+		t.Fatalf("DeleteStudent = %v, %v", ok, err)
+	}
+	create(t, s, "Ann Other", "ann@example.com")
+	if _, err := s.RestoreStudent(ctx, ann); !errors.Is(err, storage.ErrDuplicateEmail) {
+		t.Errorf("restore with the email taken: %v, want ErrDuplicateEmail", err)
+	}
+}
+
+func testMissing(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	const id = 999
+
+	if _, err := s.GetStudentById(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetStudentById: %v, want ErrNotFound", err)
+	}
+	if ok, err := s.UpdateStudent(ctx, id, student("Ann Lee", "ann@example.com"), 0); ok || err != nil {
+		t.Errorf("UpdateStudent = %v, %v; want false, nil", ok, err)
+	}
+	age := 30
+	if ok, err := s.PatchStudent(ctx, id, types.StudentPatch{Age: &age}, 0); ok || err != nil {
+		t.Errorf("PatchStudent = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := s.DeleteStudent(ctx, id); ok || err != nil {
+		t.Errorf("DeleteStudent = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := s.RestoreStudent(ctx, id); ok || err != nil {
+		t.Errorf("RestoreStudent = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func testListAndCount(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	ids := []int64{
+		create(t, s, "Ann Lee", "ann@example.com"),
+		create(t, s, "Bob Lee", "bob@school.org"),
+		create(t, s, "Cid Moe", "cid@example.com"),
+	}
+	if _, err := s.AddStudentTag(ctx, ids[2], "honours"); err != nil {
+		t.Fatalf("AddStudentTag: %v", err)
+	}
+
+	rowIDs := func(students []types.Student) []int64 {
+		out := []int64{}
+		for _, student := range students {
+			out = append(out, student.Id)
+		}
+		return out
+	}
+	equal := func(a, b []int64) bool { return fmt.Sprint(a) == fmt.Sprint(b) }
+
+	// ordered by ID, one page at a time
+	page, err := s.ListStudents(ctx, types.StudentFilter{}, 2, 0)
+	if err != nil || !equal(rowIDs(page), ids[:2]) {
+		t.Errorf("ListStudents(limit 2) = %v, %v; want %v", rowIDs(page), err, ids[:2])
+	}
+	page, err = s.ListStudents(ctx, types.StudentFilter{}, 2, 2)
+	if err != nil || !equal(rowIDs(page), ids[2:]) {
+		t.Errorf("ListStudents(offset 2) = %v, %v; want %v", rowIDs(page), err, ids[2:])
+	}
+	page, err = s.ListStudentsAfter(ctx, types.StudentFilter{}, ids[0], 10)
+	if err != nil || !equal(rowIDs(page), ids[1:]) {
+		t.Errorf("ListStudentsAfter(%d) = %v, %v; want %v", ids[0], rowIDs(page), err, ids[1:])
+	}
+
+	filters := []struct {
+		name   string
+		filter types.StudentFilter
+		want   []int64
+	}{
+		{"name", types.StudentFilter{Name: "lee"}, ids[:2]},
+		{"email domain", types.StudentFilter{EmailDomain: "example.com"}, []int64{ids[0], ids[2]}},
+		{"tag", types.StudentFilter{Tags: []string{"honours"}}, ids[2:]},
+		{"status", types.StudentFilter{Status: types.StatusSuspended}, []int64{}},
+	}
+	for _, f := range filters {
+		page, err := s.ListStudents(ctx, f.filter, 10, 0)
+		if err != nil || !equal(rowIDs(page), f.want) {
+			t.Errorf("ListStudents(%s) = %v, %v; want %v", f.name, rowIDs(page), err, f.want)
+		}
+		if n, err := s.CountStudents(ctx, f.filter); err != nil || n != len(f.want) {
+			t.Errorf("CountStudents(%s) = %d, %v; want %d", f.name, n, err, len(f.want))
+		}
+	}
+}
+
+func testUpdateAndPatch(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	id := create(t, s, "Ann Lee", "ann@example.com")
+
+	update := student("Ann Marie Lee", "ann@example.com")
+	update.Age = 21
+	if ok, err := s.UpdateStudent(ctx, id, update, 1); !ok || err != nil {
+		t.Fatalf("UpdateStudent = %v, %v", ok, err)
+	}
+	if got := get(t, s, id); got.Name != "Ann Marie Lee" || got.Age != 21 || got.Version != 2 {
+		t.Errorf("after update: %+v", got)
+	}
+
+	// a write at an old version is refused, changing nothing
+	if _, err := s.UpdateStudent(ctx, id, student("Someone Else", "ann@example.com"), 1); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Errorf("update at a stale version: %v, want ErrVersionConflict", err)
+	}
+	age := 22
+	if _, err := s.PatchStudent(ctx, id, types.StudentPatch{Age: &age}, 1); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Errorf("patch at a stale version: %v, want ErrVersionConflict", err)
+	}
+
+	// a patch changes the fields it has, and nothing else
+	if ok, err := s.PatchStudent(ctx, id, types.StudentPatch{Age: &age}, 2); !ok || err != nil {
+		t.Fatalf("PatchStudent = %v, %v", ok, err)
+	}
+	if got := get(t, s, id); got.Name != "Ann Marie Lee" || got.Age != 22 || got.Version != 3 || got.UpdatedAt.Before(got.CreatedAt) {
+		t.Errorf("after patch: %+v", got)
+	}
+}
+
+func testSoftDelete(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	id := create(t, s, "Ann Lee", "ann@example.com")
+
+	if ok, err := s.DeleteStudent(ctx, id); !ok || err != nil {
+		t.Fatalf("DeleteStudent = %v, %v", ok, err)
+	}
+	if ok, err := s.DeleteStudent(ctx, id); ok || err != nil {
+		t.Errorf("second DeleteStudent = %v, %v; want false, nil", ok, err)
+	}
+	if _, err := s.GetStudentById(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("get after delete: %v, want ErrNotFound", err)
+	}
+	if n, err := s.CountStudents(ctx, types.StudentFilter{}); n != 0 || err != nil {
+		t.Errorf("CountStudents = %d, %v; want 0", n, err)
+	}
+	if n, err := s.CountStudents(ctx, types.StudentFilter{IncludeDeleted: true}); n != 1 || err != nil {
+		t.Errorf("CountStudents(include deleted) = %d, %v; want 1", n, err)
+	}
+
+	if ok, err := s.RestoreStudent(ctx, id); !ok || err != nil {
+		t.Fatalf("RestoreStudent = %v, %v", ok, err)
+	}
+	if got := get(t, s, id); got.DeletedAt != nil || got.Version != 2 {
+		t.Errorf("after restore: %+v", got)
+	}
+}
+
+func testTags(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	id := create(t, s, "Ann Lee", "ann@example.com")
+
+	steps := []struct {
+		name string
+		call func() (bool, error)
+		want bool
+	}{
+		{"add", func() (bool, error) { return s.AddStudentTag(ctx, id, "honours") }, true},
+		{"add again", func() (bool, error) { return s.AddStudentTag(ctx, id, "honours") }, false},
+		{"remove", func() (bool, error) { return s.RemoveStudentTag(ctx, id, "honours") }, true},
+		{"remove again", func() (bool, error) { return s.RemoveStudentTag(ctx, id, "honours") }, false},
+	}
+	for _, step := range steps {
+		if got, err := step.call(); got != step.want || err != nil {
+			t.Errorf("%s = %v, %v; want %v", step.name, got, err, step.want)
+		}
+	}
+
+	// only the two changes are new versions
+	if got := get(t, s, id); got.Version != 3 || len(got.Tags) != 0 {
+		t.Errorf("after the tag changes: %+v", got)
+	}
+	if _, err := s.AddStudentTag(ctx, 999, "honours"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("tag a missing student: %v, want ErrNotFound", err)
+	}
+}
+
+func testIdempotency(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+
+	if _, ok, err := s.ReserveIdempotencyKey(ctx, "k1", "hash", expires); !ok || err != nil {
+		t.Fatalf("first reserve = %v, %v", ok, err)
+	}
+	record, ok, err := s.ReserveIdempotencyKey(ctx, "k1", "other", expires)
+	if ok || err != nil || record.RequestHash != "hash" || record.Response != nil {
+		t.Errorf("reserve while running = %+v, %v, %v", record, ok, err)
+	}
+
+	if err := s.CompleteIdempotencyKey(ctx, "k1", []byte("answer")); err != nil {
+		t.Fatalf("CompleteIdempotencyKey: %v", err)
+	}
+	record, ok, err = s.ReserveIdempotencyKey(ctx, "k1", "hash", expires)
+	if ok || err != nil || string(record.Response) != "answer" {
+		t.Errorf("reserve after complete = %+v, %v, %v", record, ok, err)
+	}
+
+	// a released key can be reserved again
+	if _, ok, err := s.ReserveIdempotencyKey(ctx, "k2", "hash", expires); !ok || err != nil {
+		t.Fatalf("reserve k2 = %v, %v", ok, err)
+	}
+	if err := s.ReleaseIdempotencyKey(ctx, "k2"); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey: %v", err)
+	}
+	if _, ok, err := s.ReserveIdempotencyKey(ctx, "k2", "hash", expires); !ok || err != nil {
+		t.Errorf("reserve after release = %v, %v", ok, err)
+	}
+
+	// both keys expire at "expires"
+	if n, err := s.PurgeExpiredIdempotencyKeys(ctx, time.Now()); n != 0 || err != nil {
+		t.Errorf("purge before the expiry = %d, %v; want 0", n, err)
+	}
+	if n, err := s.PurgeExpiredIdempotencyKeys(ctx, expires.Add(time.Second)); n != 2 || err != nil {
+		t.Errorf("purge after the expiry = %d, %v; want 2", n, err)
+	}
+}
+
+// testConcurrency creates students from many goroutines at once: every one
+// of them gets its own ID, and of the ones sharing an email only one gets in.
+// Run with -race, it also checks the backend's locking.
+func testConcurrency(t *testing.T, s storage.Storage, _ bool) {
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	ids := make([]int64, concurrentCreates)
+	errs := make([]error, concurrentCreates)
+	for i := range concurrentCreates {
+		wg.Go(func() {
+			ids[i], errs[i] = s.CreateStudent(ctx, student("Ann Lee", fmt.Sprintf("ann%d@example.com", i)))
+		})
+	}
+	wg.Wait()
+
+	seen := map[int64]bool{}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+		if seen[ids[i]] {
+			t.Errorf("ID %d given twice", ids[i])
+		}
+		seen[ids[i]] = true
+	}
+
+	created := make([]bool, concurrentCreates)
+	for i := range concurrentCreates {
+		wg.Go(func() {
+			_, err := s.CreateStudent(ctx, student("Bob Lee", "bob@example.com"))
+			if err != nil && !errors.Is(err, storage.ErrDuplicateEmail) {
+				t.Errorf("create of the shared email: %v", err)
+			}
+			created[i] = err == nil
+		})
+	}
+	wg.Wait()
+
+	n := 0
+	for _, ok := range created {
+		if ok {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("%d creates of the same email went through, want 1", n)
+	}
+	if total, err := s.CountStudents(ctx, types.StudentFilter{}); total != concurrentCreates+1 || err != nil {
+		t.Errorf("CountStudents = %d, %v; want %d", total, err, concurrentCreates+1)
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - context       → every storage call receives the request context
+   - encoding/json → the SQL backends store the tags as a JSON array
+   - errors        → the tag sentinel error
+*/
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// ErrTooManyTags is returned (wrapped, with the limit) by AddStudentTag when
+// the student already has types.MaxTags tags.
+var ErrTooManyTags = errors.New("student has too many tags")
+
+/*
+TagStore INTERFACE
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → One tag at a time, for POST / DELETE
+	    /api/students/{id}/tags/{tag}. Create, update and patch
+	    set all the tags at once, with the other fields.
+
+	METHODS:
+	  - AddStudentTag    → tags a live student; reports whether
+	                       it did not have the tag yet
+	  - RemoveStudentTag → untags a live student; reports
+	                       whether it had the tag
+
+	RULES:
+	  → tag is normalized (types.NormalizeTag). Both return
+	    ErrNotFound for a missing (or soft-deleted) student.
+	  → A change increments version and records an "update"
+	    audit event, like any other write; adding a tag the
+	    student has (or removing one it hasn't) changes nothing.
+*/
+type TagStore interface {
+	AddStudentTag(ctx context.Context, id int64, tag string) (bool, error)
+	RemoveStudentTag(ctx context.Context, id int64, tag string) (bool, error)
+}
+
+// TagsJSON encodes the tags of a student for the SQL backends' tags column;
+// nil is stored as [] like no tags. A []string always encodes.
+func TagsJSON(tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
+
+// ParseTags decodes what TagsJSON stored, never returning nil.
+func ParseTags(encoded []byte) ([]string, error) {
+	tags := make([]string, 0)
+	if err := json.Unmarshal(encoded, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
@@ -1,8 +1,621 @@
 package types
 
-type Student struct{
-	id int 
-	Name string	`validate:"required"`
-	Email string `validate:"required"`
-	Age int	`validate:"required"`
-}
\ No newline at end of file
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/phone"
+)
+
+// Student is the API representation of a student. Id, PublicId, CreatedAt,
+// UpdatedAt and DeletedAt are assigned by storage: handlers discard them
+// when they appear in a request body, but they are serialized in responses
+// (the timestamps as RFC 3339). They carry no validate tags for that reason.
+// Id is the row ID the server uses internally (storage calls, audit,
+// enrollments, the cache) and is never serialized: clients only see
+// PublicId, as "id" (see ID).
+// DeletedAt is only set on soft-deleted students, which are only returned
+// when a filter asks for them.
+// Names are 2-100 characters without control characters, emails must be
+// valid addresses and ages are 1-150; "required" comes first so a missing
+// key says so.
+// DateOfBirth is optional, a YYYY-MM-DD date giving an age of MinBirthAge
+// to MaxBirthAge (tag dob, see the validation package). When it is set, Age
+// is derived from it on every read (see DeriveAge); a body may still send
+// age alone while clients move over, or both if they agree.
+// Phone is optional and stored in E.164 ("+14155550123"): handlers call
+// NormalizePhone, then the phone tag rejects what could not be read.
+// Tags are free-form labels ("scholarship", "2025-intake"): at most MaxTags,
+// each one IsTag once normalized (see NormalizeTags). Reads always return
+// them, [] when there are none.
+// Version starts at 1 and is incremented by every update; in a PUT body it
+// names the version the client edited (like If-Match).
+// Status is one of the Status* constants, active when a create leaves it
+// out. Only POST /api/students/{id}/status changes it afterwards, so it can
+// follow the legal transitions (see CanChangeStatus): in a PUT body it is
+// ignored.
+// The Student schema in internal/http/handlers/docs/openapi.json mirrors
+// these fields and rules: change both together.
+type Student struct {
+	Id          int64      `json:"-"`
+	PublicId    ID         `json:"id"`
+	Name        string     `json:"name" validate:"required,min=2,max=100,nocontrol"`
+	Email       string     `json:"email" validate:"required,email"`
+	Age         int        `json:"age" validate:"required_without=DateOfBirth,omitempty,gte=1,lte=150"`
+	DateOfBirth *string    `json:"date_of_birth,omitempty" validate:"omitnil,dob"`
+	Phone       *string    `json:"phone,omitempty" validate:"omitnil,phone"`
+	Tags        []string   `json:"tags" validate:"max=10,dive,tag"`
+	Status      string     `json:"status" validate:"oneof=active suspended graduated"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+/*
+ID TYPE
+-------------------------------------------------------------
+  - The ID clients see of a student: its row ID in decimal
+    ("7") under storage.id_scheme serial, its UUID
+    ("0190b4c2-6f1e-7c3a-9d2b-5e8f1a2b3c4d") under uuid, so the
+    IDs of a uuid deployment don't tell how many students
+    there are.
+  - Sent as a JSON number when it is a row ID, as a string
+    otherwise, so serial deployments answer exactly as before.
+    Both are accepted in a body; "" (no ID yet) is sent as 0.
+*/
+type ID string
+
+// IsSerial reports whether id is a row ID ("7") rather than a UUID.
+func (id ID) IsSerial() bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// SerialID is the ID of a student under storage.id_scheme serial.
+func SerialID(id int64) ID {
+	return ID(strconv.FormatInt(id, 10))
+}
+
+// MarshalJSON sends a row ID as a number, anything else as a string.
+func (id ID) MarshalJSON() ([]byte, error) {
+	switch {
+	case id == "":
+		return []byte("0"), nil
+	case id.IsSerial():
+		return []byte(id), nil
+	default:
+		return json.Marshal(string(id))
+	}
+}
+
+// UnmarshalJSON accepts a number or a string; null leaves id unset.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err == nil {
+		*id = ID(number)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	*id = ID(text)
+	return nil
+}
+
+// Student statuses. The oneof tag of Student.Status lists the same values.
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusGraduated = "graduated"
+)
+
+// Statuses lists every status, in the order stats report them.
+var Statuses = []string{StatusActive, StatusSuspended, StatusGraduated}
+
+// statusTransitions maps each status to those it may change to. Graduated
+// is terminal; a status never "changes" to itself.
+var statusTransitions = map[string][]string{
+	StatusActive:    {StatusSuspended, StatusGraduated},
+	StatusSuspended: {StatusActive, StatusGraduated},
+	StatusGraduated: {},
+}
+
+// IsStatus reports whether status is one of Statuses.
+func IsStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+// CanChangeStatus reports whether a student may go from one status to the
+// other.
+func CanChangeStatus(from, to string) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// NormalizeEmail is the one spelling of an address that is stored and
+// compared: surrounding whitespace removed, lower-cased. The local part is
+// case-sensitive on paper, but no real mail provider treats it so, and
+// "John@Example.com" registering next to "john@example.com" is always a
+// mistake.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeName trims name and collapses every inner run of whitespace to
+// one space, so "  Mary   Ann " is stored as "Mary Ann". A name of only
+// whitespace becomes "" and fails validation as missing.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// HasNoControl reports whether s holds no control characters (the nocontrol
+// validation tag). Tabs and newlines are gone once NormalizeName ran; a NUL
+// or an escape sequence in a name is never meant, and Postgres can't store
+// a NUL at all.
+func HasNoControl(s string) bool {
+	return !strings.ContainsFunc(s, unicode.IsControl)
+}
+
+// Ages a date of birth may give (the dob validation tag): anything outside is
+// a typo rather than a student.
+const (
+	MinBirthAge = 3
+	MaxBirthAge = 120
+)
+
+// ParseDate reads a YYYY-MM-DD date, as a date of birth is sent and stored.
+func ParseDate(date string) (time.Time, error) {
+	return time.Parse(time.DateOnly, date)
+}
+
+// AgeOn returns the age on the day on of someone born on dob: whole years,
+// the birthday counting from its month and day (a February 29 birthday
+// from March 1 in other years). Both dates are read in UTC.
+func AgeOn(dob, on time.Time) int {
+	dob, on = dob.UTC(), on.UTC()
+
+	age := on.Year() - dob.Year()
+	if on.Month() < dob.Month() || (on.Month() == dob.Month() && on.Day() < dob.Day()) {
+		age--
+	}
+
+	return age
+}
+
+// BornBy returns the latest date of birth that is age years old on the day
+// on; the SQL backends turn min_age / max_age into date ranges with it. A
+// February 29 that doesn't exist in that year becomes February 28.
+func BornBy(age int, on time.Time) time.Time {
+	on = on.UTC()
+
+	born := time.Date(on.Year()-age, on.Month(), on.Day(), 0, 0, 0, 0, time.UTC)
+	if born.Month() != on.Month() {
+		born = born.AddDate(0, 0, -born.Day())
+	}
+
+	return born
+}
+
+// IsDateOfBirth reports whether date is a YYYY-MM-DD date giving an age of
+// MinBirthAge to MaxBirthAge today (so in the past).
+func IsDateOfBirth(date string, today time.Time) bool {
+	dob, err := ParseDate(date)
+	if err != nil {
+		return false
+	}
+
+	age := AgeOn(dob, today)
+	return age >= MinBirthAge && age <= MaxBirthAge
+}
+
+// DeriveAge sets Age from DateOfBirth, as of today, when there is a valid
+// one. Storage calls it on every student it reads, so ages never go stale.
+func (s *Student) DeriveAge(today time.Time) {
+	if s.DateOfBirth == nil {
+		return
+	}
+
+	if dob, err := ParseDate(*s.DateOfBirth); err == nil {
+		s.Age = AgeOn(dob, today)
+	}
+}
+
+// Normalize puts the fields of s in their stored form (see NormalizeName,
+// NormalizeEmail and NormalizeTags) and gives it the default status when it
+// has none.
+// A blank date of birth counts as none; with a valid one and no age, the
+// age is derived from it so the rules on age hold (a different age is
+// rejected by validation, an invalid date is left to its own rule).
+// Handlers call it after decoding, before validation, so the cleaned values
+// are what is validated, stored and returned.
+func (s *Student) Normalize() {
+	s.Name = NormalizeName(s.Name)
+	s.Email = NormalizeEmail(s.Email)
+	if s.Status == "" {
+		s.Status = StatusActive
+	}
+	s.DateOfBirth = normalizeOptional(s.DateOfBirth)
+	s.Phone = normalizeOptional(s.Phone)
+	s.Tags = NormalizeTags(s.Tags)
+	if s.Age == 0 && s.DateOfBirth != nil && IsDateOfBirth(*s.DateOfBirth, time.Now()) {
+		s.DeriveAge(time.Now())
+	}
+}
+
+// normalizeOptional trims an optional field; a blank one becomes nil (not
+// set).
+func normalizeOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+
+	return &trimmed
+}
+
+// NormalizePhone puts Phone in E.164, reading numbers without "+" as
+// numbers of country (config api.phone_country). A number that can't be
+// read is left as sent, for the phone tag to reject.
+func (s *Student) NormalizePhone(country string) {
+	s.Phone = normalizePhone(s.Phone, country)
+}
+
+func normalizePhone(number *string, country string) *string {
+	if number == nil {
+		return nil
+	}
+
+	if e164, ok := phone.Normalize(*number, country); ok {
+		return &e164
+	}
+
+	return number
+}
+
+// Tag rules: a student has at most MaxTags tags of at most MaxTagLength
+// characters; the max=10 of the Student.Tags and StudentPatch.Tags tags is
+// MaxTags.
+const (
+	MaxTags      = 10
+	MaxTagLength = 32
+)
+
+// NormalizeTag is the one spelling of a tag that is stored and compared:
+// surrounding whitespace removed, lower-cased.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// IsTag reports whether tag is a normalized tag: 1 to MaxTagLength
+// lower-case letters, digits, "-" and "_", starting with a letter or a
+// digit ("scholarship", "2025-intake").
+func IsTag(tag string) bool {
+	if tag == "" || len(tag) > MaxTagLength || tag[0] == '-' || tag[0] == '_' {
+		return false
+	}
+
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NormalizeTags normalizes every tag (NormalizeTag), drops the duplicates
+// and sorts them, so a set of tags has one stored form. nil becomes an
+// empty list: a student always has tags, maybe none.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized = append(normalized, NormalizeTag(tag))
+	}
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
+
+// WithTag returns a copy of the (normalized) tags with tag added, and
+// whether it was missing.
+func WithTag(tags []string, tag string) ([]string, bool) {
+	i, found := slices.BinarySearch(tags, tag)
+	if found {
+		return tags, false
+	}
+
+	return slices.Insert(slices.Clone(tags), i, tag), true
+}
+
+// WithoutTag returns a copy of the (normalized) tags without tag, and
+// whether it was there.
+func WithoutTag(tags []string, tag string) ([]string, bool) {
+	i, found := slices.BinarySearch(tags, tag)
+	if !found {
+		return tags, false
+	}
+
+	return slices.Delete(slices.Clone(tags), i, i+1), true
+}
+
+// StudentPatch is the body of a PATCH request. Pointer fields let us tell
+// "key not sent" (nil) apart from "key sent with a value", and omitnil makes
+// the validator skip absent keys while applying the same rules as Student
+// to the present ones.
+// Version is not a field to change: like If-Match, it names the version the
+// client edited.
+// DateOfBirth also sets the age it gives (Normalize fills Age in); Age alone
+// replaces the date of birth, which is cleared, like a PUT without one.
+// Tags replaces all the tags; [] removes them.
+type StudentPatch struct {
+	Name        *string   `json:"name" validate:"omitnil,min=2,max=100,nocontrol"`
+	Email       *string   `json:"email" validate:"omitnil,email"`
+	Age         *int      `json:"age" validate:"omitnil,gte=1,lte=150"`
+	DateOfBirth *string   `json:"date_of_birth" validate:"omitnil,dob"`
+	Phone       *string   `json:"phone" validate:"omitnil,phone"`
+	Tags        *[]string `json:"tags" validate:"omitnil,max=10,dive,tag"`
+	Version     *int      `json:"version" validate:"omitnil,min=1"`
+}
+
+// IsEmpty reports whether the patch carries no fields to change (Version
+// alone changes nothing).
+func (p StudentPatch) IsEmpty() bool {
+	return p.Name == nil && p.Email == nil && p.Age == nil && p.DateOfBirth == nil && p.Phone == nil &&
+		p.Tags == nil
+}
+
+// NormalizePhone is Student.NormalizePhone for a patch.
+func (p *StudentPatch) NormalizePhone(country string) {
+	p.Phone = normalizePhone(p.Phone, country)
+}
+
+// Normalize is Student.Normalize for the fields present in the patch.
+func (p *StudentPatch) Normalize() {
+	if p.Name != nil {
+		name := NormalizeName(*p.Name)
+		p.Name = &name
+	}
+	if p.Email != nil {
+		email := NormalizeEmail(*p.Email)
+		p.Email = &email
+	}
+	if p.Tags != nil {
+		tags := NormalizeTags(*p.Tags)
+		p.Tags = &tags
+	}
+	if p.DateOfBirth != nil {
+		date := strings.TrimSpace(*p.DateOfBirth)
+		p.DateOfBirth = &date
+
+		if dob, err := ParseDate(date); err == nil && p.Age == nil && IsDateOfBirth(date, time.Now()) {
+			age := AgeOn(dob, time.Now())
+			p.Age = &age
+		}
+	}
+}
+
+// StudentFilter narrows a student list. Zero values mean "no filter"; all
+// set fields must match (AND). Name is a case-insensitive substring match,
+// Email a case-insensitive exact match, Status an exact match, EmailDomain the part after the "@"
+// (case-insensitive, exact: "example.com" doesn't match "mail.example.com"),
+// MinAge/MaxAge inclusive bounds (on the age of today, for students with a
+// date of birth).
+// Terms is the tokenized search query: every term must appear
+// (case-insensitively) in the name or the email. Tags are normalized tags
+// the student must all have. Soft-deleted students are left out unless
+// IncludeDeleted is set.
+// The JSON form is what the audit event of a bulk delete records.
+type StudentFilter struct {
+	Name           string   `json:"name,omitempty"`
+	Email          string   `json:"email,omitempty"`
+	EmailDomain    string   `json:"email_domain,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	MinAge         *int     `json:"min_age,omitempty"`
+	MaxAge         *int     `json:"max_age,omitempty"`
+	Terms          []string `json:"terms,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	IncludeDeleted bool     `json:"include_deleted,omitempty"`
+}
+
+// IsEmpty reports whether the filter matches every student (IncludeDeleted
+// alone narrows nothing).
+func (f StudentFilter) IsEmpty() bool {
+	return f.Name == "" && f.Email == "" && f.EmailDomain == "" && f.Status == "" &&
+		f.MinAge == nil && f.MaxAge == nil && len(f.Terms) == 0 && len(f.Tags) == 0
+}
+
+// AgeBucketWidth is the span of one StudentStats.AgeBuckets entry: 1-10,
+// 11-20, … up to the maximum age (150).
+const AgeBucketWidth = 10
+
+// StudentStats is the aggregate view of the live (not soft-deleted)
+// students served by GET /api/students/stats. Storage fills in the statuses,
+// buckets and days that have students; the handler adds the empty ones, so
+// the JSON always lists every status, every bucket and every day of the
+// window. ByTag only lists the tags in use, most used first (ties by tag).
+type StudentStats struct {
+	Total         int           `json:"total"`
+	ByStatus      []StatusCount `json:"by_status"`
+	ByTag         []TagCount    `json:"by_tag"`
+	Age           AgeStats      `json:"age"`
+	AgeBuckets    []AgeBucket   `json:"age_buckets"`
+	CreatedPerDay []DayCount    `json:"created_per_day"`
+}
+
+// StatusCount counts the students with Status.
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// TagCount counts the students tagged Tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// AgeStats summarizes the ages; all zero when there are no students.
+type AgeStats struct {
+	Average float64 `json:"average"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+}
+
+// AgeBucket counts the students aged From to To (inclusive).
+type AgeBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// DayCount counts the students created on Date (YYYY-MM-DD, UTC).
+type DayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Audit actions, one per kind of change recorded in AuditEvent.Action. PUT
+// and PATCH are both "update": Before and After show what changed.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+
+	// AuditBulkDelete is the summary of one DELETE /api/students, recorded
+	// next to the "delete" event of every student it removed.
+	AuditBulkDelete = "bulk_delete"
+)
+
+// AuditStudentNone is the StudentId of events about many students at once
+// (a bulk delete): they belong to no one student's trail.
+const AuditStudentNone = 0
+
+// AuditEvent is one change of a student, written by storage in the same
+// transaction as the change itself and served by GET
+// /api/students/{id}/audit. Actor is who made it (see storage.WithActor).
+// Before is the student as it was (nil for a create), After as it became;
+// both are stored as JSON, so later schema changes don't rewrite history.
+// Details is only set on summaries (AuditBulkDelete), which have neither.
+// StudentId is the row ID, like Student.Id; handlers set StudentPublicId,
+// which is what clients see.
+type AuditEvent struct {
+	Id              int64         `json:"id"`
+	StudentId       int64         `json:"-"`
+	StudentPublicId ID            `json:"student_id"`
+	Action          string        `json:"action"`
+	Actor           string        `json:"actor"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Before          *Student      `json:"before,omitempty"`
+	After           *Student      `json:"after,omitempty"`
+	Details         *AuditDetails `json:"details,omitempty"`
+}
+
+// AuditDetails describes an operation on many students: the filter that
+// selected them and how many it changed.
+type AuditDetails struct {
+	Filter StudentFilter `json:"filter"`
+	Count  int           `json:"count"`
+}
+
+// Course is the API representation of a course students enroll in. Like
+// Student, Id and the timestamps are assigned by storage, and so is
+// Enrolled: how many students the course holds, at most Capacity. Codes
+// ("CS-101") are unique, whatever their case.
+// The Course schema in internal/http/handlers/docs/openapi.json mirrors
+// these fields and rules: change both together.
+type Course struct {
+	Id        int64     `json:"id"`
+	Code      string    `json:"code" validate:"required,min=2,max=20"`
+	Title     string    `json:"title" validate:"required,min=2,max=200"`
+	Capacity  int       `json:"capacity" validate:"required,gte=1,lte=10000"`
+	Enrolled  int       `json:"enrolled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Normalize puts the fields of c in their stored form: the code without
+// whitespace and upper-cased, the title like a name (see NormalizeName).
+func (c *Course) Normalize() {
+	c.Code = strings.ToUpper(strings.Join(strings.Fields(c.Code), ""))
+	c.Title = NormalizeName(c.Title)
+}
+
+// Enrollment is one course a student is enrolled in, served by GET
+// /api/students/{id}/enrollments with the code and title of the course.
+// StudentId is the row ID, StudentPublicId (set by the handlers) what
+// clients see, like on AuditEvent.
+type Enrollment struct {
+	StudentId       int64     `json:"-"`
+	StudentPublicId ID        `json:"student_id"`
+	CourseId        int64     `json:"course_id"`
+	Code            string    `json:"code"`
+	Title           string    `json:"title"`
+	EnrolledAt      time.Time `json:"enrolled_at"`
+}
+
+// CourseStudent is one entry of a course roster: an enrolled student and
+// when the enrollment was made.
+type CourseStudent struct {
+	Student
+	EnrolledAt time.Time
+}
+
+// Orders of a course roster, the ?sort= values of GET
+// /api/courses/{id}/students. Ties are broken by student ID.
+const (
+	RosterSortID         = "id"
+	RosterSortName       = "name"
+	RosterSortEmail      = "email"
+	RosterSortEnrolledAt = "enrolled_at"
+)
+
+// RosterSorts lists every roster order.
+var RosterSorts = []string{RosterSortID, RosterSortName, RosterSortEmail, RosterSortEnrolledAt}
+
+// RosterSort is the order of a roster page: one of the RosterSort*
+// fields, ascending unless Desc. Names compare case-insensitively.
+type RosterSort struct {
+	Field string
+	Desc  bool
+}
+
+// Photo types accepted by PUT /api/students/{id}/photo, detected from the
+// bytes themselves.
+const (
+	PhotoJPEG = "image/jpeg"
+	PhotoPNG  = "image/png"
+)
+
+// Photo is the profile photo of a student. ETag identifies the bytes (a
+// hash of Data), UpdatedAt is when it was uploaded; both are set by the
+// handler before it is stored.
+type Photo struct {
+	ContentType string
+	Data        []byte
+	ETag        string
+	UpdatedAt   time.Time
+}
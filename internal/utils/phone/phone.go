@@ -0,0 +1,202 @@
+package phone // phone package turns the usual ways of writing a phone number into E.164
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - strings → trim the input, look countries up case-insensitively
+*/
+import (
+	"strings"
+)
+
+/*
+country STRUCT
+-------------------------------------------------------------
+  - code is the calling code ("44"), trunk the prefix dialled
+    before national numbers inside the country ("0"; "" when
+    numbers keep their leading digit, like in Italy).
+  - minLen / maxLen bound the national significant number (the
+    digits after the calling code), from the country's
+    numbering plan.
+*/
+type country struct {
+	code           string
+	trunk          string
+	minLen, maxLen int
+}
+
+/*
+countries
+-------------------------------------------------------------
+
+	WHY NOT libphonenumber?
+	  → Its metadata is megabytes and changes every month; for
+	    a student record "is this a plausible number, in one
+	    spelling" is enough.
+	  → So this is the common countries with their lengths.
+	    International numbers with another calling code are
+	    still accepted, checked only against E.164's 15 digits.
+*/
+var countries = map[string]country{
+	"AE": {"971", "0", 8, 9},
+	"AR": {"54", "0", 10, 10},
+	"AT": {"43", "0", 4, 13},
+	"AU": {"61", "0", 9, 9},
+	"BD": {"880", "0", 10, 10},
+	"BE": {"32", "0", 8, 9},
+	"BR": {"55", "0", 10, 11},
+	"CA": {"1", "1", 10, 10},
+	"CH": {"41", "0", 9, 9},
+	"CN": {"86", "0", 7, 11},
+	"DE": {"49", "0", 6, 13},
+	"DK": {"45", "", 8, 8},
+	"EG": {"20", "0", 9, 10},
+	"ES": {"34", "", 9, 9},
+	"FI": {"358", "0", 5, 12},
+	"FR": {"33", "0", 9, 9},
+	"GB": {"44", "0", 9, 10},
+	"IE": {"353", "0", 7, 9},
+	"IL": {"972", "0", 8, 9},
+	"IN": {"91", "0", 10, 10},
+	"IT": {"39", "", 6, 11},
+	"JP": {"81", "0", 9, 10},
+	"KE": {"254", "0", 9, 9},
+	"KR": {"82", "0", 8, 10},
+	"MX": {"52", "", 10, 10},
+	"NG": {"234", "0", 8, 10},
+	"NL": {"31", "0", 9, 9},
+	"NO": {"47", "", 8, 8},
+	"NZ": {"64", "0", 8, 10},
+	"PK": {"92", "0", 9, 10},
+	"PL": {"48", "", 9, 9},
+	"PT": {"351", "", 9, 9},
+	"RU": {"7", "8", 10, 10},
+	"SA": {"966", "0", 9, 9},
+	"SE": {"46", "0", 7, 9},
+	"SG": {"65", "", 8, 8},
+	"TR": {"90", "0", 10, 10},
+	"US": {"1", "1", 10, 10},
+	"ZA": {"27", "0", 9, 9},
+}
+
+// E.164 bounds: a number is at most 15 digits, calling code included; below
+// 8 it is a short code or a typo.
+const (
+	minDigits = 8
+	maxDigits = 15
+)
+
+// IsCountry reports whether code (ISO 3166 alpha-2, any case) is a country
+// national numbers can be read for.
+func IsCountry(code string) bool {
+	_, ok := countries[strings.ToUpper(code)]
+	return ok
+}
+
+/*
+Normalize()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Returns number in E.164 form, "+" and digits only:
+	      "+1 (415) 555-0123"    → "+14155550123"
+	      "0044 20 7946 0958"    → "+442079460958"
+	      "+44 (0)20 7946 0958"  → "+442079460958"
+	      "0044 (0)20 7946 0958" → "+442079460958"
+	      "020 7946 0958", GB    → "+442079460958"
+
+	RULES:
+	  → Spaces, dashes, dots and parentheses are separators.
+	    Anything else but digits and one leading "+" is invalid.
+	  → "+" or "00" starts an international number. A national
+	    one needs defaultCountry (ISO 3166 alpha-2): its trunk
+	    prefix ("0") is dropped and its calling code added.
+	  → The digits after the calling code must fit the
+	    country's lengths when it is in countries.
+
+	RETURN VALUE:
+	  → the E.164 number, and false when number is not a valid
+	    one (or is national and defaultCountry is "" / unknown)
+*/
+func Normalize(number, defaultCountry string) (string, bool) {
+	number = strings.TrimSpace(number)
+
+	international := false
+	for _, prefix := range []string{"+", "00"} {
+		if rest, found := strings.CutPrefix(number, prefix); found {
+			// "+44 (0)20…": the trunk 0 some people keep in brackets
+			number, international = strings.Replace(rest, "(0)", "", 1), true
+			break
+		}
+	}
+
+	digits, ok := stripSeparators(number)
+	if !ok || digits == "" {
+		return "", false
+	}
+
+	if !international {
+		c, known := countries[strings.ToUpper(defaultCountry)]
+		if !known {
+			return "", false
+		}
+
+		// 020 7946 0958 loses its 0, a 10-digit Russian number
+		// starting with 8 keeps it: the trunk only goes if enough
+		// digits are left
+		national := digits
+		if rest, found := strings.CutPrefix(national, c.trunk); found && c.trunk != "" && len(rest) >= c.minLen {
+			national = rest
+		}
+		digits = c.code + national
+	}
+
+	if !valid(digits) {
+		return "", false
+	}
+
+	return "+" + digits, true
+}
+
+// IsE164 reports whether number is already what Normalize returns.
+func IsE164(number string) bool {
+	normalized, ok := Normalize(number, "")
+	return ok && normalized == number
+}
+
+// stripSeparators drops the separators of number and reports false when
+// anything but digits is left.
+func stripSeparators(number string) (string, bool) {
+	var digits strings.Builder
+
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+
+	return digits.String(), true
+}
+
+// valid checks the digits of an international number (no "+"): the E.164
+// length, no leading 0, and the country's lengths when the calling code is
+// a known one.
+func valid(digits string) bool {
+	if len(digits) < minDigits || len(digits) > maxDigits || digits[0] == '0' {
+		return false
+	}
+
+	// calling codes are prefix-free: at most one of them matches
+	for _, c := range countries {
+		if national, found := strings.CutPrefix(digits, c.code); found {
+			return len(national) >= c.minLen && len(national) <= c.maxLen
+		}
+	}
+
+	return true
+}
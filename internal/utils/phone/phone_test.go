@@ -0,0 +1,70 @@
+package phone_test
+
+import (
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/phone"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		country string
+		want    string // "" when invalid
+	}{
+		// separators
+		{"spaces", "+44 20 7946 0958", "", "+442079460958"},
+		{"dashes", "+1-415-555-0123", "", "+14155550123"},
+		{"dots and parentheses", "+1 (415) 555.0123", "", "+14155550123"},
+		{"surrounding whitespace", "  +442079460958 ", "", "+442079460958"},
+		{"letters", "+44 20 7946 CALL", "", ""},
+		{"two pluses", "++442079460958", "", ""},
+
+		// explicit country code
+		{"+CC", "+442079460958", "", "+442079460958"},
+		{"+CC, other default", "+442079460958", "US", "+442079460958"},
+		{"00 for +", "0044 20 7946 0958", "", "+442079460958"},
+		{"kept trunk in brackets", "+44 (0)20 7946 0958", "", "+442079460958"},
+		{"unknown calling code", "+999 1234 5678", "", "+99912345678"},
+		{"too short for the country", "+44 20 7946", "", ""},
+		{"too long for E.164", "+999 1234 5678 9012 3", "", ""},
+
+		// leading zeros and national numbers
+		{"national, trunk 0 dropped", "020 7946 0958", "GB", "+442079460958"},
+		{"national, default in lower case", "020 7946 0958", "gb", "+442079460958"},
+		{"national without a default", "020 7946 0958", "", ""},
+		{"national, unknown default", "020 7946 0958", "XX", ""},
+		{"no trunk prefix, leading 0 kept", "06 1234 5678", "IT", "+390612345678"},
+		{"trunk 8 kept when it is a digit", "8123456789", "RU", "+78123456789"},
+		{"leading 0 after +", "+0442079460958", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := phone.Normalize(tt.number, tt.country)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("Normalize(%q, %q) = %q, %t; want %q", tt.number, tt.country, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsE164(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"+442079460958", true},
+		{"+44 20 7946 0958", false},
+		{"442079460958", false},
+		{"0044 20 7946 0958", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := phone.IsE164(tt.number); got != tt.want {
+			t.Errorf("IsE164(%q) = %t, want %t", tt.number, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package response
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - encoding/json → the *json.UnmarshalTypeError being explained
+   - errors        → strconv.ErrRange
+   - fmt           → build the field message
+   - math          → the int64 bounds a giant number is checked against
+   - reflect       → what type the field expected
+   - strconv       → tell "too big" apart from "not an integer"
+   - strings       → JSON path of the field
+*/
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*
+TypeError()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Turns the *json.UnmarshalTypeError of a request body
+	    into a 400 naming the field, in the same shape as
+	    ValidationError, instead of encoding/json's
+	    "cannot unmarshal number 20.5 into Go struct field
+	    Student.age of type int".
+	  → Integers must be written as integers: 20.5, 20.0, 1e3
+	    and "20" are all "age must be an integer", a value
+	    past int64 is "age is out of range", also when it is
+	    written as 1e30 or 1e400. Nothing is ever truncated or
+	    converted.
+
+	RETURNS:
+	  Response{
+	      Status: "Error",
+	      Code:   "validation_failed",
+	      Error:  "age must be an integer",
+	      Errors: [{Field: "age", Tag: "type", Message: "age must be an integer"}],
+	  }
+	  → a bad_request "body must be a JSON object" when the
+	    body itself has the wrong type ([1], "x", …)
+*/
+func TypeError(err *json.UnmarshalTypeError) Response {
+	if err.Field == "" {
+		return BadRequest("body must be a JSON object")
+	}
+
+	field := typeErrorField(err.Field)
+	msg := typeMessage(field, err)
+
+	return Response{
+		Status: StatusError,
+		Code:   CodeValidation,
+		Error:  msg,
+		Errors: []FieldError{{Field: field, Tag: "type", Message: msg}},
+	}
+}
+
+// typeErrorField writes the dotted path of encoding/json ("tags.0",
+// "address.city") the way fieldName does: "tags[0]", "address.city".
+func typeErrorField(path string) string {
+	parts := strings.Split(path, ".")
+
+	var b strings.Builder
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(part)
+	}
+
+	return b.String()
+}
+
+// typeMessage names the JSON type the field should have had.
+func typeMessage(field string, err *json.UnmarshalTypeError) string {
+	kind := err.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = err.Type.Elem().Kind()
+	}
+
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// Value is "number 20.5" for numbers, the JSON type otherwise
+		literal, isNumber := strings.CutPrefix(err.Value, "number ")
+		if isNumber && outOfRange(literal) {
+			return fmt.Sprintf("%s is out of range", field)
+		}
+		return fmt.Sprintf("%s must be an integer", field)
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%s must be a number", field)
+	case reflect.String:
+		return fmt.Sprintf("%s must be a string", field)
+	case reflect.Bool:
+		return fmt.Sprintf("%s must be true or false", field)
+	case reflect.Slice, reflect.Array:
+		return fmt.Sprintf("%s must be an array", field)
+	case reflect.Struct, reflect.Map:
+		return fmt.Sprintf("%s must be an object", field)
+	default:
+		return fmt.Sprintf("%s has the wrong type", field)
+	}
+}
+
+// outOfRange reports whether the JSON number literal is past int64, in
+// integer or float notation: 1e400 parses to ±Inf, 1e-400 to 0.
+func outOfRange(literal string) bool {
+	if _, err := strconv.ParseInt(literal, 10, 64); errors.Is(err, strconv.ErrRange) {
+		return true
+	}
+
+	f, _ := strconv.ParseFloat(literal, 64)
+	return math.Abs(f) >= math.MaxInt64
+}
@@ -0,0 +1,73 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+func TestTypeError(t *testing.T) {
+	// the field types of the request bodies
+	type body struct {
+		Age     int      `json:"age"`
+		Version *int     `json:"version"`
+		Score   float64  `json:"score"`
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Address struct {
+			City string `json:"city"`
+		} `json:"address"`
+	}
+
+	tests := []struct {
+		name  string
+		json  string
+		field string
+		want  string
+	}{
+		{"fraction", `{"age": 20.5}`, "age", "age must be an integer"},
+		{"integral float", `{"age": 20.0}`, "age", "age must be an integer"},
+		{"exponent", `{"age": 1e3}`, "age", "age must be an integer"},
+		{"tiny exponent", `{"age": 1e-400}`, "age", "age must be an integer"},
+		{"string", `{"age": "20"}`, "age", "age must be an integer"},
+		{"past int64", `{"age": 9223372036854775808}`, "age", "age is out of range"},
+		{"before int64", `{"age": -9223372036854775809}`, "age", "age is out of range"},
+		{"huge exponent", `{"age": 1e30}`, "age", "age is out of range"},
+		{"past float64", `{"age": 1e400}`, "age", "age is out of range"},
+		{"pointer", `{"version": 1.5}`, "version", "version must be an integer"},
+		{"number", `{"score": "high"}`, "score", "score must be a number"},
+		{"array item", `{"tags": ["a", 1]}`, "tags[1]", "tags[1] must be a string"},
+		{"array", `{"tags": "a"}`, "tags", "tags must be an array"},
+		{"nested", `{"address": {"city": 7}}`, "address.city", "address.city must be a string"},
+		{"object", `{"address": []}`, "address", "address must be an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var typeErr *json.UnmarshalTypeError
+			if err := json.Unmarshal([]byte(tt.json), new(body)); !errors.As(err, &typeErr) {
+				t.Fatalf("Unmarshal(%s): %v, want an *json.UnmarshalTypeError", tt.json, err)
+			}
+
+			got := response.TypeError(typeErr)
+			if got.Status != response.StatusError || got.Code != response.CodeValidation || got.Error != tt.want {
+				t.Errorf("got %+v, want %q", got, tt.want)
+			}
+			want := response.FieldError{Field: tt.field, Tag: "type", Message: tt.want}
+			if len(got.Errors) != 1 || got.Errors[0] != want {
+				t.Errorf("errors %+v, want [%+v]", got.Errors, want)
+			}
+		})
+	}
+
+	// the body itself has the wrong type
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal([]byte(`[1]`), new(body)); !errors.As(err, &typeErr) {
+		t.Fatalf("Unmarshal([1]): %v", err)
+	}
+	if got := response.TypeError(typeErr); got.Error != "body must be a JSON object" || got.Code != response.CodeBadRequest || got.Errors != nil {
+		t.Errorf("array body: got %+v", got)
+	}
+}
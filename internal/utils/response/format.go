@@ -0,0 +1,62 @@
+package response
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - net/http → http.ResponseWriter
+*/
+import (
+	"net/http"
+)
+
+/*
+Format STRUCT
+-------------------------------------------------------------
+  - How WriteJson renders bodies for one request, decided from
+    the request by middleware.Negotiate:
+      - Problem  → error Responses are sent as
+                   application/problem+json (see WriteProblem)
+      - Instance → the request path, the problem's "instance"
+      - Pretty   → indent the JSON (?pretty), for humans on curl
+  - WriteJson only receives the ResponseWriter, so the Format
+    travels on it (WithFormat) instead of in a new parameter.
+*/
+type Format struct {
+	Problem  bool
+	Instance string
+	Pretty   bool
+}
+
+// formatWriter is the ResponseWriter returned by WithFormat.
+type formatWriter struct {
+	http.ResponseWriter
+	format Format
+}
+
+// Unwrap lets http.ResponseController (and formatOf) reach the original writer.
+func (fw *formatWriter) Unwrap() http.ResponseWriter {
+	return fw.ResponseWriter
+}
+
+// WithFormat returns w carrying format for every WriteJson call made on it
+// (or on any writer wrapping it).
+func WithFormat(w http.ResponseWriter, format Format) http.ResponseWriter {
+	return &formatWriter{ResponseWriter: w, format: format}
+}
+
+// formatOf finds the Format attached to w, following Unwrap through the
+// writers middleware wrapped around it. No Format → the zero value (plain
+// JSON).
+func formatOf(w http.ResponseWriter) Format {
+	for {
+		switch fw := w.(type) {
+		case *formatWriter:
+			return fw.format
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = fw.Unwrap()
+		default:
+			return Format{}
+		}
+	}
+}
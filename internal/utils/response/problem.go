@@ -0,0 +1,156 @@
+package response
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - mime     → parse the media ranges of an Accept header
+   - net/http → status texts, http.ResponseWriter
+   - strconv  → q-values
+   - strings  → split the Accept header
+*/
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 bodies.
+const ProblemContentType = "application/problem+json"
+
+/*
+Problem STRUCT
+-------------------------------------------------------------
+  - RFC 7807 "Problem Details" body, the alternative error
+    format for clients that ask for application/problem+json.
+  - type     → "about:blank": the status says what happened
+  - title    → the HTTP status text ("Not Found")
+  - status   → the HTTP status code again
+  - detail   → the same message Response.Error carries
+  - instance → the request path
+  - code / invalid-params → extensions: the stable error code,
+    and one entry per failing field on validation errors
+*/
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is one entry of Problem.InvalidParams.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+/*
+WriteProblem()
+-------------------------------------------------------------
+   PURPOSE:
+     → WriteJson with the application/problem+json media type.
+     → Missing type / title / status are filled in from status.
+*/
+func WriteProblem(w http.ResponseWriter, status int, problem Problem) error {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(status)
+	}
+	if problem.Status == 0 {
+		problem.Status = status
+	}
+
+	return writeEncoded(w, status, ProblemContentType, problem)
+}
+
+// problemFrom converts an error Response into the equivalent Problem.
+func problemFrom(status int, resp Response, instance string) Problem {
+	problem := Problem{
+		Status:   status,
+		Detail:   resp.Error,
+		Instance: instance,
+		Code:     resp.Code,
+	}
+
+	for _, fieldErr := range resp.Errors {
+		problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+			Name:   fieldErr.Field,
+			Reason: fieldErr.Message,
+		})
+	}
+
+	return problem
+}
+
+/*
+PrefersProblem()
+-------------------------------------------------------------
+   PURPOSE:
+     → Content negotiation for error bodies: reports whether an
+       Accept header prefers application/problem+json over the
+       default application/json.
+
+   RULES:
+     → problem+json must be listed explicitly: the wildcard
+       ranges match both types equally, and ties keep the
+       default.
+     → Listed with a q-value at least as high as the best range
+       matching application/json → problem+json.
+     → No Accept, or only unknown types → default JSON (errors
+       are never turned into 406).
+*/
+func PrefersProblem(accept string) bool {
+	problemQ, explicit := acceptQuality(accept, ProblemContentType)
+	if !explicit || problemQ == 0 {
+		return false
+	}
+
+	jsonQ, _ := acceptQuality(accept, "application/json")
+	return problemQ >= jsonQ
+}
+
+// acceptQuality returns the q-value the most specific matching range of
+// accept gives mediaType, and whether that range named it exactly.
+func acceptQuality(accept, mediaType string) (float64, bool) {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		var s int
+		switch {
+		case rangeType == mediaType:
+			s = 2
+		case rangeType == mainType+"/*":
+			s = 1
+		case rangeType == "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(raw, 64); err == nil && parsed >= 0 && parsed <= 1 {
+				q = parsed
+			}
+		}
+		quality, specificity = q, s
+	}
+
+	return quality, specificity == 2
+}
@@ -0,0 +1,36 @@
+package response_test
+
+import (
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+)
+
+func TestPrefersProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/*", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/problem+json, application/json", true},
+		{"application/json, application/problem+json;q=0.9", false},
+		{"application/json;q=0.5, application/problem+json", true},
+		{"application/problem+json;q=0.8, */*;q=0.8", true},
+		{"application/problem+json;q=0", false},
+		{"application/problem+json, application/json;q=2", true},
+		{"text/html, application/xml", false},
+		{"not a media type", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := response.PrefersProblem(tt.accept); got != tt.want {
+				t.Errorf("PrefersProblem(%q) = %t, want %t", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
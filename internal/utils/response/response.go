@@ -4,16 +4,22 @@ package response // response package holds helper methods to send JSON response
    ---------------------------------------------------------
    IMPORTS
    ---------------------------------------------------------
+   - bytes         → buffer the JSON before anything is written.
    - encoding/json → used to encode Go structs or maps into JSON.
    - fmt           → used for building formatted error messages.
+   - slog          → log encode failures (they are server bugs).
    - net/http      → used to set headers & manage HTTP response codes.
+   - reflect       → used to tell string length rules apart from number rules.
    - strings       → used to join error messages for validation.
    - validator/v10 → used to detect validation errors returned by validator.
 */
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -26,10 +32,31 @@ Response STRUCT
    - Fields are exported (capital letter) so JSON encoder can access them.
    - json:"status" → key inside the JSON output will be "status".
    - json:"error"  → key inside JSON output will be "error".
+   - json:"code"   → machine-readable error code (CodeNotFound…) so
+                     clients don't have to parse English text.
+   - json:"errors" → per-field details; only present for validation
+                     failures (omitempty hides it everywhere else).
 */
 type Response struct {
-	Status string `json:"status"`
-	Error  string `json:"error"`
+	Status string       `json:"status"`
+	Code   string       `json:"code,omitempty"`
+	Error  string       `json:"error"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+/*
+FieldError STRUCT
+-------------------------------------------------------------
+   - One entry per failing field, so frontends can attach the
+     message to the right form input without parsing text.
+   - field   → JSON path of the field ("name", "address.city")
+   - tag     → the validate rule that failed ("required", "email")
+   - message → human-readable text for that field
+*/
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
 }
 
 /*
@@ -43,6 +70,29 @@ const (
 	StatusError = "Error"
 )
 
+/*
+ERROR CODES
+-------------------------------------------------------------
+   - Stable values for Response.Code.
+   - Clients should switch on these, never on the message text.
+*/
+const (
+	CodeValidation   = "validation_failed"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeTimeout      = "timeout"
+	CodeBadRequest   = "bad_request"
+	CodeUnavailable  = "unavailable"
+
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePreconditionRequired = "precondition_required"
+	CodeIdempotencyConflict  = "idempotency_conflict"
+)
+
 /*
 WriteJson()
 -------------------------------------------------------------
@@ -56,23 +106,132 @@ WriteJson()
      - w      : http.ResponseWriter → used to send output to client.
      - status : integer → HTTP status code (200, 201, 400 etc.)
      - data   : interface{} → any data you want to send as JSON.
+
+   PROBLEM DETAILS:
+     → When the request asked for application/problem+json (see
+       Format), error Responses are sent as a Problem instead.
 */
 func WriteJson(w http.ResponseWriter, status int, data interface{}) error {
+	if resp, ok := data.(Response); ok && resp.Status == StatusError {
+		if format := formatOf(w); format.Problem {
+			return WriteProblem(w, status, problemFrom(status, resp, format.Instance))
+		}
+	}
 
-	// 1. Set header so browser/Postman knows data is JSON
-	w.Header().Set("Content-Type", "application/json")
+	return writeEncoded(w, status, "application/json", data)
+}
 
-	// 2. Must write HTTP status before writing the body
-	w.WriteHeader(status)
+/*
+writeEncoded()
+-------------------------------------------------------------
+   PURPOSE:
+     → The encoding part of WriteJson / WriteProblem, which only
+       differ in the Content-Type they send.
+*/
+func writeEncoded(w http.ResponseWriter, status int, contentType string, data any) error {
 
 	/*
-	   3. Encode the data into JSON:
-	      json.NewEncoder(w).Encode(data)
+	   1. Encode the data into a buffer FIRST:
+	      json.NewEncoder(&buf).Encode(data)
 	      - Converts "data" into JSON.
-	      - Writes JSON directly into HTTP response.
-	      - If any error occurs, Encode returns an error.
+	      - Nothing reaches the client yet, so if encoding fails
+	        (a channel value, a broken MarshalJSON…) we can still
+	        send a clean 500 instead of "200 + half a body".
 	*/
-	return json.NewEncoder(w).Encode(data)
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if formatOf(w).Pretty {
+		encoder.SetIndent("", "  ")
+	}
+
+	if err := encoder.Encode(data); err != nil {
+		slog.Error("failed to encode json response",
+			slog.Int("status", status),
+			slog.String("error", err.Error()),
+		)
+
+		// The real error is for the logs only; the client gets a generic message
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(Internal("internal server error"))
+
+		return err
+	}
+
+	// 2. Set header so browser/Postman knows data is JSON
+	w.Header().Set("Content-Type", contentType)
+
+	// 3. Must write HTTP status before writing the body
+	w.WriteHeader(status)
+
+	// 4. Encoding succeeded → send the buffered bytes
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+/*
+WriteNoContent()
+-------------------------------------------------------------
+   PURPOSE:
+     → Sends "204 No Content" for successful calls that
+       have nothing to return (e.g. DELETE).
+
+   WHY NO Content-Type?
+     → A 204 response must not carry a body, so there is
+       no JSON to describe.
+*/
+func WriteNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/*
+WriteNotModified()
+-------------------------------------------------------------
+
+   PURPOSE:
+     → Sends "304 Not Modified" when a conditional GET
+       (If-None-Match) shows the client's copy is current.
+
+   WHY THE ETag AGAIN?
+     → A 304 has no body, but it must repeat the validator so
+       caches can refresh the copy they keep.
+*/
+func WriteNotModified(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+/*
+PagedResponse / Meta STRUCTS
+-------------------------------------------------------------
+   - Envelope for list endpoints:
+       {"data":[...],"meta":{"total":1234,"limit":50,"offset":100}}
+   - total counts every matching row, not just this page, so
+     clients can render "page 3 of 12".
+   - next_cursor is only set in cursor mode, and only when more
+     rows follow; pass it back as ?cursor= for the next page.
+*/
+type PagedResponse struct {
+	Data any  `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+type Meta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+/*
+WriteJsonWithMeta()
+-------------------------------------------------------------
+   PURPOSE:
+     → WriteJson for list endpoints: wraps data and meta in a
+       PagedResponse so every list uses the same envelope.
+*/
+func WriteJsonWithMeta(w http.ResponseWriter, status int, data any, meta Meta) error {
+	return WriteJson(w, status, PagedResponse{Data: data, Meta: meta})
 }
 
 /*
@@ -98,6 +257,121 @@ func GeneralError(err error) Response {
 	}
 }
 
+/*
+NotFound() / Conflict() / Internal() / Unauthorized() / Timeout() / …
+-------------------------------------------------------------
+   PURPOSE:
+     → Error responses that carry both a message and the
+       matching machine-readable code.
+
+   EXAMPLE:
+     response.NotFound("student with id 7 not found")
+       → {"status":"Error","code":"not_found","error":"student with id 7 not found"}
+*/
+func NotFound(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeNotFound,
+		Error:  msg,
+	}
+}
+
+func Conflict(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeConflict,
+		Error:  msg,
+	}
+}
+
+func PreconditionFailed(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodePreconditionFailed,
+		Error:  msg,
+	}
+}
+
+func PreconditionRequired(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodePreconditionRequired,
+		Error:  msg,
+	}
+}
+
+func Internal(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeInternal,
+		Error:  msg,
+	}
+}
+
+func Unauthorized(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeUnauthorized,
+		Error:  msg,
+	}
+}
+
+// Forbidden is for callers that are authenticated but whose role doesn't
+// allow the request (403), unlike Unauthorized (401, no valid credentials).
+func Forbidden(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeForbidden,
+		Error:  msg,
+	}
+}
+
+func Timeout(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeTimeout,
+		Error:  msg,
+	}
+}
+
+// BadRequest is for requests that can't be read at all: a malformed query
+// parameter or body, unlike ValidationError (well-formed, invalid values).
+func BadRequest(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeBadRequest,
+		Error:  msg,
+	}
+}
+
+func MethodNotAllowed(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeMethodNotAllowed,
+		Error:  msg,
+	}
+}
+
+// Unavailable is for a 503 the client should retry later (server busy),
+// unlike Timeout (the request itself took too long).
+func Unavailable(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeUnavailable,
+		Error:  msg,
+	}
+}
+
+// IdempotencyConflict is for an Idempotency-Key reused with another
+// request (422): retrying can't help, the client needs a new key.
+func IdempotencyConflict(msg string) Response {
+	return Response{
+		Status: StatusError,
+		Code:   CodeIdempotencyConflict,
+		Error:  msg,
+	}
+}
+
 /*
 ValidationError()
 -------------------------------------------------------------
@@ -106,37 +380,147 @@ ValidationError()
 
    FLOW:
      - Loop through all validation errors.
-     - Check validation type using err.ActualTag() (“required”, “email”, etc.)
-     - Build a user-friendly error message.
-     - Combine all messages into a single string.
+     - Build one FieldError per failing field (see fieldMessage).
+     - Combine all messages into a single summary string.
+
+   FIELD NAMES:
+     → fieldName() uses the JSON path ("name", "address.city"),
+       which needs the validator to have a RegisterTagNameFunc
+       returning the json tag (see the validation package).
 
    RETURNS:
      Response{
          Status: "Error",
-         Error:  "field X is required, field Y is invalid"
+         Code:   "validation_failed",
+         Error:  "name is required, email must be a valid email address",
+         Errors: [
+             {Field: "name",  Tag: "required", Message: "name is required"},
+             {Field: "email", Tag: "email",    Message: "email must be a valid email address"},
+         ],
      }
 */
 func ValidationError(errs validator.ValidationErrors) Response {
 	var errMsg []string // slice to collect all error messages
+	fieldErrs := make([]FieldError, 0, len(errs))
 
 	for _, err := range errs {
-		switch err.ActualTag() {
+		msg := fieldMessage(err)
 
-		// If struct tag validation = required
-		case "required":
-			errMsg = append(errMsg,
-				fmt.Sprintf("field %s is required field", err.Field()))
-
-		// For all other validation types
-		default:
-			errMsg = append(errMsg,
-				fmt.Sprintf("field %s is invalid", err.Field()))
-		}
+		errMsg = append(errMsg, msg)
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   fieldName(err),
+			Tag:     err.ActualTag(),
+			Message: msg,
+		})
 	}
 
 	// Join messages into single string:  "msg1, msg2, msg3"
 	return Response{
 		Status: StatusError,
+		Code:   CodeValidation,
 		Error:  strings.Join(errMsg, ", "),
+		Errors: fieldErrs,
+	}
+}
+
+/*
+fieldMessage()
+-------------------------------------------------------------
+   PURPOSE:
+     → Builds the message for ONE failing field.
+     → Check validation type using err.ActualTag() (“required”, “email”, etc.)
+*/
+func fieldMessage(err validator.FieldError) string {
+	field := fieldName(err)
+
+	switch err.ActualTag() {
+
+	// If struct tag validation = required
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+
+	// validate:"required_without=X" → required unless X is sent
+	case "required_without":
+		return fmt.Sprintf("%s is required", field)
+
+	// validate:"email"
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+
+	// validate:"gte=N" / validate:"lte=N" → the validator reports only the
+	// bound that failed, so name that one ("age must be at most 150")
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s%s", field, err.Param(), unit(err))
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s%s", field, err.Param(), unit(err))
+
+	// validate:"min=N" / validate:"max=N" → length for strings, value for numbers
+	case "min":
+		return fmt.Sprintf("%s must be at least %s%s", field, err.Param(), unit(err))
+	case "max":
+		return fmt.Sprintf("%s must be at most %s%s", field, err.Param(), unit(err))
+
+	// validate:"oneof=a b c" → list the allowed values
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, strings.Join(strings.Fields(err.Param()), ", "))
+
+	// validate:"dob" (types.IsDateOfBirth) and the age_dob struct rule
+	case "dob":
+		return fmt.Sprintf("%s must be a YYYY-MM-DD date for an age of 3 to 120", field)
+	case "age_dob":
+		return fmt.Sprintf("%s does not match date_of_birth", field)
+
+	// validate:"phone" → E.164 once normalized, see the phone package
+	case "phone":
+		return fmt.Sprintf("%s must be a valid phone number, with its country code (+44 20 7946 0958)", field)
+
+	// validate:"dive,tag" → one entry of a list of tags (types.IsTag)
+	case "tag":
+		return fmt.Sprintf("%s must be 1-32 lower-case letters, digits, - or _, starting with a letter or digit", field)
+
+	// validate:"nocontrol" → no NUL, escape, … (types.HasNoControl)
+	case "nocontrol":
+		return fmt.Sprintf("%s must not contain control characters", field)
+
+	// For all other validation types
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+/*
+fieldName()
+-------------------------------------------------------------
+   PURPOSE:
+     → Returns the dotted path of the failing field without
+       the top-level struct name.
+
+   EXAMPLE:
+     err.Namespace() = "Student.address.city" → "address.city"
+*/
+func fieldName(err validator.FieldError) string {
+	_, path, found := strings.Cut(err.Namespace(), ".")
+	if !found {
+		return err.Field()
+	}
+
+	return path
+}
+
+/*
+unit()
+-------------------------------------------------------------
+   PURPOSE:
+     → min/max mean "length" for strings and "count" for
+       slices/maps, but a plain value for numbers.
+*/
+func unit(err validator.FieldError) string {
+	switch err.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
 	}
 }
@@ -0,0 +1,194 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// form has one field per kind of rule ValidationError words differently.
+type form struct {
+	Name    string   `json:"name" validate:"required,min=2"`
+	Email   string   `json:"email,omitempty" validate:"omitempty,email"`
+	Age     int      `json:"age" validate:"gte=1,lte=150"`
+	Status  string   `json:"status" validate:"oneof=active suspended"`
+	Tags    []string `json:"tags" validate:"max=2,dive,min=1"`
+	Address struct {
+		City string `json:"city" validate:"required"`
+	} `json:"address"`
+}
+
+// valid is a form that passes; the cases break one part of it.
+func valid() form {
+	f := form{Name: "Ann Lee", Age: 20, Status: "active"}
+	f.Address.City = "York"
+
+	return f
+}
+
+// TestValidationError checks the structured error shape: one FieldError
+// per failing field, by JSON path, with the failed tag and its message,
+// and the messages joined in Error.
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name  string
+		spoil func(*form)
+		want  []response.FieldError
+	}{
+		{"required", func(f *form) { f.Name = "" }, []response.FieldError{
+			{Field: "name", Tag: "required", Message: "name is required"},
+		}},
+		{"string length", func(f *form) { f.Name = "A" }, []response.FieldError{
+			{Field: "name", Tag: "min", Message: "name must be at least 2 characters"},
+		}},
+		{"email", func(f *form) { f.Email = "not-an-email" }, []response.FieldError{
+			{Field: "email", Tag: "email", Message: "email must be a valid email address"},
+		}},
+		{"number bounds", func(f *form) { f.Age = 151 }, []response.FieldError{
+			{Field: "age", Tag: "lte", Message: "age must be at most 150"},
+		}},
+		{"oneof", func(f *form) { f.Status = "gone" }, []response.FieldError{
+			{Field: "status", Tag: "oneof", Message: "status must be one of active, suspended"},
+		}},
+		{"item count", func(f *form) { f.Tags = []string{"a", "b", "c"} }, []response.FieldError{
+			{Field: "tags", Tag: "max", Message: "tags must be at most 2 items"},
+		}},
+		{"list entry", func(f *form) { f.Tags = []string{"a", ""} }, []response.FieldError{
+			{Field: "tags[1]", Tag: "min", Message: "tags[1] must be at least 1 characters"},
+		}},
+		{"nested", func(f *form) { f.Address.City = "" }, []response.FieldError{
+			{Field: "address.city", Tag: "required", Message: "address.city is required"},
+		}},
+		{"several, in field order", func(f *form) { f.Name, f.Age = "", 0 }, []response.FieldError{
+			{Field: "name", Tag: "required", Message: "name is required"},
+			{Field: "age", Tag: "gte", Message: "age must be at least 1"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := valid()
+			tt.spoil(&f)
+
+			var errs validator.ValidationErrors
+			if err := validation.Struct(f); !errors.As(err, &errs) {
+				t.Fatalf("Struct: %v, want validator.ValidationErrors", err)
+			}
+
+			got := response.ValidationError(errs)
+			if got.Status != response.StatusError || got.Code != response.CodeValidation {
+				t.Errorf("status %q, code %q", got.Status, got.Code)
+			}
+			if !slices.Equal(got.Errors, tt.want) {
+				t.Errorf("errors\n got %+v\nwant %+v", got.Errors, tt.want)
+			}
+			var messages []string
+			for _, fe := range tt.want {
+				messages = append(messages, fe.Message)
+			}
+			if want := strings.Join(messages, ", "); got.Error != want {
+				t.Errorf("error %q, want %q", got.Error, want)
+			}
+		})
+	}
+}
+
+// TestErrorShape checks the error bodies on the wire: code and errors are
+// left out when there are none, so a general error is just status and
+// error.
+func TestErrorShape(t *testing.T) {
+	f := valid()
+	f.Name, f.Address.City = "A", ""
+	var errs validator.ValidationErrors
+	if !errors.As(validation.Struct(f), &errs) {
+		t.Fatal("form should fail validation")
+	}
+
+	tests := []struct {
+		name string
+		resp response.Response
+		want string
+	}{
+		{
+			"general", response.GeneralError(errors.New("empty body")),
+			`{"status":"Error","error":"empty body"}`,
+		},
+		{
+			"with a code", response.NotFound("student with id 7 not found"),
+			`{"status":"Error","code":"not_found","error":"student with id 7 not found"}`,
+		},
+		{
+			"validation", response.ValidationError(errs),
+			`{"status":"Error","code":"validation_failed","error":"name must be at least 2 characters, address.city is required",` +
+				`"errors":[{"field":"name","tag":"min","message":"name must be at least 2 characters"},` +
+				`{"field":"address.city","tag":"required","message":"address.city is required"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// broken is a value whose MarshalJSON fails.
+type broken struct{}
+
+func (broken) MarshalJSON() ([]byte, error) { return nil, errors.New("cannot marshal") }
+
+// TestWriteJson checks WriteJson encodes before writing anything: a value
+// that fails to encode, even after parts that would, gives a clean 500
+// with the generic internal error instead of the status and half a body.
+func TestWriteJson(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   any
+		status int
+		want   string
+	}{
+		{"encodes", map[string]int{"count": 2}, http.StatusOK, `{"count":2}`},
+		{"failing MarshalJSON", broken{}, http.StatusInternalServerError, `{"status":"Error","code":"internal","error":"internal server error"}`},
+		{"failing after a good part", []any{"first", broken{}}, http.StatusInternalServerError, `{"status":"Error","code":"internal","error":"internal server error"}`},
+		{"unsupported type", map[string]any{"ch": make(chan int)}, http.StatusInternalServerError, `{"status":"Error","code":"internal","error":"internal server error"}`},
+	}
+
+	// the encode failures are logged as server bugs
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := response.WriteJson(rec, http.StatusOK, tt.data)
+
+			if failed := tt.status != http.StatusOK; (err != nil) != failed {
+				t.Errorf("error %v, want one: %t", err, failed)
+			}
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type %q, want application/json", got)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
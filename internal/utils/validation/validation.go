@@ -0,0 +1,152 @@
+package validation // validation package owns the single validator instance used by all handlers
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - reflect       → read json struct tags for field names
+   - strings       → split json tag options ("name,omitempty")
+   - time          → dates of birth are checked against today
+   - phone         → what a valid phone number is
+   - types         → the date of birth and tag rules, the structs they apply to
+   - validator/v10 → the struct validation library
+*/
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/phone"
+	"github.com/go-playground/validator/v10"
+)
+
+/*
+validate (package-level instance)
+-------------------------------------------------------------
+
+	WHY ONLY ONE?
+	  → validator.Validate caches the parsed tags of every struct
+	    it has seen. Creating a new one per request throws that
+	    cache away and re-parses the tags every time.
+	  → A *validator.Validate is safe for concurrent use once it
+	    is configured, so all requests can share it.
+
+	IMPORTANT:
+	  → Register tag-name funcs and custom validations ONLY in
+	    newValidator(), never after the server has started.
+*/
+var validate = newValidator()
+
+/*
+Struct()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Validates v against its validate:"..." tags.
+
+	RETURN VALUE:
+	  → nil when valid
+	  → validator.ValidationErrors listing every failing field
+*/
+func Struct(v any) error {
+	return validate.Struct(v)
+}
+
+/*
+newValidator()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Builds a validator that reports fields by their JSON name.
+
+	WHY RegisterTagNameFunc?
+	  → By default errors use Go field names ("Name"), but clients
+	    send "name". The func below reads the json tag instead, so
+	    nested structs also produce JSON paths like "address.city".
+*/
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		default:
+			return name
+		}
+	})
+
+	validate.RegisterValidation("dob", isDateOfBirth)
+	validate.RegisterValidation("phone", isPhone)
+	validate.RegisterValidation("tag", isTag)
+	validate.RegisterValidation("nocontrol", hasNoControl)
+	validate.RegisterStructValidation(studentAgeMatchesDOB, types.Student{})
+	validate.RegisterStructValidation(patchAgeMatchesDOB, types.StudentPatch{})
+
+	return validate
+}
+
+// isDateOfBirth is the dob tag: a YYYY-MM-DD string giving an age of
+// types.MinBirthAge to types.MaxBirthAge today.
+func isDateOfBirth(fl validator.FieldLevel) bool {
+	return types.IsDateOfBirth(fl.Field().String(), time.Now())
+}
+
+// isPhone is the phone tag: an E.164 number ("+14155550123") the phone
+// package accepts. Handlers normalize first (types.Student.NormalizePhone),
+// so a number in any spelling it can read gets here in that form.
+func isPhone(fl validator.FieldLevel) bool {
+	return phone.IsE164(fl.Field().String())
+}
+
+// isTag is the tag tag, applied to each entry of the tags (dive):
+// types.IsTag, on tags already normalized (types.NormalizeTags).
+func isTag(fl validator.FieldLevel) bool {
+	return types.IsTag(fl.Field().String())
+}
+
+// hasNoControl is the nocontrol tag: types.HasNoControl.
+func hasNoControl(fl validator.FieldLevel) bool {
+	return types.HasNoControl(fl.Field().String())
+}
+
+/*
+studentAgeMatchesDOB() / patchAgeMatchesDOB()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → A body sending both age and date_of_birth must agree:
+	    otherwise "age" fails with the tag age_dob.
+	  → Normalize already filled in a missing age, so only an
+	    age the client sent can differ; an invalid date is left
+	    to the dob tag.
+*/
+func studentAgeMatchesDOB(sl validator.StructLevel) {
+	student := sl.Current().Interface().(types.Student)
+	if student.DateOfBirth != nil && !ageMatches(student.Age, *student.DateOfBirth) {
+		sl.ReportError(student.Age, "age", "Age", "age_dob", "")
+	}
+}
+
+func patchAgeMatchesDOB(sl validator.StructLevel) {
+	patch := sl.Current().Interface().(types.StudentPatch)
+	if patch.Age != nil && patch.DateOfBirth != nil && !ageMatches(*patch.Age, *patch.DateOfBirth) {
+		sl.ReportError(*patch.Age, "age", "Age", "age_dob", "")
+	}
+}
+
+// ageMatches reports whether age is the age today of someone born on dob
+// (true when dob fails the dob tag anyway).
+func ageMatches(age int, dob string) bool {
+	born, err := types.ParseDate(dob)
+	if err != nil || !types.IsDateOfBirth(dob, time.Now()) {
+		return true
+	}
+
+	return types.AgeOn(born, time.Now()) == age
+}
@@ -0,0 +1,120 @@
+package validation_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/types"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/response"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/utils/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// failures validates student the way the handlers do, normalized first,
+// and returns the messages of the failing fields.
+func failures(t testing.TB, student types.Student) []string {
+	t.Helper()
+
+	student.Normalize()
+	err := validation.Struct(student)
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct: %v, want validator.ValidationErrors", err)
+	}
+
+	var messages []string
+	for _, fe := range response.ValidationError(errs).Errors {
+		messages = append(messages, fe.Message)
+	}
+
+	return messages
+}
+
+// TestStudentBounds checks the limits on age and name right at their edges,
+// and that a name of only whitespace counts as no name.
+func TestStudentBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		student types.Student
+		want    []string
+	}{
+		{"age 0", types.Student{Name: "Ann Lee", Email: "ann@example.com", Age: 0}, []string{"age is required"}},
+		{"age 1", types.Student{Name: "Ann Lee", Email: "ann@example.com", Age: 1}, nil},
+		{"age 150", types.Student{Name: "Ann Lee", Email: "ann@example.com", Age: 150}, nil},
+		{"age 151", types.Student{Name: "Ann Lee", Email: "ann@example.com", Age: 151}, []string{"age must be at most 150"}},
+		{"negative age", types.Student{Name: "Ann Lee", Email: "ann@example.com", Age: -1}, []string{"age must be at least 1"}},
+		{"empty name", types.Student{Name: "", Email: "ann@example.com", Age: 20}, []string{"name is required"}},
+		{"whitespace-only name", types.Student{Name: " \t\n ", Email: "ann@example.com", Age: 20}, []string{"name is required"}},
+		{"one letter", types.Student{Name: " A ", Email: "ann@example.com", Age: 20}, []string{"name must be at least 2 characters"}},
+		{"two letters", types.Student{Name: "Al", Email: "ann@example.com", Age: 20}, nil},
+		{"100 characters", types.Student{Name: strings.Repeat("a", 100), Email: "ann@example.com", Age: 20}, nil},
+		{"101 characters", types.Student{Name: strings.Repeat("a", 101), Email: "ann@example.com", Age: 20}, []string{"name must be at most 100 characters"}},
+		{"inner whitespace collapsed", types.Student{Name: "Ann \t  Lee", Email: "ann@example.com", Age: 20}, nil},
+		{"control character", types.Student{Name: "Ann\x00Lee", Email: "ann@example.com", Age: 20}, []string{"name must not contain control characters"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failures(t, tt.student); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// student is a valid student using every rule, custom ones included, in
+// the normalized form handlers validate (its age derived).
+func student() types.Student {
+	dob, phone := "2004-05-06", "+442079460958"
+	s := types.Student{
+		Name: "Ann Lee", Email: "ann@example.com", DateOfBirth: &dob, Phone: &phone,
+		Tags: []string{"honours", "year-1"}, Status: types.StatusActive,
+	}
+	s.Normalize()
+
+	return s
+}
+
+// TestStructConcurrent checks the shared validator under -race: goroutines
+// validating valid and invalid students at once all get their own answer.
+func TestStructConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 64 {
+		wg.Go(func() {
+			s := student()
+			invalid := i%2 == 1
+			if invalid {
+				s.Name = "A"
+			}
+
+			var errs validator.ValidationErrors
+			err := validation.Struct(s)
+			if invalid && (!errors.As(err, &errs) || len(errs) != 1 || errs[0].Field() != "name") {
+				t.Errorf("goroutine %d: %v, want only name to fail", i, err)
+			}
+			if !invalid && err != nil {
+				t.Errorf("goroutine %d: %v, want no error", i, err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func BenchmarkStruct(b *testing.B) {
+	s := student()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := validation.Struct(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
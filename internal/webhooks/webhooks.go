@@ -0,0 +1,273 @@
+package webhooks // webhooks package notifies downstream systems (billing, LMS…) when students change
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes         → request body of a delivery
+   - context       → drain deadline, abandon retries at shutdown
+   - crypto/hmac   → signature of every delivery
+   - crypto/rand   → event IDs
+   - crypto/sha256 → HMAC-SHA256
+   - encoding/hex  → IDs and signatures as text
+   - encoding/json → event bodies
+   - fmt           → delivery errors
+   - io            → drain receiver responses
+   - log/slog      → dropped events, permanent delivery failures
+   - net/http      → POST to the receivers
+   - sync          → worker WaitGroup, closed flag
+   - time          → timestamps, backoff
+   - config        → the "webhooks" config section
+*/
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+)
+
+// SignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>" keyed with
+// webhooks.secret; receivers recompute it to check the sender.
+const SignatureHeader = "X-Webhook-Signature"
+
+// firstRetryDelay is the backoff before the second attempt, doubled after
+// every further failure.
+const firstRetryDelay = 500 * time.Millisecond
+
+/*
+Event STRUCT
+-------------------------------------------------------------
+  - The JSON body POSTed to every webhook URL.
+  - type is one of the events.Student* types ("student.created"…).
+  - id is unique per event (but the same on every retry), so
+    receivers can ignore duplicates.
+  - data is the student as stored after the change (before it,
+    for student.deleted).
+*/
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+/*
+Dispatcher STRUCT
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Delivers events in the background: Publish only queues
+	    them, so HTTP responses never wait for a receiver.
+	  → An events.Publisher: events.NotifyStorage feeds it.
+
+	LIFECYCLE:
+	  → New → Start (workers run) → Publish… → Close (drain)
+	  → Close stops accepting events, lets the workers finish the
+	    queue, and gives up on what is left when its ctx expires.
+*/
+type Dispatcher struct {
+	cfg    config.Webhooks
+	client *http.Client
+	queue  chan Event
+	wg     sync.WaitGroup
+
+	// mu guards closed: Publish must not send on the closed queue
+	mu     sync.RWMutex
+	closed bool
+
+	// abort is cancelled when Close gives up, cutting retries short
+	abortCtx context.Context
+	abort    context.CancelFunc
+}
+
+// New returns a Dispatcher for cfg; call Start before publishing.
+func New(cfg config.Webhooks) *Dispatcher {
+	abortCtx, abort := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		queue:    make(chan Event, cfg.QueueSize),
+		abortCtx: abortCtx,
+		abort:    abort,
+	}
+}
+
+// Start launches the delivery workers.
+func (d *Dispatcher) Start() {
+	for range d.cfg.Workers {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for event := range d.queue {
+				d.deliver(event)
+			}
+		}()
+	}
+}
+
+/*
+Publish()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Queues an event of eventType carrying data. Never blocks:
+	    with the queue full (receivers down for long) the event is
+	    dropped and logged, rather than stalling the API.
+*/
+func (d *Dispatcher) Publish(eventType string, data any) {
+	event := Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		slog.Warn("webhook event dropped: shutting down", slog.String("event", eventType), slog.String("event_id", event.ID))
+		return
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		slog.Error("webhook event dropped: queue is full",
+			slog.String("event", eventType),
+			slog.String("event_id", event.ID),
+			slog.Int("queue_size", d.cfg.QueueSize),
+		)
+	}
+}
+
+// Close stops accepting events and waits for the queue to drain. When ctx
+// ends first, pending retries are abandoned and ctx.Err() is returned.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.abort()
+		return nil
+	case <-ctx.Done():
+		d.abort()
+		slog.Warn("webhook queue not drained before the shutdown deadline", slog.Int("pending", len(d.queue)))
+		return ctx.Err()
+	}
+}
+
+// deliver sends one event to every URL.
+func (d *Dispatcher) deliver(event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("webhook event cannot be encoded", slog.String("event_id", event.ID), slog.String("error", err.Error()))
+		return
+	}
+
+	mac := hmac.New(sha256.New, []byte(d.cfg.Secret))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	for _, url := range d.cfg.URLs {
+		d.deliverTo(url, event, body, signature)
+	}
+}
+
+/*
+deliverTo()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → POSTs one event to one URL, up to max_attempts times.
+
+	RULES:
+	  → 2xx                      → delivered
+	  → 4xx (but 408 / 429)      → the receiver rejected it: no retry
+	  → 5xx, 408, 429, network   → retry after 0.5s, 1s, 2s…
+	  → Giving up is logged as an error: nothing else records it.
+*/
+func (d *Dispatcher) deliverTo(url string, event Event, body []byte, signature string) {
+	logger := slog.With(slog.String("event", event.Type), slog.String("event_id", event.ID), slog.String("url", url))
+
+	delay := firstRetryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := d.post(url, event, body, signature)
+		if err == nil {
+			return
+		}
+
+		if !retry || attempt >= d.cfg.MaxAttempts {
+			logger.Error("webhook delivery failed", slog.Int("attempts", attempt), slog.String("error", err.Error()))
+			return
+		}
+
+		select {
+		case <-d.abortCtx.Done():
+			logger.Error("webhook delivery abandoned at shutdown", slog.Int("attempts", attempt), slog.String("error", err.Error()))
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post makes one attempt; retry reports whether a failure is worth another.
+func (d *Dispatcher) post(url string, event Event, body []byte, signature string) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(d.abortCtx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "students-api-webhooks")
+	req.Header.Set("X-Webhook-Event", event.Type)
+	req.Header.Set("X-Webhook-Id", event.ID)
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, fmt.Errorf("receiver answered %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("receiver rejected the event with %d", resp.StatusCode)
+	}
+}
+
+// newEventID returns 16 random bytes as hex.
+func newEventID() string {
+	id := make([]byte, 16)
+	rand.Read(id) // never fails (crypto/rand panics instead)
+	return hex.EncodeToString(id)
+}
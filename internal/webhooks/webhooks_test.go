@@ -0,0 +1,240 @@
+package webhooks_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/config"
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/webhooks"
+)
+
+const secret = "s3cret"
+
+// delivery is one request the receiver got.
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver answers every delivery with status and records it; each one is
+// also sent on arrived when it is not nil.
+type receiver struct {
+	status  int
+	arrived chan struct{}
+
+	mu         sync.Mutex
+	deliveries []delivery
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	rc.deliveries = append(rc.deliveries, delivery{header: r.Header.Clone(), body: body})
+	rc.mu.Unlock()
+
+	if rc.arrived != nil {
+		rc.arrived <- struct{}{}
+	}
+	w.WriteHeader(rc.status)
+}
+
+func (rc *receiver) got() []delivery {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return append([]delivery(nil), rc.deliveries...)
+}
+
+// start serves rc and returns a started Dispatcher posting to it, with one
+// worker and up to maxAttempts attempts.
+func start(t *testing.T, rc *receiver, maxAttempts int) *webhooks.Dispatcher {
+	t.Helper()
+
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	d := webhooks.New(config.Webhooks{
+		URLs:        []string{srv.URL},
+		Secret:      secret,
+		MaxAttempts: maxAttempts,
+		Timeout:     time.Second,
+		Workers:     1,
+		QueueSize:   8,
+	})
+	d.Start()
+
+	return d
+}
+
+// quiet discards the dispatcher's logs (dropped events, failed deliveries).
+func quiet(t *testing.T) {
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+}
+
+// TestDelivery checks the body is the event and the signature the hex
+// HMAC-SHA256 of that body, keyed with the secret.
+func TestDelivery(t *testing.T) {
+	rc := &receiver{status: http.StatusNoContent}
+	d := start(t, rc, 3)
+
+	d.Publish("student.created", map[string]any{"id": 1})
+	if err := d.Close(t.Context()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got := rc.got()
+	if len(got) != 1 {
+		t.Fatalf("%d deliveries, want 1", len(got))
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(got[0].body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got[0].header.Get(webhooks.SignatureHeader) != want {
+		t.Errorf("%s %q, want %q", webhooks.SignatureHeader, got[0].header.Get(webhooks.SignatureHeader), want)
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(got[0].body, &event); err != nil {
+		t.Fatalf("body %s: %v", got[0].body, err)
+	}
+	if event.Type != "student.created" || event.Data.ID != 1 || event.ID == "" {
+		t.Errorf("event %s", got[0].body)
+	}
+	if got[0].header.Get("X-Webhook-Event") != event.Type || got[0].header.Get("X-Webhook-Id") != event.ID {
+		t.Errorf("X-Webhook-Event %q, X-Webhook-Id %q; want the event's type and id",
+			got[0].header.Get("X-Webhook-Event"), got[0].header.Get("X-Webhook-Id"))
+	}
+}
+
+// TestRetries checks 5xx, 408 and 429 are retried up to max_attempts with
+// the same body, and the other answers are not.
+func TestRetries(t *testing.T) {
+	quiet(t)
+
+	tests := []struct {
+		status   int
+		attempts int
+	}{
+		{http.StatusOK, 1},
+		{http.StatusBadRequest, 1},
+		{http.StatusNotFound, 1},
+		{http.StatusUnprocessableEntity, 1},
+		{http.StatusRequestTimeout, 3},
+		{http.StatusTooManyRequests, 3},
+		{http.StatusInternalServerError, 3},
+		{http.StatusServiceUnavailable, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			// the 0.5s + 1s of backoff are waited in parallel
+			t.Parallel()
+
+			rc := &receiver{status: tt.status}
+			d := start(t, rc, 3)
+
+			d.Publish("student.updated", map[string]any{"id": 1})
+			if err := d.Close(t.Context()); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			got := rc.got()
+			if len(got) != tt.attempts {
+				t.Fatalf("%d attempts, want %d", len(got), tt.attempts)
+			}
+			for _, attempt := range got[1:] {
+				if string(attempt.body) != string(got[0].body) {
+					t.Errorf("retry body %s, want the first one %s", attempt.body, got[0].body)
+				}
+			}
+		})
+	}
+}
+
+// TestPublishAfterClose checks an event published once Close returned is
+// dropped rather than sent on the closed queue.
+func TestPublishAfterClose(t *testing.T) {
+	quiet(t)
+	rc := &receiver{status: http.StatusOK}
+	d := start(t, rc, 3)
+
+	if err := d.Close(t.Context()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	d.Publish("student.created", map[string]any{"id": 1})
+
+	if got := rc.got(); len(got) != 0 {
+		t.Errorf("%d deliveries after Close, want 0", len(got))
+	}
+}
+
+// TestCloseDrains checks Close delivers what is still queued before
+// returning.
+func TestCloseDrains(t *testing.T) {
+	rc := &receiver{status: http.StatusOK}
+	d := start(t, rc, 3)
+
+	for i := range 5 {
+		d.Publish("student.created", map[string]any{"id": i + 1})
+	}
+	if err := d.Close(t.Context()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	ids := map[string]bool{}
+	for _, got := range rc.got() {
+		ids[got.header.Get("X-Webhook-Id")] = true
+	}
+	if len(ids) != 5 {
+		t.Errorf("%d events delivered, want the 5 queued", len(ids))
+	}
+}
+
+// TestCloseDeadline checks Close gives up at its deadline with ctx.Err(),
+// and the worker abandons the retries it was waiting for.
+func TestCloseDeadline(t *testing.T) {
+	quiet(t)
+	rc := &receiver{status: http.StatusServiceUnavailable, arrived: make(chan struct{}, 10)}
+	d := start(t, rc, 10)
+
+	d.Publish("student.deleted", map[string]any{"id": 1})
+	<-rc.arrived // the first attempt failed, the worker waits 0.5s to retry
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	if err := d.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v, want context.DeadlineExceeded", err)
+	}
+
+	// a second Close returns once the worker is gone: well before the
+	// retry it gave up on
+	ctx, cancel = context.WithTimeout(t.Context(), 300*time.Millisecond)
+	defer cancel()
+	if err := d.Close(ctx); err != nil {
+		t.Fatalf("the worker still runs after the deadline: %v", err)
+	}
+	if got := rc.got(); len(got) != 1 {
+		t.Errorf("%d attempts, want the 1 before the deadline", len(got))
+	}
+}
@@ -0,0 +1,344 @@
+package client // client package is the Go client of the students API, for other services
+
+/*
+   ---------------------------------------------------------
+   IMPORTS
+   ---------------------------------------------------------
+   - bytes         → request bodies are kept so retries can resend them
+   - context       → every call is cancellable
+   - crypto/rand   → Idempotency-Key of CreateStudent
+   - encoding/hex  → the key as text
+   - encoding/json → bodies in and out
+   - errors        → errors.As on *APIError (retry decisions)
+   - fmt           → error wrapping
+   - io            → drain response bodies
+   - net/http      → the transport
+   - net/url       → base URL and query strings
+   - strconv       → query values
+   - strings       → trim the base URL
+   - time          → default timeout, backoff
+*/
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of Config.
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 100 * time.Millisecond
+)
+
+// studentsPath is the collection the client talks to, below Config.BaseURL.
+const studentsPath = "/api/v1/students"
+
+/*
+Config STRUCT
+-------------------------------------------------------------
+  - BaseURL      → scheme and host of the API ("http://students:8082")
+  - HTTPClient   → nil uses a client with a 10s timeout
+  - Token        → sent as "Authorization: Bearer <token>"
+  - APIKey       → sent as X-API-Key (used when Token is empty)
+  - MaxRetries   → retries of a failed call: 0 means 3, negative
+                   disables them
+  - RetryBackoff → delay before the first retry, doubled after each
+                   one: 0 means 100ms
+*/
+type Config struct {
+	BaseURL      string
+	HTTPClient   *http.Client
+	Token        string
+	APIKey       string
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+/*
+Client STRUCT
+-------------------------------------------------------------
+  - Safe for concurrent use; build it once with New.
+
+	RETRIES:
+	  → Every call is safe to repeat, so every call is retried
+	    on connection errors and 5xx answers: GET, PUT and DELETE
+	    are idempotent, and CreateStudent sends an Idempotency-Key
+	    so the server replays the first result instead of creating
+	    the student twice.
+	  → Retries stop as soon as ctx is done.
+	  → A retried DELETE whose first attempt went through gets 404,
+	    a retried SetStudentStatus 409 (already in that status).
+*/
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	token        string
+	apiKey       string
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// New returns a Client for cfg. It fails when BaseURL is not an absolute
+// http(s) URL.
+func New(cfg Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("client: base URL %q must be an absolute http(s) URL", cfg.BaseURL)
+	}
+
+	c := &Client{
+		baseURL:      base,
+		httpClient:   cfg.HTTPClient,
+		token:        cfg.Token,
+		apiKey:       cfg.APIKey,
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = defaultMaxRetries
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.retryBackoff <= 0 {
+		c.retryBackoff = defaultRetryBackoff
+	}
+
+	return c, nil
+}
+
+// CreateStudent creates a student and returns it as stored.
+func (c *Client) CreateStudent(ctx context.Context, input StudentInput) (Student, error) {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return Student{}, err
+	}
+
+	var student Student
+	err = c.do(ctx, http.MethodPost, studentsPath, nil, input, http.Header{"Idempotency-Key": {key}}, &student)
+	return student, err
+}
+
+// GetStudent returns the student with id; a missing one is an *APIError
+// with StatusCode 404.
+func (c *Client) GetStudent(ctx context.Context, id ID) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodGet, studentPath(id), nil, nil, nil, &student)
+	return student, err
+}
+
+// ListStudents returns one page of the students matching opts.
+func (c *Client) ListStudents(ctx context.Context, opts ListOptions) (Page, error) {
+	var page Page
+	err := c.do(ctx, http.MethodGet, studentsPath, opts.query(), nil, nil, &page)
+	return page, err
+}
+
+// UpdateStudent replaces the student with id. With input.Version set, the
+// update only applies to that version (the server answers 412 otherwise).
+func (c *Client) UpdateStudent(ctx context.Context, id ID, input StudentInput) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodPut, studentPath(id), nil, input, nil, &student)
+	return student, err
+}
+
+// SetStudentStatus moves the student with id to status ("active",
+// "suspended" or "graduated"). A transition the server doesn't allow is an
+// *APIError with StatusCode 409.
+func (c *Client) SetStudentStatus(ctx context.Context, id ID, status string) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodPost, studentPath(id)+"/status", nil, map[string]string{"status": status}, nil, &student)
+	return student, err
+}
+
+// AddStudentTag tags the student with id; tagging it twice is not an error.
+// An 11th tag is an *APIError with StatusCode 409.
+func (c *Client) AddStudentTag(ctx context.Context, id ID, tag string) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodPost, studentPath(id)+"/tags/"+url.PathEscape(tag), nil, nil, nil, &student)
+	return student, err
+}
+
+// RemoveStudentTag untags the student with id; removing a tag it hasn't is
+// not an error.
+func (c *Client) RemoveStudentTag(ctx context.Context, id ID, tag string) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodDelete, studentPath(id)+"/tags/"+url.PathEscape(tag), nil, nil, nil, &student)
+	return student, err
+}
+
+// DeleteStudent soft-deletes the student with id.
+func (c *Client) DeleteStudent(ctx context.Context, id ID) error {
+	return c.do(ctx, http.MethodDelete, studentPath(id), nil, nil, nil, nil)
+}
+
+func studentPath(id ID) string {
+	return studentsPath + "/" + url.PathEscape(string(id))
+}
+
+// query encodes the non-zero options.
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Name != "" {
+		query.Set("name", o.Name)
+	}
+	if o.Email != "" {
+		query.Set("email", o.Email)
+	}
+	if o.Status != "" {
+		query.Set("status", o.Status)
+	}
+	if o.MinAge != nil {
+		query.Set("min_age", strconv.Itoa(*o.MinAge))
+	}
+	if o.MaxAge != nil {
+		query.Set("max_age", strconv.Itoa(*o.MaxAge))
+	}
+	for _, tag := range o.Tags {
+		query.Add("tag", tag)
+	}
+	if o.IncludeDeleted {
+		query.Set("include_deleted", "true")
+	}
+
+	return query
+}
+
+/*
+do()
+-------------------------------------------------------------
+
+	PURPOSE:
+	  → Sends one API call, with retries (see Client), and decodes
+	    a 2xx body into out (nil = ignore the body).
+
+	RETURN VALUE:
+	  → *APIError for a non-2xx answer
+	  → ctx.Err() once the context is done
+	  → the transport error of the last attempt otherwise
+*/
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in any, header http.Header, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("client: encoding request: %w", err)
+		}
+	}
+
+	target := c.baseURL.JoinPath(path)
+	target.RawQuery = query.Encode()
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, target.String(), body, header, out)
+
+		var apiErr *APIError
+		retryable := err != nil && ctx.Err() == nil &&
+			(!errors.As(err, &apiErr) || apiErr.StatusCode >= http.StatusInternalServerError)
+
+		if !retryable || attempt >= c.maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send is one attempt of do.
+func (c *Client) send(ctx context.Context, method, target string, body []byte, header http.Header, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("client: building request: %w", err)
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		// *url.Error already names the method and URL
+		return fmt.Errorf("client: %w", err)
+	}
+	defer func() {
+		// drain so the connection can be reused
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decoding %s %s response: %w", method, target, err)
+	}
+
+	return nil
+}
+
+// newIdempotencyKey returns 16 random bytes as hex.
+func newIdempotencyKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("client: generating idempotency key: %w", err)
+	}
+
+	return hex.EncodeToString(key), nil
+}
@@ -0,0 +1,246 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VINAYAK777CODER/STUDENTS-API/internal/apptest"
+	"github.com/VINAYAK777CODER/STUDENTS-API/pkg/client"
+)
+
+// flaky sits in front of the API and spoils the answers to the first POSTs:
+// dropped ones never reach the API, lost ones do (the student is created)
+// but the client only gets a 502, like a proxy timing out. It notes the
+// Idempotency-Key of every POST.
+type flaky struct {
+	api     http.Handler
+	dropped int
+	lost    int
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		f.api.ServeHTTP(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	f.keys = append(f.keys, r.Header.Get("Idempotency-Key"))
+	drop := f.dropped > 0
+	lose := !drop && f.lost > 0
+	if drop {
+		f.dropped--
+	} else if lose {
+		f.lost--
+	}
+	f.mu.Unlock()
+
+	switch {
+	case drop:
+		http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+	case lose:
+		f.api.ServeHTTP(httptest.NewRecorder(), r)
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	default:
+		f.api.ServeHTTP(w, r)
+	}
+}
+
+// attempts is the Idempotency-Keys seen, one per POST.
+func (f *flaky) attempts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.keys...)
+}
+
+// newClient serves the API over memory storage behind front and returns a
+// client for it, with a short backoff.
+func newClient(t *testing.T, front func(api http.Handler) http.Handler) *client.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(front(apptest.Handler(apptest.Config(t))))
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(client.Config{BaseURL: srv.URL, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+
+	return c
+}
+
+var ann = client.StudentInput{Name: "Ann Lee", Email: "ann@example.com", Age: 20}
+
+func TestClientRoundTrip(t *testing.T) {
+	c := newClient(t, func(api http.Handler) http.Handler { return api })
+	ctx := context.Background()
+
+	created, err := c.CreateStudent(ctx, ann)
+	if err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+	if created.Id != "1" || created.Name != "Ann Lee" || created.Version != 1 || created.CreatedAt.IsZero() {
+		t.Errorf("created %+v", created)
+	}
+
+	got, err := c.GetStudent(ctx, created.Id)
+	if err != nil || got.Email != "ann@example.com" {
+		t.Errorf("GetStudent = %+v, %v", got, err)
+	}
+
+	update := ann
+	update.Age = 21
+	update.Version = 1
+	if got, err = c.UpdateStudent(ctx, created.Id, update); err != nil || got.Age != 21 || got.Version != 2 {
+		t.Errorf("UpdateStudent = %+v, %v", got, err)
+	}
+
+	page, err := c.ListStudents(ctx, client.ListOptions{Limit: 10})
+	if err != nil || len(page.Data) != 1 || page.Meta.Total != 1 || page.Meta.Limit != 10 {
+		t.Errorf("ListStudents = %+v, %v", page, err)
+	}
+
+	if err := c.DeleteStudent(ctx, created.Id); err != nil {
+		t.Fatalf("DeleteStudent: %v", err)
+	}
+	var apiErr *client.APIError
+	if _, err := c.GetStudent(ctx, created.Id); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "not_found" {
+		t.Errorf("GetStudent after delete: %v, want a 404 not_found", err)
+	}
+}
+
+func TestClientRetriesCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		dropped, lost int
+	}{
+		{"answers dropped before the API", 2, 0},
+		{"answer lost after the create", 0, 1},
+		{"both", 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var front *flaky
+			c := newClient(t, func(api http.Handler) http.Handler {
+				front = &flaky{api: api, dropped: tt.dropped, lost: tt.lost}
+				return front
+			})
+			ctx := context.Background()
+
+			created, err := c.CreateStudent(ctx, ann)
+			if err != nil {
+				t.Fatalf("CreateStudent: %v", err)
+			}
+			if created.Id != "1" || created.Email != "ann@example.com" {
+				t.Errorf("created %+v", created)
+			}
+
+			// every attempt carried the same key, so the API created once
+			// and replayed that answer
+			keys := front.attempts()
+			if len(keys) != tt.dropped+tt.lost+1 {
+				t.Errorf("%d attempts, want %d", len(keys), tt.dropped+tt.lost+1)
+			}
+			for _, key := range keys {
+				if key == "" || key != keys[0] {
+					t.Errorf("Idempotency-Keys %q, want one and the same", keys)
+					break
+				}
+			}
+			page, err := c.ListStudents(ctx, client.ListOptions{})
+			if err != nil || page.Meta.Total != 1 {
+				t.Errorf("ListStudents = %d students, %v; want 1", page.Meta.Total, err)
+			}
+
+			// the next create is a new key
+			if _, err := c.CreateStudent(ctx, client.StudentInput{Name: "Bob Lee", Email: "bob@example.com", Age: 30}); err != nil {
+				t.Fatalf("second CreateStudent: %v", err)
+			}
+			if keys := front.attempts(); keys[len(keys)-1] == keys[0] {
+				t.Error("the second create reused the first one's Idempotency-Key")
+			}
+		})
+	}
+}
+
+func TestClientGivesUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		calls  int32
+	}{
+		{"5xx, retried 3 times by default", http.StatusInternalServerError, 4},
+		{"4xx, never retried", http.StatusConflict, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			c := newClient(t, func(http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					calls.Add(1)
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"status":"Error","code":"some_code","error":"it failed"}`))
+				})
+			})
+
+			_, err := c.CreateStudent(context.Background(), ann)
+			var apiErr *client.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Code != "some_code" || apiErr.Message != "it failed" {
+				t.Errorf("error %v, want an *APIError %d", err, tt.status)
+			}
+			if got := calls.Load(); got != tt.calls {
+				t.Errorf("%d calls, want %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestClientValidationError(t *testing.T) {
+	c := newClient(t, func(api http.Handler) http.Handler { return api })
+
+	_, err := c.CreateStudent(context.Background(), client.StudentInput{Name: "A", Email: "ann@example.com", Age: 20})
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "validation_failed" {
+		t.Fatalf("error %v, want a 400 validation_failed", err)
+	}
+	want := client.FieldError{Field: "name", Tag: "min", Message: "name must be at least 2 characters"}
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0] != want {
+		t.Errorf("fields %+v, want [%+v]", apiErr.Fields, want)
+	}
+}
+
+func TestClientStopsWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	// with an hour of backoff only the context can end the call
+	c, err := client.New(client.Config{BaseURL: srv.URL, RetryBackoff: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.GetStudent(ctx, "1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error %v, want context.DeadlineExceeded", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("took %s after the context ended", took)
+	}
+}
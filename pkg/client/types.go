@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ID is the ID of a student: a number ("7") on a server with
+// storage.id_scheme serial, a UUID with uuid. Either way it is used as it
+// is, in paths; the server sends a number as a JSON number and a UUID as
+// a JSON string, UnmarshalJSON takes both.
+type ID string
+
+// UnmarshalJSON reads a JSON number or string.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '"' && !bytes.Equal(data, []byte("null")) {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		*id = ID(number)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*id = ID(text)
+	return nil
+}
+
+// Student is a student as the API returns it. It mirrors the server's
+// types.Student, which other modules cannot import.
+// DateOfBirth is YYYY-MM-DD, nil when the student has none; with one, Age
+// is derived from it by the server. Phone is in E.164 ("+14155550123").
+// Tags are lower-case and sorted, [] when the student has none.
+type Student struct {
+	Id          ID         `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Age         int        `json:"age"`
+	DateOfBirth *string    `json:"date_of_birth,omitempty"`
+	Phone       *string    `json:"phone,omitempty"`
+	Tags        []string   `json:"tags"`
+	Status      string     `json:"status"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// StudentInput is the body of a create or a full update. For updates,
+// Version is the version being edited (0 = overwrite whatever is current).
+// Status only applies to a create ("" = active); updates ignore it, see
+// Client.SetStudentStatus.
+// Send DateOfBirth (YYYY-MM-DD) rather than Age: the age is then derived
+// from it, and an Age sent too must match. Phone may be in any usual
+// format; the server stores it in E.164. Tags (at most 10) are lower-cased
+// and deduplicated by the server; an update replaces them all.
+type StudentInput struct {
+	Name        string   `json:"name"`
+	Email       string   `json:"email"`
+	Age         int      `json:"age,omitempty"`
+	DateOfBirth string   `json:"date_of_birth,omitempty"`
+	Phone       string   `json:"phone,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Version     int      `json:"version,omitempty"`
+}
+
+// ListOptions are the query parameters of ListStudents. Zero values are
+// left out, so the server defaults apply. A student must have all the Tags.
+type ListOptions struct {
+	Limit          int
+	Offset         int
+	Cursor         string
+	Name           string
+	Email          string
+	Status         string
+	MinAge         *int
+	MaxAge         *int
+	Tags           []string
+	IncludeDeleted bool
+}
+
+// Page is one page of ListStudents. Meta.NextCursor is set in cursor mode
+// when more students follow; pass it as ListOptions.Cursor.
+type Page struct {
+	Data []Student `json:"data"`
+	Meta Meta      `json:"meta"`
+}
+
+// Meta describes a Page.
+type Meta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+/*
+APIError STRUCT
+-------------------------------------------------------------
+  - Returned for every non-2xx answer of the server, built from
+    its error body ({"status":"Error","code":…,"error":…}).
+  - StatusCode is the HTTP status; Code the stable error code
+    ("not_found", "conflict", …) to switch on.
+  - Use errors.As(err, &apiErr) to get at it.
+*/
+type APIError struct {
+	StatusCode int
+	Code       string       `json:"code"`
+	Message    string       `json:"error"`
+	Fields     []FieldError `json:"errors"`
+}
+
+// FieldError is one failing field of a validation error.
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "students api: %d", e.StatusCode)
+	if e.Code != "" {
+		b.WriteString(" " + e.Code)
+	}
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+
+	return b.String()
+}